@@ -2,15 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"maintainer-firewall/api-go/internal/config"
 	"maintainer-firewall/api-go/internal/http/handlers"
+	"maintainer-firewall/api-go/internal/logging"
+	"maintainer-firewall/api-go/internal/metrics"
+	"maintainer-firewall/api-go/internal/secretbox"
 	"maintainer-firewall/api-go/internal/service"
 	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -18,10 +27,26 @@ import (
 
 func main() {
 	cfg := config.Load()
+	if err := logging.Setup(cfg.LogLevel); err != nil {
+		fatal("invalid LOG_LEVEL", err)
+	}
+
+	// rootCtx is cancelled on SIGINT/SIGTERM, which starts the shutdown below.
+	rootCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
 
-	eventStore, err := store.NewWebhookEventStore(context.Background(), cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(rootCtx, tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.OTLPEndpoint,
+		ServiceName: cfg.TracingServiceName,
+	})
 	if err != nil {
-		log.Fatalf("failed to init webhook event store: %v", err)
+		fatal("failed to init tracing", err)
+	}
+
+	eventStore, err := store.NewWebhookEventStore(rootCtx, cfg.DatabaseURL)
+	if err != nil {
+		fatal("failed to init webhook event store", err)
 	}
 	defer eventStore.Close()
 
@@ -31,25 +56,139 @@ func main() {
 		if adminName != "" && adminPass != "" {
 			hash, hashErr := bcrypt.GenerateFromPassword([]byte(adminPass), bcrypt.DefaultCost)
 			if hashErr != nil {
-				log.Fatalf("failed to hash bootstrap admin password: %v", hashErr)
+				fatal("failed to hash bootstrap admin password", hashErr)
 			}
-			if err := eventStore.EnsureBootstrapAdminUser(context.Background(), adminName, string(hash)); err != nil {
-				log.Fatalf("failed to bootstrap admin user: %v", err)
+			if err := eventStore.EnsureBootstrapAdminUser(rootCtx, adminName, string(hash)); err != nil {
+				fatal("failed to bootstrap admin user", err)
 			}
 		}
 	}
 
+	var secretsBox *secretbox.Box
+	if strings.TrimSpace(cfg.SecretsEncryptionKey) != "" {
+		secretsBox, err = secretbox.New(cfg.SecretsEncryptionKey)
+		if err != nil {
+			fatal("failed to init secrets encryption", err)
+		}
+	} else {
+		slog.Warn("SECRETS_ENCRYPTION_KEY is not set; per-tenant credentials are disabled")
+	}
+	tenantCredentials := service.NewTenantCredentials(eventStore, secretsBox)
+	tenantCredentialsHandler := handlers.NewTenantCredentialsHandler(eventStore, secretsBox)
+	tenantCredentialsHandler.OnChange = tenantCredentials.Invalidate
+
 	webhookHandler := handlers.NewWebhookHandler(cfg.GitHubWebhookSecret, eventStore)
+	webhookHandler.SecondarySecret = cfg.GitHubWebhookSecretPrevious
+	webhookHandler.SecondarySecretExpiresAt = cfg.WebhookSecretPrevExpiresAt
+	webhookHandler.Secrets = tenantCredentials
+	webhookHandler.SignatureSchemes, err = handlers.ParseSignatureSchemes(cfg.WebhookSignatureSchemes)
+	if err != nil {
+		fatal("invalid WEBHOOK_SIGNATURE_SCHEMES", err)
+	}
+	webhookHandler.Router = eventStore
+	webhookHandler.Repositories = eventStore
+	webhookHandler.Quarantine = eventStore
+	webhookHandler.MaxBodyBytes = int64(cfg.WebhookMaxBodyBytes)
+	webhookRoutesHandler := handlers.NewWebhookRoutesHandler(eventStore)
 	githubExecutor := service.NewGitHubActionExecutor(cfg.GitHubToken)
+	githubExecutor.TokenLookup = tenantCredentials.GitHubToken
+	if cfg.GitHubAppID != "" && strings.TrimSpace(cfg.GitHubAppPrivateKey) != "" {
+		appTokens, appErr := service.NewGitHubAppTokenSource(cfg.GitHubAppID, []byte(cfg.GitHubAppPrivateKey))
+		if appErr != nil {
+			fatal("failed to init github app auth", appErr)
+		}
+		githubExecutor.AppTokens = appTokens
+		githubExecutor.InstallationLookup = eventStore.GetTenantGitHubInstallationID
+		slog.Info("github app auth enabled", "app_id", cfg.GitHubAppID)
+	}
 	webhookHandler.ActionExecutor = githubExecutor
+	webhookHandler.GitLabSecret = cfg.GitLabWebhookToken
+	webhookHandler.GiteaSecret = cfg.GiteaWebhookSecret
+	var gitlabExecutor, giteaExecutor service.ActionExecutor
+	if strings.TrimSpace(cfg.GitLabToken) != "" {
+		gitlabExecutor = service.NewGitLabActionExecutor(cfg.GitLabBaseURL, cfg.GitLabToken)
+	}
+	if strings.TrimSpace(cfg.GiteaToken) != "" && cfg.GiteaBaseURL != "" {
+		giteaExecutor = service.NewGiteaActionExecutor(cfg.GiteaBaseURL, cfg.GiteaToken)
+	}
+	providers := service.NewProviderRegistry(
+		service.GitHubProvider(githubExecutor),
+		service.GitLabProvider(gitlabExecutor),
+		service.GiteaProvider(giteaExecutor),
+	)
+	webhookHandler.Providers = providers
+	supervisor := service.NewSupervisor()
+	workersHandler := handlers.NewWorkersHandler(supervisor)
+	healthHandler := handlers.NewHealthHandler(eventStore, supervisor)
+	healthHandler.BacklogWarnThreshold = int64(cfg.ActionBacklogWarnThreshold)
+	metrics.Default.MustRegister(eventStore.DBStatsCollector())
+	prometheusHandler := handlers.NewPrometheusHandler(metrics.Default, cfg.MetricsToken)
 	actionFailureRetryHandler := handlers.NewActionFailureRetryHandler(eventStore, githubExecutor)
+	actionFailureRetryHandler.Providers = providers
+	actionFailureRetryHandler.MaxAttempts = cfg.ActionRetryMaxAttempts
+	actionFailureRetryHandler.BaseBackoff = time.Duration(cfg.ActionRetryBaseBackoffSec) * time.Second
+	if cfg.ActionRetryIntervalMinute > 0 {
+		interval := time.Duration(cfg.ActionRetryIntervalMinute) * time.Minute
+		service.StartActionFailureRetryWorker(supervisor, interval, actionFailureRetryHandler.RetryDueFailures)
+		slog.Info("action failure retry worker enabled", "interval", interval.String(), "max_attempts", cfg.ActionRetryMaxAttempts)
+	}
 	eventsHandler := handlers.NewEventsHandler(eventStore, githubExecutor)
+	eventsHandler.Sources = eventStore
+	eventsHandler.Poller = githubExecutor
+	eventsHandler.History = eventStore
+	eventsHandler.Processor = webhookHandler
+	eventsHandler.EvaluateFeed = cfg.GitHubSyncEvaluateRules
+	eventsHandler.ExecuteActions = cfg.GitHubSyncExecuteActions
+	eventsHandler.Known = eventStore
+	eventSourcesHandler := handlers.NewGitHubEventSourcesHandler(eventStore)
+	githubSyncHandler := handlers.NewGitHubSyncHandler(eventStore, eventsHandler)
+	githubSyncHandler.RunTimeout = time.Duration(cfg.GitHubSyncTimeoutSeconds) * time.Second
 	if cfg.GitHubSyncIntervalMinute > 0 {
-		interval := time.Duration(cfg.GitHubSyncIntervalMinute) * time.Minute
-		service.StartGitHubEventsSyncWorker(context.Background(), interval, eventsHandler.SyncGitHubEvents)
-		log.Printf("github events sync worker enabled: interval=%s", interval)
+		// Seeds the default tenant's schedule; later changes go through the API.
+		if err := eventStore.EnsureGitHubSyncSchedule(rootCtx, store.GitHubSyncScheduleRecord{
+			IntervalMinutes: cfg.GitHubSyncIntervalMinute,
+			NextRunAt:       time.Now().UTC(),
+			UpdatedBy:       "config",
+		}); err != nil {
+			slog.Error("seed default github sync schedule failed", "error", err)
+		}
+	}
+	if cfg.GitHubSyncSchedulerSeconds > 0 {
+		interval := time.Duration(cfg.GitHubSyncSchedulerSeconds) * time.Second
+		service.StartGitHubEventsSyncWorker(supervisor, interval, 10*time.Minute, githubSyncHandler.RunDueSyncs)
+		// A due schedule should be claimed within a tick or two, plus one run.
+		healthHandler.SyncOverdueAfter = 2*interval + 10*time.Minute
+		slog.Info("github events sync scheduler enabled", "interval", interval.String(), "holder", githubSyncHandler.Holder)
+	}
+	githubStatusHandler := handlers.NewGitHubStatusHandler(githubExecutor)
+	scheduledJobsHandler := handlers.NewScheduledJobsHandler(eventStore, githubExecutor)
+	if cfg.ScheduledJobsIntervalMinute > 0 {
+		interval := time.Duration(cfg.ScheduledJobsIntervalMinute) * time.Minute
+		service.StartScheduledJobsWorker(supervisor, interval, scheduledJobsHandler.RunDueJobs)
+		slog.Info("scheduled jobs worker enabled", "interval", interval.String())
 	}
 	alertsHandler := handlers.NewAlertsHandler(eventStore)
+	repositoriesHandler := handlers.NewRepositoriesHandler(eventStore)
+	quarantineHandler := handlers.NewQuarantineHandler(eventStore, webhookHandler)
+	eventReprocessHandler := handlers.NewEventReprocessHandler(eventStore, webhookHandler)
+	hookRecoveryHandler := handlers.NewHookRecoveryHandler(eventStore, githubExecutor, webhookHandler, eventStore)
+	if cfg.HookRecoveryIntervalMinute > 0 {
+		interval := time.Duration(cfg.HookRecoveryIntervalMinute) * time.Minute
+		service.StartHookRecoveryWorker(supervisor, interval, hookRecoveryHandler.RunAll)
+		slog.Info("hook delivery recovery worker enabled", "interval", interval.String())
+	}
+	sloHandler := handlers.NewSLOHandler(eventStore)
+	if cfg.SLOEvaluationIntervalMinute > 0 {
+		interval := time.Duration(cfg.SLOEvaluationIntervalMinute) * time.Minute
+		service.StartSLOWorker(supervisor, interval, sloHandler.EvaluateBurnRates)
+		slog.Info("slo burn rate worker enabled", "interval", interval.String())
+	}
+	metricsRollupHandler := handlers.NewMetricsRollupHandler(eventStore)
+	if cfg.MetricsRollupIntervalSec > 0 {
+		interval := time.Duration(cfg.MetricsRollupIntervalSec) * time.Second
+		service.StartMetricsRollupWorker(supervisor, interval, metricsRollupHandler.Compact)
+		slog.Info("metrics rollup compaction enabled", "interval", interval.String(), "holder", metricsRollupHandler.Holder)
+	}
 	rulesHandler := handlers.NewRulesHandler(eventStore)
 	usersHandler := handlers.NewUserHandler(eventStore)
 	tenantsHandler := handlers.NewTenantsHandler(eventStore)
@@ -63,13 +202,15 @@ func main() {
 	})
 	authHandler := handlers.NewAuthHandlerWithStore(eventStore, cfg.AdminUsername, cfg.AdminPassword, cfg.JWTSecret, 24*time.Hour, cfg.AuthEnvFallback)
 
-	r := gin.Default()
+	r := gin.New()
+	r.Use(handlers.TracingMiddleware(), handlers.RequestLogger(), handlers.Recovery())
 
 	// CORS中间件
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "http://localhost:5173")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -81,8 +222,19 @@ func main() {
 	})
 
 	r.GET("/health", handlers.Health)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
+	r.GET("/metrics", prometheusHandler.Serve)
 	r.POST("/auth/login", authHandler.Login)
 	r.POST("/webhook/github", webhookHandler.GitHub)
+	r.POST("/webhook/github/t/:token", webhookHandler.GitHub)
+	r.POST("/webhook/github/:tenant", webhookHandler.GitHub)
+	r.POST("/webhook/gitlab", webhookHandler.GitLab)
+	r.POST("/webhook/gitlab/t/:token", webhookHandler.GitLab)
+	r.POST("/webhook/gitlab/:tenant", webhookHandler.GitLab)
+	r.POST("/webhook/gitea", webhookHandler.Gitea)
+	r.POST("/webhook/gitea/t/:token", webhookHandler.Gitea)
+	r.POST("/webhook/gitea/:tenant", webhookHandler.Gitea)
 
 	api := r.Group("/api")
 	api.Use(handlers.AuthMiddleware(cfg.JWTSecret))
@@ -93,6 +245,7 @@ func main() {
 	readAPI.GET("/events/sync-status", eventsHandler.GitHubSyncStatus)
 	readAPI.GET("/alerts", alertsHandler.List)
 	readAPI.GET("/alerts/filter-options", alertsHandler.FilterOptions)
+	readAPI.GET("/repositories", repositoriesHandler.List)
 	readAPI.GET("/rules", rulesHandler.List)
 	readAPI.GET("/rules/filter-options", rulesHandler.FilterOptions)
 	readAPI.GET("/rules/versions", rulesHandler.ListVersions)
@@ -105,7 +258,17 @@ func main() {
 	readAPI.GET("/metrics/overview", observabilityHandler.MetricsOverview)
 	readAPI.GET("/metrics/timeseries", observabilityHandler.MetricsTimeSeries)
 	readAPI.GET("/action-failures", observabilityHandler.ActionFailures)
+	readAPI.GET("/action-failures/retry-policy", actionFailureRetryHandler.GetRetryPolicy)
 	readAPI.GET("/audit-logs", observabilityHandler.AuditLogs)
+	readAPI.GET("/github/rate-limit", githubStatusHandler.RateLimit)
+	readAPI.GET("/scheduled-jobs", scheduledJobsHandler.List)
+	readAPI.GET("/scheduled-jobs/:id/runs", scheduledJobsHandler.ListRuns)
+	readAPI.GET("/github/sync/schedule", githubSyncHandler.GetSchedule)
+	readAPI.GET("/github/sync/runs", githubSyncHandler.ListRuns)
+	readAPI.GET("/workers", workersHandler.List)
+	readAPI.GET("/slos", sloHandler.List)
+	readAPI.GET("/slos/status", sloHandler.StatusAll)
+	readAPI.GET("/slos/:id/status", sloHandler.Status)
 
 	writeAPI := api.Group("")
 	writeAPI.Use(handlers.RequirePermission("write"))
@@ -117,20 +280,103 @@ func main() {
 	writeAPI.PUT("/users/:id/password", usersHandler.UpdatePassword)
 	writeAPI.PATCH("/users/:id/active", usersHandler.UpdateActive)
 	writeAPI.POST("/action-failures/:id/retry", actionFailureRetryHandler.Retry)
+	writeAPI.POST("/action-failures/retry", actionFailureRetryHandler.RetryMatching)
+	writeAPI.POST("/events/:delivery_id/reprocess", eventReprocessHandler.Reprocess)
+	writeAPI.POST("/events/reprocess", eventReprocessHandler.ReprocessMatching)
+	writeAPI.POST("/scheduled-jobs", scheduledJobsHandler.Create)
+	writeAPI.PATCH("/scheduled-jobs/:id/active", scheduledJobsHandler.UpdateActive)
+	writeAPI.POST("/scheduled-jobs/:id/run", scheduledJobsHandler.RunNow)
+	writeAPI.PUT("/github/sync/schedule", githubSyncHandler.UpdateSchedule)
+	writeAPI.PATCH("/github/sync/schedule/paused", githubSyncHandler.UpdatePaused)
+	writeAPI.POST("/github/sync/run", githubSyncHandler.RunNow)
 
 	adminAPI := api.Group("")
 	adminAPI.Use(handlers.RequirePermission("admin"))
 	adminAPI.POST("/tenants", tenantsHandler.Create)
+	adminAPI.PUT("/action-failures/retry-policy", actionFailureRetryHandler.UpdateRetryPolicy)
+	adminAPI.GET("/tenants/:id/credentials", tenantCredentialsHandler.List)
+	adminAPI.PUT("/tenants/:id/credentials/:kind", tenantCredentialsHandler.Put)
+	adminAPI.POST("/tenants/:id/webhook-secret/rotation", tenantCredentialsHandler.StartWebhookSecretRotation)
+	adminAPI.GET("/tenants/:id/webhook-routes", webhookRoutesHandler.List)
+	adminAPI.POST("/tenants/:id/webhook-routes", webhookRoutesHandler.Create)
+	adminAPI.GET("/webhook-quarantine", quarantineHandler.List)
+	adminAPI.GET("/webhook-quarantine/:id", quarantineHandler.Get)
+	adminAPI.PUT("/webhook-quarantine/:id/payload", quarantineHandler.UpdatePayload)
+	adminAPI.POST("/webhook-quarantine/:id/reingest", quarantineHandler.Reingest)
+	adminAPI.POST("/webhook-quarantine/:id/discard", quarantineHandler.Discard)
+	adminAPI.GET("/github/event-sources", eventSourcesHandler.List)
+	adminAPI.POST("/github/event-sources", eventSourcesHandler.Create)
+	adminAPI.GET("/hook-recovery/hooks", hookRecoveryHandler.List)
+	adminAPI.POST("/hook-recovery/hooks", hookRecoveryHandler.Create)
+	adminAPI.POST("/hook-recovery/hooks/:id/run", hookRecoveryHandler.RunNow)
+	adminAPI.POST("/slos", sloHandler.Create)
+	adminAPI.PUT("/slos/:id", sloHandler.Update)
 
 	dangerAdminAPI := api.Group("")
 	dangerAdminAPI.Use(handlers.RequirePermission("admin"), handlers.RequireDangerConfirm())
 	dangerAdminAPI.DELETE("/users/:id", usersHandler.Delete)
 	dangerAdminAPI.PATCH("/tenants/:id/active", tenantsHandler.UpdateActive)
+	dangerAdminAPI.PATCH("/tenants/:id/github-installation", tenantsHandler.UpdateGitHubInstallation)
+	dangerAdminAPI.PATCH("/tenants/:id/webhook-signature", tenantsHandler.UpdateWebhookSignature)
+	dangerAdminAPI.DELETE("/tenants/:id/credentials/:kind", tenantCredentialsHandler.Delete)
+	dangerAdminAPI.DELETE("/tenants/:id/webhook-secret/rotation", tenantCredentialsHandler.FinishWebhookSecretRotation)
+	dangerAdminAPI.DELETE("/tenants/:id/webhook-routes/:routeId", webhookRoutesHandler.Delete)
 	dangerAdminAPI.POST("/config-update", observabilityHandler.ConfigUpdate)
 	dangerAdminAPI.POST("/rules/rollback", rulesHandler.Rollback)
+	dangerAdminAPI.DELETE("/hook-recovery/hooks/:id", hookRecoveryHandler.Delete)
+	dangerAdminAPI.DELETE("/github/event-sources/:id", eventSourcesHandler.Delete)
+	dangerAdminAPI.DELETE("/slos/:id", sloHandler.Delete)
 
 	addr := fmt.Sprintf(":%s", cfg.Port)
-	if err := r.Run(addr); err != nil {
-		panic(err)
+	srv := &http.Server{Addr: addr, Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	slog.Info("listening", "addr", addr)
+
+	var serveFailure error
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			serveFailure = err
+		}
+	case <-rootCtx.Done():
+	}
+	stopSignals()
+
+	// In-flight requests and worker runs share one drain deadline.
+	drainTimeout := time.Duration(cfg.ShutdownTimeoutSeconds) * time.Second
+	slog.Info("shutting down: draining requests and workers", "timeout", drainTimeout.String())
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http server shutdown failed", "error", err)
+	}
+	if err := supervisor.Shutdown(shutdownCtx); err != nil {
+		slog.Error("worker shutdown failed", "error", err)
+	}
+	releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancelRelease()
+	if err := githubSyncHandler.ReleaseLease(releaseCtx); err != nil {
+		slog.Error("release github sync lease failed", "error", err)
+	}
+	if err := metricsRollupHandler.ReleaseLease(releaseCtx); err != nil {
+		slog.Error("release metrics rollup lease failed", "error", err)
 	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("flush traces failed", "error", err)
+	}
+	if serveFailure != nil {
+		fatal("http server failed", serveFailure)
+	}
+	slog.Info("shutdown complete")
+}
+
+// fatal logs err and exits; like log.Fatal it skips deferred calls.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -4,20 +4,53 @@ import (
 	"bufio"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	Port                     string
-	GitHubWebhookSecret      string
-	GitHubToken              string
-	AdminUsername            string
-	AdminPassword            string
-	JWTSecret                string
-	DatabaseURL              string
-	AuthEnvFallback          bool
-	BootstrapAdmin           bool
-	GitHubSyncIntervalMinute int
+	Port                        string
+	GitHubWebhookSecret         string
+	GitHubWebhookSecretPrevious string
+	WebhookSecretPrevExpiresAt  time.Time
+	WebhookSignatureSchemes     string
+	WebhookMaxBodyBytes         int
+	GitHubToken                 string
+	GitLabWebhookToken          string
+	GitLabToken                 string
+	GitLabBaseURL               string
+	GiteaWebhookSecret          string
+	GiteaToken                  string
+	GiteaBaseURL                string
+	GitHubAppID                 string
+	GitHubAppPrivateKey         string
+	SecretsEncryptionKey        string
+	AdminUsername               string
+	AdminPassword               string
+	JWTSecret                   string
+	DatabaseURL                 string
+	AuthEnvFallback             bool
+	BootstrapAdmin              bool
+	GitHubSyncIntervalMinute    int
+	GitHubSyncSchedulerSeconds  int
+	GitHubSyncTimeoutSeconds    int
+	GitHubSyncEvaluateRules     bool
+	GitHubSyncExecuteActions    bool
+	ScheduledJobsIntervalMinute int
+	ActionRetryIntervalMinute   int
+	ActionRetryMaxAttempts      int
+	ActionRetryBaseBackoffSec   int
+	HookRecoveryIntervalMinute  int
+	SLOEvaluationIntervalMinute int
+	MetricsRollupIntervalSec    int
+	ShutdownTimeoutSeconds      int
+	ActionBacklogWarnThreshold  int
+	MetricsToken                string
+	TracingExporter             string
+	OTLPEndpoint                string
+	TracingServiceName          string
+	LogLevel                    string
 }
 
 func Load() Config {
@@ -30,6 +63,22 @@ func Load() Config {
 	authEnvFallback := strings.ToLower(strings.TrimSpace(getenvOrDefault("AUTH_ENV_FALLBACK", "true"))) != "false"
 	bootstrapAdmin := strings.ToLower(strings.TrimSpace(getenvOrDefault("BOOTSTRAP_ADMIN_ON_START", "true"))) != "false"
 	githubSyncIntervalMinute := parseSyncIntervalMinutes(getenvOrDefault("GITHUB_EVENTS_SYNC_INTERVAL_MINUTES", "0"))
+	githubSyncSchedulerSeconds := parseBoundedInt(getenvOrDefault("GITHUB_SYNC_SCHEDULER_SECONDS", "30"), 30, 0, 3600)
+	githubSyncTimeoutSeconds := parseBoundedInt(getenvOrDefault("GITHUB_SYNC_TIMEOUT_SECONDS", "120"), 120, 10, 3600)
+	githubSyncEvaluateRules := strings.ToLower(strings.TrimSpace(os.Getenv("GITHUB_SYNC_EVALUATE_RULES"))) == "true"
+	githubSyncExecuteActions := strings.ToLower(strings.TrimSpace(os.Getenv("GITHUB_SYNC_EXECUTE_ACTIONS"))) == "true"
+	scheduledJobsIntervalMinute := parseSyncIntervalMinutes(getenvOrDefault("SCHEDULED_JOBS_INTERVAL_MINUTES", "1"))
+	actionRetryIntervalMinute := parseSyncIntervalMinutes(getenvOrDefault("ACTION_RETRY_INTERVAL_MINUTES", "5"))
+	actionRetryMaxAttempts := parseBoundedInt(getenvOrDefault("ACTION_RETRY_MAX_ATTEMPTS", "5"), 5, 1, 50)
+	actionRetryBaseBackoffSec := parseBoundedInt(getenvOrDefault("ACTION_RETRY_BASE_BACKOFF_SECONDS", "60"), 60, 1, 86400)
+	hookRecoveryIntervalMinute := parseSyncIntervalMinutes(getenvOrDefault("HOOK_RECOVERY_INTERVAL_MINUTES", "0"))
+	sloEvaluationIntervalMinute := parseSyncIntervalMinutes(getenvOrDefault("SLO_EVALUATION_INTERVAL_MINUTES", "5"))
+	metricsRollupIntervalSec := parseBoundedInt(getenvOrDefault("METRICS_ROLLUP_INTERVAL_SECONDS", "60"), 60, 0, 3600)
+	actionBacklogWarnThreshold := parseBoundedInt(getenvOrDefault("ACTION_BACKLOG_WARN_THRESHOLD", "500"), 500, 0, 1000000)
+	shutdownTimeoutSeconds := parseBoundedInt(getenvOrDefault("SHUTDOWN_TIMEOUT_SECONDS", "30"), 30, 1, 600)
+	webhookMaxBodyBytes := parseBoundedInt(getenvOrDefault("WEBHOOK_MAX_BODY_BYTES", "5242880"), 5<<20, 1024, 100<<20)
+
+	githubAppPrivateKey := loadGitHubAppPrivateKey()
 
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
@@ -40,17 +89,67 @@ func Load() Config {
 	}
 
 	return Config{
-		Port:                     port,
-		GitHubWebhookSecret:      githubWebhookSecret,
-		GitHubToken:              os.Getenv("GITHUB_TOKEN"),
-		AdminUsername:            adminUsername,
-		AdminPassword:            adminPassword,
-		JWTSecret:                jwtSecret,
-		DatabaseURL:              os.Getenv("DATABASE_URL"),
-		AuthEnvFallback:          authEnvFallback,
-		BootstrapAdmin:           bootstrapAdmin,
-		GitHubSyncIntervalMinute: githubSyncIntervalMinute,
+		Port:                        port,
+		GitHubWebhookSecret:         githubWebhookSecret,
+		GitHubWebhookSecretPrevious: strings.TrimSpace(os.Getenv("GITHUB_WEBHOOK_SECRET_PREVIOUS")),
+		WebhookSecretPrevExpiresAt:  parseOptionalRFC3339(os.Getenv("GITHUB_WEBHOOK_SECRET_PREVIOUS_EXPIRES_AT")),
+		WebhookSignatureSchemes:     getenvOrDefault("WEBHOOK_SIGNATURE_SCHEMES", "sha256"),
+		WebhookMaxBodyBytes:         webhookMaxBodyBytes,
+		GitHubToken:                 os.Getenv("GITHUB_TOKEN"),
+		GitLabWebhookToken:          os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+		GitLabToken:                 os.Getenv("GITLAB_TOKEN"),
+		GitLabBaseURL:               getenvOrDefault("GITLAB_BASE_URL", "https://gitlab.com"),
+		GiteaWebhookSecret:          os.Getenv("GITEA_WEBHOOK_SECRET"),
+		GiteaToken:                  os.Getenv("GITEA_TOKEN"),
+		GiteaBaseURL:                strings.TrimSpace(os.Getenv("GITEA_BASE_URL")),
+		GitHubAppID:                 strings.TrimSpace(os.Getenv("GITHUB_APP_ID")),
+		GitHubAppPrivateKey:         githubAppPrivateKey,
+		SecretsEncryptionKey:        os.Getenv("SECRETS_ENCRYPTION_KEY"),
+		AdminUsername:               adminUsername,
+		AdminPassword:               adminPassword,
+		JWTSecret:                   jwtSecret,
+		DatabaseURL:                 os.Getenv("DATABASE_URL"),
+		AuthEnvFallback:             authEnvFallback,
+		BootstrapAdmin:              bootstrapAdmin,
+		GitHubSyncIntervalMinute:    githubSyncIntervalMinute,
+		GitHubSyncSchedulerSeconds:  githubSyncSchedulerSeconds,
+		GitHubSyncTimeoutSeconds:    githubSyncTimeoutSeconds,
+		GitHubSyncEvaluateRules:     githubSyncEvaluateRules,
+		GitHubSyncExecuteActions:    githubSyncExecuteActions,
+		ScheduledJobsIntervalMinute: scheduledJobsIntervalMinute,
+		ActionRetryIntervalMinute:   actionRetryIntervalMinute,
+		ActionRetryMaxAttempts:      actionRetryMaxAttempts,
+		ActionRetryBaseBackoffSec:   actionRetryBaseBackoffSec,
+		HookRecoveryIntervalMinute:  hookRecoveryIntervalMinute,
+		SLOEvaluationIntervalMinute: sloEvaluationIntervalMinute,
+		MetricsRollupIntervalSec:    metricsRollupIntervalSec,
+		ShutdownTimeoutSeconds:      shutdownTimeoutSeconds,
+		ActionBacklogWarnThreshold:  actionBacklogWarnThreshold,
+		MetricsToken:                strings.TrimSpace(os.Getenv("METRICS_TOKEN")),
+		TracingExporter:             strings.ToLower(strings.TrimSpace(getenvOrDefault("TRACING_EXPORTER", "none"))),
+		OTLPEndpoint:                getenvOrDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		TracingServiceName:          getenvOrDefault("OTEL_SERVICE_NAME", "maintainer-firewall-api"),
+		LogLevel:                    getenvOrDefault("LOG_LEVEL", "info"),
+	}
+}
+
+// loadGitHubAppPrivateKey prefers GITHUB_APP_PRIVATE_KEY_PATH; the inline variant
+// may carry the PEM with literal "\n" escapes since .env values are single-line.
+func loadGitHubAppPrivateKey() string {
+	if path := strings.TrimSpace(os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH")); path != "" {
+		if content, err := os.ReadFile(path); err == nil {
+			return string(content)
+		}
+	}
+	return strings.ReplaceAll(os.Getenv("GITHUB_APP_PRIVATE_KEY"), `\n`, "\n")
+}
+
+func parseOptionalRFC3339(raw string) time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}
 	}
+	return t.UTC()
 }
 
 func parseSyncIntervalMinutes(raw string) int {
@@ -74,6 +173,20 @@ func parseSyncIntervalMinutes(raw string) int {
 	return v
 }
 
+func parseBoundedInt(raw string, fallback int, min int, max int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return fallback
+	}
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
 func getenvOrDefault(key, fallback string) string {
 	v := os.Getenv(key)
 	if v == "" {
@@ -19,6 +19,10 @@ func TestLoad_UsesDevDefaultsWhenEnvMissing(t *testing.T) {
 	t.Setenv("AUTH_ENV_FALLBACK", "")
 	t.Setenv("BOOTSTRAP_ADMIN_ON_START", "")
 	t.Setenv("GITHUB_EVENTS_SYNC_INTERVAL_MINUTES", "")
+	t.Setenv("SCHEDULED_JOBS_INTERVAL_MINUTES", "")
+	t.Setenv("ACTION_RETRY_INTERVAL_MINUTES", "")
+	t.Setenv("ACTION_RETRY_MAX_ATTEMPTS", "")
+	t.Setenv("ACTION_RETRY_BASE_BACKOFF_SECONDS", "")
 	t.Setenv("BREEZELL_TEST_DOTENV_CONTENT", "")
 	t.Setenv("BREEZELL_TEST_DOTENV_PATH", filepath.Join(t.TempDir(), "not-found.env"))
 
@@ -51,6 +55,12 @@ func TestLoad_UsesDevDefaultsWhenEnvMissing(t *testing.T) {
 	if cfg.GitHubSyncIntervalMinute != 0 {
 		t.Fatalf("expected default GITHUB_EVENTS_SYNC_INTERVAL_MINUTES=0, got %d", cfg.GitHubSyncIntervalMinute)
 	}
+	if cfg.ScheduledJobsIntervalMinute != 1 {
+		t.Fatalf("expected default SCHEDULED_JOBS_INTERVAL_MINUTES=1, got %d", cfg.ScheduledJobsIntervalMinute)
+	}
+	if cfg.ActionRetryIntervalMinute != 5 || cfg.ActionRetryMaxAttempts != 5 || cfg.ActionRetryBaseBackoffSec != 60 {
+		t.Fatalf("unexpected action retry defaults: interval=%d attempts=%d backoff=%d", cfg.ActionRetryIntervalMinute, cfg.ActionRetryMaxAttempts, cfg.ActionRetryBaseBackoffSec)
+	}
 }
 
 func TestLoad_BootstrapAdminFalse(t *testing.T) {
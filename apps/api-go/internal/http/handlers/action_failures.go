@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"maintainer-firewall/api-go/internal/service"
 	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,16 +21,59 @@ type ActionFailureRetryStore interface {
 	GetActionExecutionFailureByID(ctx context.Context, id int64) (store.ActionExecutionFailureRecord, error)
 	UpdateActionFailureRetryResult(ctx context.Context, id int64, success bool, message string) error
 	GetWebhookEventPayloadByDeliveryID(ctx context.Context, deliveryID string) (json.RawMessage, error)
+	ListRetryableActionFailures(ctx context.Context, schedule store.ActionRetrySchedule, limit int) ([]store.ActionExecutionFailureRecord, error)
+	ClaimActionFailureRetry(ctx context.Context, id int64, lastRetryAt time.Time) (bool, error)
+	ListActionExecutionFailuresByFilter(ctx context.Context, filter store.ActionFailureFilter, limit int) ([]store.ActionExecutionFailureRecord, error)
+	MarkActionFailureDead(ctx context.Context, id int64, message string) error
+	GetTenantAutoRetryEnabled(ctx context.Context) (bool, error)
+	UpdateTenantAutoRetryEnabled(ctx context.Context, enabled bool) error
 	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
 }
 
+type ActionFailureExecutor = service.ActionExecutor
+
 type ActionFailureRetryHandler struct {
-	Store    ActionFailureRetryStore
-	Executor *service.GitHubActionExecutor
+	Store       ActionFailureRetryStore
+	Executor    ActionFailureExecutor
+	MaxAttempts int
+	BaseBackoff time.Duration
+	Now         func() time.Time
+
+	// Providers supplies the executor for each failure's source. Executor is used for
+	// GitHub when the registry has no GitHub executor.
+	Providers *service.ProviderRegistry
+}
+
+type bulkRetryActionFailuresRequest struct {
+	store.ActionFailureFilter
+	Limit int `json:"limit"`
+}
+
+type bulkRetryResult struct {
+	ID      int64  `json:"id"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
 }
 
-func NewActionFailureRetryHandler(s ActionFailureRetryStore, exec *service.GitHubActionExecutor) *ActionFailureRetryHandler {
-	return &ActionFailureRetryHandler{Store: s, Executor: exec}
+type updateRetryPolicyRequest struct {
+	AutoRetryEnabled *bool `json:"auto_retry_enabled"`
+}
+
+const (
+	defaultActionRetryMaxAttempts = 5
+	defaultActionRetryBaseBackoff = time.Minute
+	actionRetryBatch              = 200
+	autoRetryActor                = "auto-retry"
+)
+
+func NewActionFailureRetryHandler(s ActionFailureRetryStore, exec ActionFailureExecutor) *ActionFailureRetryHandler {
+	return &ActionFailureRetryHandler{
+		Store:       s,
+		Executor:    exec,
+		MaxAttempts: defaultActionRetryMaxAttempts,
+		BaseBackoff: defaultActionRetryBaseBackoff,
+		Now:         time.Now,
+	}
 }
 
 func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
@@ -36,7 +81,7 @@ func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "store is not configured"})
 		return
 	}
-	if h.Executor == nil {
+	if !h.hasExecutor() {
 		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "executor is not configured"})
 		return
 	}
@@ -60,31 +105,157 @@ func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
 		return
 	}
 
-	payloadBytes, err := h.Store.GetWebhookEventPayloadByDeliveryID(ctx, failure.DeliveryID)
+	target, err := h.resolveRetryTarget(ctx, failure)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("load related event failed: %v", err)})
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid ") || strings.Contains(err.Error(), "unsupported") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"ok": false, "message": err.Error()})
 		return
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("parse related event failed: %v", err)})
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	claimed, err := h.Store.ClaimActionFailureRetry(ctx, failure.ID, failure.LastRetryAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("claim failure failed: %v", err)})
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "failure is already resolved or being retried"})
 		return
 	}
 
-	number := extractTargetNumber(failure.EventType, payload)
-	if number <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid issue/pr number for retry"})
+	if err := h.executeRetry(ctx, failure, target, actor); err != nil {
+		status := http.StatusBadGateway
+		message := fmt.Sprintf("retry failed: %v", err)
+		if service.IsPermanentActionError(err) {
+			status = http.StatusBadRequest
+			message = fmt.Sprintf("retry failed and cannot succeed: %v", err)
+		} else if strings.Contains(strings.ToLower(err.Error()), "not configured") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"ok": false, "message": message})
 		return
 	}
 
-	switch failure.SuggestionType {
-	case "label":
-		err = h.Executor.AddLabel(ctx, failure.RepositoryFullName, number, failure.SuggestionValue)
-	case "comment":
-		err = h.Executor.AddComment(ctx, failure.RepositoryFullName, number, failure.SuggestionValue)
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "unsupported suggestion type"})
+	c.JSON(http.StatusOK, gin.H{"ok": true, "message": "retry succeeded"})
+}
+
+// RetryMatching retries every unresolved failure of the current tenant that matches the filter.
+func (h *ActionFailureRetryHandler) RetryMatching(c *gin.Context) {
+	if h.Store == nil || !h.hasExecutor() {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "retry is not configured"})
+		return
+	}
+
+	var req bulkRetryActionFailuresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 50
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListActionExecutionFailuresByFilter(ctx, req.ActionFailureFilter, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list action failures failed: %v", err)})
+		return
+	}
+
+	results := make([]bulkRetryResult, 0, len(items))
+	succeeded, skipped := 0, 0
+	for _, failure := range items {
+		target, err := h.resolveRetryTarget(ctx, failure)
+		if err == nil {
+			var claimed bool
+			claimed, err = h.Store.ClaimActionFailureRetry(ctx, failure.ID, failure.LastRetryAt)
+			if err == nil && !claimed {
+				skipped++
+				results = append(results, bulkRetryResult{ID: failure.ID, OK: false, Message: "skipped: already resolved or being retried"})
+				continue
+			}
+		}
+		if err == nil {
+			err = h.executeRetry(ctx, failure, target, actor)
+		}
+		if err != nil {
+			results = append(results, bulkRetryResult{ID: failure.ID, OK: false, Message: err.Error()})
+			continue
+		}
+		succeeded++
+		results = append(results, bulkRetryResult{ID: failure.ID, OK: true})
+	}
+
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "failure.retry.bulk",
+		Target:   "action_failure",
+		TargetID: "*",
+		Payload:  fmt.Sprintf(`{"event_type":"%s","suggestion_type":"%s","repository_full_name":"%s","matched":%d,"succeeded":%d,"skipped":%d}`, req.EventType, req.SuggestionType, req.RepositoryFullName, len(items), succeeded, skipped),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true, "matched": len(items), "succeeded": succeeded, "skipped": skipped, "failed": len(items) - succeeded - skipped, "results": results})
+}
+
+func (h *ActionFailureRetryHandler) GetRetryPolicy(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	enabled, err := h.Store.GetTenantAutoRetryEnabled(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get retry policy failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ok":                   true,
+		"auto_retry_enabled":   enabled,
+		"max_attempts":         h.MaxAttempts,
+		"base_backoff_seconds": int(h.BaseBackoff.Seconds()),
+	})
+}
+
+func (h *ActionFailureRetryHandler) UpdateRetryPolicy(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "store is not configured"})
+		return
+	}
+
+	var req updateRetryPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.AutoRetryEnabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "auto_retry_enabled is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	if err := h.Store.UpdateTenantAutoRetryEnabled(ctx, *req.AutoRetryEnabled); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "tenant not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update retry policy failed: %v", err)})
 		return
 	}
 
@@ -92,6 +263,165 @@ func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
 	if actor == "" {
 		actor = "unknown"
 	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "failure.retry_policy.update",
+		Target:   "tenant",
+		TargetID: tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID),
+		Payload:  fmt.Sprintf(`{"auto_retry_enabled":%t}`, *req.AutoRetryEnabled),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true, "auto_retry_enabled": *req.AutoRetryEnabled})
+}
+
+// RetryDueFailures is the background retrier. Failures are retried once their
+// exponential backoff has elapsed and marked dead after MaxAttempts retries or
+// on a permanent error. It returns the number of successful and attempted retries.
+func (h *ActionFailureRetryHandler) RetryDueFailures(ctx context.Context) (int, int, error) {
+	if h.Store == nil || !h.hasExecutor() {
+		return 0, 0, fmt.Errorf("retry is not configured")
+	}
+
+	now := time.Now()
+	if h.Now != nil {
+		now = h.Now()
+	}
+	baseBackoff := h.BaseBackoff
+	if baseBackoff <= 0 {
+		baseBackoff = defaultActionRetryBaseBackoff
+	}
+
+	items, err := h.Store.ListRetryableActionFailures(ctx, store.ActionRetrySchedule{Now: now, Base: baseBackoff, Max: service.MaxActionRetryBackoff}, actionRetryBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxAttempts := h.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultActionRetryMaxAttempts
+	}
+
+	succeeded, attempted := 0, 0
+	for _, failure := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		tenantCtx := tenantctx.WithTenantID(ctx, failure.TenantID)
+
+		lastMessage := failure.ErrorMessage
+		if failure.RetryCount > 0 {
+			lastMessage = failure.LastRetryMessage
+		}
+		if service.IsPermanentActionErrorMessage(lastMessage) {
+			h.markDead(tenantCtx, failure, "permanent error: "+lastMessage)
+			continue
+		}
+		if failure.RetryCount >= maxAttempts {
+			h.markDead(tenantCtx, failure, fmt.Sprintf("gave up after %d retries: %s", failure.RetryCount, lastMessage))
+			continue
+		}
+
+		lastAttemptAt := failure.LastRetryAt
+		if lastAttemptAt.IsZero() {
+			lastAttemptAt = failure.OccurredAt
+		}
+		if now.Before(lastAttemptAt.Add(service.ActionRetryBackoff(failure.RetryCount, baseBackoff))) {
+			continue
+		}
+		claimed, err := h.Store.ClaimActionFailureRetry(tenantCtx, failure.ID, failure.LastRetryAt)
+		if err != nil {
+			return succeeded, attempted, err
+		}
+		if !claimed {
+			continue
+		}
+
+		target, err := h.resolveRetryTarget(tenantCtx, failure)
+		if err != nil {
+			if service.IsPermanentActionError(err) {
+				h.markDead(tenantCtx, failure, err.Error())
+			} else {
+				slog.WarnContext(tenantCtx, "auto retry of action failure skipped", "failure_id", failure.ID, "delivery_id", failure.DeliveryID, "error", err)
+			}
+			continue
+		}
+
+		attempted++
+		if err := h.executeRetry(tenantCtx, failure, target, autoRetryActor); err != nil {
+			if service.IsPermanentActionError(err) {
+				h.markDead(tenantCtx, failure, "permanent error: "+err.Error())
+			} else if failure.RetryCount+1 >= maxAttempts {
+				h.markDead(tenantCtx, failure, fmt.Sprintf("gave up after %d retries: %v", failure.RetryCount+1, err))
+			}
+			continue
+		}
+		succeeded++
+	}
+	return succeeded, attempted, nil
+}
+
+func (h *ActionFailureRetryHandler) resolveRetryTarget(ctx context.Context, failure store.ActionExecutionFailureRecord) (service.NormalizedEvent, error) {
+	if failure.SuggestionType != "label" && failure.SuggestionType != "comment" {
+		return service.NormalizedEvent{}, fmt.Errorf("unsupported suggestion type")
+	}
+
+	payloadBytes, err := h.Store.GetWebhookEventPayloadByDeliveryID(ctx, failure.DeliveryID)
+	if err != nil {
+		return service.NormalizedEvent{}, fmt.Errorf("load related event failed: %w", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return service.NormalizedEvent{}, fmt.Errorf("parse related event failed: %w", err)
+	}
+
+	target := service.NormalizePayload(failure.Source, failure.EventType, payload)
+	if target.Number <= 0 || (failure.EventType != "issues" && failure.EventType != "pull_request") {
+		return service.NormalizedEvent{}, fmt.Errorf("invalid issue/pr number for retry")
+	}
+	return target, nil
+}
+
+func (h *ActionFailureRetryHandler) executorFor(source string) ActionFailureExecutor {
+	if source == "" {
+		source = store.EventSourceGitHub
+	}
+	if executor := h.Providers.Executor(source); executor != nil {
+		return executor
+	}
+	if source == store.EventSourceGitHub && h.Executor != nil {
+		return h.Executor
+	}
+	return nil
+}
+
+func (h *ActionFailureRetryHandler) hasExecutor() bool {
+	if h.Executor != nil {
+		return true
+	}
+	for _, name := range h.Providers.Names() {
+		if h.Providers.Executor(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *ActionFailureRetryHandler) executeRetry(ctx context.Context, failure store.ActionExecutionFailureRecord, target service.NormalizedEvent, actor string) error {
+	executor := h.executorFor(failure.Source)
+	ctx = service.WithTargetKind(ctx, target.TargetKind)
+	number := target.Number
+
+	var err error
+	switch {
+	case executor == nil:
+		err = fmt.Errorf("unsupported source %q", failure.Source)
+	case failure.SuggestionType == "label":
+		err = executor.AddLabel(ctx, failure.RepositoryFullName, number, failure.SuggestionValue)
+	case failure.SuggestionType == "comment":
+		err = executor.AddComment(ctx, failure.RepositoryFullName, number, failure.SuggestionValue)
+	default:
+		return fmt.Errorf("unsupported suggestion type")
+	}
 
 	if err != nil {
 		_ = h.Store.UpdateActionFailureRetryResult(ctx, failure.ID, false, err.Error())
@@ -102,18 +432,7 @@ func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
 			TargetID: fmt.Sprintf("%d", failure.ID),
 			Payload:  fmt.Sprintf(`{"delivery_id":"%s","error":"%s"}`, failure.DeliveryID, strings.ReplaceAll(err.Error(), `"`, `'`)),
 		})
-
-		status := http.StatusBadGateway
-		message := fmt.Sprintf("retry failed: %v", err)
-		errMsg := strings.ToLower(err.Error())
-		if strings.Contains(errMsg, "github api status: 404") {
-			status = http.StatusBadRequest
-			message = "retry failed: target issue/pr not found or inaccessible on GitHub"
-		} else if strings.Contains(errMsg, "not configured") || strings.Contains(errMsg, "invalid ") || strings.Contains(errMsg, "empty ") || strings.Contains(errMsg, "unsupported") {
-			status = http.StatusBadRequest
-		}
-		c.JSON(status, gin.H{"ok": false, "message": message})
-		return
+		return err
 	}
 
 	_ = h.Store.UpdateActionFailureRetryResult(ctx, failure.ID, true, "retry succeeded")
@@ -124,6 +443,19 @@ func (h *ActionFailureRetryHandler) Retry(c *gin.Context) {
 		TargetID: fmt.Sprintf("%d", failure.ID),
 		Payload:  fmt.Sprintf(`{"delivery_id":"%s"}`, failure.DeliveryID),
 	})
+	return nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true, "message": "retry succeeded"})
+func (h *ActionFailureRetryHandler) markDead(ctx context.Context, failure store.ActionExecutionFailureRecord, reason string) {
+	if err := h.Store.MarkActionFailureDead(ctx, failure.ID, reason); err != nil {
+		slog.ErrorContext(ctx, "mark action failure dead failed", "failure_id", failure.ID, "delivery_id", failure.DeliveryID, "error", err)
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    autoRetryActor,
+		Action:   "failure.retry.dead",
+		Target:   "action_failure",
+		TargetID: fmt.Sprintf("%d", failure.ID),
+		Payload:  fmt.Sprintf(`{"delivery_id":"%s","reason":"%s"}`, failure.DeliveryID, strings.ReplaceAll(reason, `"`, `'`)),
+	})
 }
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockActionFailureRetryStore struct {
+	failures     map[int64]store.ActionExecutionFailureRecord
+	retryable    []store.ActionExecutionFailureRecord
+	filtered     []store.ActionExecutionFailureRecord
+	lastFilter   store.ActionFailureFilter
+	payloads     map[string]json.RawMessage
+	results      map[int64]bool
+	dead         map[int64]string
+	autoRetry    bool
+	audits       []store.AuditLogRecord
+	updatePolicy *bool
+	schedule     store.ActionRetrySchedule
+	claimedBy    map[int64]bool
+}
+
+func newMockActionFailureRetryStore() *mockActionFailureRetryStore {
+	return &mockActionFailureRetryStore{
+		failures: map[int64]store.ActionExecutionFailureRecord{},
+		payloads: map[string]json.RawMessage{},
+		results:  map[int64]bool{},
+		dead:     map[int64]string{},
+	}
+}
+
+func (m *mockActionFailureRetryStore) GetActionExecutionFailureByID(_ context.Context, id int64) (store.ActionExecutionFailureRecord, error) {
+	rec, ok := m.failures[id]
+	if !ok {
+		return rec, fmt.Errorf("action failure not found")
+	}
+	return rec, nil
+}
+
+func (m *mockActionFailureRetryStore) UpdateActionFailureRetryResult(_ context.Context, id int64, success bool, _ string) error {
+	m.results[id] = success
+	return nil
+}
+
+func (m *mockActionFailureRetryStore) GetWebhookEventPayloadByDeliveryID(_ context.Context, deliveryID string) (json.RawMessage, error) {
+	payload, ok := m.payloads[deliveryID]
+	if !ok {
+		return nil, fmt.Errorf("webhook event not found")
+	}
+	return payload, nil
+}
+
+func (m *mockActionFailureRetryStore) ListRetryableActionFailures(_ context.Context, schedule store.ActionRetrySchedule, _ int) ([]store.ActionExecutionFailureRecord, error) {
+	m.schedule = schedule
+	return m.retryable, nil
+}
+
+// ClaimActionFailureRetry lets each failure be claimed once, standing in for
+// the compare-and-set on last_retry_at.
+func (m *mockActionFailureRetryStore) ClaimActionFailureRetry(_ context.Context, id int64, _ time.Time) (bool, error) {
+	if m.claimedBy == nil {
+		m.claimedBy = map[int64]bool{}
+	}
+	if m.claimedBy[id] {
+		return false, nil
+	}
+	m.claimedBy[id] = true
+	return true, nil
+}
+
+func (m *mockActionFailureRetryStore) ListActionExecutionFailuresByFilter(_ context.Context, filter store.ActionFailureFilter, _ int) ([]store.ActionExecutionFailureRecord, error) {
+	m.lastFilter = filter
+	return m.filtered, nil
+}
+
+func (m *mockActionFailureRetryStore) MarkActionFailureDead(_ context.Context, id int64, message string) error {
+	m.dead[id] = message
+	return nil
+}
+
+func (m *mockActionFailureRetryStore) GetTenantAutoRetryEnabled(_ context.Context) (bool, error) {
+	return m.autoRetry, nil
+}
+
+func (m *mockActionFailureRetryStore) UpdateTenantAutoRetryEnabled(_ context.Context, enabled bool) error {
+	m.updatePolicy = &enabled
+	return nil
+}
+
+func (m *mockActionFailureRetryStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+type mockFailureExecutor struct {
+	errByNumber map[int]error
+	labeled     []int
+}
+
+func (m *mockFailureExecutor) AddLabel(_ context.Context, _ string, number int, _ string) error {
+	if err := m.errByNumber[number]; err != nil {
+		return err
+	}
+	m.labeled = append(m.labeled, number)
+	return nil
+}
+
+func (m *mockFailureExecutor) AddComment(_ context.Context, _ string, number int, _ string) error {
+	return m.errByNumber[number]
+}
+
+func issuePayload(number int) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"issue":{"number":%d}}`, number))
+}
+
+func TestActionFailureRetryDueFailures_BackoffAndDeadLetter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	mockStore := newMockActionFailureRetryStore()
+	base := store.ActionExecutionFailure{EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "label", SuggestionValue: "triage"}
+
+	due := base
+	due.DeliveryID = "d-1"
+	due.OccurredAt = now.Add(-2 * time.Minute)
+
+	notYet := base
+	notYet.DeliveryID = "d-2"
+	notYet.RetryCount = 3
+	notYet.LastRetryMessage = "github api status: 502"
+	notYet.LastRetryAt = now.Add(-5 * time.Minute)
+
+	permanent := base
+	permanent.DeliveryID = "d-3"
+	permanent.ErrorMessage = "github api status: 404"
+	permanent.OccurredAt = now.Add(-time.Hour)
+
+	lastChance := base
+	lastChance.DeliveryID = "d-4"
+	lastChance.RetryCount = 4
+	lastChance.LastRetryMessage = "github api status: 502"
+	lastChance.LastRetryAt = now.Add(-time.Hour)
+
+	mockStore.retryable = []store.ActionExecutionFailureRecord{
+		{ID: 1, TenantID: "team-a", ActionExecutionFailure: due},
+		{ID: 2, TenantID: "team-a", ActionExecutionFailure: notYet},
+		{ID: 3, TenantID: "team-a", ActionExecutionFailure: permanent},
+		{ID: 4, TenantID: "team-b", ActionExecutionFailure: lastChance},
+	}
+	mockStore.payloads["d-1"] = issuePayload(11)
+	mockStore.payloads["d-2"] = issuePayload(12)
+	mockStore.payloads["d-3"] = issuePayload(13)
+	mockStore.payloads["d-4"] = issuePayload(14)
+
+	executor := &mockFailureExecutor{errByNumber: map[int]error{14: fmt.Errorf("github api status: 502")}}
+	h := NewActionFailureRetryHandler(mockStore, executor)
+	h.Now = func() time.Time { return now }
+
+	succeeded, attempted, err := h.RetryDueFailures(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if succeeded != 1 || attempted != 2 {
+		t.Fatalf("expected succeeded=1 attempted=2, got %d/%d", succeeded, attempted)
+	}
+	if !mockStore.results[1] {
+		t.Fatalf("expected failure 1 to be retried successfully")
+	}
+	if _, ok := mockStore.results[2]; ok {
+		t.Fatalf("expected failure 2 to wait for backoff")
+	}
+	if _, ok := mockStore.results[3]; ok {
+		t.Fatalf("expected permanent failure 3 not to be retried")
+	}
+	if !strings.Contains(mockStore.dead[3], "404") {
+		t.Fatalf("expected failure 3 dead-lettered, got %q", mockStore.dead[3])
+	}
+	if !strings.Contains(mockStore.dead[4], "gave up after 5 retries") {
+		t.Fatalf("expected failure 4 dead-lettered after max attempts, got %q", mockStore.dead[4])
+	}
+}
+
+func TestActionFailureRetryDueFailures_SkipsFailuresClaimedElsewhere(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	mockStore := newMockActionFailureRetryStore()
+	failure := store.ActionExecutionFailure{DeliveryID: "d-1", EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "label", SuggestionValue: "triage", OccurredAt: now.Add(-time.Hour)}
+	mockStore.retryable = []store.ActionExecutionFailureRecord{
+		{ID: 1, TenantID: "team-a", ActionExecutionFailure: failure},
+	}
+	mockStore.payloads["d-1"] = issuePayload(11)
+	executor := &mockFailureExecutor{}
+
+	// Two replicas list the same due failure; only the first claim wins.
+	first := NewActionFailureRetryHandler(mockStore, executor)
+	first.Now = func() time.Time { return now }
+	second := NewActionFailureRetryHandler(mockStore, executor)
+	second.Now = func() time.Time { return now }
+
+	if _, attempted, err := first.RetryDueFailures(context.Background()); err != nil || attempted != 1 {
+		t.Fatalf("expected first replica to retry, attempted=%d err=%v", attempted, err)
+	}
+	if _, attempted, err := second.RetryDueFailures(context.Background()); err != nil || attempted != 0 {
+		t.Fatalf("expected second replica to skip the claimed failure, attempted=%d err=%v", attempted, err)
+	}
+	if len(executor.labeled) != 1 {
+		t.Fatalf("expected one label call, got %v", executor.labeled)
+	}
+	if !mockStore.schedule.Now.Equal(now) || mockStore.schedule.Base != time.Minute || mockStore.schedule.Max != service.MaxActionRetryBackoff {
+		t.Fatalf("expected the backoff to be filtered in the store, got %+v", mockStore.schedule)
+	}
+}
+
+func TestActionFailureRetryMatching_UsesFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := newMockActionFailureRetryStore()
+	mockStore.filtered = []store.ActionExecutionFailureRecord{
+		{ID: 5, ActionExecutionFailure: store.ActionExecutionFailure{DeliveryID: "d-5", EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "label", SuggestionValue: "x"}},
+		{ID: 6, ActionExecutionFailure: store.ActionExecutionFailure{DeliveryID: "d-missing", EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "label", SuggestionValue: "x"}},
+		{ID: 8, ActionExecutionFailure: store.ActionExecutionFailure{DeliveryID: "d-8", EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "label", SuggestionValue: "x"}},
+	}
+	mockStore.payloads["d-5"] = issuePayload(21)
+	mockStore.payloads["d-8"] = issuePayload(22)
+	// The background retrier already claimed failure 8.
+	mockStore.claimedBy = map[int64]bool{8: true}
+	executor := &mockFailureExecutor{}
+	h := NewActionFailureRetryHandler(mockStore, executor)
+	r := gin.New()
+	r.POST("/action-failures/:id/retry", h.Retry)
+	r.POST("/action-failures/retry", h.RetryMatching)
+
+	req := httptest.NewRequest(http.MethodPost, "/action-failures/retry", strings.NewReader(`{"repository_full_name":"owner/repo","suggestion_type":"label"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if mockStore.lastFilter.RepositoryFullName != "owner/repo" || mockStore.lastFilter.SuggestionType != "label" {
+		t.Fatalf("unexpected filter: %+v", mockStore.lastFilter)
+	}
+	var resp struct {
+		Matched   int `json:"matched"`
+		Succeeded int `json:"succeeded"`
+		Skipped   int `json:"skipped"`
+		Failed    int `json:"failed"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Matched != 3 || resp.Succeeded != 1 || resp.Skipped != 1 || resp.Failed != 1 {
+		t.Fatalf("unexpected response: %s", w.Body.String())
+	}
+	if len(executor.labeled) != 1 || executor.labeled[0] != 21 {
+		t.Fatalf("expected only #21 labeled, got %v", executor.labeled)
+	}
+}
+
+func TestActionFailureRetry_ReportsPermanentForgeErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := newMockActionFailureRetryStore()
+	mockStore.failures[7] = store.ActionExecutionFailureRecord{ID: 7, ActionExecutionFailure: store.ActionExecutionFailure{DeliveryID: "d-7", EventType: "issues", RepositoryFullName: "group/project", SuggestionType: "label", SuggestionValue: "x"}}
+	mockStore.payloads["d-7"] = issuePayload(31)
+	h := NewActionFailureRetryHandler(mockStore, &mockFailureExecutor{errByNumber: map[int]error{31: fmt.Errorf("gitlab api status: 403: forbidden")}})
+	r := gin.New()
+	r.POST("/action-failures/:id/retry", h.Retry)
+
+	req := httptest.NewRequest(http.MethodPost, "/action-failures/7/retry", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "cannot succeed") {
+		t.Fatalf("expected 400 for a permanent error, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestActionFailureRetry_ConflictsWithAClaimedFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := newMockActionFailureRetryStore()
+	mockStore.failures[9] = store.ActionExecutionFailureRecord{ID: 9, ActionExecutionFailure: store.ActionExecutionFailure{DeliveryID: "d-9", EventType: "issues", RepositoryFullName: "owner/repo", SuggestionType: "comment", SuggestionValue: "hi"}}
+	mockStore.payloads["d-9"] = issuePayload(41)
+	mockStore.claimedBy = map[int64]bool{9: true}
+	h := NewActionFailureRetryHandler(mockStore, &mockFailureExecutor{})
+	r := gin.New()
+	r.POST("/action-failures/:id/retry", h.Retry)
+
+	req := httptest.NewRequest(http.MethodPost, "/action-failures/9/retry", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if _, ok := mockStore.results[9]; ok {
+		t.Fatalf("expected the claimed failure not to be retried")
+	}
+}
+
+func TestActionFailureRetryPolicy_Update(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := newMockActionFailureRetryStore()
+	h := NewActionFailureRetryHandler(mockStore, &mockFailureExecutor{})
+	r := gin.New()
+	r.PUT("/action-failures/retry-policy", h.UpdateRetryPolicy)
+
+	req := httptest.NewRequest(http.MethodPut, "/action-failures/retry-policy", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without auto_retry_enabled, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/action-failures/retry-policy", strings.NewReader(`{"auto_retry_enabled":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if mockStore.updatePolicy == nil || *mockStore.updatePolicy {
+		t.Fatalf("expected auto retry disabled, got %v", mockStore.updatePolicy)
+	}
+	if len(mockStore.audits) != 1 || mockStore.audits[0].Action != "failure.retry_policy.update" {
+		t.Fatalf("expected audit log, got %+v", mockStore.audits)
+	}
+}
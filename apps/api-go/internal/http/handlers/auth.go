@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"maintainer-firewall/api-go/internal/logging"
 	"maintainer-firewall/api-go/internal/store"
 	"maintainer-firewall/api-go/internal/tenantctx"
 
@@ -189,6 +190,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		if claims, ok := parsed.Claims.(jwt.MapClaims); ok {
 			if sub, ok := claims["sub"].(string); ok {
 				c.Set("actor", strings.TrimSpace(sub))
+				logging.SetActor(c.Request.Context(), strings.TrimSpace(sub))
 			}
 			tenantID := tenantctx.DefaultTenantID
 			if claimTenantID, ok := claims["tenant_id"].(string); ok {
@@ -198,6 +200,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			c.Set("role", role)
 			c.Set("permissions", permissions)
 			c.Set("tenant_id", tenantID)
+			logging.SetTenantID(c.Request.Context(), tenantID)
 			c.Request = c.Request.WithContext(tenantctx.WithTenantID(c.Request.Context(), tenantID))
 		} else {
 			c.Set("role", "admin")
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventReprocessStore interface {
+	GetWebhookEventByDeliveryID(ctx context.Context, deliveryID string) (store.WebhookEventRecord, error)
+	ListEventsForReprocess(ctx context.Context, filter store.EventReprocessFilter, limit int) ([]store.WebhookEventRecord, error)
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type EventReprocessor interface {
+	Reprocess(ctx context.Context, tenantID string, record store.WebhookEventRecord, executeActions bool) ([]service.SuggestedAction, []int64, error)
+}
+
+// Reprocess runs a stored event through the pipeline again with the current rules
+// and returns the ids of the alerts it newly raised. The event row itself is kept,
+// alerts already raised for it are not duplicated, and repository lifecycle
+// changes are not applied again. With executeActions, only new alerts are acted on.
+func (h *WebhookHandler) Reprocess(ctx context.Context, tenantID string, record store.WebhookEventRecord, executeActions bool) ([]service.SuggestedAction, []int64, error) {
+	if h.Store == nil {
+		return nil, nil, fmt.Errorf("event store is not configured")
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(record.PayloadJSON, &payload); err != nil {
+		return nil, nil, fmt.Errorf("stored payload is not valid JSON: %v", err)
+	}
+	normalized := service.NormalizePayload(record.Source, record.EventType, payload)
+	normalized.Raw = record.PayloadJSON
+	result, err := h.processDelivery(ctx, tenantID, record.DeliveryID, normalized, deliveryOptions{executeActions: executeActions, replay: true})
+	return result.suggestions, result.alertIDs, err
+}
+
+// ProcessSyncedEvent runs an event polled from the GitHub Events API through the
+// webhook pipeline. Such events have no installation or delivery signature, and
+// actions only run when the caller asks for them.
+func (h *WebhookHandler) ProcessSyncedEvent(ctx context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent, executeActions bool) ([]service.SuggestedAction, error) {
+	if h.Store == nil {
+		return nil, fmt.Errorf("event store is not configured")
+	}
+	result, err := h.processDelivery(ctx, tenantID, deliveryID, normalized, deliveryOptions{executeActions: executeActions})
+	return result.suggestions, err
+}
+
+// EventReprocessHandler replays stored webhook events, e.g. after a rule fix or an
+// outage. Every replayed event is audited under a shared request id so the alerts
+// it raised can be traced back to the request.
+type EventReprocessHandler struct {
+	Store     EventReprocessStore
+	Processor EventReprocessor
+}
+
+// reprocessEventRequest leaves actions off unless ExecuteActions is set, since a
+// replay usually follows a rule fix and should not repeat side effects.
+type reprocessEventRequest struct {
+	ExecuteActions bool `json:"execute_actions"`
+}
+
+type bulkReprocessEventsRequest struct {
+	store.EventReprocessFilter
+	Limit          int  `json:"limit"`
+	ExecuteActions bool `json:"execute_actions"`
+}
+
+type reprocessResult struct {
+	DeliveryID       string                    `json:"delivery_id"`
+	OK               bool                      `json:"ok"`
+	Message          string                    `json:"message,omitempty"`
+	SuggestedActions []service.SuggestedAction `json:"suggested_actions,omitempty"`
+	AlertIDs         []int64                   `json:"alert_ids,omitempty"`
+}
+
+func NewEventReprocessHandler(s EventReprocessStore, processor EventReprocessor) *EventReprocessHandler {
+	return &EventReprocessHandler{Store: s, Processor: processor}
+}
+
+func (h *EventReprocessHandler) Reprocess(c *gin.Context) {
+	if h.Store == nil || h.Processor == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "reprocessing is not configured"})
+		return
+	}
+	var req reprocessEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	deliveryID := strings.TrimSpace(c.Param("delivery_id"))
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "delivery_id is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	record, err := h.Store.GetWebhookEventByDeliveryID(ctx, deliveryID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"ok": false, "message": err.Error()})
+		return
+	}
+
+	requestID := newReprocessRequestID()
+	result := h.reprocessOne(ctx, c, requestID, record, req.ExecuteActions)
+	if !result.OK {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "request_id": requestID, "message": result.Message})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ok":                true,
+		"request_id":        requestID,
+		"delivery_id":       record.DeliveryID,
+		"execute_actions":   req.ExecuteActions,
+		"suggested_actions": result.SuggestedActions,
+		"alert_ids":         nonNilIDs(result.AlertIDs),
+	})
+}
+
+func (h *EventReprocessHandler) ReprocessMatching(c *gin.Context) {
+	if h.Store == nil || h.Processor == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "reprocessing is not configured"})
+		return
+	}
+	var req bulkReprocessEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 50
+	}
+	if req.Limit > 500 {
+		req.Limit = 500
+	}
+	if !req.Since.IsZero() && !req.Until.IsZero() && !req.Until.After(req.Since) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "until must be after since"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListEventsForReprocess(ctx, req.EventReprocessFilter, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list events failed: %v", err)})
+		return
+	}
+
+	requestID := newReprocessRequestID()
+	results := make([]reprocessResult, 0, len(items))
+	succeeded := 0
+	for _, record := range items {
+		result := h.reprocessOne(ctx, c, requestID, record, req.ExecuteActions)
+		if result.OK {
+			succeeded++
+		}
+		results = append(results, result)
+	}
+
+	summary, _ := json.Marshal(gin.H{
+		"request_id":      requestID,
+		"filter":          req.EventReprocessFilter,
+		"execute_actions": req.ExecuteActions,
+		"matched":         len(items),
+		"succeeded":       succeeded,
+	})
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    requestActor(c),
+		Action:   "event.reprocess.bulk",
+		Target:   "webhook_event",
+		TargetID: "*",
+		Payload:  string(summary),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"ok":         true,
+		"request_id": requestID,
+		"matched":    len(items),
+		"succeeded":  succeeded,
+		"failed":     len(items) - succeeded,
+		"results":    results,
+	})
+}
+
+// reprocessOne replays a single event and audits it with the ids of the alerts
+// it newly raised.
+func (h *EventReprocessHandler) reprocessOne(ctx context.Context, c *gin.Context, requestID string, record store.WebhookEventRecord, executeActions bool) reprocessResult {
+	tenantID := tenantctx.MustFromContext(c.Request.Context(), tenantctx.DefaultTenantID)
+	suggestions, alertIDs, err := h.Processor.Reprocess(ctx, tenantID, record, executeActions)
+	if err != nil {
+		return reprocessResult{DeliveryID: record.DeliveryID, OK: false, Message: err.Error()}
+	}
+
+	payload, _ := json.Marshal(gin.H{
+		"request_id":      requestID,
+		"execute_actions": executeActions,
+		"alert_ids":       nonNilIDs(alertIDs),
+	})
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    requestActor(c),
+		Action:   "event.reprocess",
+		Target:   "webhook_event",
+		TargetID: record.DeliveryID,
+		Payload:  string(payload),
+	})
+	return reprocessResult{DeliveryID: record.DeliveryID, OK: true, SuggestedActions: suggestions, AlertIDs: alertIDs}
+}
+
+// nonNilIDs keeps an empty id list encoded as [] rather than null.
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
+func newReprocessRequestID() string {
+	return fmt.Sprintf("reprocess-%d", time.Now().UTC().UnixNano())
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockEventReprocessStore struct {
+	events     []store.WebhookEventRecord
+	lastFilter store.EventReprocessFilter
+	lastLimit  int
+	audits     []store.AuditLogRecord
+}
+
+func (m *mockEventReprocessStore) GetWebhookEventByDeliveryID(_ context.Context, deliveryID string) (store.WebhookEventRecord, error) {
+	for _, evt := range m.events {
+		if evt.DeliveryID == deliveryID {
+			return evt, nil
+		}
+	}
+	return store.WebhookEventRecord{}, fmt.Errorf("webhook event not found")
+}
+
+func (m *mockEventReprocessStore) ListEventsForReprocess(_ context.Context, filter store.EventReprocessFilter, limit int) ([]store.WebhookEventRecord, error) {
+	m.lastFilter = filter
+	m.lastLimit = limit
+	return m.events, nil
+}
+
+func (m *mockEventReprocessStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+func storedIssueEvent(deliveryID string, title string) store.WebhookEventRecord {
+	payload := fmt.Sprintf(`{"action":"opened","repository":{"full_name":"owner/repo"},"sender":{"login":"alice"},"issue":{"number":7,"title":%q}}`, title)
+	return store.WebhookEventRecord{DeliveryID: deliveryID, EventType: "issues", Action: "opened", Source: store.EventSourceGitHub, PayloadJSON: []byte(payload)}
+}
+
+func TestEventReprocess_SingleEventRunsNoActionsByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	webhookStore := &mockWebhookStore{rules: []store.RuleRecord{
+		{EventType: "issues", Keyword: "crash", SuggestionType: "label", SuggestionValue: "bug", Reason: "fixed rule"},
+	}}
+	processor := NewWebhookHandler("secret", webhookStore)
+	exec := &mockWebhookExecutor{}
+	processor.ActionExecutor = exec
+	reprocessStore := &mockEventReprocessStore{events: []store.WebhookEventRecord{storedIssueEvent("d-1", "app crash on start")}}
+	h := NewEventReprocessHandler(reprocessStore, processor)
+
+	r := gin.New()
+	r.POST("/events/:delivery_id/reprocess", h.Reprocess)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/d-1/reprocess", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(webhookStore.savedAlerts) != 1 || webhookStore.savedAlerts[0].DeliveryID != "d-1" || webhookStore.savedAlerts[0].SuggestionValue != "bug" {
+		t.Fatalf("expected alert from the current rules, got %+v", webhookStore.savedAlerts)
+	}
+	if exec.labelCalls != 0 {
+		t.Fatalf("expected no actions without execute_actions, got %d label calls", exec.labelCalls)
+	}
+
+	var resp map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	requestID, _ := resp["request_id"].(string)
+	if requestID == "" {
+		t.Fatalf("expected request_id in response: %s", w.Body.String())
+	}
+	if len(reprocessStore.audits) != 1 || reprocessStore.audits[0].TargetID != "d-1" ||
+		!strings.Contains(reprocessStore.audits[0].Payload, requestID) || !strings.Contains(reprocessStore.audits[0].Payload, `"alert_ids":[1]`) {
+		t.Fatalf("expected audit linking alert 1 to %s, got %+v", requestID, reprocessStore.audits)
+	}
+
+	// Replaying again with actions on raises no new alert, so nothing runs.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/d-1/reprocess", strings.NewReader(`{"execute_actions":true}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if exec.labelCalls != 0 || len(webhookStore.savedAlerts) != 1 {
+		t.Fatalf("expected existing alert not to be acted on again, got %d label calls and %d alerts", exec.labelCalls, len(webhookStore.savedAlerts))
+	}
+	if !strings.Contains(reprocessStore.audits[1].Payload, `"alert_ids":[]`) {
+		t.Fatalf("expected no new alert ids in the audit, got %s", reprocessStore.audits[1].Payload)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/missing/reprocess", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown delivery, got %d", w.Code)
+	}
+}
+
+func TestEventReprocess_BulkRunsActions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	webhookStore := &mockWebhookStore{rules: []store.RuleRecord{
+		{EventType: "issues", Keyword: "crash", SuggestionType: "label", SuggestionValue: "bug", Reason: "fixed rule"},
+	}}
+	processor := NewWebhookHandler("secret", webhookStore)
+	exec := &mockWebhookExecutor{}
+	processor.ActionExecutor = exec
+	broken := store.WebhookEventRecord{DeliveryID: "d-3", EventType: "issues", PayloadJSON: []byte(`not json`)}
+	reprocessStore := &mockEventReprocessStore{events: []store.WebhookEventRecord{
+		storedIssueEvent("d-1", "crash"), storedIssueEvent("d-2", "question"), broken,
+	}}
+	h := NewEventReprocessHandler(reprocessStore, processor)
+
+	r := gin.New()
+	r.POST("/events/reprocess", h.ReprocessMatching)
+
+	body := `{"event_type":"issues","repository_full_name":"owner/repo","since":"2026-01-01T00:00:00Z","limit":1000,"execute_actions":true}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/reprocess", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if reprocessStore.lastLimit != 500 || reprocessStore.lastFilter.RepositoryFullName != "owner/repo" || reprocessStore.lastFilter.Since.IsZero() {
+		t.Fatalf("unexpected filter passed to store: %+v limit=%d", reprocessStore.lastFilter, reprocessStore.lastLimit)
+	}
+	var resp struct {
+		Matched   int               `json:"matched"`
+		Succeeded int               `json:"succeeded"`
+		Failed    int               `json:"failed"`
+		Results   []reprocessResult `json:"results"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Matched != 3 || resp.Succeeded != 2 || resp.Failed != 1 || resp.Results[2].OK {
+		t.Fatalf("unexpected bulk result: %s", w.Body.String())
+	}
+	if len(exec.labels) != 1 || exec.labels[0] != "bug" {
+		t.Fatalf("expected label action for the matching event, got %+v", exec.labels)
+	}
+	last := reprocessStore.audits[len(reprocessStore.audits)-1]
+	if last.Action != "event.reprocess.bulk" || len(reprocessStore.audits) != 3 {
+		t.Fatalf("expected per-event audits plus a bulk summary, got %+v", reprocessStore.audits)
+	}
+}
+
+func TestEventReprocess_SkipsRepositoryLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	webhookStore := &mockWebhookStore{}
+	registry := &mockRepositoryRegistry{}
+	processor := NewWebhookHandler("secret", webhookStore)
+	processor.Repositories = registry
+	deleted := store.WebhookEventRecord{
+		DeliveryID: "d-9", EventType: "installation", Action: "deleted", Source: store.EventSourceGitHub,
+		PayloadJSON: []byte(`{"action":"deleted","installation":{"id":7}}`),
+	}
+	h := NewEventReprocessHandler(&mockEventReprocessStore{events: []store.WebhookEventRecord{deleted}}, processor)
+
+	r := gin.New()
+	r.POST("/events/:delivery_id/reprocess", h.Reprocess)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/d-9/reprocess", strings.NewReader(`{"execute_actions":true}`)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(registry.deactivated) != 0 || len(registry.upserted) != 0 {
+		t.Fatalf("expected replay not to touch the repository registry, got %+v", registry)
+	}
+}
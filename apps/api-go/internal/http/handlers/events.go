@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"maintainer-firewall/api-go/internal/service"
 	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,6 +28,31 @@ type GitHubEventTypesProvider interface {
 	ListRecentEvents(ctx context.Context) ([]service.GitHubUserEvent, error)
 }
 
+type GitHubEventSourceStore interface {
+	ListGitHubEventSources(ctx context.Context) ([]store.GitHubEventSourceRecord, error)
+	ListActiveGitHubEventSources(ctx context.Context) ([]store.GitHubEventSourceRecord, error)
+	RecordGitHubEventSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) error
+}
+
+type GitHubEventSourcePoller interface {
+	PollSourceEvents(ctx context.Context, src service.GitHubEventSource, etag string, sinceID int64) (service.GitHubEventPoll, error)
+}
+
+// SyncedEventProcessor stores a synced event and evaluates rules and alerts on it
+// the way webhook deliveries are processed.
+type SyncedEventProcessor interface {
+	ProcessSyncedEvent(ctx context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent, executeActions bool) ([]service.SuggestedAction, error)
+}
+
+type KnownDeliveryLister interface {
+	ListKnownDeliveryIDs(ctx context.Context, deliveryIDs []string) (map[string]bool, error)
+}
+
+type GitHubSyncHistoryStore interface {
+	GetGitHubSyncSchedule(ctx context.Context) (store.GitHubSyncScheduleRecord, error)
+	ListGitHubSyncRuns(ctx context.Context, limit int, offset int) ([]store.GitHubSyncRunRecord, int64, error)
+}
+
 type GitHubSyncStatus struct {
 	Running        bool       `json:"running"`
 	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
@@ -41,6 +68,21 @@ type GitHubSyncStatus struct {
 type EventsHandler struct {
 	Store          WebhookEventStore
 	GitHubProvider GitHubEventTypesProvider
+	// Sources and Poller, when set, sync the configured repositories and orgs
+	// instead of the token owner's feed.
+	Sources GitHubEventSourceStore
+	Poller  GitHubEventSourcePoller
+	// History, when set, adds the tenant's persisted schedule and last run to the
+	// sync status; the in-memory status only covers this process.
+	History GitHubSyncHistoryStore
+	// Processor, when set, runs newly synced events of sources with EvaluateRules
+	// through rules and alerts, and those of the token owner's feed with
+	// EvaluateFeed. Suggested actions only run with ExecuteActions. Known lets the
+	// sync skip events it already stored, so they are not evaluated twice.
+	Processor      SyncedEventProcessor
+	EvaluateFeed   bool
+	ExecuteActions bool
+	Known          KnownDeliveryLister
 
 	syncMu     sync.Mutex
 	syncStatus GitHubSyncStatus
@@ -76,7 +118,7 @@ func (h *EventsHandler) List(c *gin.Context) {
 		defer cancel()
 
 		if syncEnabled {
-			saved, total, err := h.SyncGitHubEvents(ctx)
+			saved, total, err := h.syncGitHubEvents(ctx, false)
 			if err != nil {
 				status := http.StatusBadGateway
 				errMsg := strings.ToLower(err.Error())
@@ -206,7 +248,17 @@ func (h *EventsHandler) List(c *gin.Context) {
 	})
 }
 
+// SyncGitHubEvents syncs the sources of every tenant.
 func (h *EventsHandler) SyncGitHubEvents(ctx context.Context) (int, int, error) {
+	return h.syncGitHubEvents(ctx, true)
+}
+
+// SyncTenantGitHubEvents syncs only the tenant in ctx; scheduled runs use it.
+func (h *EventsHandler) SyncTenantGitHubEvents(ctx context.Context) (int, int, error) {
+	return h.syncGitHubEvents(ctx, false)
+}
+
+func (h *EventsHandler) syncGitHubEvents(ctx context.Context, allTenants bool) (int, int, error) {
 	h.syncMu.Lock()
 	if h.syncStatus.Running {
 		h.syncMu.Unlock()
@@ -242,20 +294,32 @@ func (h *EventsHandler) SyncGitHubEvents(ctx context.Context) (int, int, error)
 	if h.Store == nil {
 		return finish(0, 0, fmt.Errorf("event store is not configured"))
 	}
+	if h.Sources != nil && h.Poller != nil {
+		var sources []store.GitHubEventSourceRecord
+		var err error
+		if allTenants {
+			sources, err = h.Sources.ListActiveGitHubEventSources(ctx)
+		} else {
+			sources, err = h.Sources.ListGitHubEventSources(ctx)
+		}
+		if err != nil {
+			return finish(0, 0, fmt.Errorf("list github event sources failed: %w", err))
+		}
+		if len(sources) > 0 {
+			return finish(h.pollSources(ctx, sources))
+		}
+	}
 	events, err := h.GitHubProvider.ListRecentEvents(ctx)
 	if err != nil {
 		return finish(0, 0, fmt.Errorf("sync github events failed: %w", err))
 	}
+	known, err := h.knownSyncedDeliveries(ctx, events, h.EvaluateFeed)
+	if err != nil {
+		return finish(0, len(events), err)
+	}
 	saved := 0
 	for _, evt := range events {
-		saveErr := h.Store.SaveEvent(ctx, store.WebhookEvent{
-			DeliveryID:         evt.DeliveryID,
-			EventType:          evt.EventType,
-			Action:             evt.Action,
-			RepositoryFullName: evt.RepositoryFullName,
-			SenderLogin:        evt.SenderLogin,
-			PayloadJSON:        evt.PayloadJSON,
-		})
+		saveErr := h.saveSyncedEvent(ctx, evt, h.EvaluateFeed, known)
 		if saveErr != nil {
 			return finish(saved, len(events), fmt.Errorf("save github event failed: %w", saveErr))
 		}
@@ -264,15 +328,125 @@ func (h *EventsHandler) SyncGitHubEvents(ctx context.Context) (int, int, error)
 	return finish(saved, len(events), nil)
 }
 
+// pollSources polls each active source and saves its new events oldest first. A
+// failing source does not stop the others; its cursor only covers saved events.
+func (h *EventsHandler) pollSources(ctx context.Context, sources []store.GitHubEventSourceRecord) (int, int, error) {
+	saved, total, failed := 0, 0, 0
+	var firstErr error
+	for _, src := range sources {
+		if !src.IsActive {
+			continue
+		}
+		sourceCtx := tenantctx.WithTenantID(ctx, src.TenantID)
+		poll, err := h.Poller.PollSourceEvents(sourceCtx, service.GitHubEventSource{Kind: src.Kind, Name: src.Name}, src.ETag, src.LastEventID)
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("poll %s %s: %w", src.Kind, src.Name, err)
+			}
+			h.recordSourcePoll(sourceCtx, src.ID, src.ETag, src.LastEventID, "failed", err.Error())
+			continue
+		}
+		total += len(poll.Events)
+
+		cursor := src.LastEventID
+		known, saveErr := h.knownSyncedDeliveries(sourceCtx, poll.Events, src.EvaluateRules)
+		for i := len(poll.Events) - 1; i >= 0 && saveErr == nil; i-- {
+			evt := poll.Events[i]
+			saveErr = h.saveSyncedEvent(sourceCtx, evt, src.EvaluateRules, known)
+			if saveErr != nil {
+				break
+			}
+			saved++
+			cursor = service.GitHubEventID(evt)
+		}
+		if saveErr != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("save github event failed: %w", saveErr)
+			}
+			// Drop the etag so the unsaved events are fetched again.
+			h.recordSourcePoll(sourceCtx, src.ID, "", cursor, "failed", saveErr.Error())
+			continue
+		}
+		message := fmt.Sprintf("new=%d", len(poll.Events))
+		if poll.NotModified {
+			message = "not modified"
+		}
+		h.recordSourcePoll(sourceCtx, src.ID, poll.ETag, poll.LastEventID, "success", message)
+	}
+	if firstErr != nil {
+		return saved, total, fmt.Errorf("%d of %d sources failed: %w", failed, len(sources), firstErr)
+	}
+	return saved, total, nil
+}
+
+// saveSyncedEvent stores a feed event in its webhook shape. With evaluate and a
+// Processor it also goes through rule evaluation, unless it was already stored.
+func (h *EventsHandler) saveSyncedEvent(ctx context.Context, evt service.GitHubUserEvent, evaluate bool, known map[string]bool) error {
+	normalized, err := service.NormalizeGitHubFeedEvent(evt)
+	if err != nil {
+		return err
+	}
+	if evaluate && h.Processor != nil && !known[evt.DeliveryID] {
+		tenantID := tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)
+		_, err = h.Processor.ProcessSyncedEvent(ctx, tenantID, evt.DeliveryID, normalized, h.ExecuteActions)
+		return err
+	}
+	return h.Store.SaveEvent(ctx, store.WebhookEvent{
+		DeliveryID:         evt.DeliveryID,
+		EventType:          normalized.EventType,
+		Action:             normalized.Action,
+		RepositoryFullName: orUnknown(normalized.Repository),
+		SenderLogin:        orUnknown(normalized.Sender),
+		PayloadJSON:        normalized.Raw,
+		Source:             store.EventSourceGitHub,
+	})
+}
+
+// knownSyncedDeliveries looks up which events are already stored; it is only
+// needed when synced events are evaluated.
+func (h *EventsHandler) knownSyncedDeliveries(ctx context.Context, events []service.GitHubUserEvent, evaluate bool) (map[string]bool, error) {
+	if !evaluate || h.Processor == nil || h.Known == nil || len(events) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(events))
+	for _, evt := range events {
+		ids = append(ids, evt.DeliveryID)
+	}
+	known, err := h.Known.ListKnownDeliveryIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("list known github deliveries failed: %w", err)
+	}
+	return known, nil
+}
+
+func (h *EventsHandler) recordSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) {
+	if err := h.Sources.RecordGitHubEventSourcePoll(ctx, id, etag, lastEventID, status, message); err != nil {
+		slog.ErrorContext(ctx, "record github event source poll failed", "source_id", id, "error", err)
+	}
+}
+
 func (h *EventsHandler) GitHubSyncStatus(c *gin.Context) {
 	h.syncMu.Lock()
 	status := h.syncStatus
 	h.syncMu.Unlock()
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"ok":     true,
 		"source": "github",
 		"status": status,
-	})
+	}
+	if h.History != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+		if schedule, err := h.History.GetGitHubSyncSchedule(ctx); err == nil {
+			resp["schedule"] = schedule
+		}
+		if runs, _, err := h.History.ListGitHubSyncRuns(ctx, 1, 0); err == nil && len(runs) > 0 {
+			resp["last_run"] = runs[0]
+		}
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *EventsHandler) FilterOptions(c *gin.Context) {
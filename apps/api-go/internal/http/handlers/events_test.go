@@ -418,6 +418,7 @@ func TestEventsFilterOptions_Success(t *testing.T) {
 		Actions:      []string{"opened", "pushed"},
 		Repositories: []string{"owner/repo"},
 		Senders:      []string{"alice"},
+		Sources:      []string{"gitea", "github"},
 	}}
 	h := NewEventsHandler(mockStore, &mockGitHubEventTypesProvider{})
 	r := gin.New()
@@ -435,7 +436,7 @@ func TestEventsFilterOptions_Success(t *testing.T) {
 		Options store.EventFilterOptions `json:"options"`
 	}
 	_ = json.Unmarshal(w.Body.Bytes(), &resp)
-	if !resp.OK || len(resp.Options.EventTypes) != 2 || len(resp.Options.Actions) != 2 {
+	if !resp.OK || len(resp.Options.EventTypes) != 2 || len(resp.Options.Actions) != 2 || len(resp.Options.Sources) != 2 {
 		t.Fatalf("unexpected response: %s", w.Body.String())
 	}
 }
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GitHubEventSourceAdminStore interface {
+	CreateGitHubEventSource(ctx context.Context, item store.GitHubEventSourceRecord) (int64, error)
+	ListGitHubEventSources(ctx context.Context) ([]store.GitHubEventSourceRecord, error)
+	DeleteGitHubEventSource(ctx context.Context, id int64) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+// GitHubEventSourcesHandler manages the repositories and orgs a tenant's event
+// sync polls.
+type GitHubEventSourcesHandler struct {
+	Store GitHubEventSourceAdminStore
+}
+
+type createGitHubEventSourceRequest struct {
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	EvaluateRules bool   `json:"evaluate_rules"`
+}
+
+func NewGitHubEventSourcesHandler(s GitHubEventSourceAdminStore) *GitHubEventSourcesHandler {
+	return &GitHubEventSourcesHandler{Store: s}
+}
+
+func (h *GitHubEventSourcesHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "event source store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListGitHubEventSources(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list github event sources failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items})
+}
+
+func (h *GitHubEventSourcesHandler) Create(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "event source store is not configured"})
+		return
+	}
+	var req createGitHubEventSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	req.Kind = strings.ToLower(strings.TrimSpace(req.Kind))
+	req.Name = strings.Trim(strings.TrimSpace(req.Name), "/")
+	if err := service.ValidateGitHubEventSource(service.GitHubEventSource{Kind: req.Kind, Name: req.Name}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	actor := requestActor(c)
+	id, err := h.Store.CreateGitHubEventSource(ctx, store.GitHubEventSourceRecord{Kind: req.Kind, Name: req.Name, EvaluateRules: req.EvaluateRules, CreatedBy: actor})
+	if err != nil {
+		if store.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "github event source already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("create github event source failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "github_event_source.create",
+		Target:   "github_event_source",
+		TargetID: strconv.FormatInt(id, 10),
+		Payload:  fmt.Sprintf(`{"kind":%q,"name":%q,"evaluate_rules":%t}`, req.Kind, req.Name, req.EvaluateRules),
+	})
+	c.JSON(http.StatusCreated, gin.H{"ok": true, "id": id})
+}
+
+func (h *GitHubEventSourcesHandler) Delete(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "event source store is not configured"})
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid event source id"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	if err := h.Store.DeleteGitHubEventSource(ctx, id); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("delete github event source failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    requestActor(c),
+		Action:   "github_event_source.delete",
+		Target:   "github_event_source",
+		TargetID: strconv.FormatInt(id, 10),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+)
+
+type mockGitHubEventSourceStore struct {
+	sources []store.GitHubEventSourceRecord
+	polls   map[int64]store.GitHubEventSourceRecord
+}
+
+func (m *mockGitHubEventSourceStore) ListGitHubEventSources(_ context.Context) ([]store.GitHubEventSourceRecord, error) {
+	return m.sources, nil
+}
+
+func (m *mockGitHubEventSourceStore) ListActiveGitHubEventSources(_ context.Context) ([]store.GitHubEventSourceRecord, error) {
+	return m.sources, nil
+}
+
+func (m *mockGitHubEventSourceStore) RecordGitHubEventSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) error {
+	if m.polls == nil {
+		m.polls = map[int64]store.GitHubEventSourceRecord{}
+	}
+	tenantID := tenantctx.MustFromContext(ctx, "")
+	m.polls[id] = store.GitHubEventSourceRecord{ID: id, TenantID: tenantID, ETag: etag, LastEventID: lastEventID, LastStatus: status, LastMessage: message}
+	return nil
+}
+
+type mockGitHubEventSourcePoller struct {
+	polls   map[string]service.GitHubEventPoll
+	errs    map[string]error
+	tenants []string
+}
+
+func (m *mockGitHubEventSourcePoller) PollSourceEvents(ctx context.Context, src service.GitHubEventSource, _ string, _ int64) (service.GitHubEventPoll, error) {
+	m.tenants = append(m.tenants, tenantctx.MustFromContext(ctx, ""))
+	if err := m.errs[src.Name]; err != nil {
+		return service.GitHubEventPoll{}, err
+	}
+	return m.polls[src.Name], nil
+}
+
+func TestEventsSyncGitHubEvents_PollsConfiguredSources(t *testing.T) {
+	eventsStore := &mockEventsStore{}
+	sources := &mockGitHubEventSourceStore{sources: []store.GitHubEventSourceRecord{
+		{ID: 1, TenantID: "tenant-a", Kind: service.GitHubEventSourceRepo, Name: "owner/repo", ETag: `W/"old"`, LastEventID: 10, IsActive: true},
+		{ID: 2, TenantID: "tenant-b", Kind: service.GitHubEventSourceOrg, Name: "acme", ETag: `W/"same"`, LastEventID: 50, IsActive: true},
+		{ID: 3, TenantID: "tenant-b", Kind: service.GitHubEventSourceOrg, Name: "broken", IsActive: true},
+	}}
+	poller := &mockGitHubEventSourcePoller{
+		polls: map[string]service.GitHubEventPoll{
+			"owner/repo": {ETag: `W/"new"`, LastEventID: 12, Events: []service.GitHubUserEvent{
+				{DeliveryID: "gh-12", EventType: "IssuesEvent"}, {DeliveryID: "gh-11", EventType: "PushEvent"},
+			}},
+			"acme": {ETag: `W/"same"`, LastEventID: 50, NotModified: true},
+		},
+		errs: map[string]error{"broken": errors.New("github api status: 404")},
+	}
+	legacy := &mockGitHubEventTypesProvider{}
+	h := NewEventsHandler(eventsStore, legacy)
+	h.Sources = sources
+	h.Poller = poller
+
+	saved, total, err := h.SyncGitHubEvents(context.Background())
+	if err == nil || saved != 2 || total != 2 {
+		t.Fatalf("expected 2 saved with one failing source, got saved=%d total=%d err=%v", saved, total, err)
+	}
+	if legacy.calls != 0 {
+		t.Fatalf("user feed must not be used when sources are configured")
+	}
+	if len(eventsStore.savedEvents) != 2 || eventsStore.savedEvents[0].DeliveryID != "gh-11" {
+		t.Fatalf("expected events saved oldest first, got %+v", eventsStore.savedEvents)
+	}
+	if got := sources.polls[1]; got.ETag != `W/"new"` || got.LastEventID != 12 || got.TenantID != "tenant-a" || got.LastStatus != "success" {
+		t.Fatalf("unexpected cursor for repo source: %+v", got)
+	}
+	if got := sources.polls[2]; got.LastMessage != "not modified" || got.ETag != `W/"same"` {
+		t.Fatalf("unexpected cursor for org source: %+v", got)
+	}
+	if got := sources.polls[3]; got.LastStatus != "failed" {
+		t.Fatalf("expected failing source to be recorded, got %+v", got)
+	}
+	if poller.tenants[0] != "tenant-a" || poller.tenants[1] != "tenant-b" {
+		t.Fatalf("expected each source polled as its tenant, got %v", poller.tenants)
+	}
+}
+
+func TestEventsSyncGitHubEvents_SaveFailureKeepsCursorAtLastSaved(t *testing.T) {
+	eventsStore := &mockEventsStore{saveErr: errors.New("db down")}
+	sources := &mockGitHubEventSourceStore{sources: []store.GitHubEventSourceRecord{
+		{ID: 1, TenantID: "tenant-a", Kind: service.GitHubEventSourceRepo, Name: "owner/repo", ETag: `W/"old"`, LastEventID: 10, IsActive: true},
+	}}
+	poller := &mockGitHubEventSourcePoller{polls: map[string]service.GitHubEventPoll{
+		"owner/repo": {ETag: `W/"new"`, LastEventID: 11, Events: []service.GitHubUserEvent{{DeliveryID: "gh-11"}}},
+	}}
+	h := NewEventsHandler(eventsStore, &mockGitHubEventTypesProvider{})
+	h.Sources = sources
+	h.Poller = poller
+
+	if _, _, err := h.SyncGitHubEvents(context.Background()); err == nil {
+		t.Fatalf("expected save failure")
+	}
+	if got := sources.polls[1]; got.ETag != "" || got.LastEventID != 10 || got.LastStatus != "failed" {
+		t.Fatalf("expected etag dropped and cursor kept, got %+v", got)
+	}
+}
+
+type mockSyncedEventProcessor struct {
+	deliveries []string
+	types      []string
+	tenants    []string
+	actions    []bool
+}
+
+func (m *mockSyncedEventProcessor) ProcessSyncedEvent(_ context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent, executeActions bool) ([]service.SuggestedAction, error) {
+	m.deliveries = append(m.deliveries, deliveryID)
+	m.types = append(m.types, normalized.EventType)
+	m.tenants = append(m.tenants, tenantID)
+	m.actions = append(m.actions, executeActions)
+	return nil, nil
+}
+
+type mockKnownDeliveries struct {
+	known map[string]bool
+}
+
+func (m *mockKnownDeliveries) ListKnownDeliveryIDs(_ context.Context, _ []string) (map[string]bool, error) {
+	return m.known, nil
+}
+
+func TestEventsSyncGitHubEvents_SavesWebhookShape(t *testing.T) {
+	eventsStore := &mockEventsStore{}
+	sources := &mockGitHubEventSourceStore{sources: []store.GitHubEventSourceRecord{
+		{ID: 1, TenantID: "tenant-a", Kind: service.GitHubEventSourceRepo, Name: "owner/repo", IsActive: true},
+	}}
+	poller := &mockGitHubEventSourcePoller{polls: map[string]service.GitHubEventPoll{
+		"owner/repo": {LastEventID: 5, Events: []service.GitHubUserEvent{{
+			DeliveryID: "gh-5", EventType: "IssuesEvent", Action: "opened", RepositoryFullName: "owner/repo", SenderLogin: "alice",
+			PayloadJSON: []byte(`{"id":"5","type":"IssuesEvent","payload":{"action":"opened","issue":{"number":3}}}`),
+		}}},
+	}}
+	h := NewEventsHandler(eventsStore, &mockGitHubEventTypesProvider{})
+	h.Sources = sources
+	h.Poller = poller
+
+	if _, _, err := h.SyncGitHubEvents(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(eventsStore.savedEvents) != 1 {
+		t.Fatalf("expected 1 saved event, got %d", len(eventsStore.savedEvents))
+	}
+	got := eventsStore.savedEvents[0]
+	if got.EventType != "issues" || got.Action != "opened" || got.Source != store.EventSourceGitHub {
+		t.Fatalf("expected webhook event type, got %+v", got)
+	}
+	if strings.Contains(string(got.PayloadJSON), `"payload"`) || !strings.Contains(string(got.PayloadJSON), `"full_name":"owner/repo"`) {
+		t.Fatalf("expected webhook-shaped payload, got %s", got.PayloadJSON)
+	}
+}
+
+func TestEventsSyncGitHubEvents_ProcessorEvaluatesNewEventsOnly(t *testing.T) {
+	eventsStore := &mockEventsStore{}
+	sources := &mockGitHubEventSourceStore{sources: []store.GitHubEventSourceRecord{
+		{ID: 1, TenantID: "tenant-a", Kind: service.GitHubEventSourceRepo, Name: "owner/repo", EvaluateRules: true, IsActive: true},
+		{ID: 2, TenantID: "tenant-b", Kind: service.GitHubEventSourceRepo, Name: "owner/hooked", IsActive: true},
+	}}
+	poller := &mockGitHubEventSourcePoller{polls: map[string]service.GitHubEventPoll{
+		"owner/repo": {LastEventID: 12, Events: []service.GitHubUserEvent{
+			{DeliveryID: "gh-12", EventType: "PullRequestEvent"}, {DeliveryID: "gh-11", EventType: "IssuesEvent"},
+		}},
+		"owner/hooked": {LastEventID: 20, Events: []service.GitHubUserEvent{{DeliveryID: "gh-20", EventType: "IssuesEvent"}}},
+	}}
+	processor := &mockSyncedEventProcessor{}
+	h := NewEventsHandler(eventsStore, &mockGitHubEventTypesProvider{})
+	h.Sources = sources
+	h.Poller = poller
+	h.Processor = processor
+	h.Known = &mockKnownDeliveries{known: map[string]bool{"gh-11": true}}
+
+	saved, _, err := h.SyncGitHubEvents(context.Background())
+	if err != nil || saved != 3 {
+		t.Fatalf("expected all events handled, got saved=%d err=%v", saved, err)
+	}
+	if len(processor.deliveries) != 1 || processor.deliveries[0] != "gh-12" || processor.types[0] != "pull_request" {
+		t.Fatalf("expected only the new event evaluated, got %+v", processor)
+	}
+	if processor.tenants[0] != "tenant-a" || processor.actions[0] {
+		t.Fatalf("expected source tenant and actions off by default, got %+v", processor)
+	}
+	if len(eventsStore.savedEvents) != 2 || eventsStore.savedEvents[0].DeliveryID != "gh-11" || eventsStore.savedEvents[1].DeliveryID != "gh-20" {
+		t.Fatalf("expected the known event and the non-evaluating source saved without evaluation, got %+v", eventsStore.savedEvents)
+	}
+	if got := sources.polls[1]; got.LastEventID != 12 || got.LastStatus != "success" {
+		t.Fatalf("unexpected cursor: %+v", got)
+	}
+}
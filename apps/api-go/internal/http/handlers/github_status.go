@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GitHubRateLimitProvider interface {
+	RateLimitStatus(ctx context.Context) (map[string]service.GitHubRateLimit, time.Time)
+	FetchRateLimit(ctx context.Context) (map[string]service.GitHubRateLimit, error)
+}
+
+type GitHubStatusHandler struct {
+	Provider GitHubRateLimitProvider
+}
+
+func NewGitHubStatusHandler(provider GitHubRateLimitProvider) *GitHubStatusHandler {
+	return &GitHubStatusHandler{Provider: provider}
+}
+
+// RateLimit reports the rate-limit headroom last seen on GitHub responses. With
+// refresh=true, or when nothing has been observed yet, it queries /rate_limit.
+func (h *GitHubStatusHandler) RateLimit(c *gin.Context) {
+	if h.Provider == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github provider is not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	resources, pausedUntil := h.Provider.RateLimitStatus(ctx)
+	now := time.Now().UTC()
+	paused := pausedUntil.After(now)
+	source := "observed"
+
+	refresh := strings.EqualFold(c.Query("refresh"), "true") || len(resources) == 0
+	if refresh && !paused {
+		live, err := h.Provider.FetchRateLimit(ctx)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"ok": false, "message": fmt.Sprintf("fetch github rate limit failed: %v", err), "resources": resources})
+			return
+		}
+		resources = live
+		source = "live"
+	}
+
+	resp := gin.H{"ok": true, "source": source, "resources": resources, "paused": paused}
+	if paused {
+		resp["paused_until"] = pausedUntil.UTC()
+	}
+	c.JSON(http.StatusOK, resp)
+}
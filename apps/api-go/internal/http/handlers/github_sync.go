@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/metrics"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+	"maintainer-firewall/api-go/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GitHubSyncStore interface {
+	GetGitHubSyncSchedule(ctx context.Context) (store.GitHubSyncScheduleRecord, error)
+	UpsertGitHubSyncSchedule(ctx context.Context, item store.GitHubSyncScheduleRecord) error
+	UpdateGitHubSyncSchedulePaused(ctx context.Context, isPaused bool, nextRunAt time.Time, updatedBy string) error
+	ListDueGitHubSyncSchedules(ctx context.Context, now time.Time, limit int) ([]store.GitHubSyncScheduleRecord, error)
+	ClaimGitHubSyncSchedule(ctx context.Context, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error)
+	CreateGitHubSyncRun(ctx context.Context, run store.GitHubSyncRunRecord) (int64, error)
+	FinishGitHubSyncRun(ctx context.Context, run store.GitHubSyncRunRecord) error
+	ListGitHubSyncRuns(ctx context.Context, limit int, offset int) ([]store.GitHubSyncRunRecord, int64, error)
+	AcquireWorkerLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	ReleaseWorkerLease(ctx context.Context, name string, holder string) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type TenantEventSyncer interface {
+	SyncTenantGitHubEvents(ctx context.Context) (int, int, error)
+}
+
+// GitHubSyncHandler runs each tenant's GitHub event sync on its own schedule and
+// records every run. Only the replica holding the sync lease runs schedules;
+// claiming next_run_at keeps an activation from running twice even if the lease
+// changes hands mid-batch.
+type GitHubSyncHandler struct {
+	Store      GitHubSyncStore
+	Syncer     TenantEventSyncer
+	Holder     string
+	LeaseTTL   time.Duration
+	RunTimeout time.Duration
+	Now        func() time.Time
+	// Jitter returns the delay added to each next run; it defaults to up to a
+	// tenth of the interval, capped at a minute, so tenants drift apart.
+	Jitter func(interval time.Duration) time.Duration
+}
+
+type updateGitHubSyncScheduleRequest struct {
+	IntervalMinutes int  `json:"interval_minutes"`
+	IsPaused        bool `json:"is_paused"`
+}
+
+type updateGitHubSyncPausedRequest struct {
+	IsPaused bool `json:"is_paused"`
+}
+
+const (
+	githubSyncLeaseName = "github_events_sync"
+	githubSyncDueBatch  = 20
+)
+
+func NewGitHubSyncHandler(s GitHubSyncStore, syncer TenantEventSyncer) *GitHubSyncHandler {
+	return &GitHubSyncHandler{
+		Store:      s,
+		Syncer:     syncer,
+		Holder:     newWorkerLeaseHolder(),
+		LeaseTTL:   2 * time.Minute,
+		RunTimeout: 2 * time.Minute,
+		Now:        time.Now,
+	}
+}
+
+func newWorkerLeaseHolder() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%x", host, os.Getpid(), rand.Uint32())
+}
+
+func (h *GitHubSyncHandler) now() time.Time {
+	if h.Now == nil {
+		return time.Now().UTC()
+	}
+	return h.Now().UTC()
+}
+
+func (h *GitHubSyncHandler) nextRunAt(from time.Time, intervalMinutes int) time.Time {
+	interval := time.Duration(intervalMinutes) * time.Minute
+	if h.Jitter != nil {
+		return from.Add(interval + h.Jitter(interval))
+	}
+	maxJitter := min(interval/10, time.Minute)
+	if maxJitter <= 0 {
+		return from.Add(interval)
+	}
+	return from.Add(interval + rand.N(maxJitter))
+}
+
+func (h *GitHubSyncHandler) GetSchedule(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github sync store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	schedule, err := h.Store.GetGitHubSyncSchedule(ctx)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get github sync schedule failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "schedule": schedule})
+}
+
+func (h *GitHubSyncHandler) UpdateSchedule(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github sync store is not configured"})
+		return
+	}
+	var req updateGitHubSyncScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	if req.IntervalMinutes < 1 || req.IntervalMinutes > 1440 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "interval_minutes must be between 1 and 1440"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	actor := requestActor(c)
+	nextRunAt := h.nextRunAt(h.now(), req.IntervalMinutes)
+	if err := h.Store.UpsertGitHubSyncSchedule(ctx, store.GitHubSyncScheduleRecord{
+		IntervalMinutes: req.IntervalMinutes,
+		IsPaused:        req.IsPaused,
+		NextRunAt:       nextRunAt,
+		UpdatedBy:       actor,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update github sync schedule failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "github_sync.schedule.update",
+		Target:   "github_sync_schedule",
+		TargetID: tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID),
+		Payload:  fmt.Sprintf(`{"interval_minutes":%d,"is_paused":%t}`, req.IntervalMinutes, req.IsPaused),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true, "next_run_at": nextRunAt})
+}
+
+func (h *GitHubSyncHandler) UpdatePaused(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github sync store is not configured"})
+		return
+	}
+	var req updateGitHubSyncPausedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	schedule, err := h.Store.GetGitHubSyncSchedule(ctx)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get github sync schedule failed: %v", err)})
+		return
+	}
+
+	// Resuming skips the runs missed while paused.
+	nextRunAt := h.nextRunAt(h.now(), schedule.IntervalMinutes)
+	actor := requestActor(c)
+	if err := h.Store.UpdateGitHubSyncSchedulePaused(ctx, req.IsPaused, nextRunAt, actor); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update github sync schedule failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "github_sync.schedule.paused",
+		Target:   "github_sync_schedule",
+		TargetID: schedule.TenantID,
+		Payload:  fmt.Sprintf(`{"is_paused":%t}`, req.IsPaused),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true, "is_paused": req.IsPaused, "next_run_at": nextRunAt})
+}
+
+func (h *GitHubSyncHandler) RunNow(c *gin.Context) {
+	if h.Store == nil || h.Syncer == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github sync is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.runTimeout()+5*time.Second)
+	defer cancel()
+
+	actor := requestActor(c)
+	run := h.runSync(ctx, "manual", actor)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "github_sync.run",
+		Target:   "github_sync_run",
+		TargetID: fmt.Sprintf("%d", run.ID),
+		Payload:  fmt.Sprintf(`{"status":%q,"saved":%d,"total":%d}`, run.Status, run.Saved, run.Total),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": run.Status == "success", "run": run})
+}
+
+func (h *GitHubSyncHandler) ListRuns(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "github sync store is not configured"})
+		return
+	}
+	limit := parseIntOrDefault(c.Query("limit"), 20)
+	offset := parseIntOrDefault(c.Query("offset"), 0)
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, total, err := h.Store.ListGitHubSyncRuns(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list github sync runs failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// RunDueSyncs runs every due schedule if this replica holds the sync lease. It
+// returns the number of syncs that ran and the number that were due.
+func (h *GitHubSyncHandler) RunDueSyncs(ctx context.Context) (int, int, error) {
+	if h.Store == nil || h.Syncer == nil {
+		return 0, 0, fmt.Errorf("github sync is not configured")
+	}
+	leader, err := h.Store.AcquireWorkerLease(ctx, githubSyncLeaseName, h.Holder, h.LeaseTTL)
+	if err != nil || !leader {
+		return 0, 0, err
+	}
+
+	now := h.now()
+	schedules, err := h.Store.ListDueGitHubSyncSchedules(ctx, now, githubSyncDueBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ran := 0
+	for i, schedule := range schedules {
+		if i > 0 {
+			// Renew between tenants; a lost lease hands the rest to the new leader.
+			if leader, err := h.Store.AcquireWorkerLease(ctx, githubSyncLeaseName, h.Holder, h.LeaseTTL); err != nil || !leader {
+				return ran, len(schedules), err
+			}
+		}
+		scheduleCtx := tenantctx.WithTenantID(ctx, schedule.TenantID)
+		claimed, err := h.Store.ClaimGitHubSyncSchedule(scheduleCtx, schedule.NextRunAt, h.nextRunAt(now, schedule.IntervalMinutes))
+		if err != nil {
+			return ran, len(schedules), err
+		}
+		if !claimed {
+			continue
+		}
+		h.runSync(scheduleCtx, "schedule", "scheduler")
+		ran++
+	}
+	return ran, len(schedules), nil
+}
+
+// ReleaseLease hands the sync lease back on shutdown so another replica can take
+// over without waiting for it to expire.
+func (h *GitHubSyncHandler) ReleaseLease(ctx context.Context) error {
+	if h.Store == nil {
+		return nil
+	}
+	return h.Store.ReleaseWorkerLease(ctx, githubSyncLeaseName, h.Holder)
+}
+
+func (h *GitHubSyncHandler) runTimeout() time.Duration {
+	if h.RunTimeout <= 0 {
+		return 2 * time.Minute
+	}
+	return h.RunTimeout
+}
+
+func (h *GitHubSyncHandler) runSync(ctx context.Context, trigger string, actor string) store.GitHubSyncRunRecord {
+	ctx, span := tracing.Start(ctx, "github.sync", trace.WithAttributes(
+		attribute.String("mf.tenant_id", tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)),
+		attribute.String("mf.trigger", trigger),
+	))
+	defer span.End()
+	run := store.GitHubSyncRunRecord{
+		TenantID:  tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID),
+		Trigger:   trigger,
+		Status:    "running",
+		Actor:     actor,
+		StartedAt: h.now(),
+	}
+	if id, err := h.Store.CreateGitHubSyncRun(ctx, run); err == nil {
+		run.ID = id
+	} else {
+		slog.ErrorContext(ctx, "record github sync run failed", "error", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, h.runTimeout())
+	saved, total, err := h.Syncer.SyncTenantGitHubEvents(runCtx)
+	cancel()
+
+	run.Saved = saved
+	run.Total = total
+	run.Status = "success"
+	if err != nil {
+		run.Status = "failed"
+		if saved > 0 {
+			run.Status = "partial"
+		}
+		run.ErrorMessage = err.Error()
+	}
+	metrics.GitHubSyncRuns.WithLabelValues(trigger, run.Status).Inc()
+	span.SetAttributes(attribute.String("mf.status", run.Status), attribute.Int("mf.saved", saved))
+	tracing.RecordError(span, err)
+	finishedAt := h.now()
+	run.FinishedAt = &finishedAt
+	if run.ID > 0 {
+		if err := h.Store.FinishGitHubSyncRun(ctx, run); err != nil {
+			slog.ErrorContext(ctx, "finish github sync run failed", "run_id", run.ID, "error", err)
+		}
+	}
+	return run
+}
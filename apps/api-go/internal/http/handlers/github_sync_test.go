@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockGitHubSyncStore struct {
+	schedules   map[string]store.GitHubSyncScheduleRecord
+	due         []store.GitHubSyncScheduleRecord
+	runs        []store.GitHubSyncRunRecord
+	leaseHolder string
+	leaseCalls  int
+	claimed     []string
+	audits      []store.AuditLogRecord
+}
+
+func (m *mockGitHubSyncStore) GetGitHubSyncSchedule(ctx context.Context) (store.GitHubSyncScheduleRecord, error) {
+	schedule, ok := m.schedules[tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)]
+	if !ok {
+		return schedule, fmt.Errorf("github sync schedule not found")
+	}
+	return schedule, nil
+}
+
+func (m *mockGitHubSyncStore) UpsertGitHubSyncSchedule(ctx context.Context, item store.GitHubSyncScheduleRecord) error {
+	item.TenantID = tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)
+	m.schedules[item.TenantID] = item
+	return nil
+}
+
+func (m *mockGitHubSyncStore) UpdateGitHubSyncSchedulePaused(ctx context.Context, isPaused bool, nextRunAt time.Time, _ string) error {
+	tenantID := tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)
+	schedule := m.schedules[tenantID]
+	schedule.IsPaused = isPaused
+	schedule.NextRunAt = nextRunAt
+	m.schedules[tenantID] = schedule
+	return nil
+}
+
+func (m *mockGitHubSyncStore) ListDueGitHubSyncSchedules(_ context.Context, _ time.Time, _ int) ([]store.GitHubSyncScheduleRecord, error) {
+	return m.due, nil
+}
+
+func (m *mockGitHubSyncStore) ClaimGitHubSyncSchedule(ctx context.Context, _ time.Time, _ time.Time) (bool, error) {
+	tenantID := tenantctx.MustFromContext(ctx, "")
+	for _, claimed := range m.claimed {
+		if claimed == tenantID {
+			return false, nil
+		}
+	}
+	m.claimed = append(m.claimed, tenantID)
+	return true, nil
+}
+
+func (m *mockGitHubSyncStore) CreateGitHubSyncRun(_ context.Context, run store.GitHubSyncRunRecord) (int64, error) {
+	run.ID = int64(len(m.runs) + 1)
+	m.runs = append(m.runs, run)
+	return run.ID, nil
+}
+
+func (m *mockGitHubSyncStore) FinishGitHubSyncRun(_ context.Context, run store.GitHubSyncRunRecord) error {
+	m.runs[run.ID-1] = run
+	return nil
+}
+
+func (m *mockGitHubSyncStore) ListGitHubSyncRuns(_ context.Context, _ int, _ int) ([]store.GitHubSyncRunRecord, int64, error) {
+	return m.runs, int64(len(m.runs)), nil
+}
+
+func (m *mockGitHubSyncStore) AcquireWorkerLease(_ context.Context, _ string, holder string, _ time.Duration) (bool, error) {
+	m.leaseCalls++
+	if m.leaseHolder == "" {
+		m.leaseHolder = holder
+	}
+	return m.leaseHolder == holder, nil
+}
+
+func (m *mockGitHubSyncStore) ReleaseWorkerLease(_ context.Context, _ string, holder string) error {
+	if m.leaseHolder == holder {
+		m.leaseHolder = ""
+	}
+	return nil
+}
+
+func (m *mockGitHubSyncStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+type mockTenantEventSyncer struct {
+	tenants []string
+	err     map[string]error
+}
+
+func (m *mockTenantEventSyncer) SyncTenantGitHubEvents(ctx context.Context) (int, int, error) {
+	tenantID := tenantctx.MustFromContext(ctx, "")
+	m.tenants = append(m.tenants, tenantID)
+	if err := m.err[tenantID]; err != nil {
+		return 1, 3, err
+	}
+	return 2, 2, nil
+}
+
+func TestGitHubSyncRunDueSyncs_OnlyLeaderRuns(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	syncStore := &mockGitHubSyncStore{schedules: map[string]store.GitHubSyncScheduleRecord{}, due: []store.GitHubSyncScheduleRecord{
+		{TenantID: "tenant-a", IntervalMinutes: 5, NextRunAt: now.Add(-time.Minute)},
+		{TenantID: "tenant-b", IntervalMinutes: 10, NextRunAt: now.Add(-time.Second)},
+		{TenantID: "tenant-a", IntervalMinutes: 5, NextRunAt: now.Add(-time.Minute)},
+	}}
+	syncer := &mockTenantEventSyncer{err: map[string]error{"tenant-b": errors.New("poll acme: boom")}}
+
+	leader := NewGitHubSyncHandler(syncStore, syncer)
+	leader.Holder = "replica-1"
+	leader.Now = func() time.Time { return now }
+	follower := NewGitHubSyncHandler(syncStore, syncer)
+	follower.Holder = "replica-2"
+
+	ran, due, err := leader.RunDueSyncs(context.Background())
+	if err != nil || ran != 2 || due != 3 {
+		t.Fatalf("expected 2 of 3 due syncs to run, got ran=%d due=%d err=%v", ran, due, err)
+	}
+	if len(syncer.tenants) != 2 || syncer.tenants[0] != "tenant-a" || syncer.tenants[1] != "tenant-b" {
+		t.Fatalf("expected each tenant synced once under its own tenant, got %v", syncer.tenants)
+	}
+	if len(syncStore.runs) != 2 || syncStore.runs[0].Status != "success" || syncStore.runs[0].Trigger != "schedule" || syncStore.runs[0].FinishedAt == nil {
+		t.Fatalf("unexpected run history: %+v", syncStore.runs)
+	}
+	if syncStore.runs[1].Status != "partial" || !strings.Contains(syncStore.runs[1].ErrorMessage, "boom") {
+		t.Fatalf("expected partial run for the failing tenant, got %+v", syncStore.runs[1])
+	}
+
+	ran, due, err = follower.RunDueSyncs(context.Background())
+	if err != nil || ran != 0 || due != 0 || len(syncer.tenants) != 2 {
+		t.Fatalf("expected follower to skip, got ran=%d due=%d err=%v", ran, due, err)
+	}
+}
+
+func TestGitHubSyncNextRunAtAddsJitter(t *testing.T) {
+	h := NewGitHubSyncHandler(nil, nil)
+	from := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		next := h.nextRunAt(from, 60)
+		if next.Before(from.Add(time.Hour)) || !next.Before(from.Add(time.Hour+time.Minute)) {
+			t.Fatalf("next run %s outside [1h, 1h1m)", next.Sub(from))
+		}
+	}
+	if next := h.nextRunAt(from, 1); next.Sub(from) < time.Minute || next.Sub(from) >= time.Minute+6*time.Second {
+		t.Fatalf("expected jitter capped at a tenth of the interval, got %s", next.Sub(from))
+	}
+}
+
+func TestGitHubSyncScheduleLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	syncStore := &mockGitHubSyncStore{schedules: map[string]store.GitHubSyncScheduleRecord{}}
+	syncer := &mockTenantEventSyncer{}
+	h := NewGitHubSyncHandler(syncStore, syncer)
+	h.Jitter = func(time.Duration) time.Duration { return 0 }
+
+	r := gin.New()
+	r.GET("/github/sync/schedule", h.GetSchedule)
+	r.PUT("/github/sync/schedule", h.UpdateSchedule)
+	r.PATCH("/github/sync/schedule/paused", h.UpdatePaused)
+	r.POST("/github/sync/run", h.RunNow)
+	r.GET("/github/sync/runs", h.ListRuns)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/github/sync/schedule", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before a schedule exists, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/github/sync/schedule", strings.NewReader(`{"interval_minutes":0}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a zero interval, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/github/sync/schedule", strings.NewReader(`{"interval_minutes":15}`)))
+	if w.Code != http.StatusOK || syncStore.schedules["default"].IntervalMinutes != 15 {
+		t.Fatalf("expected schedule saved, got %d %+v", w.Code, syncStore.schedules)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/github/sync/schedule/paused", strings.NewReader(`{"is_paused":true}`)))
+	if w.Code != http.StatusOK || !syncStore.schedules["default"].IsPaused {
+		t.Fatalf("expected schedule paused, got %d %+v", w.Code, syncStore.schedules["default"])
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/github/sync/run", nil))
+	if w.Code != http.StatusOK || len(syncer.tenants) != 1 {
+		t.Fatalf("expected manual run while paused, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(syncStore.runs) != 1 || syncStore.runs[0].Trigger != "manual" || syncStore.runs[0].Status != "success" {
+		t.Fatalf("unexpected run history: %+v", syncStore.runs)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/github/sync/runs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"trigger":"manual"`) {
+		t.Fatalf("expected run history, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(syncStore.audits) != 3 {
+		t.Fatalf("expected schedule, pause and run audits, got %+v", syncStore.audits)
+	}
+}
@@ -1,6 +1,17 @@
 package handlers
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
 
 type HealthResponse struct {
 	Status  string `json:"status"`
@@ -13,3 +24,192 @@ func Health(c *gin.Context) {
 		Service: "maintainer-firewall-api",
 	})
 }
+
+const (
+	healthCheckOK      = "ok"
+	healthCheckWarn    = "warn"
+	healthCheckFail    = "fail"
+	healthCheckSkipped = "skipped"
+)
+
+type ReadinessStore interface {
+	Ping(ctx context.Context) error
+	CheckSchema(ctx context.Context) error
+	CountActionRetryBacklog(ctx context.Context) (int64, error)
+	ListGitHubSyncHealth(ctx context.Context) ([]store.GitHubSyncHealthRecord, error)
+}
+
+type HealthCheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Message   string  `json:"message,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status  string              `json:"status"`
+	Service string              `json:"service"`
+	Checks  []HealthCheckResult `json:"checks"`
+}
+
+// HealthHandler serves liveness and readiness probes. Only failing checks make
+// the instance unready; "warn" is informational.
+type HealthHandler struct {
+	Store   ReadinessStore
+	Workers WorkerStatusProvider
+	// BacklogWarnThreshold turns the action backlog check to "warn" above it;
+	// 0 disables the threshold.
+	BacklogWarnThreshold int64
+	// SyncOverdueAfter turns the sync check to "warn" when a schedule's
+	// next_run_at is further in the past than this; 0 disables it.
+	SyncOverdueAfter time.Duration
+	CheckTimeout     time.Duration
+	Now              func() time.Time
+}
+
+func NewHealthHandler(s ReadinessStore, workers WorkerStatusProvider) *HealthHandler {
+	return &HealthHandler{
+		Store:        s,
+		Workers:      workers,
+		CheckTimeout: 2 * time.Second,
+	}
+}
+
+// Live reports that the process is up and serving; it does not touch
+// dependencies, so a database outage does not get the instance restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok", Service: "maintainer-firewall-api"})
+}
+
+func (h *HealthHandler) Ready(c *gin.Context) {
+	checks := []HealthCheckResult{
+		h.runCheck(c.Request.Context(), "database", h.checkDatabase),
+		h.runCheck(c.Request.Context(), "schema", h.checkSchema),
+		h.runCheck(c.Request.Context(), "sync_worker", h.checkSyncWorker),
+		h.runCheck(c.Request.Context(), "action_backlog", h.checkActionBacklog),
+	}
+	resp := ReadinessResponse{Status: "ready", Service: "maintainer-firewall-api", Checks: checks}
+	status := http.StatusOK
+	for _, check := range checks {
+		if check.Status == healthCheckFail {
+			resp.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+	c.JSON(status, resp)
+}
+
+func (h *HealthHandler) runCheck(parent context.Context, name string, check func(context.Context) (string, string)) HealthCheckResult {
+	timeout := h.CheckTimeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	started := time.Now()
+	status, message := check(ctx)
+	return HealthCheckResult{
+		Name:      name,
+		Status:    status,
+		LatencyMS: float64(time.Since(started).Microseconds()) / 1000,
+		Message:   message,
+	}
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) (string, string) {
+	if h.Store == nil {
+		return healthCheckFail, "event store is not configured"
+	}
+	if err := h.Store.Ping(ctx); err != nil {
+		return healthCheckFail, err.Error()
+	}
+	return healthCheckOK, ""
+}
+
+// checkSchema fails when schema setup left out a table or column this build
+// queries, which would otherwise only surface as errors on live traffic.
+func (h *HealthHandler) checkSchema(ctx context.Context) (string, string) {
+	if h.Store == nil {
+		return healthCheckFail, "event store is not configured"
+	}
+	if err := h.Store.CheckSchema(ctx); err != nil {
+		return healthCheckFail, err.Error()
+	}
+	return healthCheckOK, ""
+}
+
+// checkSyncWorker fails when this instance's sync scheduler is not running.
+// Tenants whose latest sync failed, or whose schedule is overdue, only warn:
+// that state is shared by every replica, like the action backlog.
+func (h *HealthHandler) checkSyncWorker(ctx context.Context) (string, string) {
+	if h.Workers == nil {
+		return healthCheckSkipped, "no worker supervisor"
+	}
+	var worker *service.WorkerStatus
+	for _, st := range h.Workers.Statuses() {
+		if st.Name == service.GitHubEventsSyncWorkerName {
+			worker = &st
+			break
+		}
+	}
+	if worker == nil {
+		return healthCheckSkipped, "sync scheduler is disabled"
+	}
+	if worker.State != service.WorkerStateRunning {
+		return healthCheckFail, fmt.Sprintf("state=%s restarts=%d last_error=%s", worker.State, worker.Restarts, worker.LastError)
+	}
+	message := fmt.Sprintf("restarts=%d", worker.Restarts)
+	if h.Store == nil {
+		return healthCheckOK, message
+	}
+
+	items, err := h.Store.ListGitHubSyncHealth(ctx)
+	if err != nil {
+		return healthCheckWarn, fmt.Sprintf("%s %v", message, err)
+	}
+	now := time.Now()
+	if h.Now != nil {
+		now = h.Now()
+	}
+	var failing, overdue []string
+	lastError := ""
+	for _, item := range items {
+		if item.LastStatus == "failed" {
+			failing = append(failing, item.TenantID)
+			if lastError == "" {
+				lastError = item.LastError
+			}
+		}
+		if h.SyncOverdueAfter > 0 && now.Sub(item.NextRunAt) > h.SyncOverdueAfter {
+			overdue = append(overdue, item.TenantID)
+		}
+	}
+	if len(failing) > 0 {
+		message += fmt.Sprintf(" failing=%s last_error=%s", strings.Join(failing, ","), lastError)
+	}
+	if len(overdue) > 0 {
+		message += " overdue=" + strings.Join(overdue, ",")
+	}
+	if len(failing) > 0 || len(overdue) > 0 {
+		return healthCheckWarn, message
+	}
+	return healthCheckOK, message
+}
+
+// checkActionBacklog never fails readiness: the backlog is shared by every
+// replica, so failing on it would take them all out of rotation at once.
+func (h *HealthHandler) checkActionBacklog(ctx context.Context) (string, string) {
+	if h.Store == nil {
+		return healthCheckSkipped, "event store is not configured"
+	}
+	backlog, err := h.Store.CountActionRetryBacklog(ctx)
+	if err != nil {
+		return healthCheckWarn, err.Error()
+	}
+	message := fmt.Sprintf("pending=%d", backlog)
+	if h.BacklogWarnThreshold > 0 && backlog > h.BacklogWarnThreshold {
+		return healthCheckWarn, fmt.Sprintf("%s threshold=%d", message, h.BacklogWarnThreshold)
+	}
+	return healthCheckOK, message
+}
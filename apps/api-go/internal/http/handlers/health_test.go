@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockReadinessStore struct {
+	pingErr   error
+	schemaErr error
+	backlog   int64
+	syncs     []store.GitHubSyncHealthRecord
+}
+
+func (m *mockReadinessStore) Ping(_ context.Context) error {
+	return m.pingErr
+}
+
+func (m *mockReadinessStore) CheckSchema(_ context.Context) error {
+	return m.schemaErr
+}
+
+func (m *mockReadinessStore) CountActionRetryBacklog(_ context.Context) (int64, error) {
+	return m.backlog, nil
+}
+
+func (m *mockReadinessStore) ListGitHubSyncHealth(_ context.Context) ([]store.GitHubSyncHealthRecord, error) {
+	return m.syncs, nil
+}
+
+type mockWorkerStatuses struct {
+	items []service.WorkerStatus
+}
+
+func (m *mockWorkerStatuses) Statuses() []service.WorkerStatus {
+	return m.items
+}
+
+func (m *mockWorkerStatuses) Healthy() bool {
+	for _, st := range m.items {
+		if st.State != service.WorkerStateRunning {
+			return false
+		}
+	}
+	return true
+}
+
+func serveReady(t *testing.T, h *HealthHandler) (int, map[string]HealthCheckResult) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	var resp ReadinessResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode readiness: %v body=%s", err, w.Body.String())
+	}
+	checks := map[string]HealthCheckResult{}
+	for _, check := range resp.Checks {
+		checks[check.Name] = check
+	}
+	return w.Code, checks
+}
+
+func TestHealthReady_AllChecksPass(t *testing.T) {
+	h := NewHealthHandler(&mockReadinessStore{backlog: 900}, &mockWorkerStatuses{items: []service.WorkerStatus{
+		{Name: service.GitHubEventsSyncWorkerName, State: service.WorkerStateRunning},
+	}})
+	h.BacklogWarnThreshold = 500
+
+	code, checks := serveReady(t, h)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %+v", code, checks)
+	}
+	if checks["database"].Status != healthCheckOK || checks["schema"].Status != healthCheckOK || checks["sync_worker"].Status != healthCheckOK {
+		t.Fatalf("unexpected checks: %+v", checks)
+	}
+	if checks["action_backlog"].Status != healthCheckWarn {
+		t.Fatalf("expected backlog above threshold to warn, got %+v", checks["action_backlog"])
+	}
+}
+
+func TestHealthReady_FailsOnDatabaseSchemaOrWorker(t *testing.T) {
+	cases := map[string]struct {
+		store   *mockReadinessStore
+		workers *mockWorkerStatuses
+		failing string
+	}{
+		"database down": {
+			store:   &mockReadinessStore{pingErr: errors.New("connection refused")},
+			workers: &mockWorkerStatuses{},
+			failing: "database",
+		},
+		"schema missing columns": {
+			store:   &mockReadinessStore{schemaErr: errors.New("schema is missing scheduled_jobs.inactive_by")},
+			workers: &mockWorkerStatuses{},
+			failing: "schema",
+		},
+		"sync worker crashed": {
+			store: &mockReadinessStore{},
+			workers: &mockWorkerStatuses{items: []service.WorkerStatus{
+				{Name: service.GitHubEventsSyncWorkerName, State: service.WorkerStateBackoff, Restarts: 2, LastError: "panic"},
+			}},
+			failing: "sync_worker",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHealthHandler(tc.store, tc.workers)
+			code, checks := serveReady(t, h)
+			if code != http.StatusServiceUnavailable {
+				t.Fatalf("expected 503, got %d", code)
+			}
+			if checks[tc.failing].Status != healthCheckFail {
+				t.Fatalf("expected %s to fail, got %+v", tc.failing, checks)
+			}
+		})
+	}
+}
+
+func TestHealthReady_SyncWorkerDisabledIsSkipped(t *testing.T) {
+	h := NewHealthHandler(&mockReadinessStore{}, &mockWorkerStatuses{})
+	code, checks := serveReady(t, h)
+	if code != http.StatusOK || checks["sync_worker"].Status != healthCheckSkipped {
+		t.Fatalf("expected ready with skipped sync worker, got %d %+v", code, checks)
+	}
+}
+
+func TestHealthReady_SyncWorkerWarnsOnFailingOrOverdueTenants(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lastRun := now.Add(-10 * time.Minute)
+	h := NewHealthHandler(&mockReadinessStore{syncs: []store.GitHubSyncHealthRecord{
+		{TenantID: "team-a", NextRunAt: now.Add(5 * time.Minute), LastStatus: "success", LastStartedAt: &lastRun},
+		{TenantID: "team-b", NextRunAt: now.Add(5 * time.Minute), LastStatus: "failed", LastError: "github api status: 401", LastStartedAt: &lastRun},
+		{TenantID: "team-c", NextRunAt: now.Add(-2 * time.Hour)},
+	}}, &mockWorkerStatuses{items: []service.WorkerStatus{
+		{Name: service.GitHubEventsSyncWorkerName, State: service.WorkerStateRunning},
+	}})
+	h.SyncOverdueAfter = 30 * time.Minute
+	h.Now = func() time.Time { return now }
+
+	code, checks := serveReady(t, h)
+	if code != http.StatusOK {
+		t.Fatalf("expected sync failures not to fail readiness, got %d", code)
+	}
+	sync := checks["sync_worker"]
+	if sync.Status != healthCheckWarn || !strings.Contains(sync.Message, "failing=team-b") || !strings.Contains(sync.Message, "401") || !strings.Contains(sync.Message, "overdue=team-c") {
+		t.Fatalf("unexpected sync check: %+v", sync)
+	}
+}
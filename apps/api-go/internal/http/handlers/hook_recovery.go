@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HookRecoveryStore interface {
+	CreateRecoveryHook(ctx context.Context, item store.RecoveryHookRecord) (int64, error)
+	ListRecoveryHooks(ctx context.Context) ([]store.RecoveryHookRecord, error)
+	ListActiveRecoveryHooks(ctx context.Context) ([]store.RecoveryHookRecord, error)
+	GetRecoveryHook(ctx context.Context, id int64) (store.RecoveryHookRecord, error)
+	DeleteRecoveryHook(ctx context.Context, id int64) error
+	RecordRecoveryRun(ctx context.Context, id int64, run store.RecoveryRunRecord) error
+	ListKnownDeliveryIDs(ctx context.Context, deliveryIDs []string) (map[string]bool, error)
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type HookDeliveriesClient interface {
+	ListHookDeliveries(ctx context.Context, hook service.GitHubHookRef, next string) ([]service.GitHubHookDelivery, string, error)
+	GetHookDelivery(ctx context.Context, hook service.GitHubHookRef, deliveryID int64) (service.GitHubHookDeliveryPayload, error)
+	RedeliverHookDelivery(ctx context.Context, hook service.GitHubHookRef, deliveryID int64) error
+}
+
+type DeliveryIngester interface {
+	IngestPayload(ctx context.Context, tenantID string, source string, eventName string, deliveryID string, body []byte) ([]service.SuggestedAction, error)
+}
+
+// HookRecoveryHandler recovers deliveries GitHub recorded for a hook but that never
+// made it into webhook_events, e.g. while the server was down. The app hook is
+// shared by every installation, so its deliveries are only recovered for the
+// tenant their installation routes to.
+type HookRecoveryHandler struct {
+	Store    HookRecoveryStore
+	GitHub   HookDeliveriesClient
+	Ingester DeliveryIngester
+	Router   WebhookTenantRouter
+}
+
+// hookRecoveryMaxPages bounds how many pages one run reads; a longer backlog is
+// worked through over several runs from a saved cursor.
+const hookRecoveryMaxPages = 10
+
+type createRecoveryHookRequest struct {
+	Scope  string `json:"scope"`
+	Target string `json:"target"`
+	HookID int64  `json:"hook_id"`
+	Mode   string `json:"mode"`
+}
+
+type hookRecoveryResult struct {
+	ID        int64  `json:"id"`
+	Scanned   int    `json:"scanned"`
+	Missing   int    `json:"missing"`
+	Recovered int    `json:"recovered"`
+	Skipped   int    `json:"skipped"`
+	Watermark int64  `json:"watermark"`
+	Resumable bool   `json:"resumable,omitempty"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+
+	cursor string
+	top    int64
+}
+
+func NewHookRecoveryHandler(s HookRecoveryStore, github HookDeliveriesClient, ingester DeliveryIngester, router WebhookTenantRouter) *HookRecoveryHandler {
+	return &HookRecoveryHandler{Store: s, GitHub: github, Ingester: ingester, Router: router}
+}
+
+func (h *HookRecoveryHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "hook recovery store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListRecoveryHooks(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list recovery hooks failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items})
+}
+
+func (h *HookRecoveryHandler) Create(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "hook recovery store is not configured"})
+		return
+	}
+	var req createRecoveryHookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	req.Scope = strings.ToLower(strings.TrimSpace(req.Scope))
+	req.Target = strings.Trim(strings.TrimSpace(req.Target), "/")
+	req.Mode = strings.ToLower(strings.TrimSpace(req.Mode))
+	if req.Mode == "" {
+		req.Mode = store.RecoveryModeFetch
+	}
+	if req.Scope == service.GitHubHookScopeApp {
+		req.Target = ""
+		req.HookID = 0
+	}
+	if err := service.ValidateGitHubHookRef(service.GitHubHookRef{Scope: req.Scope, Target: req.Target, HookID: req.HookID}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": err.Error()})
+		return
+	}
+	if !store.IsValidRecoveryMode(req.Mode) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "mode must be fetch or redeliver"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	actor := requestActor(c)
+	id, err := h.Store.CreateRecoveryHook(ctx, store.RecoveryHookRecord{Scope: req.Scope, Target: req.Target, HookID: req.HookID, Mode: req.Mode, CreatedBy: actor})
+	if err != nil {
+		if store.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "recovery hook already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("create recovery hook failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "hook_recovery.create",
+		Target:   "webhook_recovery_hook",
+		TargetID: strconv.FormatInt(id, 10),
+		Payload:  fmt.Sprintf(`{"scope":%q,"target":%q,"hook_id":%d,"mode":%q}`, req.Scope, req.Target, req.HookID, req.Mode),
+	})
+	c.JSON(http.StatusCreated, gin.H{"ok": true, "id": id})
+}
+
+func (h *HookRecoveryHandler) Delete(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "hook recovery store is not configured"})
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid recovery hook id"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	if err := h.Store.DeleteRecoveryHook(ctx, id); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("delete recovery hook failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    requestActor(c),
+		Action:   "hook_recovery.delete",
+		Target:   "webhook_recovery_hook",
+		TargetID: strconv.FormatInt(id, 10),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *HookRecoveryHandler) RunNow(c *gin.Context) {
+	if h.Store == nil || h.GitHub == nil || h.Ingester == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "hook recovery is not configured"})
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid recovery hook id"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	defer cancel()
+
+	hook, err := h.Store.GetRecoveryHook(ctx, id)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get recovery hook failed: %v", err)})
+		return
+	}
+	result := h.recoverHook(ctx, hook)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    requestActor(c),
+		Action:   "hook_recovery.run",
+		Target:   "webhook_recovery_hook",
+		TargetID: strconv.FormatInt(id, 10),
+		Payload:  fmt.Sprintf(`{"missing":%d,"recovered":%d,"skipped":%d,"status":%q}`, result.Missing, result.Recovered, result.Skipped, result.Status),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": result.Status == "success", "result": result})
+}
+
+// RunAll scans every active hook of every active tenant. It returns the number of
+// deliveries recovered and the number of hooks scanned.
+func (h *HookRecoveryHandler) RunAll(ctx context.Context) (int, int, error) {
+	if h.Store == nil || h.GitHub == nil || h.Ingester == nil {
+		return 0, 0, fmt.Errorf("hook recovery is not configured")
+	}
+	hooks, err := h.Store.ListActiveRecoveryHooks(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	recovered := 0
+	for _, hook := range hooks {
+		result := h.recoverHook(ctx, hook)
+		if result.Status != "success" {
+			slog.WarnContext(tenantctx.WithTenantID(ctx, hook.TenantID), "hook delivery recovery failed", "hook_id", hook.ID, "scope", hook.Scope, "target", hook.Target, "error", result.Message)
+		}
+		recovered += result.Recovered
+	}
+	return recovered, len(hooks), nil
+}
+
+// recoverHook walks the delivery log back to the watermark, then handles missing
+// deliveries oldest first. The watermark only advances past deliveries that were
+// handled, so a failure is retried on the next run. When the page cap stops the
+// walk before the watermark, the deliveries seen are handled but the watermark
+// stays put; later runs continue from the saved cursor until they reach it, and
+// only then move the watermark to the newest delivery of the scan.
+func (h *HookRecoveryHandler) recoverHook(ctx context.Context, hook store.RecoveryHookRecord) hookRecoveryResult {
+	ctx = tenantctx.WithTenantID(ctx, hook.TenantID)
+	ref := service.GitHubHookRef{Scope: hook.Scope, Target: hook.Target, HookID: hook.HookID}
+	result := hookRecoveryResult{ID: hook.ID, Watermark: hook.LastDeliveryID, cursor: hook.ScanCursor, top: hook.ScanTopDeliveryID}
+
+	deliveries := []service.GitHubHookDelivery{}
+	next := hook.ScanCursor
+	top := hook.ScanTopDeliveryID
+	complete := false
+	for page := 0; page < hookRecoveryMaxPages; page++ {
+		items, nextURL, err := h.GitHub.ListHookDeliveries(ctx, ref, next)
+		if err != nil {
+			return h.finishRecovery(ctx, hook, result, fmt.Errorf("list deliveries: %w", err))
+		}
+		reachedWatermark := false
+		for _, d := range items {
+			if d.ID <= hook.LastDeliveryID {
+				reachedWatermark = true
+				break
+			}
+			top = max(top, d.ID)
+			deliveries = append(deliveries, d)
+		}
+		if reachedWatermark || nextURL == "" {
+			complete = true
+			break
+		}
+		next = nextURL
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	result.Scanned = len(deliveries)
+
+	guids := make([]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		if d.GUID != "" {
+			guids = append(guids, d.GUID)
+		}
+	}
+	known, err := h.Store.ListKnownDeliveryIDs(ctx, guids)
+	if err != nil {
+		return h.finishRecovery(ctx, hook, result, err)
+	}
+
+	for _, d := range deliveries {
+		skip := d.GUID == "" || d.Event == "ping" || known[d.GUID]
+		// A redelivery we requested shows up as a new entry; asking again would loop.
+		if hook.Mode == store.RecoveryModeRedeliver && d.Redelivery {
+			skip = true
+		}
+		if skip {
+			if complete {
+				result.Watermark = d.ID
+			}
+			continue
+		}
+		known[d.GUID] = true
+		result.Missing++
+
+		err := h.recoverDelivery(ctx, hook, ref, d)
+		var rejected *deliveryRejectedError
+		switch {
+		case errors.As(err, &rejected):
+			result.Skipped++
+			slog.WarnContext(ctx, "hook delivery recovery skipped a delivery", "hook_id", hook.ID, "delivery_id", d.GUID, "error", err)
+		case err != nil:
+			return h.finishRecovery(ctx, hook, result, fmt.Errorf("delivery %s: %w", d.GUID, err))
+		default:
+			result.Recovered++
+		}
+		if complete {
+			result.Watermark = d.ID
+		}
+	}
+	if complete {
+		result.Watermark = max(result.Watermark, top)
+		result.cursor, result.top = "", 0
+	} else {
+		result.cursor, result.top = next, top
+		result.Resumable = true
+	}
+	return h.finishRecovery(ctx, hook, result, nil)
+}
+
+func (h *HookRecoveryHandler) recoverDelivery(ctx context.Context, hook store.RecoveryHookRecord, ref service.GitHubHookRef, d service.GitHubHookDelivery) error {
+	if hook.Scope == service.GitHubHookScopeApp && !h.routesToTenant(ctx, d.InstallationID, hook.TenantID) {
+		return &deliveryRejectedError{status: http.StatusConflict, err: fmt.Errorf("installation %d is not routed to tenant %s", d.InstallationID, hook.TenantID)}
+	}
+	if hook.Mode == store.RecoveryModeRedeliver {
+		return h.GitHub.RedeliverHookDelivery(ctx, ref, d.ID)
+	}
+	delivery, err := h.GitHub.GetHookDelivery(ctx, ref, d.ID)
+	if err != nil {
+		return err
+	}
+	event := delivery.Event
+	if event == "" {
+		event = d.Event
+	}
+	_, err = h.Ingester.IngestPayload(ctx, hook.TenantID, store.EventSourceGitHub, event, d.GUID, delivery.Payload)
+	return err
+}
+
+// routesToTenant reports whether the shared endpoint would route an installation's
+// deliveries to tenantID.
+func (h *HookRecoveryHandler) routesToTenant(ctx context.Context, installationID int64, tenantID string) bool {
+	routed := tenantctx.DefaultTenantID
+	if h.Router != nil && installationID > 0 {
+		if id, err := h.Router.ResolveWebhookRoute(ctx, store.WebhookRouteKindInstallation, strconv.FormatInt(installationID, 10)); err == nil {
+			routed = id
+		}
+	}
+	return routed == tenantID
+}
+
+func (h *HookRecoveryHandler) finishRecovery(ctx context.Context, hook store.RecoveryHookRecord, result hookRecoveryResult, err error) hookRecoveryResult {
+	result.Status = "success"
+	result.Message = fmt.Sprintf("scanned=%d missing=%d recovered=%d skipped=%d", result.Scanned, result.Missing, result.Recovered, result.Skipped)
+	if result.Resumable {
+		result.Message += " (older deliveries remain; the next run continues from the saved cursor)"
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.Message = err.Error()
+	}
+	run := store.RecoveryRunRecord{
+		LastDeliveryID:    result.Watermark,
+		ScanCursor:        result.cursor,
+		ScanTopDeliveryID: result.top,
+		Status:            result.Status,
+		Message:           result.Message,
+	}
+	if recordErr := h.Store.RecordRecoveryRun(ctx, hook.ID, run); recordErr != nil {
+		slog.ErrorContext(ctx, "record hook delivery recovery run failed", "hook_id", hook.ID, "error", recordErr)
+	}
+	return result
+}
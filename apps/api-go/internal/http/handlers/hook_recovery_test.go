@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockHookRecoveryStore struct {
+	hooks  []store.RecoveryHookRecord
+	known  map[string]bool
+	runs   []store.RecoveryHookRecord
+	audits []store.AuditLogRecord
+}
+
+func (m *mockHookRecoveryStore) CreateRecoveryHook(_ context.Context, item store.RecoveryHookRecord) (int64, error) {
+	item.ID = int64(len(m.hooks) + 1)
+	m.hooks = append(m.hooks, item)
+	return item.ID, nil
+}
+
+func (m *mockHookRecoveryStore) ListRecoveryHooks(_ context.Context) ([]store.RecoveryHookRecord, error) {
+	return m.hooks, nil
+}
+
+func (m *mockHookRecoveryStore) ListActiveRecoveryHooks(_ context.Context) ([]store.RecoveryHookRecord, error) {
+	return m.hooks, nil
+}
+
+func (m *mockHookRecoveryStore) GetRecoveryHook(_ context.Context, id int64) (store.RecoveryHookRecord, error) {
+	if id < 1 || int(id) > len(m.hooks) {
+		return store.RecoveryHookRecord{}, fmt.Errorf("recovery hook not found")
+	}
+	return m.hooks[id-1], nil
+}
+
+func (m *mockHookRecoveryStore) DeleteRecoveryHook(_ context.Context, _ int64) error {
+	return nil
+}
+
+func (m *mockHookRecoveryStore) RecordRecoveryRun(_ context.Context, id int64, run store.RecoveryRunRecord) error {
+	m.runs = append(m.runs, store.RecoveryHookRecord{ID: id, LastDeliveryID: run.LastDeliveryID, ScanCursor: run.ScanCursor, ScanTopDeliveryID: run.ScanTopDeliveryID, LastStatus: run.Status, LastMessage: run.Message})
+	if run.LastDeliveryID > m.hooks[id-1].LastDeliveryID {
+		m.hooks[id-1].LastDeliveryID = run.LastDeliveryID
+	}
+	m.hooks[id-1].ScanCursor = run.ScanCursor
+	m.hooks[id-1].ScanTopDeliveryID = run.ScanTopDeliveryID
+	return nil
+}
+
+func (m *mockHookRecoveryStore) ListKnownDeliveryIDs(_ context.Context, deliveryIDs []string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, id := range deliveryIDs {
+		if m.known[id] {
+			out[id] = true
+		}
+	}
+	return out, nil
+}
+
+func (m *mockHookRecoveryStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+type mockHookDeliveriesClient struct {
+	pages       [][]service.GitHubHookDelivery
+	payloads    map[int64]string
+	fetched     []int64
+	redelivered []int64
+}
+
+func (m *mockHookDeliveriesClient) ListHookDeliveries(_ context.Context, _ service.GitHubHookRef, next string) ([]service.GitHubHookDelivery, string, error) {
+	page := 0
+	if next != "" {
+		fmt.Sscanf(next, "page-%d", &page)
+	}
+	nextURL := ""
+	if page+1 < len(m.pages) {
+		nextURL = fmt.Sprintf("page-%d", page+1)
+	}
+	return m.pages[page], nextURL, nil
+}
+
+func (m *mockHookDeliveriesClient) GetHookDelivery(_ context.Context, _ service.GitHubHookRef, deliveryID int64) (service.GitHubHookDeliveryPayload, error) {
+	m.fetched = append(m.fetched, deliveryID)
+	return service.GitHubHookDeliveryPayload{Event: "issues", Payload: []byte(m.payloads[deliveryID])}, nil
+}
+
+func (m *mockHookDeliveriesClient) RedeliverHookDelivery(_ context.Context, _ service.GitHubHookRef, deliveryID int64) error {
+	m.redelivered = append(m.redelivered, deliveryID)
+	return nil
+}
+
+func TestHookRecovery_FetchesMissingDeliveriesIncrementally(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	webhookStore := &mockWebhookStore{}
+	ingester := NewWebhookHandler("secret", webhookStore)
+	recoveryStore := &mockHookRecoveryStore{
+		hooks: []store.RecoveryHookRecord{{ID: 1, TenantID: "default", Scope: service.GitHubHookScopeRepo, Target: "owner/repo", HookID: 7, Mode: store.RecoveryModeFetch, LastDeliveryID: 100}},
+		known: map[string]bool{"g-103": true},
+	}
+	client := &mockHookDeliveriesClient{
+		pages: [][]service.GitHubHookDelivery{
+			{{ID: 105, GUID: "g-105", Event: "issues"}, {ID: 104, GUID: "g-104", Event: "ping"}},
+			{{ID: 103, GUID: "g-103", Event: "issues"}, {ID: 102, GUID: "g-102", Event: "issues"}},
+			{{ID: 100, GUID: "g-100", Event: "issues"}, {ID: 99, GUID: "g-99", Event: "issues"}},
+		},
+		payloads: map[int64]string{
+			102: `{"action":"opened","repository":{"full_name":"owner/repo"},"issue":{"number":1,"title":"first"}}`,
+			105: `{"action":"opened","repository":{"full_name":"owner/repo"},"issue":{"number":"bad"}}`,
+		},
+	}
+	h := NewHookRecoveryHandler(recoveryStore, client, ingester, nil)
+
+	r := gin.New()
+	r.POST("/hook-recovery/hooks/:id/run", h.RunNow)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/hook-recovery/hooks/1/run", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	if len(client.fetched) != 2 || client.fetched[0] != 102 || client.fetched[1] != 105 {
+		t.Fatalf("expected only missing deliveries to be fetched oldest first, got %v", client.fetched)
+	}
+	if len(webhookStore.saved) != 1 || webhookStore.saved[0].DeliveryID != "g-102" {
+		t.Fatalf("expected recovered delivery to be stored under its GUID, got %+v", webhookStore.saved)
+	}
+	run := recoveryStore.runs[0]
+	if run.LastDeliveryID != 105 || run.LastStatus != "success" {
+		t.Fatalf("expected watermark to advance past the rejected delivery, got %+v", run)
+	}
+
+	recovered, hooks, err := h.RunAll(context.Background())
+	if err != nil || recovered != 0 || hooks != 1 || len(client.fetched) != 2 {
+		t.Fatalf("expected second run to stop at the watermark, got recovered=%d hooks=%d fetched=%v err=%v", recovered, hooks, client.fetched, err)
+	}
+}
+
+func TestHookRecovery_RedeliverSkipsRedeliveriesAndForeignInstallations(t *testing.T) {
+	recoveryStore := &mockHookRecoveryStore{
+		hooks: []store.RecoveryHookRecord{{ID: 1, TenantID: "default", Scope: service.GitHubHookScopeApp, Mode: store.RecoveryModeRedeliver}},
+	}
+	client := &mockHookDeliveriesClient{pages: [][]service.GitHubHookDelivery{{
+		{ID: 3, GUID: "g-1", Event: "issues", Redelivery: true, InstallationID: 11},
+		{ID: 2, GUID: "g-2", Event: "issues", InstallationID: 22},
+		{ID: 1, GUID: "g-1", Event: "issues", InstallationID: 11},
+	}}}
+	router := &mockWebhookRouter{routes: map[string]string{store.WebhookRouteKindInstallation + ":22": "tenant-b"}}
+	h := NewHookRecoveryHandler(recoveryStore, client, NewWebhookHandler("secret", &mockWebhookStore{}), router)
+
+	if _, _, err := h.RunAll(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(client.redelivered) != 1 || client.redelivered[0] != 1 {
+		t.Fatalf("expected only the original default-tenant delivery to be redelivered, got %v", client.redelivered)
+	}
+	if run := recoveryStore.runs[0]; run.LastDeliveryID != 3 || run.LastStatus != "success" {
+		t.Fatalf("unexpected run record: %+v", run)
+	}
+}
+
+func TestHookRecovery_ResumesBacklogBeyondPageCap(t *testing.T) {
+	recoveryStore := &mockHookRecoveryStore{
+		hooks: []store.RecoveryHookRecord{{ID: 1, TenantID: "default", Scope: service.GitHubHookScopeRepo, Target: "owner/repo", HookID: 7, Mode: store.RecoveryModeRedeliver, LastDeliveryID: 1}},
+	}
+	// Two deliveries per page, newest first: 25..2 are missing, 1 is the watermark.
+	pages := hookRecoveryMaxPages + 3
+	client := &mockHookDeliveriesClient{}
+	for page := 0; page < pages; page++ {
+		newest := int64(2*(pages-page) + 1)
+		client.pages = append(client.pages, []service.GitHubHookDelivery{
+			{ID: newest, GUID: fmt.Sprintf("g-%d", newest), Event: "issues"},
+			{ID: newest - 1, GUID: fmt.Sprintf("g-%d", newest-1), Event: "issues"},
+		})
+	}
+	client.pages[pages-1] = append(client.pages[pages-1], service.GitHubHookDelivery{ID: 1, GUID: "g-1", Event: "issues"})
+	h := NewHookRecoveryHandler(recoveryStore, client, NewWebhookHandler("secret", &mockWebhookStore{}), nil)
+
+	if _, _, err := h.RunAll(context.Background()); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	first := recoveryStore.runs[0]
+	if len(client.redelivered) != 2*hookRecoveryMaxPages || client.redelivered[0] != 8 {
+		t.Fatalf("expected the first run to handle the capped pages oldest first, got %v", client.redelivered)
+	}
+	if first.LastDeliveryID != 1 || first.ScanCursor == "" || first.ScanTopDeliveryID != 27 {
+		t.Fatalf("expected the watermark to stay put and a cursor to be saved, got %+v", first)
+	}
+
+	if _, _, err := h.RunAll(context.Background()); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	second := recoveryStore.runs[1]
+	if got := client.redelivered[2*hookRecoveryMaxPages:]; len(got) != 6 || got[0] != 2 || got[5] != 7 {
+		t.Fatalf("expected the second run to resume with the older deliveries, got %v", got)
+	}
+	if second.LastDeliveryID != 27 || second.ScanCursor != "" || second.ScanTopDeliveryID != 0 {
+		t.Fatalf("expected the watermark to move to the newest delivery once the backlog is done, got %+v", second)
+	}
+
+	if _, _, err := h.RunAll(context.Background()); err != nil || len(client.redelivered) != 26 {
+		t.Fatalf("expected the third run to stop at the watermark, got %v err=%v", client.redelivered, err)
+	}
+}
+
+func TestHookRecovery_CreateValidatesHook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recoveryStore := &mockHookRecoveryStore{}
+	h := NewHookRecoveryHandler(recoveryStore, &mockHookDeliveriesClient{}, nil, nil)
+	r := gin.New()
+	r.POST("/hook-recovery/hooks", h.Create)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/hook-recovery/hooks", strings.NewReader(`{"scope":"repo","target":"owner","hook_id":1}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid repo target, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/hook-recovery/hooks", strings.NewReader(`{"scope":"org","target":"acme","hook_id":5}`)))
+	if w.Code != http.StatusCreated || len(recoveryStore.hooks) != 1 || recoveryStore.hooks[0].Mode != store.RecoveryModeFetch {
+		t.Fatalf("expected hook created with fetch mode, got %d %+v", w.Code, recoveryStore.hooks)
+	}
+	if len(recoveryStore.audits) != 1 || recoveryStore.audits[0].Action != "hook_recovery.create" {
+		t.Fatalf("unexpected audits: %+v", recoveryStore.audits)
+	}
+}
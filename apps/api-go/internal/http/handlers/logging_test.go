@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.NewLogger(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestRequestLogger_EchoesRequestIDAndLogsRequestFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := captureLogs(t)
+
+	r := gin.New()
+	r.Use(RequestLogger(), Recovery())
+	r.GET("/api/things/:id", func(c *gin.Context) {
+		logging.SetTenantID(c.Request.Context(), "acme")
+		logging.SetActor(c.Request.Context(), "alice")
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	r.GET("/boom", func(c *gin.Context) { panic("token=abc123 leaked") })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/things/7", nil)
+	req.Header.Set(RequestIDHeader, "upstream-42")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get(RequestIDHeader); got != "upstream-42" {
+		t.Fatalf("expected caller's request id to be echoed, got %q", got)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON log line, got %q", logs.String())
+	}
+	if line["request_id"] != "upstream-42" || line["tenant_id"] != "acme" || line["actor"] != "alice" || line["route"] != "/api/things/:id" {
+		t.Fatalf("unexpected request log: %v", line)
+	}
+
+	logs.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set(RequestIDHeader, "bad id with spaces")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	generated := w.Header().Get(RequestIDHeader)
+	if w.Code != http.StatusInternalServerError || generated == "" || generated == "bad id with spaces" {
+		t.Fatalf("expected 500 with a generated request id, got %d %q", w.Code, generated)
+	}
+	if bytes.Contains(logs.Bytes(), []byte("abc123")) || !bytes.Contains(logs.Bytes(), []byte(generated)) {
+		t.Fatalf("expected redacted panic log tagged with the request id, got %s", logs.String())
+	}
+}
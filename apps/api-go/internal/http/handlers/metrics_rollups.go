@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type MetricsRollupStore interface {
+	AcquireWorkerLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	ReleaseWorkerLease(ctx context.Context, name string, holder string) error
+	CompactMetricsRollups(ctx context.Context, now time.Time) (int, error)
+}
+
+// MetricsRollupHandler compacts the metrics rollups on whichever replica holds
+// the rollup lease; compaction runs must not overlap.
+type MetricsRollupHandler struct {
+	Store    MetricsRollupStore
+	Holder   string
+	LeaseTTL time.Duration
+	Now      func() time.Time
+}
+
+const metricsRollupLeaseName = "metrics_rollups"
+
+func NewMetricsRollupHandler(s MetricsRollupStore) *MetricsRollupHandler {
+	return &MetricsRollupHandler{
+		Store:    s,
+		Holder:   newWorkerLeaseHolder(),
+		LeaseTTL: 5 * time.Minute,
+		Now:      time.Now,
+	}
+}
+
+// Compact returns the number of minute rollups written; replicas without the
+// lease report zero.
+func (h *MetricsRollupHandler) Compact(ctx context.Context) (int, int, error) {
+	if h.Store == nil {
+		return 0, 0, fmt.Errorf("metrics rollups are not configured")
+	}
+	leader, err := h.Store.AcquireWorkerLease(ctx, metricsRollupLeaseName, h.Holder, h.LeaseTTL)
+	if err != nil || !leader {
+		return 0, 0, err
+	}
+	now := time.Now()
+	if h.Now != nil {
+		now = h.Now()
+	}
+	written, err := h.Store.CompactMetricsRollups(ctx, now)
+	return written, written, err
+}
+
+func (h *MetricsRollupHandler) ReleaseLease(ctx context.Context) error {
+	if h.Store == nil {
+		return nil
+	}
+	return h.Store.ReleaseWorkerLease(ctx, metricsRollupLeaseName, h.Holder)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type mockMetricsRollupStore struct {
+	leader    bool
+	compacted int
+	released  bool
+}
+
+func (m *mockMetricsRollupStore) AcquireWorkerLease(_ context.Context, _ string, _ string, _ time.Duration) (bool, error) {
+	return m.leader, nil
+}
+
+func (m *mockMetricsRollupStore) ReleaseWorkerLease(_ context.Context, _ string, _ string) error {
+	m.released = true
+	return nil
+}
+
+func (m *mockMetricsRollupStore) CompactMetricsRollups(_ context.Context, _ time.Time) (int, error) {
+	m.compacted++
+	return 42, nil
+}
+
+func TestMetricsRollupCompactOnlyOnLeaseHolder(t *testing.T) {
+	follower := &mockMetricsRollupStore{}
+	if written, _, err := NewMetricsRollupHandler(follower).Compact(context.Background()); err != nil || written != 0 || follower.compacted != 0 {
+		t.Fatalf("expected replica without the lease to skip compaction, got written=%d err=%v", written, err)
+	}
+
+	leader := &mockMetricsRollupStore{leader: true}
+	h := NewMetricsRollupHandler(leader)
+	if written, _, err := h.Compact(context.Background()); err != nil || written != 42 || leader.compacted != 1 {
+		t.Fatalf("expected lease holder to compact, got written=%d err=%v", written, err)
+	}
+	if err := h.ReleaseLease(context.Background()); err != nil || !leader.released {
+		t.Fatalf("expected lease release, err=%v", err)
+	}
+}
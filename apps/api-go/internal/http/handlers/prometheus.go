@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusHandler serves the metrics registry in the Prometheus text format.
+// When Token is set, scrapers must send it as a bearer token; it is separate from
+// the user JWTs so a scraper never holds API credentials.
+type PrometheusHandler struct {
+	Token   string
+	handler http.Handler
+}
+
+func NewPrometheusHandler(gatherer prometheus.Gatherer, token string) *PrometheusHandler {
+	return &PrometheusHandler{Token: token, handler: promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})}
+}
+
+func (h *PrometheusHandler) Serve(c *gin.Context) {
+	if h.Token != "" {
+		got := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if subtle.ConstantTimeCompare([]byte(got), []byte(h.Token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"ok": false, "message": "invalid metrics token"})
+			return
+		}
+	}
+	h.handler.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RepositoryListStore interface {
+	ListRepositories(ctx context.Context, limit int, offset int, source string, query string, activeOnly bool) ([]store.RepositoryRecord, int64, error)
+}
+
+// RepositoriesHandler lists the repository registry kept up to date by GitHub
+// installation and repository webhooks.
+type RepositoriesHandler struct {
+	Store RepositoryListStore
+}
+
+type listRepositoriesResponse struct {
+	OK         bool                     `json:"ok"`
+	Items      []store.RepositoryRecord `json:"items"`
+	Limit      int                      `json:"limit"`
+	Offset     int                      `json:"offset"`
+	Total      int64                    `json:"total"`
+	Source     string                   `json:"source,omitempty"`
+	Query      string                   `json:"q,omitempty"`
+	ActiveOnly bool                     `json:"active_only"`
+}
+
+func NewRepositoriesHandler(s RepositoryListStore) *RepositoriesHandler {
+	return &RepositoriesHandler{Store: s}
+}
+
+func (h *RepositoriesHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(500, gin.H{"ok": false, "message": "repository store is not configured"})
+		return
+	}
+
+	limit := parseIntOrDefault(c.Query("limit"), 50)
+	offset := parseIntOrDefault(c.Query("offset"), 0)
+	source := strings.ToLower(strings.TrimSpace(c.Query("source")))
+	query := strings.TrimSpace(c.Query("q"))
+	activeOnly := strings.EqualFold(c.DefaultQuery("active_only", "true"), "true")
+
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, total, err := h.Store.ListRepositories(ctx, limit, offset, source, query, activeOnly)
+	if err != nil {
+		c.JSON(500, gin.H{"ok": false, "message": fmt.Sprintf("list repositories failed: %v", err)})
+		return
+	}
+
+	c.JSON(200, listRepositoriesResponse{
+		OK:         true,
+		Items:      items,
+		Limit:      limit,
+		Offset:     offset,
+		Total:      total,
+		Source:     source,
+		Query:      query,
+		ActiveOnly: activeOnly,
+	})
+}
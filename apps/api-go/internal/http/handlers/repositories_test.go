@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockRepositoryListStore struct {
+	items          []store.RepositoryRecord
+	lastLimit      int
+	lastSource     string
+	lastQuery      string
+	lastActiveOnly bool
+}
+
+func (m *mockRepositoryListStore) ListRepositories(_ context.Context, limit int, _ int, source string, query string, activeOnly bool) ([]store.RepositoryRecord, int64, error) {
+	m.lastLimit = limit
+	m.lastSource = source
+	m.lastQuery = query
+	m.lastActiveOnly = activeOnly
+	return m.items, int64(len(m.items)), nil
+}
+
+func TestRepositoriesList_PassesFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockStore := &mockRepositoryListStore{items: []store.RepositoryRecord{{ID: 1, Source: "github", ExternalID: 100, FullName: "acme/api", IsActive: true}}}
+	h := NewRepositoriesHandler(mockStore)
+	r := gin.New()
+	r.GET("/repositories", h.List)
+
+	req := httptest.NewRequest(http.MethodGet, "/repositories?source=GitHub&q=acme&active_only=false&limit=500", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if mockStore.lastSource != "github" || mockStore.lastQuery != "acme" || mockStore.lastActiveOnly || mockStore.lastLimit != 200 {
+		t.Fatalf("unexpected filters: %+v", mockStore)
+	}
+	var resp listRepositoriesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Items) != 1 || resp.Items[0].FullName != "acme/api" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,517 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledJobStore interface {
+	ListScheduledJobs(ctx context.Context, limit int, offset int) ([]store.ScheduledJobRecord, int64, error)
+	GetScheduledJobByID(ctx context.Context, id int64) (store.ScheduledJobRecord, error)
+	CreateScheduledJob(ctx context.Context, job store.ScheduledJobRecord) (int64, error)
+	UpdateScheduledJobActive(ctx context.Context, id int64, isActive bool, nextRunAt time.Time) error
+	ListDueScheduledJobs(ctx context.Context, now time.Time, limit int) ([]store.ScheduledJobRecord, error)
+	ClaimScheduledJob(ctx context.Context, id int64, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error)
+	SaveScheduledJobRun(ctx context.Context, run store.ScheduledJobRunRecord) (int64, error)
+	ListScheduledJobRuns(ctx context.Context, jobID int64, limit int, offset int) ([]store.ScheduledJobRunRecord, int64, error)
+	ListScheduledJobItems(ctx context.Context, jobID int64) (map[int]bool, error)
+	SaveScheduledJobItem(ctx context.Context, jobID int64, number int) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type ScheduledJobExecutor interface {
+	SearchOpenItems(ctx context.Context, q service.GitHubItemQuery) ([]service.GitHubIssueItem, error)
+	ListIssueCommentsSince(ctx context.Context, repositoryFullName string, number int, since time.Time) ([]service.GitHubIssueComment, error)
+	AddLabel(ctx context.Context, repositoryFullName string, number int, label string) error
+	AddComment(ctx context.Context, repositoryFullName string, number int, comment string) error
+	CloseIssue(ctx context.Context, repositoryFullName string, number int) error
+}
+
+type ScheduledJobsHandler struct {
+	Store    ScheduledJobStore
+	Executor ScheduledJobExecutor
+	Now      func() time.Time
+}
+
+type createScheduledJobRequest struct {
+	Name               string `json:"name"`
+	Schedule           string `json:"schedule"`
+	RepositoryFullName string `json:"repository_full_name"`
+	TargetKind         string `json:"target_kind"`
+	Label              string `json:"label"`
+	InactiveDays       int    `json:"inactive_days"`
+	InactiveBy         string `json:"inactive_by"`
+	CommentBody        string `json:"comment_body"`
+	AddLabel           string `json:"add_label"`
+	CloseItem          bool   `json:"close_item"`
+	MaxItems           int    `json:"max_items"`
+	IsActive           bool   `json:"is_active"`
+}
+
+type updateScheduledJobActiveRequest struct {
+	IsActive bool `json:"is_active"`
+}
+
+const scheduledJobsDueBatch = 20
+
+// A run pages through search results until max_items items pass its filters.
+// The search API serves at most 1000 results per query.
+const (
+	scheduledJobSearchPageSize = 100
+	scheduledJobSearchPages    = 10
+)
+
+func NewScheduledJobsHandler(store ScheduledJobStore, executor ScheduledJobExecutor) *ScheduledJobsHandler {
+	return &ScheduledJobsHandler{Store: store, Executor: executor, Now: time.Now}
+}
+
+func (h *ScheduledJobsHandler) now() time.Time {
+	if h.Now == nil {
+		return time.Now().UTC()
+	}
+	return h.Now().UTC()
+}
+
+func (h *ScheduledJobsHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "scheduled job store is not configured"})
+		return
+	}
+
+	limit := parseIntOrDefault(c.Query("limit"), 20)
+	offset := parseIntOrDefault(c.Query("offset"), 0)
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, total, err := h.Store.ListScheduledJobs(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list scheduled jobs failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+func (h *ScheduledJobsHandler) Create(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "scheduled job store is not configured"})
+		return
+	}
+
+	var req createScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Schedule = strings.TrimSpace(req.Schedule)
+	req.RepositoryFullName = strings.TrimSpace(req.RepositoryFullName)
+	req.TargetKind = strings.TrimSpace(req.TargetKind)
+	req.Label = strings.TrimSpace(req.Label)
+	req.CommentBody = strings.TrimSpace(req.CommentBody)
+	req.AddLabel = strings.TrimSpace(req.AddLabel)
+	req.InactiveBy = strings.TrimSpace(req.InactiveBy)
+	if req.InactiveBy == "" {
+		req.InactiveBy = store.ScheduledJobInactiveByActivity
+	}
+
+	if req.Name == "" || req.Schedule == "" || req.RepositoryFullName == "" || req.TargetKind == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "name, schedule, repository_full_name, target_kind are required"})
+		return
+	}
+	schedule, err := service.ParseCronSchedule(req.Schedule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": fmt.Sprintf("invalid schedule: %v", err)})
+		return
+	}
+	if parts := strings.Split(req.RepositoryFullName, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "repository_full_name must be owner/repo"})
+		return
+	}
+	if req.TargetKind != "issues" && req.TargetKind != "pull_request" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "target_kind must be issues or pull_request"})
+		return
+	}
+	if req.InactiveDays < 0 || req.InactiveDays > 3650 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "inactive_days must be between 0 and 3650"})
+		return
+	}
+	if req.InactiveBy != store.ScheduledJobInactiveByActivity && req.InactiveBy != store.ScheduledJobInactiveByAuthor {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "inactive_by must be activity or author"})
+		return
+	}
+	if req.CommentBody == "" && req.AddLabel == "" && !req.CloseItem {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "at least one of comment_body, add_label, close_item is required"})
+		return
+	}
+	if req.MaxItems == 0 {
+		req.MaxItems = 30
+	}
+	if req.MaxItems < 1 || req.MaxItems > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "max_items must be between 1 and 100"})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	nextRunAt := schedule.Next(h.now())
+	id, err := h.Store.CreateScheduledJob(ctx, store.ScheduledJobRecord{
+		Name:               req.Name,
+		Schedule:           req.Schedule,
+		RepositoryFullName: req.RepositoryFullName,
+		TargetKind:         req.TargetKind,
+		Label:              req.Label,
+		InactiveDays:       req.InactiveDays,
+		InactiveBy:         req.InactiveBy,
+		CommentBody:        req.CommentBody,
+		AddLabel:           req.AddLabel,
+		CloseItem:          req.CloseItem,
+		MaxItems:           req.MaxItems,
+		IsActive:           req.IsActive,
+		CreatedBy:          actor,
+		NextRunAt:          nextRunAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("create scheduled job failed: %v", err)})
+		return
+	}
+
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "scheduled_job.create",
+		Target:   "scheduled_job",
+		TargetID: fmt.Sprintf("%d", id),
+		Payload:  fmt.Sprintf(`{"schedule":"%s","repository_full_name":"%s","target_kind":"%s","is_active":%t}`, req.Schedule, req.RepositoryFullName, req.TargetKind, req.IsActive),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": id, "next_run_at": nextRunAt})
+}
+
+func (h *ScheduledJobsHandler) UpdateActive(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "scheduled job store is not configured"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid scheduled job id"})
+		return
+	}
+
+	var req updateScheduledJobActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	job, err := h.Store.GetScheduledJobByID(ctx, id)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "scheduled job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get scheduled job failed: %v", err)})
+		return
+	}
+
+	// Resuming a paused job skips any activations missed while it was paused.
+	nextRunAt := job.NextRunAt
+	if schedule, err := service.ParseCronSchedule(job.Schedule); err == nil {
+		nextRunAt = schedule.Next(h.now())
+	}
+	if err := h.Store.UpdateScheduledJobActive(ctx, id, req.IsActive, nextRunAt); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "scheduled job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update scheduled job active failed: %v", err)})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "scheduled_job.update_active",
+		Target:   "scheduled_job",
+		TargetID: fmt.Sprintf("%d", id),
+		Payload:  fmt.Sprintf(`{"is_active":%t}`, req.IsActive),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true, "next_run_at": nextRunAt})
+}
+
+func (h *ScheduledJobsHandler) RunNow(c *gin.Context) {
+	if h.Store == nil || h.Executor == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "scheduled job runner is not configured"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid scheduled job id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	job, err := h.Store.GetScheduledJobByID(ctx, id)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "scheduled job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("get scheduled job failed: %v", err)})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+	run := h.runJob(ctx, job, "manual", actor)
+	c.JSON(http.StatusOK, gin.H{"ok": run.Status == "success", "run": run})
+}
+
+func (h *ScheduledJobsHandler) ListRuns(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "scheduled job store is not configured"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid scheduled job id"})
+		return
+	}
+	limit := parseIntOrDefault(c.Query("limit"), 20)
+	offset := parseIntOrDefault(c.Query("offset"), 0)
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, total, err := h.Store.ListScheduledJobRuns(ctx, id, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list scheduled job runs failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// RunDueJobs claims and runs every job whose next_run_at has passed. It returns
+// the number of jobs that ran and the number that were due.
+func (h *ScheduledJobsHandler) RunDueJobs(ctx context.Context) (int, int, error) {
+	if h.Store == nil || h.Executor == nil {
+		return 0, 0, fmt.Errorf("scheduled job runner is not configured")
+	}
+
+	now := h.now()
+	jobs, err := h.Store.ListDueScheduledJobs(ctx, now, scheduledJobsDueBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ran := 0
+	for _, job := range jobs {
+		jobCtx := tenantctx.WithTenantID(ctx, job.TenantID)
+		schedule, err := service.ParseCronSchedule(job.Schedule)
+		if err != nil {
+			slog.WarnContext(jobCtx, "scheduled job has an invalid schedule; deactivating it", "job_id", job.ID, "schedule", job.Schedule, "error", err)
+			_ = h.Store.UpdateScheduledJobActive(jobCtx, job.ID, false, job.NextRunAt)
+			continue
+		}
+		claimed, err := h.Store.ClaimScheduledJob(jobCtx, job.ID, job.NextRunAt, schedule.Next(now))
+		if err != nil {
+			return ran, len(jobs), err
+		}
+		if !claimed {
+			continue
+		}
+		h.runJob(jobCtx, job, "schedule", "scheduler")
+		ran++
+	}
+	return ran, len(jobs), nil
+}
+
+func (h *ScheduledJobsHandler) runJob(ctx context.Context, job store.ScheduledJobRecord, trigger string, actor string) store.ScheduledJobRunRecord {
+	run := store.ScheduledJobRunRecord{
+		JobID:     job.ID,
+		Trigger:   trigger,
+		Status:    "success",
+		StartedAt: h.now(),
+	}
+
+	query := service.GitHubItemQuery{
+		RepositoryFullName: job.RepositoryFullName,
+		TargetKind:         job.TargetKind,
+		Label:              job.Label,
+		PerPage:            scheduledJobSearchPageSize,
+	}
+	// Without activity of any kind since the cutoff there is no author reply
+	// to look for, so only the author mode checks comments.
+	var replyCutoff time.Time
+	if job.InactiveDays > 0 {
+		cutoff := run.StartedAt.AddDate(0, 0, -job.InactiveDays)
+		if job.InactiveBy == store.ScheduledJobInactiveByAuthor {
+			query.CreatedBefore = cutoff
+			replyCutoff = cutoff
+		} else {
+			query.UpdatedBefore = cutoff
+		}
+	}
+	maxItems := job.MaxItems
+	if maxItems <= 0 {
+		maxItems = scheduledJobSearchPageSize
+	}
+
+	var firstErr error
+	acted, err := h.Store.ListScheduledJobItems(ctx, job.ID)
+	if err != nil {
+		firstErr = err
+	}
+	for page := 1; err == nil && page <= scheduledJobSearchPages && run.Matched < maxItems; page++ {
+		query.Page = page
+		var items []service.GitHubIssueItem
+		items, err = h.Executor.SearchOpenItems(ctx, query)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			break
+		}
+		for _, item := range items {
+			if run.Matched >= maxItems {
+				break
+			}
+			if acted[item.Number] {
+				continue
+			}
+			if !replyCutoff.IsZero() {
+				replied, err := h.authorRepliedSince(ctx, job.RepositoryFullName, item, replyCutoff)
+				if err != nil {
+					run.Matched++
+					run.Failed++
+					if firstErr == nil {
+						firstErr = fmt.Errorf("#%d: %w", item.Number, err)
+					}
+					continue
+				}
+				if replied {
+					continue
+				}
+			}
+			run.Matched++
+			if err := h.applyJobActions(ctx, job, item.Number); err != nil {
+				run.Failed++
+				if firstErr == nil {
+					firstErr = fmt.Errorf("#%d: %w", item.Number, err)
+				}
+				continue
+			}
+			run.Processed++
+			if err := h.Store.SaveScheduledJobItem(ctx, job.ID, item.Number); err != nil {
+				slog.WarnContext(ctx, "failed to record scheduled job item", "job_id", job.ID, "number", item.Number, "error", err)
+			}
+		}
+		if len(items) < scheduledJobSearchPageSize {
+			break
+		}
+	}
+	if firstErr != nil {
+		run.Status = "partial"
+		if run.Processed == 0 {
+			run.Status = "failed"
+		}
+		run.ErrorMessage = firstErr.Error()
+	}
+	run.FinishedAt = h.now()
+
+	if id, err := h.Store.SaveScheduledJobRun(ctx, run); err == nil {
+		run.ID = id
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "scheduled_job.run",
+		Target:   "scheduled_job",
+		TargetID: fmt.Sprintf("%d", job.ID),
+		Payload:  fmt.Sprintf(`{"trigger":"%s","status":"%s","matched":%d,"processed":%d,"failed":%d}`, trigger, run.Status, run.Matched, run.Processed, run.Failed),
+	})
+	return run
+}
+
+// authorRepliedSince reports whether the item's author commented at or after
+// cutoff. Comments by anyone else, including the job's own, do not count.
+func (h *ScheduledJobsHandler) authorRepliedSince(ctx context.Context, repositoryFullName string, item service.GitHubIssueItem, cutoff time.Time) (bool, error) {
+	comments, err := h.Executor.ListIssueCommentsSince(ctx, repositoryFullName, item.Number, cutoff)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if strings.EqualFold(comment.AuthorLogin, item.AuthorLogin) && !comment.CreatedAt.Before(cutoff) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (h *ScheduledJobsHandler) applyJobActions(ctx context.Context, job store.ScheduledJobRecord, number int) error {
+	if job.CommentBody != "" {
+		if err := h.Executor.AddComment(ctx, job.RepositoryFullName, number, job.CommentBody); err != nil {
+			return err
+		}
+	}
+	if job.AddLabel != "" {
+		if err := h.Executor.AddLabel(ctx, job.RepositoryFullName, number, job.AddLabel); err != nil {
+			return err
+		}
+	}
+	if job.CloseItem {
+		if err := h.Executor.CloseIssue(ctx, job.RepositoryFullName, number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
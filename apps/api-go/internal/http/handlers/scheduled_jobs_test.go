@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockScheduledJobStore struct {
+	jobs        map[int64]store.ScheduledJobRecord
+	due         []store.ScheduledJobRecord
+	created     []store.ScheduledJobRecord
+	runs        []store.ScheduledJobRunRecord
+	claimed     []int64
+	claimTenant []string
+	rejectClaim bool
+	audits      []store.AuditLogRecord
+	acted       map[int64]map[int]bool
+}
+
+func (m *mockScheduledJobStore) ListScheduledJobs(_ context.Context, _ int, _ int) ([]store.ScheduledJobRecord, int64, error) {
+	items := make([]store.ScheduledJobRecord, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		items = append(items, job)
+	}
+	return items, int64(len(items)), nil
+}
+
+func (m *mockScheduledJobStore) GetScheduledJobByID(_ context.Context, id int64) (store.ScheduledJobRecord, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return store.ScheduledJobRecord{}, fmt.Errorf("scheduled job not found")
+	}
+	return job, nil
+}
+
+func (m *mockScheduledJobStore) CreateScheduledJob(_ context.Context, job store.ScheduledJobRecord) (int64, error) {
+	m.created = append(m.created, job)
+	return int64(len(m.created)), nil
+}
+
+func (m *mockScheduledJobStore) UpdateScheduledJobActive(_ context.Context, id int64, isActive bool, nextRunAt time.Time) error {
+	job, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("scheduled job not found")
+	}
+	job.IsActive = isActive
+	job.NextRunAt = nextRunAt
+	m.jobs[id] = job
+	return nil
+}
+
+func (m *mockScheduledJobStore) ListDueScheduledJobs(_ context.Context, _ time.Time, _ int) ([]store.ScheduledJobRecord, error) {
+	return m.due, nil
+}
+
+func (m *mockScheduledJobStore) ClaimScheduledJob(ctx context.Context, id int64, _ time.Time, _ time.Time) (bool, error) {
+	if m.rejectClaim {
+		return false, nil
+	}
+	tenantID, _ := tenantctx.FromContext(ctx)
+	m.claimed = append(m.claimed, id)
+	m.claimTenant = append(m.claimTenant, tenantID)
+	return true, nil
+}
+
+func (m *mockScheduledJobStore) SaveScheduledJobRun(_ context.Context, run store.ScheduledJobRunRecord) (int64, error) {
+	m.runs = append(m.runs, run)
+	return int64(len(m.runs)), nil
+}
+
+func (m *mockScheduledJobStore) ListScheduledJobRuns(_ context.Context, jobID int64, _ int, _ int) ([]store.ScheduledJobRunRecord, int64, error) {
+	items := make([]store.ScheduledJobRunRecord, 0)
+	for _, run := range m.runs {
+		if run.JobID == jobID {
+			items = append(items, run)
+		}
+	}
+	return items, int64(len(items)), nil
+}
+
+func (m *mockScheduledJobStore) ListScheduledJobItems(_ context.Context, jobID int64) (map[int]bool, error) {
+	return m.acted[jobID], nil
+}
+
+func (m *mockScheduledJobStore) SaveScheduledJobItem(_ context.Context, jobID int64, number int) error {
+	if m.acted == nil {
+		m.acted = map[int64]map[int]bool{}
+	}
+	if m.acted[jobID] == nil {
+		m.acted[jobID] = map[int]bool{}
+	}
+	m.acted[jobID][number] = true
+	return nil
+}
+
+func (m *mockScheduledJobStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+type mockScheduledJobExecutor struct {
+	items     []service.GitHubIssueItem
+	pages     [][]service.GitHubIssueItem
+	lastQuery service.GitHubItemQuery
+	replies   map[int][]service.GitHubIssueComment
+	sinces    []time.Time
+	comments  []int
+	labels    []int
+	closed    []int
+	failOn    int
+}
+
+func (m *mockScheduledJobExecutor) SearchOpenItems(_ context.Context, q service.GitHubItemQuery) ([]service.GitHubIssueItem, error) {
+	m.lastQuery = q
+	if m.pages != nil {
+		if q.Page > len(m.pages) {
+			return nil, nil
+		}
+		return m.pages[q.Page-1], nil
+	}
+	return m.items, nil
+}
+
+func (m *mockScheduledJobExecutor) ListIssueCommentsSince(_ context.Context, _ string, number int, since time.Time) ([]service.GitHubIssueComment, error) {
+	m.sinces = append(m.sinces, since)
+	return m.replies[number], nil
+}
+
+func (m *mockScheduledJobExecutor) AddLabel(_ context.Context, _ string, number int, _ string) error {
+	m.labels = append(m.labels, number)
+	return nil
+}
+
+func (m *mockScheduledJobExecutor) AddComment(_ context.Context, _ string, number int, _ string) error {
+	if number == m.failOn {
+		return fmt.Errorf("github api status: 403")
+	}
+	m.comments = append(m.comments, number)
+	return nil
+}
+
+func (m *mockScheduledJobExecutor) CloseIssue(_ context.Context, _ string, number int) error {
+	m.closed = append(m.closed, number)
+	return nil
+}
+
+func TestScheduledJobsCreate_ComputesNextRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockScheduledJobStore{}
+	h := NewScheduledJobsHandler(mockStore, &mockScheduledJobExecutor{})
+	h.Now = func() time.Time { return time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC) }
+	r := gin.New()
+	r.POST("/scheduled-jobs", h.Create)
+
+	body := `{"name":"close needs-info","schedule":"0 3 * * *","repository_full_name":"owner/repo","target_kind":"issues","label":"needs-info","inactive_days":14,"comment_body":"Closing due to inactivity","close_item":true,"is_active":true}`
+	req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if len(mockStore.created) != 1 {
+		t.Fatalf("expected 1 created job, got %d", len(mockStore.created))
+	}
+	job := mockStore.created[0]
+	want := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC)
+	if !job.NextRunAt.Equal(want) {
+		t.Fatalf("expected next run %v, got %v", want, job.NextRunAt)
+	}
+	if job.MaxItems != 30 {
+		t.Fatalf("expected default max_items=30, got %d", job.MaxItems)
+	}
+	if len(mockStore.audits) != 1 || mockStore.audits[0].Action != "scheduled_job.create" {
+		t.Fatalf("expected scheduled_job.create audit log, got %+v", mockStore.audits)
+	}
+}
+
+func TestScheduledJobsCreate_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewScheduledJobsHandler(&mockScheduledJobStore{}, &mockScheduledJobExecutor{})
+	r := gin.New()
+	r.POST("/scheduled-jobs", h.Create)
+
+	cases := []string{
+		`{"name":"x","schedule":"not a cron","repository_full_name":"owner/repo","target_kind":"issues","close_item":true}`,
+		`{"name":"x","schedule":"@daily","repository_full_name":"repo","target_kind":"issues","close_item":true}`,
+		`{"name":"x","schedule":"@daily","repository_full_name":"owner/repo","target_kind":"push","close_item":true}`,
+		`{"name":"x","schedule":"@daily","repository_full_name":"owner/repo","target_kind":"issues"}`,
+		`{"name":"x","schedule":"@daily","repository_full_name":"owner/repo","target_kind":"issues","close_item":true,"max_items":500}`,
+		`{"name":"x","schedule":"@daily","repository_full_name":"owner/repo","target_kind":"issues","close_item":true,"inactive_by":"label"}`,
+	}
+	for _, body := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d, body=%s", body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScheduledJobsUpdateActive_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewScheduledJobsHandler(&mockScheduledJobStore{jobs: map[int64]store.ScheduledJobRecord{}}, &mockScheduledJobExecutor{})
+	r := gin.New()
+	r.PATCH("/scheduled-jobs/:id/active", h.UpdateActive)
+
+	req := httptest.NewRequest(http.MethodPatch, "/scheduled-jobs/7/active", strings.NewReader(`{"is_active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestScheduledJobsRunNow_RecordsPartialRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockScheduledJobStore{jobs: map[int64]store.ScheduledJobRecord{
+		3: {ID: 3, Schedule: "@daily", RepositoryFullName: "owner/repo", TargetKind: "issues", Label: "needs-info", InactiveDays: 14, CommentBody: "bye", CloseItem: true, MaxItems: 10},
+	}}
+	executor := &mockScheduledJobExecutor{
+		items:  []service.GitHubIssueItem{{Number: 11}, {Number: 12}},
+		failOn: 12,
+	}
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	h := NewScheduledJobsHandler(mockStore, executor)
+	h.Now = func() time.Time { return now }
+	r := gin.New()
+	r.POST("/scheduled-jobs/:id/run", h.RunNow)
+
+	req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs/3/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if !executor.lastQuery.UpdatedBefore.Equal(now.AddDate(0, 0, -14)) || !executor.lastQuery.CreatedBefore.IsZero() || executor.lastQuery.Label != "needs-info" || executor.lastQuery.Page != 1 {
+		t.Fatalf("unexpected search query: %+v", executor.lastQuery)
+	}
+	if len(executor.closed) != 1 || executor.closed[0] != 11 {
+		t.Fatalf("expected only #11 closed, got %v", executor.closed)
+	}
+
+	var resp struct {
+		OK  bool                        `json:"ok"`
+		Run store.ScheduledJobRunRecord `json:"run"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.OK || resp.Run.Status != "partial" || resp.Run.Matched != 2 || resp.Run.Processed != 1 || resp.Run.Failed != 1 || resp.Run.Trigger != "manual" {
+		t.Fatalf("unexpected run response: %s", w.Body.String())
+	}
+	if len(mockStore.runs) != 1 {
+		t.Fatalf("expected run history to be saved, got %d", len(mockStore.runs))
+	}
+}
+
+func TestScheduledJobsRunNow_SkipsItemsWithAuthorReply(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockScheduledJobStore{jobs: map[int64]store.ScheduledJobRecord{
+		4: {ID: 4, Schedule: "@daily", RepositoryFullName: "owner/repo", TargetKind: "issues", Label: "needs-info", InactiveDays: 14, InactiveBy: store.ScheduledJobInactiveByAuthor, CommentBody: "closing", CloseItem: true, MaxItems: 10},
+	}}
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	executor := &mockScheduledJobExecutor{
+		items: []service.GitHubIssueItem{
+			{Number: 21, AuthorLogin: "alice"},
+			{Number: 22, AuthorLogin: "bob"},
+			{Number: 23, AuthorLogin: "carol"},
+		},
+		replies: map[int][]service.GitHubIssueComment{
+			// Only maintainer and bot comments since the cutoff: still waiting on the author.
+			21: {{AuthorLogin: "maintainer", CreatedAt: now.AddDate(0, 0, -3)}, {AuthorLogin: "firewall-bot", CreatedAt: now.AddDate(0, 0, -1)}},
+			// The author replied within the window.
+			22: {{AuthorLogin: "Bob", CreatedAt: now.AddDate(0, 0, -2)}},
+		},
+	}
+	h := NewScheduledJobsHandler(mockStore, executor)
+	h.Now = func() time.Time { return now }
+	r := gin.New()
+	r.POST("/scheduled-jobs/:id/run", h.RunNow)
+
+	req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs/4/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if !executor.lastQuery.CreatedBefore.Equal(now.AddDate(0, 0, -14)) || !executor.lastQuery.UpdatedBefore.IsZero() {
+		t.Fatalf("expected the author mode to search by creation date, got %+v", executor.lastQuery)
+	}
+	if len(executor.sinces) != 3 || !executor.sinces[0].Equal(now.AddDate(0, 0, -14)) {
+		t.Fatalf("expected comments checked since the cutoff, got %v", executor.sinces)
+	}
+	if len(executor.closed) != 2 || executor.closed[0] != 21 || executor.closed[1] != 23 {
+		t.Fatalf("expected #21 and #23 closed, got %v", executor.closed)
+	}
+	if len(mockStore.runs) != 1 || mockStore.runs[0].Matched != 2 || mockStore.runs[0].Processed != 2 {
+		t.Fatalf("unexpected run: %+v", mockStore.runs)
+	}
+}
+
+func TestScheduledJobsRunNow_PagesPastItemsTheAuthorRepliedTo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockScheduledJobStore{jobs: map[int64]store.ScheduledJobRecord{
+		6: {ID: 6, Schedule: "@daily", RepositoryFullName: "owner/repo", TargetKind: "issues", InactiveDays: 14, InactiveBy: store.ScheduledJobInactiveByAuthor, CloseItem: true, MaxItems: 2},
+	}}
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	firstPage := make([]service.GitHubIssueItem, 0, scheduledJobSearchPageSize)
+	replies := map[int][]service.GitHubIssueComment{}
+	for n := 1; n <= scheduledJobSearchPageSize; n++ {
+		firstPage = append(firstPage, service.GitHubIssueItem{Number: n, AuthorLogin: "alice"})
+		replies[n] = []service.GitHubIssueComment{{AuthorLogin: "alice", CreatedAt: now.AddDate(0, 0, -1)}}
+	}
+	executor := &mockScheduledJobExecutor{
+		pages:   [][]service.GitHubIssueItem{firstPage, {{Number: 201}, {Number: 202}, {Number: 203}}},
+		replies: replies,
+	}
+	h := NewScheduledJobsHandler(mockStore, executor)
+	h.Now = func() time.Time { return now }
+	r := gin.New()
+	r.POST("/scheduled-jobs/:id/run", h.RunNow)
+
+	req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs/6/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if executor.lastQuery.Page != 2 {
+		t.Fatalf("expected the second page to be searched, got page %d", executor.lastQuery.Page)
+	}
+	if len(executor.closed) != 2 || executor.closed[0] != 201 || executor.closed[1] != 202 {
+		t.Fatalf("expected #201 and #202 closed, got %v", executor.closed)
+	}
+}
+
+func TestScheduledJobsRunNow_CommentsOnEachItemOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockScheduledJobStore{jobs: map[int64]store.ScheduledJobRecord{
+		5: {ID: 5, Schedule: "@daily", RepositoryFullName: "owner/repo", TargetKind: "pull_request", InactiveDays: 30, CommentBody: "Is this still needed?", MaxItems: 10},
+	}}
+	executor := &mockScheduledJobExecutor{items: []service.GitHubIssueItem{{Number: 31}, {Number: 32}}}
+	h := NewScheduledJobsHandler(mockStore, executor)
+	r := gin.New()
+	r.POST("/scheduled-jobs/:id/run", h.RunNow)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/scheduled-jobs/5/run", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+		}
+	}
+	if len(executor.comments) != 2 {
+		t.Fatalf("expected each item commented on once, got %v", executor.comments)
+	}
+	if len(mockStore.runs) != 2 || mockStore.runs[1].Matched != 0 || mockStore.runs[1].Status != "success" {
+		t.Fatalf("expected the second run to match nothing, got %+v", mockStore.runs)
+	}
+}
+
+func TestScheduledJobsRunDueJobs_ClaimsPerTenant(t *testing.T) {
+	mockStore := &mockScheduledJobStore{due: []store.ScheduledJobRecord{
+		{ID: 1, TenantID: "team-a", Schedule: "@hourly", RepositoryFullName: "owner/repo", TargetKind: "pull_request", AddLabel: "stale", MaxItems: 5},
+		{ID: 2, TenantID: "team-b", Schedule: "@hourly", RepositoryFullName: "owner/other", TargetKind: "issues", AddLabel: "stale", MaxItems: 5},
+	}}
+	executor := &mockScheduledJobExecutor{items: []service.GitHubIssueItem{{Number: 5}}}
+	h := NewScheduledJobsHandler(mockStore, executor)
+
+	ran, due, err := h.RunDueJobs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if ran != 2 || due != 2 {
+		t.Fatalf("expected ran=2 due=2, got ran=%d due=%d", ran, due)
+	}
+	if strings.Join(mockStore.claimTenant, ",") != "team-a,team-b" {
+		t.Fatalf("expected claims scoped by tenant, got %v", mockStore.claimTenant)
+	}
+	if len(executor.labels) != 2 || len(mockStore.runs) != 2 || mockStore.runs[0].Trigger != "schedule" {
+		t.Fatalf("unexpected execution: labels=%v runs=%+v", executor.labels, mockStore.runs)
+	}
+}
+
+func TestScheduledJobsRunDueJobs_SkipsUnclaimed(t *testing.T) {
+	mockStore := &mockScheduledJobStore{
+		due:         []store.ScheduledJobRecord{{ID: 1, Schedule: "@daily", RepositoryFullName: "owner/repo", TargetKind: "issues", CloseItem: true}},
+		rejectClaim: true,
+	}
+	executor := &mockScheduledJobExecutor{items: []service.GitHubIssueItem{{Number: 5}}}
+	h := NewScheduledJobsHandler(mockStore, executor)
+
+	ran, due, err := h.RunDueJobs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if ran != 0 || due != 1 || len(executor.closed) != 0 {
+		t.Fatalf("expected unclaimed job to be skipped, got ran=%d due=%d closed=%v", ran, due, executor.closed)
+	}
+}
@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SLOStore interface {
+	ListSLOs(ctx context.Context) ([]store.SLORecord, error)
+	ListActiveSLOs(ctx context.Context) ([]store.SLORecord, error)
+	GetSLO(ctx context.Context, id int64) (store.SLORecord, error)
+	CreateSLO(ctx context.Context, item store.SLORecord) (int64, error)
+	UpdateSLO(ctx context.Context, item store.SLORecord) error
+	DeleteSLO(ctx context.Context, id int64) error
+	CountSLODeliveries(ctx context.Context, thresholdMS int64, since []time.Time) ([]store.SLOWindowCount, error)
+	SaveAlert(ctx context.Context, alert store.AlertRecord) (int64, error)
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+// SLOHandler manages per-tenant SLOs over webhook deliveries and reports their
+// error budget and burn rates.
+type SLOHandler struct {
+	Store    SLOStore
+	Policies []service.BurnRatePolicy
+	Now      func() time.Time
+}
+
+type sloRequest struct {
+	Name               string  `json:"name"`
+	SLI                string  `json:"sli"`
+	Objective          float64 `json:"objective"`
+	LatencyThresholdMS int64   `json:"latency_threshold_ms"`
+	WindowDays         int     `json:"window_days"`
+	IsActive           *bool   `json:"is_active"`
+}
+
+const (
+	sloAlertEventType      = "slo_burn_rate"
+	sloAlertSuggestionType = "slo_alert"
+)
+
+func NewSLOHandler(store SLOStore) *SLOHandler {
+	return &SLOHandler{Store: store, Policies: service.DefaultBurnRatePolicies, Now: time.Now}
+}
+
+func (h *SLOHandler) now() time.Time {
+	if h.Now == nil {
+		return time.Now().UTC()
+	}
+	return h.Now().UTC()
+}
+
+func (h *SLOHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListSLOs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list slos failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items, "total": len(items)})
+}
+
+// StatusAll evaluates every SLO of the tenant, active or not.
+func (h *SLOHandler) StatusAll(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	items, err := h.Store.ListSLOs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list slos failed: %v", err)})
+		return
+	}
+	statuses := make([]service.SLOStatus, 0, len(items))
+	for _, item := range items {
+		status, err := h.evaluate(ctx, item)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("evaluate slo %q failed: %v", item.Name, err)})
+			return
+		}
+		statuses = append(statuses, status)
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": statuses, "evaluated_at": h.now()})
+}
+
+func (h *SLOHandler) Status(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	id, ok := parseSLOID(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	item, err := h.Store.GetSLO(ctx, id)
+	if err != nil {
+		writeSLOLookupError(c, "get slo", err)
+		return
+	}
+	status, err := h.evaluate(ctx, item)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("evaluate slo failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "item": status, "evaluated_at": h.now()})
+}
+
+func (h *SLOHandler) Create(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	item, isActive, ok := bindSLORequest(c)
+	if !ok {
+		return
+	}
+	item.IsActive = isActive == nil || *isActive
+	actor := sloActor(c)
+	item.CreatedBy = actor
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	id, err := h.Store.CreateSLO(ctx, item)
+	if err != nil {
+		if store.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "an slo with this name already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("create slo failed: %v", err)})
+		return
+	}
+
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "slo.create",
+		Target:   "slo",
+		TargetID: fmt.Sprintf("%d", id),
+		Payload:  sloAuditPayload(item),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": id})
+}
+
+func (h *SLOHandler) Update(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	id, ok := parseSLOID(c)
+	if !ok {
+		return
+	}
+	item, isActive, ok := bindSLORequest(c)
+	if !ok {
+		return
+	}
+	item.ID = id
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	current, err := h.Store.GetSLO(ctx, id)
+	if err != nil {
+		writeSLOLookupError(c, "get slo", err)
+		return
+	}
+	item.IsActive = current.IsActive
+	if isActive != nil {
+		item.IsActive = *isActive
+	}
+	if err := h.Store.UpdateSLO(ctx, item); err != nil {
+		if store.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "an slo with this name already exists"})
+			return
+		}
+		writeSLOLookupError(c, "update slo", err)
+		return
+	}
+
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    sloActor(c),
+		Action:   "slo.update",
+		Target:   "slo",
+		TargetID: fmt.Sprintf("%d", id),
+		Payload:  sloAuditPayload(item),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *SLOHandler) Delete(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "slo store is not configured"})
+		return
+	}
+	id, ok := parseSLOID(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	if err := h.Store.DeleteSLO(ctx, id); err != nil {
+		writeSLOLookupError(c, "delete slo", err)
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    sloActor(c),
+		Action:   "slo.delete",
+		Target:   "slo",
+		TargetID: fmt.Sprintf("%d", id),
+		Payload:  "{}",
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// EvaluateBurnRates checks every active SLO and records an alert for each firing
+// burn-rate policy. Alerts are keyed by SLO, policy and hour, so a burn that
+// keeps going raises one alert per hour rather than one per run.
+func (h *SLOHandler) EvaluateBurnRates(ctx context.Context) (int, int, error) {
+	if h.Store == nil {
+		return 0, 0, fmt.Errorf("slo store is not configured")
+	}
+	items, err := h.Store.ListActiveSLOs(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	hour := h.now().Truncate(time.Hour)
+	raised := 0
+	for _, item := range items {
+		tenantCtx := tenantctx.WithTenantID(ctx, item.TenantID)
+		status, err := h.evaluate(tenantCtx, item)
+		if err != nil {
+			slog.WarnContext(tenantCtx, "slo evaluation failed", "slo_id", item.ID, "error", err)
+			continue
+		}
+		for _, alert := range status.Alerts {
+			if !alert.Firing {
+				continue
+			}
+			_, err := h.Store.SaveAlert(tenantCtx, store.AlertRecord{
+				DeliveryID:         fmt.Sprintf("slo-%d-%s-%s", item.ID, alert.Policy, hour.Format("2006010215")),
+				EventType:          sloAlertEventType,
+				Action:             alert.Severity,
+				RepositoryFullName: "-",
+				SenderLogin:        "system",
+				RuleMatched:        "slo:" + item.Name,
+				SuggestionType:     sloAlertSuggestionType,
+				SuggestionValue:    alert.Policy,
+				Reason: fmt.Sprintf("%s burn rate %.2f over %s and %.2f over %s exceeds %.2f; %.1f%% of the error budget remains",
+					alert.Policy, alert.LongBurnRate, alert.LongWindow, alert.ShortBurnRate, alert.ShortWindow, alert.Threshold,
+					status.ErrorBudget.RemainingRatio*100),
+			})
+			if err != nil {
+				slog.WarnContext(tenantCtx, "save slo alert failed", "slo_id", item.ID, "policy", alert.Policy, "error", err)
+				continue
+			}
+			raised++
+		}
+	}
+	return raised, len(items), nil
+}
+
+func (h *SLOHandler) evaluate(ctx context.Context, item store.SLORecord) (service.SLOStatus, error) {
+	now := h.now()
+	windows := service.SLOEvaluationWindows(item, h.Policies)
+	since := make([]time.Time, len(windows))
+	for i, d := range windows {
+		since[i] = now.Add(-d)
+	}
+	counts, err := h.Store.CountSLODeliveries(ctx, item.LatencyThresholdMS, since)
+	if err != nil {
+		return service.SLOStatus{}, err
+	}
+	byWindow := make(map[time.Duration]store.SLOWindowCount, len(windows))
+	for i, d := range windows {
+		if i < len(counts) {
+			byWindow[d] = counts[i]
+		}
+	}
+	return service.EvaluateSLO(item, byWindow, h.Policies), nil
+}
+
+// bindSLORequest validates the request body; is_active is returned separately
+// because creating defaults it to true and updating keeps the stored value.
+func bindSLORequest(c *gin.Context) (store.SLORecord, *bool, bool) {
+	var req sloRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return store.SLORecord{}, nil, false
+	}
+	item := store.SLORecord{
+		Name:               strings.TrimSpace(req.Name),
+		SLI:                strings.TrimSpace(req.SLI),
+		Objective:          req.Objective,
+		LatencyThresholdMS: req.LatencyThresholdMS,
+		WindowDays:         req.WindowDays,
+	}
+	if item.WindowDays == 0 {
+		item.WindowDays = 30
+	}
+	if item.SLI != store.SLILatency {
+		item.LatencyThresholdMS = 0
+	}
+	if err := service.ValidateSLO(item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": err.Error()})
+		return store.SLORecord{}, nil, false
+	}
+	return item, req.IsActive, true
+}
+
+func parseSLOID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid slo id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeSLOLookupError(c *gin.Context, op string, err error) {
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "slo not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("%s failed: %v", op, err)})
+}
+
+func sloActor(c *gin.Context) string {
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		return "unknown"
+	}
+	return actor
+}
+
+func sloAuditPayload(item store.SLORecord) string {
+	return fmt.Sprintf(`{"name":%q,"sli":%q,"objective":%g,"latency_threshold_ms":%d,"window_days":%d,"is_active":%t}`,
+		item.Name, item.SLI, item.Objective, item.LatencyThresholdMS, item.WindowDays, item.IsActive)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockSLOStore struct {
+	items  []store.SLORecord
+	counts func(since time.Duration) store.SLOWindowCount
+	now    time.Time
+	alerts []store.AlertRecord
+	audits []store.AuditLogRecord
+	tenant []string
+}
+
+func (m *mockSLOStore) ListSLOs(_ context.Context) ([]store.SLORecord, error) {
+	return m.items, nil
+}
+
+func (m *mockSLOStore) ListActiveSLOs(_ context.Context) ([]store.SLORecord, error) {
+	out := []store.SLORecord{}
+	for _, item := range m.items {
+		if item.IsActive {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockSLOStore) GetSLO(_ context.Context, id int64) (store.SLORecord, error) {
+	for _, item := range m.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return store.SLORecord{}, fmt.Errorf("slo not found")
+}
+
+func (m *mockSLOStore) CreateSLO(_ context.Context, item store.SLORecord) (int64, error) {
+	item.ID = int64(len(m.items) + 1)
+	m.items = append(m.items, item)
+	return item.ID, nil
+}
+
+func (m *mockSLOStore) UpdateSLO(_ context.Context, item store.SLORecord) error {
+	for i := range m.items {
+		if m.items[i].ID == item.ID {
+			m.items[i] = item
+			return nil
+		}
+	}
+	return fmt.Errorf("slo not found")
+}
+
+func (m *mockSLOStore) DeleteSLO(_ context.Context, _ int64) error {
+	return nil
+}
+
+func (m *mockSLOStore) CountSLODeliveries(ctx context.Context, _ int64, since []time.Time) ([]store.SLOWindowCount, error) {
+	m.tenant = append(m.tenant, tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID))
+	out := make([]store.SLOWindowCount, len(since))
+	for i, s := range since {
+		out[i] = m.counts(m.now.Sub(s))
+		out[i].Since = s
+	}
+	return out, nil
+}
+
+func (m *mockSLOStore) SaveAlert(_ context.Context, alert store.AlertRecord) (int64, error) {
+	m.alerts = append(m.alerts, alert)
+	return int64(len(m.alerts)), nil
+}
+
+func (m *mockSLOStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+func newTestSLOHandler(m *mockSLOStore) *SLOHandler {
+	m.now = time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	h := NewSLOHandler(m)
+	h.Now = func() time.Time { return m.now }
+	return h
+}
+
+func TestSLOCreateValidatesAndDefaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := &mockSLOStore{}
+	h := newTestSLOHandler(m)
+	r := gin.New()
+	r.POST("/api/slos", h.Create)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/slos", strings.NewReader(`{"name":"p95","sli":"latency","objective":95}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected latency slo without threshold to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/slos", strings.NewReader(`{"name":"deliveries","sli":"delivery_success","objective":99.5}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	if len(m.items) != 1 || m.items[0].WindowDays != 30 || !m.items[0].IsActive {
+		t.Fatalf("expected a 30-day active slo, got %+v", m.items)
+	}
+	if len(m.audits) != 1 || m.audits[0].Action != "slo.create" {
+		t.Fatalf("expected audit log, got %+v", m.audits)
+	}
+}
+
+func TestSLOStatusReportsBudgetAndBurnRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := &mockSLOStore{
+		items: []store.SLORecord{{ID: 1, Name: "deliveries", SLI: store.SLIDeliverySuccess, Objective: 99.5, WindowDays: 30, IsActive: true}},
+		counts: func(d time.Duration) store.SLOWindowCount {
+			return store.SLOWindowCount{Total: 1000, Success: 999}
+		},
+	}
+	h := newTestSLOHandler(m)
+	r := gin.New()
+	r.GET("/api/slos/:id/status", h.Status)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slos/1/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Item service.SLOStatus `json:"item"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Item.Status != service.SLOStatusOK || resp.Item.ErrorBudget.Spent != 1 || len(resp.Item.BurnRates) == 0 {
+		t.Fatalf("unexpected status %+v", resp.Item)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slos/9/status", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown slo, got %d", w.Code)
+	}
+}
+
+func TestSLOEvaluateBurnRatesRaisesDedupedAlerts(t *testing.T) {
+	m := &mockSLOStore{
+		items: []store.SLORecord{
+			{ID: 1, TenantID: "acme", Name: "deliveries", SLI: store.SLIDeliverySuccess, Objective: 99, WindowDays: 30, IsActive: true},
+			{ID: 2, TenantID: "acme", Name: "paused", SLI: store.SLIDeliverySuccess, Objective: 99, WindowDays: 30},
+		},
+		counts: func(d time.Duration) store.SLOWindowCount {
+			if d <= time.Hour {
+				return store.SLOWindowCount{Total: 100, Success: 50}
+			}
+			return store.SLOWindowCount{Total: 100000, Success: 99990}
+		},
+	}
+	h := newTestSLOHandler(m)
+
+	raised, total, err := h.EvaluateBurnRates(context.Background())
+	if err != nil || raised != 1 || total != 1 {
+		t.Fatalf("expected one alert from one active slo, got raised=%d total=%d err=%v", raised, total, err)
+	}
+	alert := m.alerts[0]
+	if alert.DeliveryID != "slo-1-fast-2026030112" || alert.EventType != "slo_burn_rate" || alert.Action != "page" || alert.RuleMatched != "slo:deliveries" {
+		t.Fatalf("unexpected alert %+v", alert)
+	}
+	if m.tenant[0] != "acme" {
+		t.Fatalf("expected counts scoped to the slo tenant, got %v", m.tenant)
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/secretbox"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantCredentialStore interface {
+	ListTenantCredentials(ctx context.Context, tenantID string) ([]store.TenantCredentialRecord, error)
+	UpsertTenantCredential(ctx context.Context, item store.TenantCredentialRecord) error
+	DeleteTenantCredential(ctx context.Context, tenantID string, kind string) error
+	RotateTenantWebhookSecret(ctx context.Context, tenantID string, sealedValue string, previousExpiresAt time.Time, actor string) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+// TenantCredentialsHandler manages per-tenant webhook secrets and GitHub tokens.
+// Values are write-only: they are sealed before storage and never returned.
+type TenantCredentialsHandler struct {
+	Store    TenantCredentialStore
+	Box      *secretbox.Box
+	OnChange func(tenantID string)
+}
+
+type putTenantCredentialRequest struct {
+	Value string `json:"value"`
+}
+
+type startWebhookSecretRotationRequest struct {
+	NewSecret    string `json:"new_secret"`
+	GraceMinutes int    `json:"grace_minutes"`
+}
+
+const (
+	defaultRotationGraceMinutes = 24 * 60
+	maxRotationGraceMinutes     = 30 * 24 * 60
+)
+
+func NewTenantCredentialsHandler(s TenantCredentialStore, box *secretbox.Box) *TenantCredentialsHandler {
+	return &TenantCredentialsHandler{Store: s, Box: box}
+}
+
+func (h *TenantCredentialsHandler) List(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	items, err := h.Store.ListTenantCredentials(ctx, tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list credentials failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items, "encryption_configured": h.Box != nil})
+}
+
+func (h *TenantCredentialsHandler) Put(c *gin.Context) {
+	tenantID, kind, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+	if h.Box == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"ok": false, "message": secretbox.ErrKeyNotConfigured.Error()})
+		return
+	}
+
+	var req putTenantCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	value := strings.TrimSpace(req.Value)
+	if value == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "value is required"})
+		return
+	}
+
+	sealed, err := h.Box.Seal(value)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("encrypt credential failed: %v", err)})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.UpsertTenantCredential(ctx, store.TenantCredentialRecord{TenantID: tenantID, Kind: kind, SealedValue: sealed, UpdatedBy: actor}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("save credential failed: %v", err)})
+		return
+	}
+	h.changed(tenantID)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.credential.set",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  fmt.Sprintf(`{"kind":"%s"}`, kind),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *TenantCredentialsHandler) Delete(c *gin.Context) {
+	tenantID, kind, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.DeleteTenantCredential(ctx, tenantID, kind); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "credential not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("delete credential failed: %v", err)})
+		return
+	}
+	h.changed(tenantID)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.credential.delete",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  fmt.Sprintf(`{"kind":"%s"}`, kind),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// StartWebhookSecretRotation installs a new webhook secret and keeps the current one
+// valid for grace_minutes so deliveries signed with either are accepted. When
+// new_secret is omitted a random secret is generated and returned once.
+func (h *TenantCredentialsHandler) StartWebhookSecretRotation(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+	if h.Box == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"ok": false, "message": secretbox.ErrKeyNotConfigured.Error()})
+		return
+	}
+
+	var req startWebhookSecretRotationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+			return
+		}
+	}
+	grace := req.GraceMinutes
+	if grace == 0 {
+		grace = defaultRotationGraceMinutes
+	}
+	if grace < 1 || grace > maxRotationGraceMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "grace_minutes must be between 1 and 43200"})
+		return
+	}
+
+	newSecret := strings.TrimSpace(req.NewSecret)
+	generated := newSecret == ""
+	if generated {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "generate secret failed"})
+			return
+		}
+		newSecret = hex.EncodeToString(buf)
+	}
+	sealed, err := h.Box.Seal(newSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("encrypt credential failed: %v", err)})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+	previousExpiresAt := time.Now().UTC().Add(time.Duration(grace) * time.Minute)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.RotateTenantWebhookSecret(ctx, tenantID, sealed, previousExpiresAt, actor); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("rotate webhook secret failed: %v", err)})
+		return
+	}
+	h.changed(tenantID)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.webhook_secret.rotation_start",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  fmt.Sprintf(`{"previous_expires_at":"%s","generated":%t}`, previousExpiresAt.Format(time.RFC3339), generated),
+	})
+
+	resp := gin.H{"ok": true, "previous_expires_at": previousExpiresAt}
+	if generated {
+		resp["new_secret"] = newSecret
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// FinishWebhookSecretRotation revokes the previous secret before its grace period ends.
+func (h *TenantCredentialsHandler) FinishWebhookSecretRotation(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.DeleteTenantCredential(ctx, tenantID, store.CredentialKindWebhookSecretPrevious); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "no rotation in progress"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("finish rotation failed: %v", err)})
+		return
+	}
+	h.changed(tenantID)
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.webhook_secret.rotation_finish",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  `{}`,
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *TenantCredentialsHandler) parseTarget(c *gin.Context) (string, string, bool) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return "", "", false
+	}
+	kind := strings.TrimSpace(c.Param("kind"))
+	if !store.IsValidCredentialKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "kind must be webhook_secret or github_token"})
+		return "", "", false
+	}
+	return tenantID, kind, true
+}
+
+func (h *TenantCredentialsHandler) changed(tenantID string) {
+	if h.OnChange != nil {
+		h.OnChange(tenantID)
+	}
+}
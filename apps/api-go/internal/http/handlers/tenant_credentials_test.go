@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/secretbox"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockTenantCredentialStore struct {
+	items  map[string]store.TenantCredentialRecord
+	audits []store.AuditLogRecord
+}
+
+func (m *mockTenantCredentialStore) ListTenantCredentials(_ context.Context, tenantID string) ([]store.TenantCredentialRecord, error) {
+	out := []store.TenantCredentialRecord{}
+	for _, item := range m.items {
+		if item.TenantID == tenantID {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockTenantCredentialStore) UpsertTenantCredential(_ context.Context, item store.TenantCredentialRecord) error {
+	m.items[item.TenantID+"/"+item.Kind] = item
+	return nil
+}
+
+func (m *mockTenantCredentialStore) DeleteTenantCredential(_ context.Context, tenantID string, kind string) error {
+	if _, ok := m.items[tenantID+"/"+kind]; !ok {
+		return fmt.Errorf("credential not found")
+	}
+	delete(m.items, tenantID+"/"+kind)
+	return nil
+}
+
+func (m *mockTenantCredentialStore) RotateTenantWebhookSecret(_ context.Context, tenantID string, sealedValue string, previousExpiresAt time.Time, actor string) error {
+	if current, ok := m.items[tenantID+"/"+store.CredentialKindWebhookSecret]; ok {
+		current.Kind = store.CredentialKindWebhookSecretPrevious
+		current.ExpiresAt = previousExpiresAt
+		m.items[tenantID+"/"+current.Kind] = current
+	}
+	m.items[tenantID+"/"+store.CredentialKindWebhookSecret] = store.TenantCredentialRecord{TenantID: tenantID, Kind: store.CredentialKindWebhookSecret, SealedValue: sealedValue, UpdatedBy: actor}
+	return nil
+}
+
+func (m *mockTenantCredentialStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+func TestTenantCredentials_PutSealsAndAudits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	box, _ := secretbox.New("test-key")
+	mockStore := &mockTenantCredentialStore{items: map[string]store.TenantCredentialRecord{}}
+	h := NewTenantCredentialsHandler(mockStore, box)
+	var invalidated []string
+	h.OnChange = func(tenantID string) { invalidated = append(invalidated, tenantID) }
+
+	r := gin.New()
+	r.PUT("/tenants/:id/credentials/:kind", h.Put)
+	r.DELETE("/tenants/:id/credentials/:kind", h.Delete)
+	r.GET("/tenants/:id/credentials", h.List)
+
+	req := httptest.NewRequest(http.MethodPut, "/tenants/team-a/credentials/webhook_secret", strings.NewReader(`{"value":"whsec"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	saved := mockStore.items["team-a/webhook_secret"]
+	if saved.SealedValue == "" || strings.Contains(saved.SealedValue, "whsec") {
+		t.Fatalf("expected sealed value, got %q", saved.SealedValue)
+	}
+	if plain, err := box.Open(saved.SealedValue); err != nil || plain != "whsec" {
+		t.Fatalf("unexpected sealed content %q %v", plain, err)
+	}
+	if len(mockStore.audits) != 1 || mockStore.audits[0].Action != "tenant.credential.set" || strings.Contains(mockStore.audits[0].Payload, "whsec") {
+		t.Fatalf("unexpected audits: %+v", mockStore.audits)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants/team-a/credentials", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), saved.SealedValue) {
+		t.Fatalf("list must not expose values, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/tenants/team-a/credentials/webhook_secret", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(invalidated) != 2 || len(mockStore.audits) != 2 || mockStore.audits[1].Action != "tenant.credential.delete" {
+		t.Fatalf("unexpected invalidations=%v audits=%+v", invalidated, mockStore.audits)
+	}
+}
+
+func TestTenantCredentials_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockTenantCredentialStore{items: map[string]store.TenantCredentialRecord{}}
+	r := gin.New()
+	r.PUT("/tenants/:id/credentials/:kind", NewTenantCredentialsHandler(mockStore, nil).Put)
+
+	req := httptest.NewRequest(http.MethodPut, "/tenants/team-a/credentials/password", strings.NewReader(`{"value":"x"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown kind, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/tenants/team-a/credentials/github_token", strings.NewReader(`{"value":"x"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without encryption key, got %d", w.Code)
+	}
+}
+
+func TestTenantCredentials_WebhookSecretRotation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	box, _ := secretbox.New("test-key")
+	oldSealed, _ := box.Seal("old-secret")
+	mockStore := &mockTenantCredentialStore{items: map[string]store.TenantCredentialRecord{
+		"team-a/webhook_secret": {TenantID: "team-a", Kind: store.CredentialKindWebhookSecret, SealedValue: oldSealed},
+	}}
+	h := NewTenantCredentialsHandler(mockStore, box)
+	r := gin.New()
+	r.POST("/tenants/:id/webhook-secret/rotation", h.StartWebhookSecretRotation)
+	r.DELETE("/tenants/:id/webhook-secret/rotation", h.FinishWebhookSecretRotation)
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/team-a/webhook-secret/rotation", strings.NewReader(`{"grace_minutes":60}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		NewSecret string `json:"new_secret"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	current, _ := box.Open(mockStore.items["team-a/webhook_secret"].SealedValue)
+	if resp.NewSecret == "" || current != resp.NewSecret {
+		t.Fatalf("expected generated secret to become primary, got %q vs %q", current, resp.NewSecret)
+	}
+	previous := mockStore.items["team-a/webhook_secret_previous"]
+	if plain, _ := box.Open(previous.SealedValue); plain != "old-secret" {
+		t.Fatalf("expected old secret kept as previous, got %q", plain)
+	}
+	if until := time.Until(previous.ExpiresAt); until < 59*time.Minute || until > 61*time.Minute {
+		t.Fatalf("unexpected previous expiry %v", previous.ExpiresAt)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/tenants/team-a/webhook-secret/rotation", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if _, ok := mockStore.items["team-a/webhook_secret_previous"]; ok {
+		t.Fatalf("expected previous secret removed")
+	}
+	if len(mockStore.audits) != 2 || mockStore.audits[0].Action != "tenant.webhook_secret.rotation_start" || mockStore.audits[1].Action != "tenant.webhook_secret.rotation_finish" {
+		t.Fatalf("unexpected audits: %+v", mockStore.audits)
+	}
+	if strings.Contains(mockStore.audits[0].Payload, resp.NewSecret) {
+		t.Fatalf("audit must not contain the secret")
+	}
+}
@@ -19,6 +19,8 @@ type TenantStore interface {
 	ListTenants(ctx context.Context) ([]store.TenantRecord, error)
 	CreateTenant(ctx context.Context, id string, name string) error
 	UpdateTenantActive(ctx context.Context, id string, isActive bool) error
+	UpdateTenantGitHubInstallationID(ctx context.Context, tenantID string, installationID int64) error
+	UpdateTenantWebhookSignatureSchemes(ctx context.Context, tenantID string, schemes string) error
 }
 
 type TenantsHandler struct {
@@ -34,6 +36,14 @@ type updateTenantActiveRequest struct {
 	IsActive bool `json:"is_active"`
 }
 
+type updateTenantWebhookSignatureRequest struct {
+	Schemes []string `json:"schemes"`
+}
+
+type updateTenantGitHubInstallationRequest struct {
+	InstallationID *int64 `json:"installation_id"`
+}
+
 func NewTenantsHandler(store TenantStore) *TenantsHandler {
 	return &TenantsHandler{Store: store}
 }
@@ -106,3 +116,71 @@ func (h *TenantsHandler) UpdateActive(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+// UpdateGitHubInstallation maps a tenant to a GitHub App installation. An
+// installation_id of 0 clears the mapping so the tenant falls back to the static token.
+func (h *TenantsHandler) UpdateGitHubInstallation(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	var req updateTenantGitHubInstallationRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.InstallationID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "installation_id is required"})
+		return
+	}
+	if *req.InstallationID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid installation_id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.UpdateTenantGitHubInstallationID(ctx, tenantID, *req.InstallationID); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "tenant not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update tenant failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "installation_id": *req.InstallationID})
+}
+
+// UpdateWebhookSignature selects which signature schemes the tenant's deliveries
+// may use. An empty list restores the server default.
+func (h *TenantsHandler) UpdateWebhookSignature(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	var req updateTenantWebhookSignatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	schemes, err := ParseSignatureSchemes(strings.Join(req.Schemes, ","))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.UpdateTenantWebhookSignatureSchemes(ctx, tenantID, strings.Join(schemes, ",")); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "tenant not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("update tenant failed: %v", err)})
+		return
+	}
+	if schemes == nil {
+		schemes = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "schemes": schemes})
+}
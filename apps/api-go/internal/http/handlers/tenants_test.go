@@ -24,6 +24,8 @@ type mockTenantStore struct {
 	updateErr       error
 	lastUpdateID    string
 	lastUpdateState bool
+	installations   map[string]int64
+	schemes         map[string]string
 }
 
 func (m *mockTenantStore) ListTenants(_ context.Context) ([]store.TenantRecord, error) {
@@ -45,6 +47,28 @@ func (m *mockTenantStore) UpdateTenantActive(_ context.Context, id string, isAct
 	return m.updateErr
 }
 
+func (m *mockTenantStore) UpdateTenantGitHubInstallationID(_ context.Context, tenantID string, installationID int64) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	if m.installations == nil {
+		m.installations = map[string]int64{}
+	}
+	m.installations[tenantID] = installationID
+	return nil
+}
+
+func (m *mockTenantStore) UpdateTenantWebhookSignatureSchemes(_ context.Context, tenantID string, schemes string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	if m.schemes == nil {
+		m.schemes = map[string]string{}
+	}
+	m.schemes[tenantID] = schemes
+	return nil
+}
+
 func TestTenantsList_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockStore := &mockTenantStore{
@@ -128,3 +152,60 @@ func TestTenantsUpdateActive_Success(t *testing.T) {
 		t.Fatalf("unexpected update args: id=%s isActive=%v", mockStore.lastUpdateID, mockStore.lastUpdateState)
 	}
 }
+
+func TestTenantsUpdateGitHubInstallation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockTenantStore{}
+	h := NewTenantsHandler(mockStore)
+	r := gin.New()
+	r.PATCH("/api/tenants/:id/github-installation", h.UpdateGitHubInstallation)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenants/team-a/github-installation", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without installation_id, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/tenants/team-a/github-installation", strings.NewReader(`{"installation_id":4242}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if mockStore.installations["team-a"] != 4242 {
+		t.Fatalf("unexpected installations: %+v", mockStore.installations)
+	}
+}
+
+func TestTenantsUpdateWebhookSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockTenantStore{}
+	h := NewTenantsHandler(mockStore)
+	r := gin.New()
+	r.PATCH("/tenants/:id/webhook-signature", h.UpdateWebhookSignature)
+
+	cases := []struct {
+		body       string
+		wantStatus int
+		wantStored string
+	}{
+		{body: `{"schemes":["SHA256"," sha1","sha256"]}`, wantStatus: http.StatusOK, wantStored: "sha256,sha1"},
+		{body: `{"schemes":["md5"]}`, wantStatus: http.StatusBadRequest, wantStored: "sha256,sha1"},
+		{body: `{"schemes":[]}`, wantStatus: http.StatusOK, wantStored: ""},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPatch, "/tenants/team-a/webhook-signature", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.wantStatus {
+			t.Fatalf("%s: expected %d, got %d body=%s", tc.body, tc.wantStatus, w.Code, w.Body.String())
+		}
+		if got := mockStore.schemes["team-a"]; got != tc.wantStored {
+			t.Fatalf("%s: expected stored %q, got %q", tc.body, tc.wantStored, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans routes spans to an in-memory recorder for the rest of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return rec
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, a := range span.Attributes() {
+		if string(a.Key) == key {
+			return a.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestWebhookGitHub_TracesPipelineUnderCallerTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := recordSpans(t)
+
+	secret := "test-secret"
+	body, _ := json.Marshal(map[string]any{
+		"action":     "opened",
+		"repository": map[string]any{"full_name": "owner/repo"},
+		"sender":     map[string]any{"login": "alice"},
+		"issue":      map[string]any{"title": "urgent", "number": 12},
+	})
+	h := NewWebhookHandler(secret, &mockWebhookStore{rules: []store.RuleRecord{
+		{EventType: "issues", Keyword: "urgent", SuggestionType: "label", SuggestionValue: "P0", Reason: "urgent rule"},
+	}})
+	h.ActionExecutor = &mockWebhookExecutor{}
+
+	r := gin.New()
+	r.Use(TracingMiddleware())
+	r.POST("/webhook/github", h.GitHub)
+	req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signBody(secret, body))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-GitHub-Delivery", "delivery-trace")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range rec.Ended() {
+		if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Fatalf("span %s is not in the caller's trace", span.Name())
+		}
+		byName[span.Name()] = span
+	}
+	server, process := byName["POST /webhook/github"], byName["webhook.process"]
+	if server == nil || process == nil {
+		t.Fatalf("missing spans: %v", byName)
+	}
+	if server.Parent().SpanID().String() != "00f067aa0ba902b7" || process.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatalf("unexpected span tree: %v", byName)
+	}
+	if spanAttr(process, "mf.delivery_id").AsString() != "delivery-trace" || spanAttr(process, "mf.tenant_id").AsString() != "default" || spanAttr(process, "mf.event_type").AsString() != "issues" {
+		t.Fatalf("missing delivery attributes: %+v", process.Attributes())
+	}
+	if spanAttr(server, "mf.outcome").AsString() != store.DeliveryOutcomeProcessed || spanAttr(server, "http.status_code").AsInt64() != 200 {
+		t.Fatalf("unexpected server span attributes: %+v", server.Attributes())
+	}
+	for _, name := range []string{"webhook.rules", "webhook.action"} {
+		if span := byName[name]; span == nil || span.Parent().SpanID() != process.SpanContext().SpanID() {
+			t.Fatalf("expected %s under webhook.process, got %v", name, span)
+		}
+	}
+}
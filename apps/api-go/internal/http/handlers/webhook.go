@@ -2,33 +2,44 @@ package handlers
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"strings"
 	"time"
 
+	"maintainer-firewall/api-go/internal/logging"
+	"maintainer-firewall/api-go/internal/metrics"
 	"maintainer-firewall/api-go/internal/service"
 	"maintainer-firewall/api-go/internal/store"
 	"maintainer-firewall/api-go/internal/tenantctx"
+	"maintainer-firewall/api-go/internal/tracing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type WebhookEventSaver interface {
 	SaveEvent(ctx context.Context, evt store.WebhookEvent) error
-	SaveAlert(ctx context.Context, alert store.AlertRecord) error
+	SaveAlert(ctx context.Context, alert store.AlertRecord) (int64, error)
 	SaveActionExecutionFailure(ctx context.Context, item store.ActionExecutionFailure) error
 	SaveDeliveryMetric(ctx context.Context, metric store.DeliveryMetric) error
 	ListRules(ctx context.Context, limit int, offset int, eventType string, keyword string, activeOnly bool) ([]store.RuleRecord, int64, error)
 }
 
-type WebhookActionExecutor interface {
-	AddLabel(ctx context.Context, repositoryFullName string, number int, label string) error
-	AddComment(ctx context.Context, repositoryFullName string, number int, body string) error
+type WebhookActionExecutor = service.ActionExecutor
+
+// WebhookSecretResolver returns the webhook secrets registered by the tenant in ctx;
+// found is false when the tenant has none.
+type WebhookSecretResolver interface {
+	WebhookSecrets(ctx context.Context) (secrets service.WebhookSecretSet, found bool, err error)
+}
+
+// WebhookTenantRouter maps deliveries to tenants.
+type WebhookTenantRouter interface {
+	ResolveWebhookRoute(ctx context.Context, kind string, matchValue string) (string, error)
+	GetTenant(ctx context.Context, id string) (store.TenantRecord, error)
 }
 
 type WebhookHandler struct {
@@ -36,6 +47,29 @@ type WebhookHandler struct {
 	Store          WebhookEventSaver
 	RuleEngine     *service.RuleEngine
 	ActionExecutor WebhookActionExecutor
+	Secrets        WebhookSecretResolver
+	Router         WebhookTenantRouter
+	Repositories   WebhookRepositoryRegistry
+	Quarantine     WebhookQuarantineSaver
+	MaxBodyBytes   int64
+
+	// SecondarySecret is the outgoing global secret during a rotation; it is
+	// accepted until SecondarySecretExpiresAt (zero means no expiry).
+	SecondarySecret          string
+	SecondarySecretExpiresAt time.Time
+
+	// SignatureSchemes lists the schemes accepted for tenants without their own
+	// setting; empty means DefaultSignatureSchemes.
+	SignatureSchemes []string
+
+	// GitLabSecret and GiteaSecret are the global secrets for those sources; empty
+	// falls back to Secret.
+	GitLabSecret string
+	GiteaSecret  string
+
+	// Providers parses deliveries and supplies per-provider executors. ActionExecutor
+	// is used for GitHub when the registry has no GitHub executor.
+	Providers *service.ProviderRegistry
 }
 
 type webhookResponse struct {
@@ -43,6 +77,7 @@ type webhookResponse struct {
 	Message          string                    `json:"message,omitempty"`
 	Event            string                    `json:"event,omitempty"`
 	SuggestedActions []service.SuggestedAction `json:"suggested_actions,omitempty"`
+	QuarantineID     int64                     `json:"quarantine_id,omitempty"`
 }
 
 func NewWebhookHandler(secret string, eventStore WebhookEventSaver) *WebhookHandler {
@@ -50,19 +85,23 @@ func NewWebhookHandler(secret string, eventStore WebhookEventSaver) *WebhookHand
 		Secret:     secret,
 		Store:      eventStore,
 		RuleEngine: service.NewRuleEngine(),
+		Providers:  service.DefaultProviderRegistry(),
 	}
 }
 
 func (h *WebhookHandler) GitHub(c *gin.Context) {
 	startedAt := time.Now().UTC()
 	deliverySuccess := false
-	tenantID := tenantctx.MustFromContext(nil, c.GetHeader("X-MF-Tenant-ID"))
+	outcome := ""
+	matchedSecret := ""
+	tenantID := tenantctx.DefaultTenantID
+	logging.SetDeliveryID(c.Request.Context(), c.GetHeader("X-GitHub-Delivery"))
 
 	defer func() {
 		if h.Store == nil {
 			return
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Second)
 		defer cancel()
 		ctx = tenantctx.WithTenantID(ctx, tenantID)
 		deliveryID := c.GetHeader("X-GitHub-Delivery")
@@ -73,37 +112,103 @@ func (h *WebhookHandler) GitHub(c *gin.Context) {
 		if strings.TrimSpace(eventType) == "" {
 			eventType = "unknown"
 		}
+		if outcome == "" {
+			outcome = store.DeliveryOutcomeFailed
+			if deliverySuccess {
+				outcome = store.DeliveryOutcomeProcessed
+			}
+		}
+		annotateDelivery(c, store.EventSourceGitHub, tenantID, deliveryID, eventType, outcome)
+		metrics.WebhookRequests.WithLabelValues(store.EventSourceGitHub, metrics.WebhookEventType(eventType), outcome).Inc()
+		metrics.WebhookProcessingSeconds.WithLabelValues(store.EventSourceGitHub, outcome).Observe(time.Since(startedAt).Seconds())
 		_ = h.Store.SaveDeliveryMetric(ctx, store.DeliveryMetric{
 			EventType:     eventType,
 			DeliveryID:    deliveryID,
 			Success:       deliverySuccess,
+			Outcome:       outcome,
+			MatchedSecret: matchedSecret,
 			ProcessingMS:  time.Since(startedAt).Milliseconds(),
 			RecordedAtUTC: time.Now().UTC(),
 		})
 	}()
 
-	if strings.TrimSpace(h.Secret) == "" {
-		c.JSON(500, webhookResponse{OK: false, Message: "GITHUB_WEBHOOK_SECRET is not configured"})
-		return
-	}
 	if h.Store == nil {
 		c.JSON(500, webhookResponse{OK: false, Message: "event store is not configured"})
 		return
 	}
 
-	signature := c.GetHeader("X-Hub-Signature-256")
-	if !strings.HasPrefix(signature, "sha256=") {
-		c.JSON(401, webhookResponse{OK: false, Message: "missing or invalid X-Hub-Signature-256"})
+	body, tooLarge, err := h.readBody(c)
+	if err != nil {
+		c.JSON(400, webhookResponse{OK: false, Message: "failed to read request body"})
 		return
 	}
 
-	body, err := io.ReadAll(c.Request.Body)
+	hint := webhookRouteHint{}
+	if !tooLarge {
+		hint = githubRouteHint(body)
+	}
+	route, err := h.routeDelivery(c, hint)
 	if err != nil {
-		c.JSON(400, webhookResponse{OK: false, Message: "failed to read request body"})
+		outcome = store.DeliveryOutcomeMisrouted
+		c.JSON(route.status, webhookResponse{OK: false, Message: err.Error()})
+		return
+	}
+	if route.tenantID != "" {
+		tenantID = route.tenantID
+	}
+	logging.SetTenantID(c.Request.Context(), tenantID)
+	if tooLarge {
+		outcome = store.DeliveryOutcomeTooLarge
+		h.rejectOversized(c)
+		return
+	}
+
+	secrets, err := h.resolveSecrets(tenantctx.WithTenantID(c.Request.Context(), tenantID), route.requireOwnSecret, h.globalSecrets())
+	if err != nil {
+		if errors.Is(err, errTenantSecretRequired) {
+			outcome = store.DeliveryOutcomeMisrouted
+			c.JSON(401, webhookResponse{OK: false, Message: err.Error()})
+			return
+		}
+		c.JSON(500, webhookResponse{OK: false, Message: fmt.Sprintf("failed to load webhook secret: %v", err)})
+		return
+	}
+	candidates := secrets.Candidates(time.Now())
+	if len(candidates) == 0 {
+		c.JSON(500, webhookResponse{OK: false, Message: "GITHUB_WEBHOOK_SECRET is not configured"})
+		return
+	}
+
+	schemes := route.signatureSchemes
+	if len(schemes) == 0 {
+		schemes = h.SignatureSchemes
+	}
+	if len(schemes) == 0 {
+		schemes = DefaultSignatureSchemes
+	}
+	var verifiers []SignatureVerifier
+	for _, v := range signatureVerifiersFor(schemes) {
+		if v.Present(c.Request.Header) {
+			verifiers = append(verifiers, v)
+		}
+	}
+	if len(verifiers) == 0 {
+		outcome = store.DeliveryOutcomeUnauthorized
+		c.JSON(401, webhookResponse{OK: false, Message: fmt.Sprintf("missing webhook signature (accepted schemes: %s)", strings.Join(schemes, ","))})
 		return
 	}
 
-	if !verifyGitHubSignature(signature, body, h.Secret) {
+verify:
+	for _, v := range verifiers {
+		for _, candidate := range candidates {
+			if v.Verify(c.Request.Header, body, candidate.Secret) {
+				matchedSecret = candidate.Slot
+				break verify
+			}
+		}
+	}
+	if matchedSecret == "" {
+		outcome = store.DeliveryOutcomeUnauthorized
 		c.JSON(401, webhookResponse{OK: false, Message: "signature verification failed"})
 		return
 	}
@@ -117,37 +222,116 @@ func (h *WebhookHandler) GitHub(c *gin.Context) {
 		deliveryID = fmt.Sprintf("missing-%d", time.Now().UnixNano())
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(body, &payload); err != nil {
-		c.JSON(400, webhookResponse{OK: false, Message: "invalid JSON payload"})
+	normalized, err := h.parseAndValidate(store.EventSourceGitHub, eventType, body)
+	if err != nil {
+		if id := h.rejectMalformed(c, tenantID, store.EventSourceGitHub, eventType, deliveryID, body, err); id > 0 {
+			outcome = store.DeliveryOutcomeQuarantined
+		}
 		return
 	}
 
-	action, _ := payload["action"].(string)
-	evt := store.WebhookEvent{
-		DeliveryID:         deliveryID,
-		EventType:          eventType,
-		Action:             action,
-		RepositoryFullName: extractRepositoryFullName(payload),
-		SenderLogin:        extractSenderLogin(payload),
-		PayloadJSON:        body,
+	if normalized.EventType == "ping" {
+		deliverySuccess = true
+		hookID, _ := normalized.Payload["hook_id"].(float64)
+		c.JSON(200, webhookResponse{OK: true, Message: fmt.Sprintf("pong: webhook %d is configured for tenant %s", int64(hookID), tenantID), Event: "ping"})
+		return
 	}
 
-	baseCtx := tenantctx.WithTenantID(c.Request.Context(), tenantID)
-	ctx, cancel := context.WithTimeout(baseCtx, 3*time.Second)
+	deliverySuccess = h.ingest(c, c.Request.Context(), tenantID, deliveryID, normalized)
+}
+
+// ingest processes a verified delivery, writes the response and reports whether
+// the delivery succeeded.
+func (h *WebhookHandler) ingest(c *gin.Context, baseCtx context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent) bool {
+	result, err := h.processDelivery(baseCtx, tenantID, deliveryID, normalized, deliveryOptions{executeActions: true})
+	if err != nil {
+		c.JSON(500, webhookResponse{OK: false, Message: err.Error()})
+		return false
+	}
+	c.JSON(200, webhookResponse{
+		OK:               true,
+		Message:          fmt.Sprintf("webhook accepted (action=%s)", normalized.Action),
+		Event:            normalized.EventType,
+		SuggestedActions: result.suggestions,
+	})
+	return true
+}
+
+// deliveryOptions controls the side effects of processDelivery.
+type deliveryOptions struct {
+	executeActions bool
+	// replay marks a stored event run again; its repository lifecycle changes
+	// were applied when it first arrived.
+	replay bool
+}
+
+// deliveryResult lists the rule suggestions for a delivery and the ids of the
+// alerts this run inserted; alerts raised by an earlier run are not included.
+type deliveryResult struct {
+	suggestions []service.SuggestedAction
+	alertIDs    []int64
+}
+
+// processDelivery applies repository lifecycle changes, persists the event,
+// evaluates rules and, when executeActions is set, runs the actions of newly
+// raised alerts through the provider's executor.
+func (h *WebhookHandler) processDelivery(baseCtx context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent, opts deliveryOptions) (deliveryResult, error) {
+	baseCtx, span := tracing.Start(baseCtx, "webhook.process", trace.WithAttributes(
+		attribute.String("mf.delivery_id", deliveryID),
+		attribute.String("mf.tenant_id", tenantID),
+		attribute.String("mf.event_type", normalized.EventType),
+		attribute.String("mf.source", normalized.Provider),
+		attribute.Bool("mf.execute_actions", opts.executeActions),
+		attribute.Bool("mf.replay", opts.replay),
+	))
+	defer span.End()
+	result, err := h.runDelivery(baseCtx, tenantID, deliveryID, normalized, opts)
+	span.SetAttributes(attribute.Int("mf.suggestions", len(result.suggestions)), attribute.Int("mf.new_alerts", len(result.alertIDs)))
+	tracing.RecordError(span, err)
+	return result, err
+}
+
+func (h *WebhookHandler) runDelivery(baseCtx context.Context, tenantID string, deliveryID string, normalized service.NormalizedEvent, opts deliveryOptions) (deliveryResult, error) {
+	if normalized.Provider == store.EventSourceGitHub {
+		if !opts.replay {
+			lifecycleCtx, cancel := context.WithTimeout(tenantctx.WithTenantID(baseCtx, tenantID), 3*time.Second)
+			err := h.applyRepositoryLifecycle(lifecycleCtx, normalized)
+			cancel()
+			if err != nil {
+				return deliveryResult{}, fmt.Errorf("failed to update repositories: %v", err)
+			}
+		}
+		baseCtx = service.WithGitHubInstallationID(baseCtx, h.tenantInstallationID(baseCtx, tenantID, normalized.Payload))
+	}
+
+	targetKind := normalized.TargetKind
+	if targetKind == "" {
+		targetKind = service.TargetKindIssue
+	}
+	ctx, cancel := context.WithTimeout(service.WithTargetKind(tenantctx.WithTenantID(baseCtx, tenantID), targetKind), 3*time.Second)
 	defer cancel()
 
+	evt := store.WebhookEvent{
+		DeliveryID:         deliveryID,
+		EventType:          normalized.EventType,
+		Action:             normalized.Action,
+		RepositoryFullName: orUnknown(normalized.Repository),
+		SenderLogin:        orUnknown(normalized.Sender),
+		PayloadJSON:        normalized.Raw,
+		Source:             normalized.Provider,
+	}
 	if err := h.Store.SaveEvent(ctx, evt); err != nil {
-		c.JSON(500, webhookResponse{OK: false, Message: fmt.Sprintf("failed to persist event: %v", err)})
-		return
+		return deliveryResult{}, fmt.Errorf("failed to persist event: %v", err)
 	}
 
 	suggestions := []service.SuggestedAction{}
 	if h.RuleEngine != nil {
-		rules, _, err := h.Store.ListRules(ctx, 200, 0, eventType, "", true)
+		rulesCtx, rulesSpan := tracing.Start(ctx, "webhook.rules", trace.WithAttributes(attribute.String("mf.event_type", evt.EventType)))
+		rules, _, err := h.Store.ListRules(rulesCtx, 200, 0, evt.EventType, "", true)
 		if err != nil {
-			c.JSON(500, webhookResponse{OK: false, Message: fmt.Sprintf("failed to load rules: %v", err)})
-			return
+			tracing.RecordError(rulesSpan, err)
+			rulesSpan.End()
+			return deliveryResult{}, fmt.Errorf("failed to load rules: %v", err)
 		}
 		if len(rules) > 0 {
 			defs := make([]service.RuleDefinition, 0, len(rules))
@@ -160,18 +344,23 @@ func (h *WebhookHandler) GitHub(c *gin.Context) {
 					Reason:          r.Reason,
 				})
 			}
-			suggestions = h.RuleEngine.EvaluateWithRules(eventType, payload, defs)
+			suggestions = h.RuleEngine.EvaluateEvent(normalized, defs)
 		} else {
-			suggestions = h.RuleEngine.Evaluate(eventType, payload)
+			suggestions = h.RuleEngine.EvaluateEvent(normalized, service.DefaultRules())
 		}
+		rulesSpan.SetAttributes(attribute.Int("mf.rules", len(rules)), attribute.Int("mf.matches", len(suggestions)))
+		rulesSpan.End()
 	}
 
-	issueNumber := extractTargetNumber(eventType, payload)
+	executor := h.executorFor(normalized.Provider)
+	issueNumber := normalized.Number
+	result := deliveryResult{suggestions: suggestions}
 	for _, s := range suggestions {
+		metrics.RuleMatches.WithLabelValues(evt.EventType, s.Type, s.Matched).Inc()
 		alert := store.AlertRecord{
-			DeliveryID:         deliveryID,
-			EventType:          eventType,
-			Action:             action,
+			DeliveryID:         evt.DeliveryID,
+			EventType:          evt.EventType,
+			Action:             evt.Action,
 			RepositoryFullName: evt.RepositoryFullName,
 			SenderLogin:        evt.SenderLogin,
 			RuleMatched:        s.Matched,
@@ -179,19 +368,33 @@ func (h *WebhookHandler) GitHub(c *gin.Context) {
 			SuggestionValue:    s.Value,
 			Reason:             s.Reason,
 		}
-		if err := h.Store.SaveAlert(ctx, alert); err != nil {
-			c.JSON(500, webhookResponse{OK: false, Message: fmt.Sprintf("failed to persist alert: %v", err)})
-			return
+		alertID, err := h.Store.SaveAlert(ctx, alert)
+		if err != nil {
+			return result, fmt.Errorf("failed to persist alert: %v", err)
+		}
+		if alertID == 0 {
+			// Raised by an earlier run of this delivery, which already acted on it.
+			continue
 		}
+		result.alertIDs = append(result.alertIDs, alertID)
 
-		if h.ActionExecutor != nil && issueNumber > 0 && evt.RepositoryFullName != "unknown" {
-			execErr, attempts := h.executeWithRetry(ctx, evt.RepositoryFullName, issueNumber, s)
+		if opts.executeActions && executor != nil && issueNumber > 0 && evt.RepositoryFullName != "unknown" {
+			actionCtx, actionSpan := tracing.Start(ctx, "webhook.action", trace.WithAttributes(
+				attribute.String("mf.suggestion_type", s.Type),
+				attribute.String("mf.repository", evt.RepositoryFullName),
+			))
+			execErr, attempts := h.executeWithRetry(actionCtx, executor, evt.RepositoryFullName, issueNumber, s)
+			actionSpan.SetAttributes(attribute.Int("mf.attempts", attempts))
+			tracing.RecordError(actionSpan, execErr)
+			actionSpan.End()
 			if execErr != nil {
+				metrics.ActionFailures.WithLabelValues(evt.Source, s.Type).Inc()
 				_ = h.Store.SaveActionExecutionFailure(ctx, store.ActionExecutionFailure{
-					DeliveryID:         deliveryID,
-					EventType:          eventType,
-					Action:             action,
+					DeliveryID:         evt.DeliveryID,
+					EventType:          evt.EventType,
+					Action:             evt.Action,
 					RepositoryFullName: evt.RepositoryFullName,
+					Source:             evt.Source,
 					SuggestionType:     s.Type,
 					SuggestionValue:    s.Value,
 					ErrorMessage:       execErr.Error(),
@@ -201,82 +404,230 @@ func (h *WebhookHandler) GitHub(c *gin.Context) {
 		}
 	}
 
-	deliverySuccess = true
-	c.JSON(200, webhookResponse{
-		OK:               true,
-		Message:          fmt.Sprintf("webhook accepted (action=%s)", action),
-		Event:            eventType,
-		SuggestedActions: suggestions,
-	})
+	return result, nil
 }
 
-func extractRepositoryFullName(payload map[string]any) string {
-	repo, ok := payload["repository"].(map[string]any)
-	if !ok {
-		return "unknown"
+var errTenantSecretRequired = errors.New("tenant has no webhook secret registered")
+
+// webhookRouteHint carries the payload fields the shared endpoint routes by.
+type webhookRouteHint struct {
+	installationID     int64
+	repositoryFullName string
+}
+
+func githubRouteHint(body []byte) webhookRouteHint {
+	var probe struct {
+		Installation struct {
+			ID int64 `json:"id"`
+		} `json:"installation"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
 	}
-	fullName, _ := repo["full_name"].(string)
-	if strings.TrimSpace(fullName) == "" {
-		return "unknown"
+	_ = json.Unmarshal(body, &probe)
+	return webhookRouteHint{installationID: probe.Installation.ID, repositoryFullName: probe.Repository.FullName}
+}
+
+type webhookRoute struct {
+	tenantID         string
+	requireOwnSecret bool
+	signatureSchemes []string
+	status           int
+}
+
+// routeDelivery decides which tenant a delivery belongs to. The X-MF-Tenant-ID
+// header is never trusted: an opaque token path or an explicit tenant path must be
+// backed by that tenant's own secret, and the shared endpoint only routes through
+// admin-configured installation or repository mappings, defaulting to the default tenant.
+// Those mappings are looked up from the unverified body, so a delivery they route
+// to another tenant must also be signed with that tenant's own secret.
+func (h *WebhookHandler) routeDelivery(c *gin.Context, hint webhookRouteHint) (webhookRoute, error) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	route := webhookRoute{tenantID: tenantctx.DefaultTenantID}
+	switch {
+	case c.Param("token") != "":
+		if h.Router == nil {
+			return webhookRoute{status: 404}, fmt.Errorf("unknown webhook token")
+		}
+		tenantID, err := h.Router.ResolveWebhookRoute(ctx, store.WebhookRouteKindToken, hashWebhookToken(c.Param("token")))
+		if err != nil {
+			return webhookRoute{status: 404}, fmt.Errorf("unknown webhook token")
+		}
+		// The unguessable token identifies the tenant, so the shared secret may verify it.
+		route.tenantID = tenantID
+	case c.Param("tenant") != "":
+		tenantID := strings.TrimSpace(c.Param("tenant"))
+		if !tenantIDPattern.MatchString(tenantID) {
+			return webhookRoute{status: 404}, fmt.Errorf("unknown tenant")
+		}
+		route.tenantID = tenantID
+		route.requireOwnSecret = tenantID != tenantctx.DefaultTenantID
+	default:
+		if h.Router == nil {
+			return route, nil
+		}
+		route.tenantID = h.routeFromHint(ctx, hint)
+		route.requireOwnSecret = route.tenantID != tenantctx.DefaultTenantID
 	}
-	return fullName
+
+	if h.Router == nil {
+		return route, nil
+	}
+	tenant, err := h.Router.GetTenant(ctx, route.tenantID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return webhookRoute{status: 404}, fmt.Errorf("unknown tenant")
+		}
+		return webhookRoute{status: 500}, fmt.Errorf("load tenant failed: %v", err)
+	}
+	if !tenant.IsActive {
+		return webhookRoute{status: 403}, fmt.Errorf("tenant is inactive")
+	}
+	if schemes, err := ParseSignatureSchemes(tenant.SignatureSchemes); err == nil {
+		route.signatureSchemes = schemes
+	}
+	return route, nil
 }
 
-func extractSenderLogin(payload map[string]any) string {
-	sender, ok := payload["sender"].(map[string]any)
-	if !ok {
-		return "unknown"
+// routeFromHint maps the installation or repository named in the body to a
+// tenant, falling back to the default tenant.
+func (h *WebhookHandler) routeFromHint(ctx context.Context, hint webhookRouteHint) string {
+	if hint.installationID > 0 {
+		if tenantID, err := h.Router.ResolveWebhookRoute(ctx, store.WebhookRouteKindInstallation, fmt.Sprintf("%d", hint.installationID)); err == nil {
+			return tenantID
+		}
 	}
-	login, _ := sender["login"].(string)
-	if strings.TrimSpace(login) == "" {
+	if name := strings.ToLower(strings.TrimSpace(hint.repositoryFullName)); name != "" && name != "unknown" {
+		if tenantID, err := h.Router.ResolveWebhookRoute(ctx, store.WebhookRouteKindRepository, name); err == nil {
+			return tenantID
+		}
+	}
+	return tenantctx.DefaultTenantID
+}
+
+// resolveSecrets prefers the tenant's own webhook secrets over the global ones.
+func (h *WebhookHandler) resolveSecrets(ctx context.Context, requireOwnSecret bool, fallback service.WebhookSecretSet) (service.WebhookSecretSet, error) {
+	if h.Secrets != nil {
+		secrets, found, err := h.Secrets.WebhookSecrets(ctx)
+		if err != nil {
+			return service.WebhookSecretSet{}, err
+		}
+		if found {
+			return secrets, nil
+		}
+	}
+	if requireOwnSecret {
+		return service.WebhookSecretSet{}, errTenantSecretRequired
+	}
+	return fallback, nil
+}
+
+func (h *WebhookHandler) executorFor(provider string) WebhookActionExecutor {
+	if executor := h.Providers.Executor(provider); executor != nil {
+		return executor
+	}
+	if provider == store.EventSourceGitHub && h.ActionExecutor != nil {
+		return h.ActionExecutor
+	}
+	return nil
+}
+
+func (h *WebhookHandler) globalSecrets() service.WebhookSecretSet {
+	return service.WebhookSecretSet{Primary: h.Secret, Secondary: h.SecondarySecret, SecondaryExpiresAt: h.SecondarySecretExpiresAt}
+}
+
+func orUnknown(value string) string {
+	if strings.TrimSpace(value) == "" {
 		return "unknown"
 	}
-	return login
+	return value
 }
 
-func verifyGitHubSignature(signatureHeader string, body []byte, secret string) bool {
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(body)
-	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
-	return hmac.Equal([]byte(expected), []byte(signatureHeader))
+// extractInstallationID returns the GitHub App installation that delivered the
+// event, or 0 for repository webhooks.
+func extractInstallationID(payload map[string]any) int64 {
+	installation, ok := payload["installation"].(map[string]any)
+	if !ok {
+		return 0
+	}
+	id, _ := installation["id"].(float64)
+	if id <= 0 {
+		return 0
+	}
+	return int64(id)
 }
 
-func extractTargetNumber(eventType string, payload map[string]any) int {
-	if eventType == "issues" {
-		if issue, ok := payload["issue"].(map[string]any); ok {
-			if n, ok := issue["number"].(float64); ok {
-				return int(n)
-			}
-		}
+// tenantInstallationID returns the payload's installation only when it is
+// mapped to tenantID, through an installation route or the tenant's own
+// github_installation_id. Otherwise it returns 0 and the executor falls back
+// to the tenant's configured installation.
+func (h *WebhookHandler) tenantInstallationID(ctx context.Context, tenantID string, payload map[string]any) int64 {
+	installationID := extractInstallationID(payload)
+	if installationID <= 0 || h.Router == nil {
+		return 0
 	}
-	if eventType == "pull_request" {
-		if pr, ok := payload["pull_request"].(map[string]any); ok {
-			if n, ok := pr["number"].(float64); ok {
-				return int(n)
-			}
+	lookupCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if routed, err := h.Router.ResolveWebhookRoute(lookupCtx, store.WebhookRouteKindInstallation, fmt.Sprintf("%d", installationID)); err == nil {
+		if routed == tenantID {
+			return installationID
 		}
+		return 0
+	}
+	if tenant, err := h.Router.GetTenant(lookupCtx, tenantID); err == nil && tenant.GitHubInstallationID == installationID {
+		return installationID
 	}
 	return 0
 }
 
-func (h *WebhookHandler) executeWithRetry(ctx context.Context, repositoryFullName string, issueNumber int, action service.SuggestedAction) (error, int) {
+func (h *WebhookHandler) executeWithRetry(ctx context.Context, executor WebhookActionExecutor, repositoryFullName string, issueNumber int, action service.SuggestedAction) (error, int) {
 	const maxAttempts = 3
 	var lastErr error
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		switch action.Type {
 		case "label":
-			lastErr = h.ActionExecutor.AddLabel(ctx, repositoryFullName, issueNumber, action.Value)
+			lastErr = executor.AddLabel(ctx, repositoryFullName, issueNumber, action.Value)
 		case "comment":
-			lastErr = h.ActionExecutor.AddComment(ctx, repositoryFullName, issueNumber, action.Value)
+			lastErr = executor.AddComment(ctx, repositoryFullName, issueNumber, action.Value)
 		default:
 			return nil, attempt
 		}
 		if lastErr == nil {
 			return nil, attempt
 		}
-		if attempt < maxAttempts {
-			time.Sleep(time.Duration(attempt*100) * time.Millisecond)
+		if attempt == maxAttempts || service.IsPermanentActionError(lastErr) {
+			return lastErr, attempt
+		}
+		delay := time.Duration(attempt*100) * time.Millisecond
+		if retryAfter, limited := service.GitHubRetryDelay(lastErr, time.Now()); limited {
+			// Waiting out a rate limit would outlive the request; leave it to the background retrier.
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(retryAfter).After(deadline) {
+				return lastErr, attempt
+			}
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return lastErr, attempt
+		case <-time.After(delay):
 		}
 	}
 	return lastErr, maxAttempts
 }
+
+// annotateDelivery labels the request span and log fields with the delivery
+// the request carried.
+func annotateDelivery(c *gin.Context, source string, tenantID string, deliveryID string, eventType string, outcome string) {
+	logging.SetTenantID(c.Request.Context(), tenantID)
+	logging.SetDeliveryID(c.Request.Context(), deliveryID)
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(
+		attribute.String("mf.source", source),
+		attribute.String("mf.tenant_id", tenantID),
+		attribute.String("mf.delivery_id", deliveryID),
+		attribute.String("mf.event_type", eventType),
+		attribute.String("mf.outcome", outcome),
+	)
+}
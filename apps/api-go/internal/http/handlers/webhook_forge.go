@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/logging"
+	"maintainer-firewall/api-go/internal/metrics"
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookForge describes how a non-GitHub source delivers webhooks over HTTP.
+// Parsing and action execution come from the provider registered under source.
+type webhookForge struct {
+	source      string
+	eventHeader string
+	deliveryID  string
+	verifiers   []SignatureVerifier
+	secret      string
+}
+
+// handleForgeDelivery authenticates, routes and ingests a delivery from another
+// forge the same way GitHub deliveries are handled.
+func (h *WebhookHandler) handleForgeDelivery(c *gin.Context, forge webhookForge) {
+	startedAt := time.Now().UTC()
+	deliverySuccess := false
+	outcome := ""
+	matchedSecret := ""
+	tenantID := tenantctx.DefaultTenantID
+	deliveryID := strings.TrimSpace(forge.deliveryID)
+	if deliveryID == "" {
+		deliveryID = fmt.Sprintf("%s-missing-%d", forge.source, startedAt.UnixNano())
+	}
+	eventName := strings.TrimSpace(c.GetHeader(forge.eventHeader))
+	logging.SetDeliveryID(c.Request.Context(), deliveryID)
+
+	defer func() {
+		if h.Store == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Second)
+		defer cancel()
+		ctx = tenantctx.WithTenantID(ctx, tenantID)
+		eventType := eventName
+		if eventType == "" {
+			eventType = "unknown"
+		}
+		if outcome == "" {
+			outcome = store.DeliveryOutcomeFailed
+			if deliverySuccess {
+				outcome = store.DeliveryOutcomeProcessed
+			}
+		}
+		annotateDelivery(c, forge.source, tenantID, deliveryID, eventType, outcome)
+		metrics.WebhookRequests.WithLabelValues(forge.source, metrics.WebhookEventType(eventType), outcome).Inc()
+		metrics.WebhookProcessingSeconds.WithLabelValues(forge.source, outcome).Observe(time.Since(startedAt).Seconds())
+		_ = h.Store.SaveDeliveryMetric(ctx, store.DeliveryMetric{
+			EventType:     eventType,
+			DeliveryID:    deliveryID,
+			Success:       deliverySuccess,
+			Outcome:       outcome,
+			MatchedSecret: matchedSecret,
+			ProcessingMS:  time.Since(startedAt).Milliseconds(),
+			RecordedAtUTC: time.Now().UTC(),
+		})
+	}()
+
+	if h.Store == nil {
+		c.JSON(500, webhookResponse{OK: false, Message: "event store is not configured"})
+		return
+	}
+
+	var verifiers []SignatureVerifier
+	for _, v := range forge.verifiers {
+		if v.Present(c.Request.Header) {
+			verifiers = append(verifiers, v)
+		}
+	}
+	if len(verifiers) == 0 {
+		outcome = store.DeliveryOutcomeUnauthorized
+		c.JSON(401, webhookResponse{OK: false, Message: fmt.Sprintf("missing %s webhook signature", forge.source)})
+		return
+	}
+
+	body, tooLarge, err := h.readBody(c)
+	if err != nil {
+		c.JSON(400, webhookResponse{OK: false, Message: "failed to read request body"})
+		return
+	}
+
+	// Parse before routing since the repository picks the tenant. A rejected
+	// payload is only reported once the signature has been checked.
+	hint := webhookRouteHint{}
+	var normalized service.NormalizedEvent
+	var parseErr error
+	if !tooLarge {
+		normalized, parseErr = h.parseAndValidate(forge.source, eventName, body)
+		var rejected *deliveryRejectedError
+		if parseErr != nil && !errors.As(parseErr, &rejected) {
+			c.JSON(500, webhookResponse{OK: false, Message: parseErr.Error()})
+			return
+		}
+		hint.repositoryFullName = normalized.Repository
+	}
+
+	route, err := h.routeDelivery(c, hint)
+	if err != nil {
+		outcome = store.DeliveryOutcomeMisrouted
+		c.JSON(route.status, webhookResponse{OK: false, Message: err.Error()})
+		return
+	}
+	if route.tenantID != "" {
+		tenantID = route.tenantID
+	}
+	logging.SetTenantID(c.Request.Context(), tenantID)
+	if tooLarge {
+		outcome = store.DeliveryOutcomeTooLarge
+		h.rejectOversized(c)
+		return
+	}
+
+	fallback := h.globalSecrets()
+	if strings.TrimSpace(forge.secret) != "" {
+		fallback = service.WebhookSecretSet{Primary: forge.secret}
+	}
+	secrets, err := h.resolveSecrets(tenantctx.WithTenantID(c.Request.Context(), tenantID), route.requireOwnSecret, fallback)
+	if err != nil {
+		if errors.Is(err, errTenantSecretRequired) {
+			outcome = store.DeliveryOutcomeMisrouted
+			c.JSON(401, webhookResponse{OK: false, Message: err.Error()})
+			return
+		}
+		c.JSON(500, webhookResponse{OK: false, Message: fmt.Sprintf("failed to load webhook secret: %v", err)})
+		return
+	}
+
+verify:
+	for _, v := range verifiers {
+		for _, candidate := range secrets.Candidates(time.Now()) {
+			if v.Verify(c.Request.Header, body, candidate.Secret) {
+				matchedSecret = candidate.Slot
+				break verify
+			}
+		}
+	}
+	if matchedSecret == "" {
+		outcome = store.DeliveryOutcomeUnauthorized
+		c.JSON(401, webhookResponse{OK: false, Message: "signature verification failed"})
+		return
+	}
+
+	if parseErr != nil {
+		if id := h.rejectMalformed(c, tenantID, forge.source, eventName, deliveryID, body, parseErr); id > 0 {
+			outcome = store.DeliveryOutcomeQuarantined
+		}
+		return
+	}
+
+	deliverySuccess = h.ingest(c, c.Request.Context(), tenantID, deliveryID, normalized)
+}
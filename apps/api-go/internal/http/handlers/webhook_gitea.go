@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"strings"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gitea ingests Gitea and Forgejo webhooks, verified by the hex HMAC-SHA256 in
+// X-Gitea-Signature (or X-Forgejo-Signature).
+func (h *WebhookHandler) Gitea(c *gin.Context) {
+	eventHeader := "X-Gitea-Event"
+	if c.GetHeader(eventHeader) == "" && c.GetHeader("X-Forgejo-Event") != "" {
+		eventHeader = "X-Forgejo-Event"
+	}
+	deliveryID := strings.TrimSpace(c.GetHeader("X-Gitea-Delivery"))
+	if deliveryID == "" {
+		deliveryID = strings.TrimSpace(c.GetHeader("X-Forgejo-Delivery"))
+	}
+	if deliveryID != "" {
+		deliveryID = "gitea-" + deliveryID
+	}
+
+	h.handleForgeDelivery(c, webhookForge{
+		source:      store.EventSourceGitea,
+		eventHeader: eventHeader,
+		deliveryID:  deliveryID,
+		verifiers: []SignatureVerifier{
+			NewGiteaSignatureVerifier("X-Gitea-Signature"),
+			NewGiteaSignatureVerifier("X-Forgejo-Signature"),
+		},
+		secret: h.GiteaSecret,
+	})
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"strings"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GitLab ingests GitLab project and group webhooks. GitLab cannot sign payloads,
+// so the delivery is authenticated by comparing X-Gitlab-Token with the tenant's
+// webhook secret. Issue, merge request and note hooks are normalised to their
+// GitHub equivalents before rules run.
+func (h *WebhookHandler) GitLab(c *gin.Context) {
+	h.handleForgeDelivery(c, webhookForge{
+		source:      store.EventSourceGitLab,
+		eventHeader: "X-Gitlab-Event",
+		deliveryID:  gitLabDeliveryID(c),
+		verifiers:   []SignatureVerifier{NewSharedTokenVerifier("X-Gitlab-Token")},
+		secret:      h.GitLabSecret,
+	})
+}
+
+// gitLabDeliveryID uses the per-event UUID; X-Gitlab-Webhook-UUID identifies the
+// hook rather than the delivery, so it cannot be used.
+func gitLabDeliveryID(c *gin.Context) string {
+	for _, header := range []string{"X-Gitlab-Event-UUID", "Idempotency-Key"} {
+		if id := strings.TrimSpace(c.GetHeader(header)); id != "" {
+			return "gitlab-" + id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// WebhookRepositoryRegistry is updated from GitHub installation and repository
+// lifecycle events.
+type WebhookRepositoryRegistry interface {
+	UpsertRepositories(ctx context.Context, items []store.RepositoryRecord) error
+	DeactivateRepositories(ctx context.Context, source string, externalIDs []int64) error
+	DeactivateInstallationRepositories(ctx context.Context, installationID int64) error
+	RenameRepository(ctx context.Context, item store.RepositoryRecord, oldFullName string) (int64, error)
+}
+
+// applyRepositoryLifecycle keeps the tenant's repository registry and stored
+// repository names in step with installation, installation_repositories and
+// repository events. Other events are ignored.
+func (h *WebhookHandler) applyRepositoryLifecycle(ctx context.Context, evt service.NormalizedEvent) error {
+	if h.Repositories == nil {
+		return nil
+	}
+	payload := evt.Payload
+	installationID := extractInstallationID(payload)
+
+	switch evt.EventType {
+	case "installation":
+		switch evt.Action {
+		case "created", "new_permissions_accepted", "unsuspend":
+			return h.Repositories.UpsertRepositories(ctx, repositoryRecords(payload["repositories"], installationID))
+		case "deleted", "suspend":
+			if installationID > 0 {
+				return h.Repositories.DeactivateInstallationRepositories(ctx, installationID)
+			}
+		}
+	case "installation_repositories":
+		switch evt.Action {
+		case "added":
+			return h.Repositories.UpsertRepositories(ctx, repositoryRecords(payload["repositories_added"], installationID))
+		case "removed":
+			return h.Repositories.DeactivateRepositories(ctx, store.EventSourceGitHub, repositoryIDs(payload["repositories_removed"]))
+		}
+	case "repository":
+		repo, _ := payload["repository"].(map[string]any)
+		records := repositoryRecords([]any{repo}, installationID)
+		if len(records) == 0 {
+			return nil
+		}
+		switch evt.Action {
+		case "created", "unarchived", "publicized", "privatized":
+			return h.Repositories.UpsertRepositories(ctx, records)
+		case "deleted":
+			return h.Repositories.DeactivateRepositories(ctx, store.EventSourceGitHub, []int64{records[0].ExternalID})
+		case "renamed", "transferred":
+			oldFullName := previousRepositoryFullName(evt.Action, payload, records[0].FullName)
+			_, err := h.Repositories.RenameRepository(ctx, records[0], oldFullName)
+			return err
+		}
+	}
+	return nil
+}
+
+func repositoryRecords(raw any, installationID int64) []store.RepositoryRecord {
+	list, _ := raw.([]any)
+	items := make([]store.RepositoryRecord, 0, len(list))
+	for _, entry := range list {
+		repo, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := repo["id"].(float64)
+		fullName, _ := repo["full_name"].(string)
+		if id <= 0 || strings.TrimSpace(fullName) == "" {
+			continue
+		}
+		items = append(items, store.RepositoryRecord{
+			Source:         store.EventSourceGitHub,
+			ExternalID:     int64(id),
+			FullName:       fullName,
+			InstallationID: installationID,
+		})
+	}
+	return items
+}
+
+func repositoryIDs(raw any) []int64 {
+	records := repositoryRecords(raw, 0)
+	ids := make([]int64, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.ExternalID)
+	}
+	return ids
+}
+
+// previousRepositoryFullName rebuilds the pre-change name from the "changes" block:
+// renames report the old name, transfers the old owner.
+func previousRepositoryFullName(action string, payload map[string]any, fullName string) string {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return ""
+	}
+	changes, _ := payload["changes"].(map[string]any)
+	switch action {
+	case "renamed":
+		repo, _ := changes["repository"].(map[string]any)
+		nameChange, _ := repo["name"].(map[string]any)
+		if from, _ := nameChange["from"].(string); from != "" {
+			return fmt.Sprintf("%s/%s", owner, from)
+		}
+	case "transferred":
+		ownerChange, _ := changes["owner"].(map[string]any)
+		from, _ := ownerChange["from"].(map[string]any)
+		for _, key := range []string{"organization", "user"} {
+			if account, ok := from[key].(map[string]any); ok {
+				if login, _ := account["login"].(string); login != "" {
+					return fmt.Sprintf("%s/%s", login, name)
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultWebhookMaxBodyBytes caps webhook bodies when MaxBodyBytes is unset.
+const DefaultWebhookMaxBodyBytes int64 = 5 << 20
+
+type WebhookQuarantineSaver interface {
+	SaveQuarantinedDelivery(ctx context.Context, item store.QuarantinedDelivery) (int64, error)
+}
+
+// deliveryRejectedError is a delivery that parsed badly or failed schema
+// validation; status is the HTTP status reported to the sender.
+type deliveryRejectedError struct {
+	status int
+	err    error
+}
+
+func (e *deliveryRejectedError) Error() string { return e.err.Error() }
+
+// redactedWebhookHeaders carry secrets and are not kept with quarantined deliveries.
+var redactedWebhookHeaders = map[string]bool{
+	"Authorization":  true,
+	"Cookie":         true,
+	"X-Gitlab-Token": true,
+	http.CanonicalHeaderKey(SharedTokenHeader): true,
+}
+
+func (h *WebhookHandler) maxBodyBytes() int64 {
+	if h.MaxBodyBytes > 0 {
+		return h.MaxBodyBytes
+	}
+	return DefaultWebhookMaxBodyBytes
+}
+
+// readBody reads at most maxBodyBytes; tooLarge reports a body over the limit.
+func (h *WebhookHandler) readBody(c *gin.Context) ([]byte, bool, error) {
+	limit := h.maxBodyBytes()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > limit {
+		return nil, true, nil
+	}
+	return body, false, nil
+}
+
+// parseAndValidate normalises a delivery through its provider and checks the
+// result against the minimal schema for its event type.
+func (h *WebhookHandler) parseAndValidate(source string, eventName string, body []byte) (service.NormalizedEvent, error) {
+	provider, ok := h.Providers.Lookup(source)
+	if !ok {
+		return service.NormalizedEvent{}, fmt.Errorf("%s provider is not registered", source)
+	}
+	normalized, err := provider.Parse(eventName, body)
+	if err != nil {
+		return service.NormalizedEvent{}, &deliveryRejectedError{status: 400, err: err}
+	}
+	if err := service.ValidatePayload(normalized.EventType, normalized.Payload); err != nil {
+		return service.NormalizedEvent{}, &deliveryRejectedError{status: 422, err: err}
+	}
+	return normalized, nil
+}
+
+// quarantine stores a rejected delivery for the tenant and returns its id, or 0
+// when no quarantine store is configured or saving failed.
+func (h *WebhookHandler) quarantine(c *gin.Context, tenantID string, item store.QuarantinedDelivery) int64 {
+	if h.Quarantine == nil {
+		return 0
+	}
+	headers := map[string]string{}
+	for name, values := range c.Request.Header {
+		if redactedWebhookHeaders[name] {
+			headers[name] = "[redacted]"
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	headersJSON, _ := json.Marshal(headers)
+	item.HeadersJSON = string(headersJSON)
+
+	ctx, cancel := context.WithTimeout(tenantctx.WithTenantID(context.WithoutCancel(c.Request.Context()), tenantID), 2*time.Second)
+	defer cancel()
+	id, err := h.Quarantine.SaveQuarantinedDelivery(ctx, item)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// rejectOversized answers 413. The signature cannot be checked without the
+// full body, so nothing from the delivery is kept.
+func (h *WebhookHandler) rejectOversized(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, webhookResponse{OK: false, Message: fmt.Sprintf("payload exceeds %d bytes", h.maxBodyBytes())})
+}
+
+// rejectMalformed reports a parse or validation failure, quarantining the raw body.
+func (h *WebhookHandler) rejectMalformed(c *gin.Context, tenantID string, source string, eventName string, deliveryID string, body []byte, err error) int64 {
+	var rejected *deliveryRejectedError
+	if !errors.As(err, &rejected) {
+		c.JSON(500, webhookResponse{OK: false, Message: err.Error()})
+		return 0
+	}
+	id := h.quarantine(c, tenantID, store.QuarantinedDelivery{Source: source, DeliveryID: deliveryID, EventName: eventName, Reason: err.Error(), Payload: body})
+	c.JSON(rejected.status, webhookResponse{OK: false, Message: err.Error(), QuarantineID: id})
+	return id
+}
+
+// Reingest runs a quarantined delivery through the normal pipeline. Its signature
+// no longer covers a fixed-up body and is not re-checked, so only admins may call it.
+func (h *WebhookHandler) Reingest(ctx context.Context, tenantID string, item store.QuarantinedDelivery) ([]service.SuggestedAction, error) {
+	if len(item.Payload) == 0 {
+		return nil, &deliveryRejectedError{status: 400, err: fmt.Errorf("quarantined delivery has no payload; fix it up first")}
+	}
+	deliveryID := strings.TrimSpace(item.DeliveryID)
+	if deliveryID == "" {
+		deliveryID = fmt.Sprintf("quarantine-%d", item.ID)
+	}
+	return h.IngestPayload(ctx, tenantID, item.Source, item.EventName, deliveryID, item.Payload)
+}
+
+// IngestPayload validates and processes a delivery body obtained outside the
+// webhook endpoint, so it has no signature to check. Pings are ignored.
+func (h *WebhookHandler) IngestPayload(ctx context.Context, tenantID string, source string, eventName string, deliveryID string, body []byte) ([]service.SuggestedAction, error) {
+	if h.Store == nil {
+		return nil, fmt.Errorf("event store is not configured")
+	}
+	normalized, err := h.parseAndValidate(source, eventName, body)
+	if err != nil {
+		return nil, err
+	}
+	if normalized.EventType == "ping" {
+		return nil, nil
+	}
+	result, err := h.processDelivery(ctx, tenantID, deliveryID, normalized, deliveryOptions{executeActions: true})
+	return result.suggestions, err
+}
+
+type WebhookQuarantineStore interface {
+	ListQuarantinedDeliveries(ctx context.Context, limit int, offset int, status string) ([]store.QuarantinedDelivery, int64, error)
+	GetQuarantinedDelivery(ctx context.Context, id int64) (store.QuarantinedDelivery, error)
+	UpdateQuarantinedPayload(ctx context.Context, id int64, payload []byte, actor string) error
+	ClaimQuarantinedDelivery(ctx context.Context, id int64, actor string) (bool, error)
+	ReleaseQuarantinedDelivery(ctx context.Context, id int64) error
+	ResolveQuarantinedDelivery(ctx context.Context, id int64, status string, note string, actor string) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type WebhookReingester interface {
+	Reingest(ctx context.Context, tenantID string, item store.QuarantinedDelivery) ([]service.SuggestedAction, error)
+}
+
+// QuarantineHandler lets admins inspect, fix up, re-ingest or discard rejected
+// webhook deliveries.
+type QuarantineHandler struct {
+	Store    WebhookQuarantineStore
+	Ingester WebhookReingester
+}
+
+type listQuarantineResponse struct {
+	OK     bool                        `json:"ok"`
+	Items  []store.QuarantinedDelivery `json:"items"`
+	Total  int64                       `json:"total"`
+	Limit  int                         `json:"limit"`
+	Offset int                         `json:"offset"`
+	Status string                      `json:"status,omitempty"`
+}
+
+type updateQuarantinePayloadRequest struct {
+	Payload json.RawMessage `json:"payload"`
+}
+
+type discardQuarantineRequest struct {
+	Note string `json:"note"`
+}
+
+func NewQuarantineHandler(s WebhookQuarantineStore, ingester WebhookReingester) *QuarantineHandler {
+	return &QuarantineHandler{Store: s, Ingester: ingester}
+}
+
+func (h *QuarantineHandler) List(c *gin.Context) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "quarantine store is not configured"})
+		return
+	}
+	limit := parseIntOrDefault(c.Query("limit"), 20)
+	offset := parseIntOrDefault(c.Query("offset"), 0)
+	status := strings.TrimSpace(c.DefaultQuery("status", store.QuarantineStatusPending))
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if status == "all" {
+		status = ""
+	}
+	if status != "" && !store.IsValidQuarantineStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "status must be pending, reingesting, reingested, discarded or all"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	items, total, err := h.Store.ListQuarantinedDeliveries(ctx, limit, offset, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list quarantined deliveries failed: %v", err)})
+		return
+	}
+	c.JSON(http.StatusOK, listQuarantineResponse{OK: true, Items: items, Total: total, Limit: limit, Offset: offset, Status: status})
+}
+
+func (h *QuarantineHandler) Get(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "item": item, "payload": string(item.Payload)})
+}
+
+// UpdatePayload replaces the body of a pending delivery, e.g. to repair a field
+// that failed validation.
+func (h *QuarantineHandler) UpdatePayload(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req updateQuarantinePayloadRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Payload) == 0 || string(req.Payload) == "null" {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "payload is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	actor := requestActor(c)
+	if err := h.Store.UpdateQuarantinedPayload(ctx, item.ID, req.Payload, actor); err != nil {
+		h.writeStoreError(c, "update quarantined payload", err)
+		return
+	}
+	h.audit(ctx, actor, "webhook.quarantine.update", item, fmt.Sprintf(`{"bytes":%d}`, len(req.Payload)))
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": item.ID})
+}
+
+func (h *QuarantineHandler) Reingest(c *gin.Context) {
+	if h.Ingester == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "webhook ingestion is not configured"})
+		return
+	}
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if item.Status != store.QuarantineStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"ok": false, "message": fmt.Sprintf("delivery is already %s", item.Status)})
+		return
+	}
+
+	// Claim the delivery first so concurrent re-ingests cannot both run it.
+	actor := requestActor(c)
+	claimCtx, cancelClaim := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	claimed, err := h.Store.ClaimQuarantinedDelivery(claimCtx, item.ID, actor)
+	cancelClaim()
+	if err != nil {
+		h.writeStoreError(c, "claim quarantined delivery", err)
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "delivery is already being re-ingested or resolved"})
+		return
+	}
+
+	tenantID := tenantctx.MustFromContext(c.Request.Context(), tenantctx.DefaultTenantID)
+	suggestions, err := h.Ingester.Reingest(c.Request.Context(), tenantID, item)
+	if err != nil {
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 3*time.Second)
+		_ = h.Store.ReleaseQuarantinedDelivery(releaseCtx, item.ID)
+		cancelRelease()
+		status := http.StatusInternalServerError
+		var rejected *deliveryRejectedError
+		if errors.As(err, &rejected) {
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{"ok": false, "message": fmt.Sprintf("re-ingest failed: %v", err)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.ResolveQuarantinedDelivery(ctx, item.ID, store.QuarantineStatusReingested, "", actor); err != nil {
+		h.writeStoreError(c, "mark quarantined delivery re-ingested", err)
+		return
+	}
+	h.audit(ctx, actor, "webhook.quarantine.reingest", item, fmt.Sprintf(`{"suggestions":%d}`, len(suggestions)))
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": item.ID, "suggested_actions": suggestions})
+}
+
+// Discard also closes a delivery left reingesting by a request that died mid-way.
+func (h *QuarantineHandler) Discard(c *gin.Context) {
+	item, ok := h.load(c)
+	if !ok {
+		return
+	}
+	var req discardQuarantineRequest
+	_ = c.ShouldBindJSON(&req)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	actor := requestActor(c)
+	if err := h.Store.ResolveQuarantinedDelivery(ctx, item.ID, store.QuarantineStatusDiscarded, strings.TrimSpace(req.Note), actor); err != nil {
+		h.writeStoreError(c, "discard quarantined delivery", err)
+		return
+	}
+	h.audit(ctx, actor, "webhook.quarantine.discard", item, "")
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": item.ID})
+}
+
+func (h *QuarantineHandler) load(c *gin.Context) (store.QuarantinedDelivery, bool) {
+	if h.Store == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "quarantine store is not configured"})
+		return store.QuarantinedDelivery{}, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid quarantine id"})
+		return store.QuarantinedDelivery{}, false
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	item, err := h.Store.GetQuarantinedDelivery(ctx, id)
+	if err != nil {
+		h.writeStoreError(c, "load quarantined delivery", err)
+		return store.QuarantinedDelivery{}, false
+	}
+	return item, true
+}
+
+func (h *QuarantineHandler) writeStoreError(c *gin.Context, op string, err error) {
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("%s failed: %v", op, err)})
+}
+
+func (h *QuarantineHandler) audit(ctx context.Context, actor string, action string, item store.QuarantinedDelivery, payload string) {
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   action,
+		Target:   "webhook_quarantine",
+		TargetID: strconv.FormatInt(item.ID, 10),
+		Payload:  payload,
+	})
+}
+
+func requestActor(c *gin.Context) string {
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+	return actor
+}
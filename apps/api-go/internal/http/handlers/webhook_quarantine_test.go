@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/service"
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockQuarantineStore struct {
+	items  []store.QuarantinedDelivery
+	audits []store.AuditLogRecord
+}
+
+func (m *mockQuarantineStore) SaveQuarantinedDelivery(_ context.Context, item store.QuarantinedDelivery) (int64, error) {
+	item.ID = int64(len(m.items) + 1)
+	item.Status = store.QuarantineStatusPending
+	m.items = append(m.items, item)
+	return item.ID, nil
+}
+
+func (m *mockQuarantineStore) ListQuarantinedDeliveries(_ context.Context, _ int, _ int, status string) ([]store.QuarantinedDelivery, int64, error) {
+	out := []store.QuarantinedDelivery{}
+	for _, item := range m.items {
+		if status == "" || item.Status == status {
+			out = append(out, item)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *mockQuarantineStore) GetQuarantinedDelivery(_ context.Context, id int64) (store.QuarantinedDelivery, error) {
+	if id < 1 || int(id) > len(m.items) {
+		return store.QuarantinedDelivery{}, fmt.Errorf("quarantined delivery not found")
+	}
+	return m.items[id-1], nil
+}
+
+func (m *mockQuarantineStore) UpdateQuarantinedPayload(_ context.Context, id int64, payload []byte, _ string) error {
+	m.items[id-1].Payload = payload
+	return nil
+}
+
+func (m *mockQuarantineStore) ClaimQuarantinedDelivery(_ context.Context, id int64, _ string) (bool, error) {
+	if m.items[id-1].Status != store.QuarantineStatusPending {
+		return false, nil
+	}
+	m.items[id-1].Status = store.QuarantineStatusReingesting
+	return true, nil
+}
+
+func (m *mockQuarantineStore) ReleaseQuarantinedDelivery(_ context.Context, id int64) error {
+	if m.items[id-1].Status == store.QuarantineStatusReingesting {
+		m.items[id-1].Status = store.QuarantineStatusPending
+	}
+	return nil
+}
+
+func (m *mockQuarantineStore) ResolveQuarantinedDelivery(_ context.Context, id int64, status string, note string, _ string) error {
+	m.items[id-1].Status = status
+	m.items[id-1].Note = note
+	return nil
+}
+
+func (m *mockQuarantineStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+func TestWebhookGitHub_OversizedBodyIsRejectedWithoutQuarantine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := "test-secret"
+	body := []byte(`{"action":"opened","issue":{"title":"` + strings.Repeat("x", 2048) + `"}}`)
+	mockStore := &mockWebhookStore{}
+	quarantine := &mockQuarantineStore{}
+	h := NewWebhookHandler(secret, mockStore)
+	h.Quarantine = quarantine
+	h.MaxBodyBytes = 1024
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signBody(secret, body))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-GitHub-Delivery", "big-1")
+	req.Header.Set(SharedTokenHeader, "shared-token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(mockStore.saved) != 0 {
+		t.Fatalf("oversized delivery must not be stored as an event")
+	}
+	// The body is unverified, so nothing from it may be persisted.
+	if len(quarantine.items) != 0 {
+		t.Fatalf("oversized delivery must not be quarantined, got %+v", quarantine.items)
+	}
+	if got := mockStore.savedDeliveryMets[0].Outcome; got != store.DeliveryOutcomeTooLarge {
+		t.Fatalf("expected too_large outcome, got %q", got)
+	}
+}
+
+func TestWebhookGitHub_MalformedPayloadIsQuarantined(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := "test-secret"
+	cases := []struct {
+		name   string
+		event  string
+		body   string
+		status int
+	}{
+		{name: "invalid json", event: "issues", body: `{"action":`, status: http.StatusBadRequest},
+		{name: "wrong type", event: "issues", body: `{"action":"opened","issue":{"number":"12"}}`, status: http.StatusUnprocessableEntity},
+		{name: "missing required", event: "repository", body: `{"action":"renamed","repository":{"id":1}}`, status: http.StatusUnprocessableEntity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStore := &mockWebhookStore{}
+			quarantine := &mockQuarantineStore{}
+			h := NewWebhookHandler(secret, mockStore)
+			h.Quarantine = quarantine
+
+			r := gin.New()
+			r.POST("/webhook/github", h.GitHub)
+			body := []byte(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader(body))
+			req.Header.Set("X-Hub-Signature-256", signBody(secret, body))
+			req.Header.Set("X-GitHub-Event", tc.event)
+			req.Header.Set("X-GitHub-Delivery", "bad-1")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status {
+				t.Fatalf("expected %d, got %d body=%s", tc.status, w.Code, w.Body.String())
+			}
+			if len(mockStore.saved) != 0 {
+				t.Fatalf("rejected delivery must not be stored as an event")
+			}
+			if len(quarantine.items) != 1 || string(quarantine.items[0].Payload) != tc.body {
+				t.Fatalf("expected raw body to be quarantined, got %+v", quarantine.items)
+			}
+			var resp webhookResponse
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp.QuarantineID != 1 {
+				t.Fatalf("expected quarantine_id in response, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookGitHub_UnsignedMalformedPayloadIsNotQuarantined(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	quarantine := &mockQuarantineStore{}
+	h := NewWebhookHandler("test-secret", &mockWebhookStore{})
+	h.Quarantine = quarantine
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	req := httptest.NewRequest(http.MethodPost, "/webhook/github", strings.NewReader(`{"action":`))
+	req.Header.Set("X-Hub-Signature-256", "sha256=invalid")
+	req.Header.Set("X-GitHub-Event", "issues")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized || len(quarantine.items) != 0 {
+		t.Fatalf("expected 401 without quarantine, got %d and %d records", w.Code, len(quarantine.items))
+	}
+}
+
+func TestQuarantineHandler_FixUpAndReingest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockStore := &mockWebhookStore{}
+	ingester := NewWebhookHandler("test-secret", mockStore)
+	quarantine := &mockQuarantineStore{items: []store.QuarantinedDelivery{{
+		ID: 1, Source: store.EventSourceGitHub, DeliveryID: "bad-1", EventName: "issues",
+		Status: store.QuarantineStatusPending, Payload: []byte(`{"action":`),
+	}}}
+	h := NewQuarantineHandler(quarantine, ingester)
+
+	r := gin.New()
+	r.PUT("/webhook-quarantine/:id/payload", h.UpdatePayload)
+	r.POST("/webhook-quarantine/:id/reingest", h.Reingest)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook-quarantine/1/reingest", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a still-broken payload, got %d body=%s", w.Code, w.Body.String())
+	}
+	if quarantine.items[0].Status != store.QuarantineStatusPending {
+		t.Fatalf("failed re-ingest must keep the delivery pending")
+	}
+
+	fixed := `{"payload":{"action":"opened","repository":{"full_name":"owner/repo"},"issue":{"number":3,"title":"fixed"}}}`
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/webhook-quarantine/1/payload", strings.NewReader(fixed)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on payload update, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook-quarantine/1/reingest", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on re-ingest, got %d body=%s", w.Code, w.Body.String())
+	}
+	if len(mockStore.saved) != 1 || mockStore.saved[0].DeliveryID != "bad-1" || mockStore.saved[0].RepositoryFullName != "owner/repo" {
+		t.Fatalf("expected re-ingested event to be stored, got %+v", mockStore.saved)
+	}
+	if quarantine.items[0].Status != store.QuarantineStatusReingested {
+		t.Fatalf("expected delivery to be marked reingested, got %q", quarantine.items[0].Status)
+	}
+	if len(quarantine.audits) != 2 || quarantine.audits[1].Action != "webhook.quarantine.reingest" {
+		t.Fatalf("unexpected audit logs: %+v", quarantine.audits)
+	}
+}
+
+type blockingReingester struct {
+	started chan struct{}
+	release chan struct{}
+	calls   int
+}
+
+func (b *blockingReingester) Reingest(_ context.Context, _ string, _ store.QuarantinedDelivery) ([]service.SuggestedAction, error) {
+	b.calls++
+	close(b.started)
+	<-b.release
+	return nil, nil
+}
+
+func TestQuarantineHandler_ReingestClaimsBeforeRunning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	quarantine := &mockQuarantineStore{items: []store.QuarantinedDelivery{{
+		ID: 1, Source: store.EventSourceGitHub, DeliveryID: "bad-1", EventName: "issues",
+		Status: store.QuarantineStatusPending, Payload: []byte(`{}`),
+	}}}
+	ingester := &blockingReingester{started: make(chan struct{}), release: make(chan struct{})}
+	h := NewQuarantineHandler(quarantine, ingester)
+	r := gin.New()
+	r.POST("/webhook-quarantine/:id/reingest", h.Reingest)
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/webhook-quarantine/1/reingest", nil))
+		close(done)
+	}()
+	<-ingester.started
+
+	// A second request while the first is still running must not ingest again.
+	second := httptest.NewRecorder()
+	r.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/webhook-quarantine/1/reingest", nil))
+	close(ingester.release)
+	<-done
+
+	if second.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for the concurrent re-ingest, got %d body=%s", second.Code, second.Body.String())
+	}
+	if first.Code != http.StatusOK || ingester.calls != 1 {
+		t.Fatalf("expected exactly one re-ingest, got code=%d calls=%d", first.Code, ingester.calls)
+	}
+	if quarantine.items[0].Status != store.QuarantineStatusReingested {
+		t.Fatalf("expected delivery to end reingested, got %q", quarantine.items[0].Status)
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRouteStore interface {
+	ListWebhookRoutes(ctx context.Context, tenantID string) ([]store.WebhookRouteRecord, error)
+	CreateWebhookRoute(ctx context.Context, item store.WebhookRouteRecord) (int64, error)
+	DeleteWebhookRoute(ctx context.Context, tenantID string, id int64) error
+	SaveAuditLog(ctx context.Context, item store.AuditLogRecord) error
+}
+
+type WebhookRoutesHandler struct {
+	Store WebhookRouteStore
+}
+
+type createWebhookRouteRequest struct {
+	Kind  string `json:"kind" binding:"required"`
+	Value string `json:"value"`
+	// Provider picks the forge endpoint returned for token routes; the token
+	// itself is accepted on every forge endpoint.
+	Provider string `json:"provider"`
+}
+
+func NewWebhookRoutesHandler(s WebhookRouteStore) *WebhookRoutesHandler {
+	return &WebhookRoutesHandler{Store: s}
+}
+
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (h *WebhookRoutesHandler) List(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	items, err := h.Store.ListWebhookRoutes(ctx, tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("list webhook routes failed: %v", err)})
+		return
+	}
+	for i := range items {
+		// Only the token hash is stored; there is nothing useful to show.
+		if items[i].Kind == store.WebhookRouteKindToken {
+			items[i].MatchValue = ""
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "items": items})
+}
+
+// Create adds a routing rule. For kind=token a random token is generated and
+// returned once; only its hash is stored.
+func (h *WebhookRoutesHandler) Create(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+
+	var req createWebhookRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid JSON payload"})
+		return
+	}
+	kind := strings.TrimSpace(req.Kind)
+	value := strings.TrimSpace(req.Value)
+	provider := strings.ToLower(strings.TrimSpace(req.Provider))
+	switch provider {
+	case "":
+		provider = store.EventSourceGitHub
+	case store.EventSourceGitHub, store.EventSourceGitLab, store.EventSourceGitea:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "provider must be github, gitlab or gitea"})
+		return
+	}
+	token := ""
+	switch kind {
+	case store.WebhookRouteKindToken:
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": "generate token failed"})
+			return
+		}
+		token = hex.EncodeToString(buf)
+		value = hashWebhookToken(token)
+	case store.WebhookRouteKindRepository:
+		value = strings.ToLower(value)
+		if parts := strings.Split(value, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "value must be owner/repo"})
+			return
+		}
+	case store.WebhookRouteKindInstallation:
+		if id, err := strconv.ParseInt(value, 10, 64); err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "value must be a positive installation id"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "kind must be token, repository or installation"})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	id, err := h.Store.CreateWebhookRoute(ctx, store.WebhookRouteRecord{TenantID: tenantID, Kind: kind, MatchValue: value, CreatedBy: actor})
+	if err != nil {
+		if store.IsDuplicateKeyError(err) || strings.Contains(strings.ToLower(err.Error()), "duplicate") {
+			c.JSON(http.StatusConflict, gin.H{"ok": false, "message": "route already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("create webhook route failed: %v", err)})
+		return
+	}
+
+	auditValue := value
+	if kind == store.WebhookRouteKindToken {
+		auditValue = ""
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.webhook_route.create",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  fmt.Sprintf(`{"id":%d,"kind":"%s","value":"%s"}`, id, kind, auditValue),
+	})
+
+	resp := gin.H{"ok": true, "id": id}
+	if token != "" {
+		resp["token"] = token
+		resp["path"] = "/webhook/" + provider + "/t/" + token
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *WebhookRoutesHandler) Delete(c *gin.Context) {
+	tenantID := strings.TrimSpace(c.Param("id"))
+	if !tenantIDPattern.MatchString(tenantID) {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid tenant id"})
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("routeId"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"ok": false, "message": "invalid route id"})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetString("actor"))
+	if actor == "" {
+		actor = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+	if err := h.Store.DeleteWebhookRoute(ctx, tenantID, id); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"ok": false, "message": "route not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"ok": false, "message": fmt.Sprintf("delete webhook route failed: %v", err)})
+		return
+	}
+	_ = h.Store.SaveAuditLog(ctx, store.AuditLogRecord{
+		Actor:    actor,
+		Action:   "tenant.webhook_route.delete",
+		Target:   "tenant",
+		TargetID: tenantID,
+		Payload:  fmt.Sprintf(`{"id":%d}`, id),
+	})
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockWebhookRouteStore struct {
+	created []store.WebhookRouteRecord
+	audits  []store.AuditLogRecord
+}
+
+func (m *mockWebhookRouteStore) ListWebhookRoutes(_ context.Context, _ string) ([]store.WebhookRouteRecord, error) {
+	return append([]store.WebhookRouteRecord(nil), m.created...), nil
+}
+
+func (m *mockWebhookRouteStore) CreateWebhookRoute(_ context.Context, item store.WebhookRouteRecord) (int64, error) {
+	m.created = append(m.created, item)
+	return int64(len(m.created)), nil
+}
+
+func (m *mockWebhookRouteStore) DeleteWebhookRoute(_ context.Context, _ string, _ int64) error {
+	return nil
+}
+
+func (m *mockWebhookRouteStore) SaveAuditLog(_ context.Context, item store.AuditLogRecord) error {
+	m.audits = append(m.audits, item)
+	return nil
+}
+
+func TestWebhookRoutesCreate_TokenIsReturnedOnceAndHashed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockWebhookRouteStore{}
+	h := NewWebhookRoutesHandler(mockStore)
+	r := gin.New()
+	r.POST("/tenants/:id/webhook-routes", h.Create)
+	r.GET("/tenants/:id/webhook-routes", h.List)
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/team-a/webhook-routes", strings.NewReader(`{"kind":"token"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Token string `json:"token"`
+		Path  string `json:"path"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Token == "" || !strings.HasSuffix(resp.Path, resp.Token) {
+		t.Fatalf("expected generated token, body=%s", w.Body.String())
+	}
+	if mockStore.created[0].MatchValue != hashWebhookToken(resp.Token) {
+		t.Fatalf("expected only the token hash to be stored, got %+v", mockStore.created[0])
+	}
+	if strings.Contains(mockStore.audits[0].Payload, resp.Token) {
+		t.Fatalf("audit log must not contain the token: %s", mockStore.audits[0].Payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants/team-a/webhook-routes", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), mockStore.created[0].MatchValue) {
+		t.Fatalf("list must not expose token hashes: %s", w.Body.String())
+	}
+}
+
+func TestWebhookRoutesCreate_TokenPathUsesProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewWebhookRoutesHandler(&mockWebhookRouteStore{})
+	r := gin.New()
+	r.POST("/tenants/:id/webhook-routes", h.Create)
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/team-a/webhook-routes", strings.NewReader(`{"kind":"token","provider":"gitlab"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp struct {
+		Token string `json:"token"`
+		Path  string `json:"path"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusCreated || resp.Path != "/webhook/gitlab/t/"+resp.Token {
+		t.Fatalf("expected gitlab token path, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tenants/team-a/webhook-routes", strings.NewReader(`{"kind":"token","provider":"bitbucket"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown provider, got %d", w.Code)
+	}
+}
+
+func TestWebhookRoutesCreate_ValidatesValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockWebhookRouteStore{}
+	r := gin.New()
+	r.POST("/tenants/:id/webhook-routes", NewWebhookRoutesHandler(mockStore).Create)
+
+	for body, want := range map[string]int{
+		`{"kind":"repository","value":"no-slash"}`:     http.StatusBadRequest,
+		`{"kind":"installation","value":"abc"}`:        http.StatusBadRequest,
+		`{"kind":"header","value":"x"}`:                http.StatusBadRequest,
+		`{"kind":"repository","value":"Acme/Widgets"}`: http.StatusCreated,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/tenants/team-a/webhook-routes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("%s: expected %d, got %d", body, want, w.Code)
+		}
+	}
+	if len(mockStore.created) != 1 || mockStore.created[0].MatchValue != "acme/widgets" {
+		t.Fatalf("expected normalized repository route, got %+v", mockStore.created)
+	}
+}
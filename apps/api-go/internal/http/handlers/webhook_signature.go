@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+const (
+	SignatureSchemeSHA256      = "sha256"
+	SignatureSchemeSHA1        = "sha1"
+	SignatureSchemeSharedToken = "token"
+
+	// SharedTokenHeader carries the plain webhook secret for sources that cannot sign payloads.
+	SharedTokenHeader = "X-MF-Webhook-Token"
+)
+
+// DefaultSignatureSchemes is used when neither the tenant nor the handler configures any.
+var DefaultSignatureSchemes = []string{SignatureSchemeSHA256}
+
+// SignatureVerifier checks one signature scheme of a webhook delivery.
+type SignatureVerifier interface {
+	Scheme() string
+	// Present reports whether the request carries this scheme's header at all.
+	Present(header http.Header) bool
+	Verify(header http.Header, body []byte, secret string) bool
+}
+
+type hmacSignatureVerifier struct {
+	scheme string
+	header string
+	prefix string
+	hash   func() hash.Hash
+}
+
+func (v hmacSignatureVerifier) Scheme() string { return v.scheme }
+
+func (v hmacSignatureVerifier) Present(header http.Header) bool {
+	signature := header.Get(v.header)
+	return signature != "" && strings.HasPrefix(signature, v.prefix)
+}
+
+func (v hmacSignatureVerifier) Verify(header http.Header, body []byte, secret string) bool {
+	signature := header.Get(v.header)
+	if secret == "" || signature == "" || !strings.HasPrefix(signature, v.prefix) {
+		return false
+	}
+	mac := hmac.New(v.hash, []byte(secret))
+	mac.Write(body)
+	expected := v.prefix + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+type sharedTokenVerifier struct {
+	header string
+}
+
+func (v sharedTokenVerifier) Scheme() string { return SignatureSchemeSharedToken }
+
+func (v sharedTokenVerifier) Present(header http.Header) bool {
+	return header.Get(v.header) != ""
+}
+
+func (v sharedTokenVerifier) Verify(header http.Header, _ []byte, secret string) bool {
+	token := header.Get(v.header)
+	if secret == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+func NewSHA256SignatureVerifier() SignatureVerifier {
+	return hmacSignatureVerifier{scheme: SignatureSchemeSHA256, header: "X-Hub-Signature-256", prefix: "sha256=", hash: sha256.New}
+}
+
+// NewSHA1SignatureVerifier accepts the legacy X-Hub-Signature header still sent by
+// older GitHub Enterprise Server releases. SHA-1 is weak, so it is opt-in only.
+func NewSHA1SignatureVerifier() SignatureVerifier {
+	return hmacSignatureVerifier{scheme: SignatureSchemeSHA1, header: "X-Hub-Signature", prefix: "sha1=", hash: sha1.New}
+}
+
+// NewGiteaSignatureVerifier checks the bare hex HMAC-SHA256 that Gitea and Forgejo
+// send in X-Gitea-Signature (Forgejo also uses X-Forgejo-Signature).
+func NewGiteaSignatureVerifier(header string) SignatureVerifier {
+	return hmacSignatureVerifier{scheme: "gitea", header: header, hash: sha256.New}
+}
+
+func NewSharedTokenVerifier(header string) SignatureVerifier {
+	return sharedTokenVerifier{header: header}
+}
+
+var signatureVerifiers = map[string]SignatureVerifier{
+	SignatureSchemeSHA256:      NewSHA256SignatureVerifier(),
+	SignatureSchemeSHA1:        NewSHA1SignatureVerifier(),
+	SignatureSchemeSharedToken: NewSharedTokenVerifier(SharedTokenHeader),
+}
+
+// ParseSignatureSchemes parses a comma-separated scheme list such as "sha256,sha1".
+// An empty value yields nil so callers can fall back to their defaults.
+func ParseSignatureSchemes(raw string) ([]string, error) {
+	var schemes []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		scheme := strings.ToLower(strings.TrimSpace(part))
+		if scheme == "" || seen[scheme] {
+			continue
+		}
+		if _, ok := signatureVerifiers[scheme]; !ok {
+			return nil, fmt.Errorf("unknown signature scheme %q", scheme)
+		}
+		seen[scheme] = true
+		schemes = append(schemes, scheme)
+	}
+	return schemes, nil
+}
+
+func signatureVerifiersFor(schemes []string) []SignatureVerifier {
+	verifiers := make([]SignatureVerifier, 0, len(schemes))
+	for _, scheme := range schemes {
+		if v, ok := signatureVerifiers[scheme]; ok {
+			verifiers = append(verifiers, v)
+		}
+	}
+	return verifiers
+}
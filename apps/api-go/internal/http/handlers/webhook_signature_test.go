@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestSignatureVerifiers(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha1.New, []byte("s3cret"))
+	mac.Write(body)
+	sha1Signature := "sha1=" + hex.EncodeToString(mac.Sum(nil))
+
+	cases := []struct {
+		name     string
+		verifier SignatureVerifier
+		header   string
+		value    string
+		secret   string
+		present  bool
+		want     bool
+	}{
+		{name: "sha256 valid", verifier: NewSHA256SignatureVerifier(), header: "X-Hub-Signature-256", value: signBody("s3cret", body), secret: "s3cret", present: true, want: true},
+		{name: "sha256 wrong secret", verifier: NewSHA256SignatureVerifier(), header: "X-Hub-Signature-256", value: signBody("other", body), secret: "s3cret", present: true},
+		{name: "sha256 missing prefix", verifier: NewSHA256SignatureVerifier(), header: "X-Hub-Signature-256", value: "deadbeef", secret: "s3cret"},
+		{name: "sha1 valid", verifier: NewSHA1SignatureVerifier(), header: "X-Hub-Signature", value: sha1Signature, secret: "s3cret", present: true, want: true},
+		{name: "sha1 ignores sha256 header", verifier: NewSHA1SignatureVerifier(), header: "X-Hub-Signature-256", value: signBody("s3cret", body), secret: "s3cret"},
+		{name: "token valid", verifier: NewSharedTokenVerifier(SharedTokenHeader), header: SharedTokenHeader, value: "s3cret", secret: "s3cret", present: true, want: true},
+		{name: "token mismatch", verifier: NewSharedTokenVerifier(SharedTokenHeader), header: SharedTokenHeader, value: "s3cret-", secret: "s3cret", present: true},
+		{name: "token empty secret", verifier: NewSharedTokenVerifier(SharedTokenHeader), header: SharedTokenHeader, value: "x", secret: "", present: true},
+	}
+	for _, tc := range cases {
+		header := http.Header{}
+		header.Set(tc.header, tc.value)
+		if got := tc.verifier.Present(header); got != tc.present {
+			t.Fatalf("%s: expected present=%v, got %v", tc.name, tc.present, got)
+		}
+		if got := tc.verifier.Verify(header, body, tc.secret); got != tc.want {
+			t.Fatalf("%s: expected verify=%v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestParseSignatureSchemes(t *testing.T) {
+	schemes, err := ParseSignatureSchemes(" SHA1, sha256,,sha1 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemes) != 2 || schemes[0] != "sha1" || schemes[1] != "sha256" {
+		t.Fatalf("unexpected schemes %v", schemes)
+	}
+	if schemes, err := ParseSignatureSchemes(""); err != nil || schemes != nil {
+		t.Fatalf("expected empty result, got %v %v", schemes, err)
+	}
+	if _, err := ParseSignatureSchemes("sha256,md5"); err == nil {
+		t.Fatalf("expected unknown scheme error")
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"bytes"
 	"context"
 	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"maintainer-firewall/api-go/internal/service"
 	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,10 +38,14 @@ type mockWebhookExecutor struct {
 	commentFailTimes int
 	labelCalls       int
 	commentCalls    int
+	labelErr         error
 }
 
 func (m *mockWebhookExecutor) AddLabel(_ context.Context, _ string, _ int, label string) error {
 	m.labelCalls++
+	if m.labelErr != nil {
+		return m.labelErr
+	}
 	if m.labelFailTimes > 0 {
 		m.labelFailTimes--
 		return errors.New("label fail")
@@ -59,9 +69,15 @@ func (m *mockWebhookStore) SaveEvent(_ context.Context, evt store.WebhookEvent)
 	return nil
 }
 
-func (m *mockWebhookStore) SaveAlert(_ context.Context, alert store.AlertRecord) error {
+// SaveAlert mirrors the store's unique key on delivery, suggestion and rule.
+func (m *mockWebhookStore) SaveAlert(_ context.Context, alert store.AlertRecord) (int64, error) {
+	for _, saved := range m.savedAlerts {
+		if saved.DeliveryID == alert.DeliveryID && saved.SuggestionType == alert.SuggestionType && saved.SuggestionValue == alert.SuggestionValue && saved.RuleMatched == alert.RuleMatched {
+			return 0, nil
+		}
+	}
 	m.savedAlerts = append(m.savedAlerts, alert)
-	return nil
+	return int64(len(m.savedAlerts)), nil
 }
 
 func (m *mockWebhookStore) SaveActionExecutionFailure(_ context.Context, item store.ActionExecutionFailure) error {
@@ -184,6 +200,46 @@ func TestWebhookGitHub_ExecutorFailureDoesNotBlockWebhook(t *testing.T) {
 	}
 }
 
+func TestWebhookGitHub_RateLimitedActionIsNotRetriedInline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := "test-secret"
+	payload := map[string]any{
+		"action":     "opened",
+		"repository": map[string]any{"full_name": "owner/repo"},
+		"sender":     map[string]any{"login": "alice"},
+		"issue":      map[string]any{"title": "urgent duplicate", "number": 12},
+	}
+	body, _ := json.Marshal(payload)
+
+	mockStore := &mockWebhookStore{
+		rules: []store.RuleRecord{{EventType: "issues", Keyword: "urgent", SuggestionType: "label", SuggestionValue: "P0", Reason: "urgent rule"}},
+	}
+	h := NewWebhookHandler(secret, mockStore)
+	exec := &mockWebhookExecutor{labelErr: &service.GitHubAPIError{StatusCode: 403, Message: "You have exceeded a secondary rate limit", RetryAfter: time.Minute}}
+	h.ActionExecutor = exec
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signBody(secret, body))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-GitHub-Delivery", "delivery-rate-limited")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if exec.labelCalls != 1 {
+		t.Fatalf("expected a single attempt when Retry-After exceeds the request budget, got %d", exec.labelCalls)
+	}
+	if len(mockStore.savedActionFails) != 1 || !strings.Contains(mockStore.savedActionFails[0].ErrorMessage, "github api status: 403") {
+		t.Fatalf("expected rate-limited failure to be persisted, got %+v", mockStore.savedActionFails)
+	}
+}
+
 func TestWebhookGitHub_SignatureInvalid(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -216,3 +272,486 @@ func signBody(secret string, body []byte) string {
 	mac.Write(body)
 	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
 }
+
+type mockWebhookSecrets map[string]string
+
+func (m mockWebhookSecrets) WebhookSecrets(ctx context.Context) (service.WebhookSecretSet, bool, error) {
+	secret, ok := m[tenantctx.MustFromContext(ctx, "")]
+	return service.WebhookSecretSet{Primary: secret}, ok, nil
+}
+
+func TestWebhookGitHub_TenantPathUsesTenantSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := []byte(`{"action":"opened","repository":{"full_name":"owner/repo"},"issue":{"number":1,"title":"hi"}}`)
+	h := NewWebhookHandler("global-secret", &mockWebhookStore{})
+	h.Secrets = mockWebhookSecrets{"team-a": "team-a-secret"}
+
+	r := gin.New()
+	r.POST("/webhook/github/:tenant", h.GitHub)
+
+	cases := []struct {
+		path   string
+		secret string
+		want   int
+	}{
+		{"/webhook/github/team-a", "team-a-secret", http.StatusOK},
+		{"/webhook/github/team-a", "global-secret", http.StatusUnauthorized},
+		// Explicit tenant paths never accept the shared secret.
+		{"/webhook/github/team-b", "global-secret", http.StatusUnauthorized},
+		{"/webhook/github/default", "global-secret", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(body))
+		req.Header.Set("X-Hub-Signature-256", signBody(tc.secret, body))
+		req.Header.Set("X-GitHub-Event", "issues")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("%s with %s: expected %d, got %d body=%s", tc.path, tc.secret, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+type mockWebhookRouter struct {
+	routes  map[string]string
+	tenants map[string]store.TenantRecord
+}
+
+func (m *mockWebhookRouter) ResolveWebhookRoute(_ context.Context, kind string, matchValue string) (string, error) {
+	tenantID, ok := m.routes[kind+":"+matchValue]
+	if !ok {
+		return "", errors.New("webhook route not found")
+	}
+	return tenantID, nil
+}
+
+func (m *mockWebhookRouter) GetTenant(_ context.Context, id string) (store.TenantRecord, error) {
+	tenant, ok := m.tenants[id]
+	if !ok {
+		return tenant, errors.New("tenant not found")
+	}
+	return tenant, nil
+}
+
+type tenantRecordingWebhookStore struct {
+	mockWebhookStore
+	eventTenants  []string
+	metricTenants []string
+}
+
+func (m *tenantRecordingWebhookStore) SaveEvent(ctx context.Context, evt store.WebhookEvent) error {
+	m.eventTenants = append(m.eventTenants, tenantctx.MustFromContext(ctx, ""))
+	return m.mockWebhookStore.SaveEvent(ctx, evt)
+}
+
+func (m *tenantRecordingWebhookStore) SaveDeliveryMetric(ctx context.Context, metric store.DeliveryMetric) error {
+	m.metricTenants = append(m.metricTenants, tenantctx.MustFromContext(ctx, ""))
+	return m.mockWebhookStore.SaveDeliveryMetric(ctx, metric)
+}
+
+func TestWebhookGitHub_TenantRouting(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const secret = "global-secret"
+	mockStore := &tenantRecordingWebhookStore{}
+	h := NewWebhookHandler(secret, mockStore)
+	h.Secrets = mockWebhookSecrets{"team-a": "team-a-secret", "team-b": "team-b-secret"}
+	h.Router = &mockWebhookRouter{
+		routes: map[string]string{
+			"token:" + hashWebhookToken("tok-a"): "team-a",
+			"repository:acme/widgets":           "team-a",
+			"installation:77":                   "team-b",
+			"repository:acme/frozen":            "team-off",
+		},
+		tenants: map[string]store.TenantRecord{
+			"default":  {ID: "default", IsActive: true},
+			"team-a":   {ID: "team-a", IsActive: true},
+			"team-b":   {ID: "team-b", IsActive: true},
+			"team-off": {ID: "team-off", IsActive: false},
+		},
+	}
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	r.POST("/webhook/github/t/:token", h.GitHub)
+	r.POST("/webhook/github/:tenant", h.GitHub)
+
+	cases := []struct {
+		name       string
+		path       string
+		body       string
+		header     string
+		secret     string
+		wantCode   int
+		wantTenant string
+		wantOut    string
+	}{
+		{"header is ignored", "/webhook/github", `{"repository":{"full_name":"other/repo"}}`, "team-a", secret, http.StatusOK, "default", store.DeliveryOutcomeProcessed},
+		{"repository mapping", "/webhook/github", `{"repository":{"full_name":"Acme/Widgets"}}`, "", "team-a-secret", http.StatusOK, "team-a", store.DeliveryOutcomeProcessed},
+		// Body hints are unverified, so the shared secret cannot vouch for a mapped tenant.
+		{"repository mapping with shared secret", "/webhook/github", `{"repository":{"full_name":"acme/widgets"}}`, "", secret, http.StatusUnauthorized, "team-a", store.DeliveryOutcomeUnauthorized},
+		{"installation mapping wins", "/webhook/github", `{"installation":{"id":77},"repository":{"full_name":"acme/widgets"}}`, "", "team-b-secret", http.StatusOK, "team-b", store.DeliveryOutcomeProcessed},
+		{"token path", "/webhook/github/t/tok-a", `{}`, "", "team-a-secret", http.StatusOK, "team-a", store.DeliveryOutcomeProcessed},
+		{"unknown token", "/webhook/github/t/nope", `{}`, "", secret, http.StatusNotFound, "default", store.DeliveryOutcomeMisrouted},
+		{"unknown tenant", "/webhook/github/ghost", `{}`, "", secret, http.StatusNotFound, "default", store.DeliveryOutcomeMisrouted},
+		{"inactive tenant", "/webhook/github", `{"repository":{"full_name":"acme/frozen"}}`, "", secret, http.StatusForbidden, "default", store.DeliveryOutcomeMisrouted},
+	}
+	for _, tc := range cases {
+		before := len(mockStore.savedDeliveryMets)
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("X-Hub-Signature-256", signBody(tc.secret, []byte(tc.body)))
+		req.Header.Set("X-GitHub-Event", "ping")
+		if tc.header != "" {
+			req.Header.Set("X-MF-Tenant-ID", tc.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.wantCode {
+			t.Fatalf("%s: expected %d, got %d body=%s", tc.name, tc.wantCode, w.Code, w.Body.String())
+		}
+		if len(mockStore.savedDeliveryMets) != before+1 {
+			t.Fatalf("%s: expected a delivery metric", tc.name)
+		}
+		metric := mockStore.savedDeliveryMets[len(mockStore.savedDeliveryMets)-1]
+		if metric.Outcome != tc.wantOut || mockStore.metricTenants[len(mockStore.metricTenants)-1] != tc.wantTenant {
+			t.Fatalf("%s: unexpected metric %+v tenant=%s", tc.name, metric, mockStore.metricTenants[len(mockStore.metricTenants)-1])
+		}
+	}
+}
+
+type installationRecordingExecutor struct {
+	mockWebhookExecutor
+	installations []int64
+}
+
+func (m *installationRecordingExecutor) AddLabel(ctx context.Context, repo string, number int, label string) error {
+	id, _ := service.GitHubInstallationIDFromContext(ctx)
+	m.installations = append(m.installations, id)
+	return m.mockWebhookExecutor.AddLabel(ctx, repo, number, label)
+}
+
+func TestWebhookGitHub_IgnoresInstallationOfAnotherTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockStore := &mockWebhookStore{
+		rules: []store.RuleRecord{{EventType: "issues", Keyword: "urgent", SuggestionType: "label", SuggestionValue: "P0", Reason: "urgent rule"}},
+	}
+	h := NewWebhookHandler("global-secret", mockStore)
+	h.Secrets = mockWebhookSecrets{"team-a": "team-a-secret", "team-b": "team-b-secret"}
+	h.Router = &mockWebhookRouter{
+		routes: map[string]string{"installation:77": "team-b"},
+		tenants: map[string]store.TenantRecord{
+			"team-a": {ID: "team-a", IsActive: true, GitHubInstallationID: 55},
+			"team-b": {ID: "team-b", IsActive: true},
+		},
+	}
+	exec := &installationRecordingExecutor{}
+	h.ActionExecutor = exec
+
+	r := gin.New()
+	r.POST("/webhook/github/:tenant", h.GitHub)
+	send := func(tenant string, installationID int) {
+		body := []byte(fmt.Sprintf(`{"action":"opened","installation":{"id":%d},"repository":{"full_name":"owner/repo"},"issue":{"number":3,"title":"urgent"}}`, installationID))
+		req := httptest.NewRequest(http.MethodPost, "/webhook/github/"+tenant, bytes.NewReader(body))
+		req.Header.Set("X-Hub-Signature-256", signBody(tenant+"-secret", body))
+		req.Header.Set("X-GitHub-Event", "issues")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+	}
+
+	// team-a claims team-b's installation: the payload ID must not be used.
+	send("team-a", 77)
+	// Installations mapped to the tenant, by route or by tenant setting, are honoured.
+	send("team-b", 77)
+	send("team-a", 55)
+
+	if fmt.Sprint(exec.installations) != "[0 77 55]" {
+		t.Fatalf("unexpected installations used: %v", exec.installations)
+	}
+}
+
+func TestWebhookGitHub_AcceptsSecondarySecretUntilExpiry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := []byte(`{"action":"opened"}`)
+	mockStore := &mockWebhookStore{}
+	h := NewWebhookHandler("new-secret", mockStore)
+	h.SecondarySecret = "old-secret"
+	h.SecondarySecretExpiresAt = time.Now().Add(time.Hour)
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	send := func(secret string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader(body))
+		req.Header.Set("X-Hub-Signature-256", signBody(secret, body))
+		req.Header.Set("X-GitHub-Event", "issues")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send("new-secret"); code != http.StatusOK {
+		t.Fatalf("expected primary secret accepted, got %d", code)
+	}
+	if code := send("old-secret"); code != http.StatusOK {
+		t.Fatalf("expected secondary secret accepted during rotation, got %d", code)
+	}
+	if got := mockStore.savedDeliveryMets[0].MatchedSecret + "," + mockStore.savedDeliveryMets[1].MatchedSecret; got != "primary,secondary" {
+		t.Fatalf("expected matched secrets recorded, got %s", got)
+	}
+
+	h.SecondarySecretExpiresAt = time.Now().Add(-time.Minute)
+	if code := send("old-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected expired secondary secret rejected, got %d", code)
+	}
+}
+
+func TestWebhookGitHub_SignatureSchemesPerTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "test-secret"
+	body := []byte(`{"action":"opened","repository":{"full_name":"owner/repo"},"sender":{"login":"alice"}}`)
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	sha1Signature := "sha1=" + hex.EncodeToString(mac.Sum(nil))
+
+	h := NewWebhookHandler(secret, &mockWebhookStore{})
+	h.Secrets = mockWebhookSecrets{"legacy": secret}
+	h.Router = &mockWebhookRouter{
+		routes: map[string]string{"repository:owner/repo": "legacy"},
+		tenants: map[string]store.TenantRecord{
+			"default": {ID: "default", IsActive: true},
+			"legacy":  {ID: "legacy", IsActive: true, SignatureSchemes: "sha1,token"},
+		},
+	}
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	r.POST("/webhook/github/:tenant", h.GitHub)
+
+	cases := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "legacy tenant sha1", path: "/webhook/github", header: "X-Hub-Signature", value: sha1Signature, want: http.StatusOK},
+		{name: "legacy tenant shared token", path: "/webhook/github", header: SharedTokenHeader, value: secret, want: http.StatusOK},
+		{name: "legacy tenant without sha256 opt-in", path: "/webhook/github", header: "X-Hub-Signature-256", value: signBody(secret, body), want: http.StatusUnauthorized},
+		{name: "default tenant rejects sha1", path: "/webhook/github/default", header: "X-Hub-Signature", value: sha1Signature, want: http.StatusUnauthorized},
+		{name: "default tenant sha256", path: "/webhook/github/default", header: "X-Hub-Signature-256", value: signBody(secret, body), want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "issues")
+		req.Header.Set(tc.header, tc.value)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d body=%s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+type kindRecordingExecutor struct {
+	mockWebhookExecutor
+	kinds []string
+	repos []string
+}
+
+func (m *kindRecordingExecutor) AddLabel(ctx context.Context, repo string, number int, label string) error {
+	m.kinds = append(m.kinds, service.TargetKindFromContext(ctx))
+	m.repos = append(m.repos, repo)
+	return m.mockWebhookExecutor.AddLabel(ctx, repo, number, label)
+}
+
+func TestWebhookGitLab_NormalisesAndRunsRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockWebhookStore{}
+	githubExec := &mockWebhookExecutor{}
+	gitlabExec := &kindRecordingExecutor{}
+	h := NewWebhookHandler("github-secret", mockStore)
+	h.GitLabSecret = "gitlab-token"
+	h.ActionExecutor = githubExec
+	h.Providers.Register(service.GitLabProvider(gitlabExec))
+	r := gin.New()
+	r.POST("/webhook/gitlab", h.GitLab)
+
+	body := `{"object_kind":"merge_request","user":{"username":"alice"},"project":{"path_with_namespace":"group/app"},"object_attributes":{"iid":5,"title":"urgent fix","description":"","action":"open"}}`
+	cases := []struct {
+		token string
+		want  int
+	}{
+		{token: "", want: http.StatusUnauthorized},
+		{token: "github-secret", want: http.StatusUnauthorized},
+		{token: "gitlab-token", want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/gitlab", strings.NewReader(body))
+		req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+		req.Header.Set("X-Gitlab-Event-UUID", "uuid-1")
+		if tc.token != "" {
+			req.Header.Set("X-Gitlab-Token", tc.token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("token %q: expected %d, got %d body=%s", tc.token, tc.want, w.Code, w.Body.String())
+		}
+	}
+
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("expected 1 saved event, got %d", len(mockStore.saved))
+	}
+	evt := mockStore.saved[0]
+	if evt.Source != store.EventSourceGitLab || evt.EventType != "pull_request" || evt.Action != "opened" || evt.RepositoryFullName != "group/app" || evt.DeliveryID != "gitlab-uuid-1" {
+		t.Fatalf("unexpected saved event %+v", evt)
+	}
+	if len(mockStore.savedAlerts) == 0 {
+		t.Fatalf("expected keyword rules to produce alerts")
+	}
+	if len(gitlabExec.labels) != 1 || gitlabExec.labels[0] != "priority-high" || gitlabExec.kinds[0] != service.TargetKindPullRequest || gitlabExec.repos[0] != "group/app" {
+		t.Fatalf("unexpected gitlab executor calls: labels=%v kinds=%v repos=%v", gitlabExec.labels, gitlabExec.kinds, gitlabExec.repos)
+	}
+	if githubExec.labelCalls != 0 || githubExec.commentCalls != 0 {
+		t.Fatalf("github executor must not be used for gitlab events")
+	}
+}
+
+func TestWebhookGitea_VerifiesSignatureAndUsesGiteaExecutor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStore := &mockWebhookStore{}
+	giteaExec := &mockWebhookExecutor{}
+	h := NewWebhookHandler("github-secret", mockStore)
+	h.GiteaSecret = "gitea-secret"
+	h.Providers.Register(service.GiteaProvider(giteaExec))
+	h.ActionExecutor = &mockWebhookExecutor{}
+	r := gin.New()
+	r.POST("/webhook/gitea", h.Gitea)
+
+	body := []byte(`{"action":"opened","number":4,"issue":{"number":4,"title":"urgent: broken","body":""},"repository":{"full_name":"org/repo"},"sender":{"login":"dave"}}`)
+	hexSignature := func(secret string) string {
+		return strings.TrimPrefix(signBody(secret, body), "sha256=")
+	}
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "missing signature", want: http.StatusUnauthorized},
+		{name: "wrong secret", header: "X-Gitea-Signature", value: hexSignature("github-secret"), want: http.StatusUnauthorized},
+		{name: "prefixed signature", header: "X-Gitea-Signature", value: signBody("gitea-secret", body), want: http.StatusUnauthorized},
+		{name: "forgejo header", header: "X-Forgejo-Signature", value: hexSignature("gitea-secret"), want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/gitea", bytes.NewReader(body))
+		req.Header.Set("X-Gitea-Event", "issues")
+		req.Header.Set("X-Gitea-Delivery", "d-1")
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d body=%s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("expected 1 saved event, got %d", len(mockStore.saved))
+	}
+	evt := mockStore.saved[0]
+	if evt.Source != store.EventSourceGitea || evt.EventType != "issues" || evt.DeliveryID != "gitea-d-1" || evt.RepositoryFullName != "org/repo" {
+		t.Fatalf("unexpected saved event %+v", evt)
+	}
+	if len(giteaExec.labels) != 1 || giteaExec.labels[0] != "priority-high" {
+		t.Fatalf("expected gitea executor to apply rule label, got %v", giteaExec.labels)
+	}
+}
+
+type mockRepositoryRegistry struct {
+	upserted    []store.RepositoryRecord
+	deactivated []int64
+	renamedFrom string
+	renamedTo   store.RepositoryRecord
+}
+
+func (m *mockRepositoryRegistry) UpsertRepositories(_ context.Context, items []store.RepositoryRecord) error {
+	m.upserted = append(m.upserted, items...)
+	return nil
+}
+
+func (m *mockRepositoryRegistry) DeactivateRepositories(_ context.Context, _ string, ids []int64) error {
+	m.deactivated = append(m.deactivated, ids...)
+	return nil
+}
+
+func (m *mockRepositoryRegistry) DeactivateInstallationRepositories(_ context.Context, installationID int64) error {
+	m.deactivated = append(m.deactivated, -installationID)
+	return nil
+}
+
+func (m *mockRepositoryRegistry) RenameRepository(_ context.Context, item store.RepositoryRecord, oldFullName string) (int64, error) {
+	m.renamedFrom = oldFullName
+	m.renamedTo = item
+	return 3, nil
+}
+
+func TestWebhookGitHub_LifecycleEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := "test-secret"
+	mockStore := &mockWebhookStore{}
+	registry := &mockRepositoryRegistry{}
+	h := NewWebhookHandler(secret, mockStore)
+	h.Repositories = registry
+
+	r := gin.New()
+	r.POST("/webhook/github", h.GitHub)
+	send := func(event string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/github", bytes.NewReader([]byte(body)))
+		req.Header.Set("X-Hub-Signature-256", signBody(secret, []byte(body)))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Delivery", "d-"+event)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("ping", `{"zen":"Keep it simple.","hook_id":42}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "webhook 42 is configured") {
+		t.Fatalf("unexpected ping response %d %s", w.Code, w.Body.String())
+	}
+	if len(mockStore.saved) != 0 {
+		t.Fatalf("ping should not be stored as an event")
+	}
+
+	w = send("installation", `{"action":"created","installation":{"id":7},"repositories":[{"id":100,"full_name":"acme/api"},{"id":101,"full_name":"acme/web"}]}`)
+	if w.Code != http.StatusOK || len(registry.upserted) != 2 || registry.upserted[0].InstallationID != 7 || registry.upserted[1].FullName != "acme/web" {
+		t.Fatalf("installation not registered: %d %+v", w.Code, registry.upserted)
+	}
+
+	send("installation_repositories", `{"action":"removed","installation":{"id":7},"repositories_removed":[{"id":101,"full_name":"acme/web"}]}`)
+	send("installation", `{"action":"deleted","installation":{"id":7}}`)
+	if len(registry.deactivated) != 2 || registry.deactivated[0] != 101 || registry.deactivated[1] != -7 {
+		t.Fatalf("unexpected deactivations %v", registry.deactivated)
+	}
+
+	send("repository", `{"action":"renamed","repository":{"id":100,"full_name":"acme/api-v2"},"changes":{"repository":{"name":{"from":"api"}}}}`)
+	if registry.renamedFrom != "acme/api" || registry.renamedTo.FullName != "acme/api-v2" || registry.renamedTo.ExternalID != 100 {
+		t.Fatalf("unexpected rename %q -> %+v", registry.renamedFrom, registry.renamedTo)
+	}
+
+	send("repository", `{"action":"transferred","repository":{"id":100,"full_name":"newco/api-v2"},"changes":{"owner":{"from":{"organization":{"login":"acme"}}}}}`)
+	if registry.renamedFrom != "acme/api-v2" || registry.renamedTo.FullName != "newco/api-v2" {
+		t.Fatalf("unexpected transfer %q -> %+v", registry.renamedFrom, registry.renamedTo)
+	}
+	if len(mockStore.saved) != 5 {
+		t.Fatalf("expected lifecycle events to be stored, got %d", len(mockStore.saved))
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"maintainer-firewall/api-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkerStatusProvider interface {
+	Statuses() []service.WorkerStatus
+	Healthy() bool
+}
+
+// WorkersHandler reports the state of the supervised background workers.
+type WorkersHandler struct {
+	Workers WorkerStatusProvider
+}
+
+func NewWorkersHandler(workers WorkerStatusProvider) *WorkersHandler {
+	return &WorkersHandler{Workers: workers}
+}
+
+func (h *WorkersHandler) List(c *gin.Context) {
+	if h.Workers == nil {
+		c.JSON(http.StatusOK, gin.H{"ok": true, "healthy": true, "items": []service.WorkerStatus{}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "healthy": h.Workers.Healthy(), "items": h.Workers.Statuses()})
+}
@@ -0,0 +1,150 @@
+// Package logging configures the server's slog logger: JSON lines that carry
+// the request ID, tenant, delivery and actor of the context they were written
+// with, and that have secrets stripped from messages and errors.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"maintainer-firewall/api-go/internal/tenantctx"
+	"maintainer-firewall/api-go/internal/tracing"
+)
+
+// Fields are the request-scoped values added to every line logged with a
+// context that carries them. Handlers fill them in as they learn them, so the
+// request log written at the end sees the tenant and delivery as well.
+type Fields struct {
+	mu         sync.Mutex
+	requestID  string
+	tenantID   string
+	deliveryID string
+	actor      string
+}
+
+type fieldsKey struct{}
+
+// NewContext returns ctx carrying fresh Fields for requestID.
+func NewContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &Fields{requestID: requestID})
+}
+
+func fieldsFrom(ctx context.Context) *Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(*Fields)
+	return fields
+}
+
+// RequestID returns the request ID in ctx, or "".
+func RequestID(ctx context.Context) string {
+	fields := fieldsFrom(ctx)
+	if fields == nil {
+		return ""
+	}
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	return fields.requestID
+}
+
+func SetTenantID(ctx context.Context, tenantID string) {
+	set(ctx, func(f *Fields) { f.tenantID = tenantID })
+}
+
+func SetDeliveryID(ctx context.Context, deliveryID string) {
+	set(ctx, func(f *Fields) { f.deliveryID = deliveryID })
+}
+
+func SetActor(ctx context.Context, actor string) {
+	set(ctx, func(f *Fields) { f.actor = actor })
+}
+
+func set(ctx context.Context, fn func(*Fields)) {
+	fields := fieldsFrom(ctx)
+	if fields == nil {
+		return
+	}
+	fields.mu.Lock()
+	fn(fields)
+	fields.mu.Unlock()
+}
+
+// ParseLevel accepts debug, info, warn (or warning) and error.
+func ParseLevel(raw string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", raw)
+}
+
+// Setup makes a JSON logger at level the slog default. Output of the standard
+// log package goes through it as well.
+func Setup(level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(NewLogger(os.Stderr, lvl))
+	return nil
+}
+
+// NewLogger returns a JSON logger writing to w.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(&contextHandler{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})})
+}
+
+// contextHandler adds the request fields, tenant and trace ID found in the
+// record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	var requestID, tenantID, deliveryID, actor string
+	if fields := fieldsFrom(ctx); fields != nil {
+		fields.mu.Lock()
+		requestID, tenantID, deliveryID, actor = fields.requestID, fields.tenantID, fields.deliveryID, fields.actor
+		fields.mu.Unlock()
+	}
+	// A tenant set on the context itself is more specific than the request's,
+	// e.g. inside a worker run that walks several tenants.
+	if id, ok := tenantctx.FromContext(ctx); ok {
+		tenantID = id
+	}
+	for _, attr := range []slog.Attr{
+		slog.String("request_id", requestID),
+		slog.String("tenant_id", tenantID),
+		slog.String("delivery_id", deliveryID),
+		slog.String("actor", actor),
+		slog.String("trace_id", tracing.TraceIDFromContext(ctx)),
+	} {
+		if attr.Value.String() != "" {
+			r.AddAttrs(attr)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
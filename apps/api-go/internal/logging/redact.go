@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(-----END [A-Z ]*PRIVATE KEY-----|$)`), redacted},
+	{regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{16,}|github_pat_[A-Za-z0-9_]{16,}|glpat-[A-Za-z0-9_\-]{16,})`), redacted},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{4,}\.[A-Za-z0-9_\-]{4,}\.[A-Za-z0-9_\-]{4,}`), redacted},
+	{regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9._~+/=\-]{8,}`), "$1 " + redacted},
+	{regexp.MustCompile(`\b(Basic) [A-Za-z0-9+/=]{16,}`), "$1 " + redacted},
+	// key=value pairs in URLs and DSNs, and quoted keys in JSON; "secret: x" in
+	// prose is left alone.
+	{regexp.MustCompile(`(?i)\b((?:access_|private_|refresh_)?token|secret|client_secret|password|passwd|api_?key|signature)(\s*=\s*["']?|["']\s*:\s*["']?)[^\s&"',;}]+`), "$1$2" + redacted},
+	{regexp.MustCompile(`(://[^:/@\s]+):[^@/\s]+@`), "$1:" + redacted + "@"},
+	{regexp.MustCompile(`\b([^:\s/@]+):[^@\s]+@tcp\(`), "$1:" + redacted + "@tcp("},
+}
+
+// Redact masks tokens, passwords, private keys and credentials embedded in
+// URLs or DSNs.
+func Redact(s string) string {
+	for _, r := range redactions {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
+
+// sensitiveKeys are attribute keys whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"private_key":   true,
+}
+
+// redactAttr is the JSON handler's ReplaceAttr hook. It covers the message and
+// every string or error attribute, so call sites can log errors as they are.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
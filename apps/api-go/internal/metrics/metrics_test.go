@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDefaultGathersRuntimeAndServerMetrics(t *testing.T) {
+	GitHubSyncRuns.WithLabelValues("manual", "succeeded").Inc()
+	families, err := Default.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	names := map[string]bool{}
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	for _, name := range []string{"go_goroutines", "mf_github_sync_runs_total"} {
+		if !names[name] {
+			t.Fatalf("expected %s in %v", name, names)
+		}
+	}
+	if got := testutil.ToFloat64(GitHubSyncRuns.WithLabelValues("manual", "succeeded")); got != 1 {
+		t.Fatalf("expected 1 sync run, got %v", got)
+	}
+}
+
+func TestWebhookEventTypeOnlyLabelsKnownEvents(t *testing.T) {
+	for name, want := range map[string]string{
+		"issues":             "issues",
+		"Merge Request Hook": "Merge Request Hook",
+		"":                   UnknownEventType,
+		"issues-1f3a9c":      UnknownEventType,
+		"ISSUES":             UnknownEventType,
+	} {
+		if got := WebhookEventType(name); got != want {
+			t.Fatalf("WebhookEventType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	WebhookRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_webhook_requests_total",
+		Help: "Webhook deliveries by source, event type and outcome.",
+	}, []string{"source", "event_type", "outcome"})
+	WebhookProcessingSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mf_webhook_processing_seconds",
+		Help:    "Time from receiving a webhook delivery to answering it.",
+		Buckets: DefaultLatencyBuckets,
+	}, []string{"source", "outcome"})
+	RuleMatches = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_rule_matches_total",
+		Help: "Rule suggestions raised, by event type, suggestion type and matched keyword.",
+	}, []string{"event_type", "suggestion_type", "rule"})
+	ActionFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_action_failures_total",
+		Help: "Suggested actions that failed after retries, by source and suggestion type.",
+	}, []string{"source", "suggestion_type"})
+	GitHubAPIRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_github_api_requests_total",
+		Help: "GitHub API calls by method and response status; transport errors use status \"error\".",
+	}, []string{"method", "status"})
+	GitHubAPIRequestSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mf_github_api_request_duration_seconds",
+		Help:    "GitHub API call latency by method.",
+		Buckets: DefaultLatencyBuckets,
+	}, []string{"method"})
+	GitHubSyncRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_github_sync_runs_total",
+		Help: "GitHub event sync runs by trigger and final status.",
+	}, []string{"trigger", "status"})
+)
+
+// UnknownEventType is the event_type label for event names outside
+// webhookEventTypes.
+const UnknownEventType = "unknown"
+
+// webhookEventTypes are the event names the forges send that WebhookRequests
+// labels as is: GitHub and Gitea event headers and GitLab hook names.
+var webhookEventTypes = map[string]bool{
+	"check_run":                   true,
+	"check_suite":                 true,
+	"create":                      true,
+	"delete":                      true,
+	"discussion":                  true,
+	"discussion_comment":          true,
+	"fork":                        true,
+	"installation":                true,
+	"installation_repositories":   true,
+	"installation_target":         true,
+	"issue_comment":               true,
+	"issues":                      true,
+	"label":                       true,
+	"member":                      true,
+	"ping":                        true,
+	"pull_request":                true,
+	"pull_request_comment":        true,
+	"pull_request_review":         true,
+	"pull_request_review_comment": true,
+	"pull_request_review_thread":  true,
+	"push":                        true,
+	"release":                     true,
+	"repository":                  true,
+	"star":                        true,
+	"status":                      true,
+	"watch":                       true,
+	"workflow_run":                true,
+	"Confidential Issue Hook":     true,
+	"Confidential Note Hook":      true,
+	"Issue Hook":                  true,
+	"Merge Request Hook":          true,
+	"Note Hook":                   true,
+	"Pipeline Hook":               true,
+	"Push Hook":                   true,
+	"Tag Push Hook":               true,
+}
+
+// WebhookEventType returns the event_type label for an event name taken from a
+// request header. The header arrives before the signature is checked, so only
+// known names become series.
+func WebhookEventType(name string) string {
+	if webhookEventTypes[name] {
+		return name
+	}
+	return UnknownEventType
+}
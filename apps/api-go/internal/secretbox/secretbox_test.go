@@ -0,0 +1,51 @@
+package secretbox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBox_SealOpenRoundTrip(t *testing.T) {
+	box, err := New("test-encryption-key")
+	if err != nil {
+		t.Fatalf("new box: %v", err)
+	}
+	sealed, err := box.Seal("s3cret")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if strings.Contains(sealed, "s3cret") || !strings.HasPrefix(sealed, "v1:") {
+		t.Fatalf("unexpected sealed value %q", sealed)
+	}
+	again, _ := box.Seal("s3cret")
+	if again == sealed {
+		t.Fatalf("expected a fresh nonce per seal")
+	}
+	plain, err := box.Open(sealed)
+	if err != nil || plain != "s3cret" {
+		t.Fatalf("open: %q %v", plain, err)
+	}
+}
+
+func TestBox_OpenRejectsWrongKeyAndTampering(t *testing.T) {
+	box, _ := New("key-a")
+	other, _ := New("key-b")
+	sealed, _ := box.Seal("value")
+	if _, err := other.Open(sealed); err == nil {
+		t.Fatalf("expected wrong key to fail")
+	}
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if _, err := box.Open(tampered); err == nil {
+		t.Fatalf("expected tampered value to fail")
+	}
+	if _, err := box.Open("plain"); err == nil {
+		t.Fatalf("expected unprefixed value to fail")
+	}
+}
+
+func TestNew_RequiresKey(t *testing.T) {
+	if _, err := New("  "); !errors.Is(err, ErrKeyNotConfigured) {
+		t.Fatalf("expected ErrKeyNotConfigured, got %v", err)
+	}
+}
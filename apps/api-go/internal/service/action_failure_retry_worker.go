@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func StartActionFailureRetryWorker(sup *Supervisor, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, "action failure retry", interval, 2*time.Minute, runOnce, func(ctx context.Context, succeeded int, attempted int) {
+		if attempted > 0 {
+			slog.InfoContext(ctx, "action failure retry done", "succeeded", succeeded, "attempted", attempted)
+		}
+	})
+}
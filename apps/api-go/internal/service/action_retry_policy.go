@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MaxActionRetryBackoff = 12 * time.Hour
+
+// permanentForgeStatuses lists the response codes per forge that retrying can
+// never fix. GitHub 403 is left out because it also signals rate limiting.
+var permanentForgeStatuses = map[string]map[int]bool{
+	"github": {401: true, 404: true, 410: true, 422: true},
+	"gitlab": {401: true, 403: true, 404: true, 422: true},
+	"gitea":  {401: true, 403: true, 404: true, 422: true},
+}
+
+// forgeStatusPattern finds the "<forge> api status: <code>" prefix the forge
+// executors put in front of the upstream response message.
+var forgeStatusPattern = regexp.MustCompile(`\b(github|gitlab|gitea) api status: (\d{3})\b`)
+
+// localValidationErrorPrefixes match errors raised before any request is sent,
+// such as "invalid repository full name" or "unsupported source".
+var localValidationErrorPrefixes = []string{
+	"invalid ",
+	"empty ",
+	"unsupported ",
+}
+
+// IsPermanentActionError reports whether retrying the action can never succeed,
+// e.g. the target was deleted or GitHub rejected the request as invalid.
+func IsPermanentActionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *GitHubAPIError
+	if errors.As(err, &apiErr) {
+		return permanentForgeStatuses["github"][apiErr.StatusCode]
+	}
+	return IsPermanentActionErrorMessage(err.Error())
+}
+
+// IsPermanentActionErrorMessage classifies a stored error message. Forge errors
+// are judged on their status code alone, since the upstream message that
+// follows it is free text.
+func IsPermanentActionErrorMessage(message string) bool {
+	msg := strings.ToLower(strings.TrimSpace(message))
+	if msg == "" {
+		return false
+	}
+	if match := forgeStatusPattern.FindStringSubmatch(msg); match != nil {
+		code, _ := strconv.Atoi(match[2])
+		return permanentForgeStatuses[match[1]][code]
+	}
+	for _, prefix := range localValidationErrorPrefixes {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(msg, " not found")
+}
+
+// ActionRetryBackoff returns base * 2^retryCount, capped at 12 hours.
+func ActionRetryBackoff(retryCount int, base time.Duration) time.Duration {
+	if base <= 0 {
+		base = time.Minute
+	}
+	if retryCount < 0 {
+		retryCount = 0
+	}
+	d := base
+	for i := 0; i < retryCount; i++ {
+		d *= 2
+		if d >= MaxActionRetryBackoff {
+			return MaxActionRetryBackoff
+		}
+	}
+	return d
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsPermanentActionError(t *testing.T) {
+	cases := map[string]bool{
+		"github api status: 404":       true,
+		"github api status: 422":       true,
+		"invalid repository full name": true,
+		"github api status: 502":       false,
+		"github api status: 403":       false,
+		"context deadline exceeded":    false,
+		"gitlab api status: 403":       true,
+		"unsupported suggestion type":  true,
+		"github api status: 502: Server Error: invalid upstream response, empty body": false,
+		"gitea api status: 500: repository not found in cache":                        false,
+		"load related event failed: webhook event not found":                          true,
+	}
+	for msg, want := range cases {
+		if got := IsPermanentActionError(errors.New(msg)); got != want {
+			t.Fatalf("%q: expected %t, got %t", msg, want, got)
+		}
+	}
+	transient := &GitHubAPIError{StatusCode: 502, Message: "Not Found: invalid gateway response"}
+	if IsPermanentActionError(fmt.Errorf("add label: %w", transient)) {
+		t.Fatalf("expected a 502 to be retryable whatever its message says")
+	}
+	if !IsPermanentActionError(&GitHubAPIError{StatusCode: 410, Message: "Gone"}) {
+		t.Fatalf("expected a 410 to be permanent")
+	}
+	if IsPermanentActionError(nil) {
+		t.Fatalf("expected nil error to be non-permanent")
+	}
+}
+
+func TestActionRetryBackoff(t *testing.T) {
+	base := time.Minute
+	if got := ActionRetryBackoff(0, base); got != time.Minute {
+		t.Fatalf("expected 1m, got %v", got)
+	}
+	if got := ActionRetryBackoff(3, base); got != 8*time.Minute {
+		t.Fatalf("expected 8m, got %v", got)
+	}
+	if got := ActionRetryBackoff(40, base); got != 12*time.Hour {
+		t.Fatalf("expected cap at 12h, got %v", got)
+	}
+}
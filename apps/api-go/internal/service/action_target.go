@@ -0,0 +1,26 @@
+package service
+
+import "context"
+
+const (
+	TargetKindIssue       = "issue"
+	TargetKindPullRequest = "pull_request"
+)
+
+type targetKindCtxKey struct{}
+
+// WithTargetKind tells executors whose APIs address issues and pull/merge requests
+// separately which kind the action number refers to.
+func WithTargetKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, targetKindCtxKey{}, kind)
+}
+
+// TargetKindFromContext defaults to TargetKindIssue.
+func TargetKindFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if kind, ok := ctx.Value(targetKindCtxKey{}).(string); ok && kind == TargetKindPullRequest {
+			return kind
+		}
+	}
+	return TargetKindIssue
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// NormalizeGiteaEventType maps X-Gitea-Event values onto GitHub webhook names.
+// Gitea payloads already follow GitHub's shape, so only the name needs mapping.
+func NormalizeGiteaEventType(event string) string {
+	event = strings.ToLower(strings.TrimSpace(event))
+	switch {
+	case event == "issue_comment" || event == "pull_request_comment":
+		return "issue_comment"
+	case strings.HasPrefix(event, "pull_request"):
+		return "pull_request"
+	case strings.HasPrefix(event, "issue"):
+		return "issues"
+	}
+	return event
+}
+
+func GiteaProvider(executor ActionExecutor) Provider {
+	return Provider{Name: store.EventSourceGitea, Parse: ParseGiteaEvent, Executor: executor}
+}
+
+func ParseGiteaEvent(eventName string, body []byte) (NormalizedEvent, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return NormalizedEvent{}, fmt.Errorf("invalid JSON payload")
+	}
+	eventType := NormalizeGiteaEventType(eventName)
+	if eventType == "" {
+		eventType = "unknown"
+	}
+	evt := NormalizePayload(store.EventSourceGitea, eventType, payload)
+	evt.Raw = body
+	return evt, nil
+}
+
+// GiteaActionExecutor applies label and comment actions through the Gitea (and
+// Forgejo) API. Pull requests share the issue index, so one endpoint serves both.
+type GiteaActionExecutor struct {
+	Token      string
+	HTTPClient *http.Client
+	BaseURL    string
+}
+
+func NewGiteaActionExecutor(baseURL string, token string) *GiteaActionExecutor {
+	return &GiteaActionExecutor{
+		Token:      strings.TrimSpace(token),
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		BaseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+	}
+}
+
+func (e *GiteaActionExecutor) AddLabel(ctx context.Context, repositoryFullName string, number int, label string) error {
+	if strings.TrimSpace(label) == "" {
+		return fmt.Errorf("empty label")
+	}
+	endpoint, err := e.issueURL(repositoryFullName, number, "/labels")
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]any{"labels": []string{label}})
+	return e.do(ctx, http.MethodPost, endpoint, body)
+}
+
+func (e *GiteaActionExecutor) AddComment(ctx context.Context, repositoryFullName string, number int, comment string) error {
+	if strings.TrimSpace(comment) == "" {
+		return fmt.Errorf("empty comment")
+	}
+	endpoint, err := e.issueURL(repositoryFullName, number, "/comments")
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]any{"body": comment})
+	return e.do(ctx, http.MethodPost, endpoint, body)
+}
+
+func (e *GiteaActionExecutor) issueURL(repositoryFullName string, number int, suffix string) (string, error) {
+	if strings.TrimSpace(repositoryFullName) == "" || repositoryFullName == "unknown" || !strings.Contains(repositoryFullName, "/") {
+		return "", fmt.Errorf("invalid repository full name")
+	}
+	if number <= 0 {
+		return "", fmt.Errorf("invalid issue/pull_request number")
+	}
+	if e.BaseURL == "" {
+		return "", fmt.Errorf("gitea base url is not configured")
+	}
+	return fmt.Sprintf("%s/api/v1/repos/%s/issues/%d%s", e.BaseURL, repositoryFullName, number, suffix), nil
+}
+
+func (e *GiteaActionExecutor) do(ctx context.Context, method string, endpoint string, body []byte) error {
+	if e.Token == "" {
+		return fmt.Errorf("gitea token is not configured")
+	}
+	client := e.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+e.Token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request gitea api: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var errBody struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(respBody, &errBody) == nil && strings.TrimSpace(errBody.Message) != "" {
+		return fmt.Errorf("gitea api status: %d: %s", resp.StatusCode, strings.TrimSpace(errBody.Message))
+	}
+	return fmt.Errorf("gitea api status: %d", resp.StatusCode)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeGiteaEventType(t *testing.T) {
+	cases := map[string]string{
+		"issues":               "issues",
+		"issue_label":          "issues",
+		"pull_request":         "pull_request",
+		"pull_request_label":   "pull_request",
+		"issue_comment":        "issue_comment",
+		"pull_request_comment": "issue_comment",
+		"push":                 "push",
+	}
+	for in, want := range cases {
+		if got := NormalizeGiteaEventType(in); got != want {
+			t.Fatalf("%s: expected %s, got %s", in, want, got)
+		}
+	}
+}
+
+func TestGiteaExecutor_LabelsAndComments(t *testing.T) {
+	var paths, auths, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		auths = append(auths, r.Header.Get("Authorization"))
+		bodies = append(bodies, string(body))
+		if strings.Contains(r.URL.Path, "/404/") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"issue does not exist"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	exec := NewGiteaActionExecutor(srv.URL+"/", "gitea-token")
+	exec.HTTPClient = srv.Client()
+	if err := exec.AddLabel(context.Background(), "org/repo", 4, "needs-triage"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	if err := exec.AddComment(context.Background(), "org/repo", 4, "hello"); err != nil {
+		t.Fatalf("add comment: %v", err)
+	}
+	if err := exec.AddComment(context.Background(), "org/repo", 404, "hello"); err == nil || !IsPermanentActionError(err) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+
+	want := []string{"POST /api/v1/repos/org/repo/issues/4/labels", "POST /api/v1/repos/org/repo/issues/4/comments", "POST /api/v1/repos/org/repo/issues/404/comments"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected calls %v", paths)
+	}
+	if auths[0] != "token gitea-token" || bodies[0] != `{"labels":["needs-triage"]}` {
+		t.Fatalf("unexpected label request auth=%q body=%q", auths[0], bodies[0])
+	}
+}
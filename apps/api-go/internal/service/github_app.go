@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const installationTokenRefreshMargin = 5 * time.Minute
+
+type githubInstallationCtxKey struct{}
+
+// WithGitHubInstallationID marks ctx so that GitHub calls made with it use that
+// installation's access token.
+func WithGitHubInstallationID(ctx context.Context, installationID int64) context.Context {
+	if installationID <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, githubInstallationCtxKey{}, installationID)
+}
+
+func GitHubInstallationIDFromContext(ctx context.Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	v, ok := ctx.Value(githubInstallationCtxKey{}).(int64)
+	return v, ok && v > 0
+}
+
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// GitHubAppTokenSource signs app JWTs and exchanges them for installation access
+// tokens, caching each token until shortly before it expires.
+type GitHubAppTokenSource struct {
+	AppID      string
+	PrivateKey *rsa.PrivateKey
+	BaseURL    string
+	HTTPClient *http.Client
+	Now        func() time.Time
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+func NewGitHubAppTokenSource(appID string, privateKeyPEM []byte) (*GitHubAppTokenSource, error) {
+	appID = strings.TrimSpace(appID)
+	if appID == "" {
+		return nil, fmt.Errorf("github app id is empty")
+	}
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubAppTokenSource{
+		AppID:      appID,
+		PrivateKey: key,
+		BaseURL:    "https://api.github.com",
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		Now:        time.Now,
+		tokens:     map[int64]installationToken{},
+	}, nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("github app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("github app private key is not RSA")
+	}
+	return key, nil
+}
+
+func (s *GitHubAppTokenSource) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// AppJWT returns a short-lived RS256 JWT identifying the app. iat is backdated
+// by a minute to tolerate clock drift, as GitHub recommends.
+func (s *GitHubAppTokenSource) AppJWT() (string, error) {
+	now := s.now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    s.AppID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	})
+	signed, err := token.SignedString(s.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("sign github app jwt: %w", err)
+	}
+	return signed, nil
+}
+
+func (s *GitHubAppTokenSource) InstallationToken(ctx context.Context, installationID int64) (string, error) {
+	if installationID <= 0 {
+		return "", fmt.Errorf("invalid github installation id")
+	}
+
+	s.mu.Lock()
+	if s.tokens == nil {
+		s.tokens = map[int64]installationToken{}
+	}
+	cached, ok := s.tokens[installationID]
+	s.mu.Unlock()
+	if ok && s.now().Add(installationTokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	appJWT, err := s.AppJWT()
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimRight(strings.TrimSpace(s.BaseURL), "/")
+	if base == "" {
+		base = "https://api.github.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/app/installations/%d/access_tokens", base, installationID), nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+appJWT)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request installation token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &GitHubAPIError{StatusCode: resp.StatusCode}
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &errBody) == nil {
+			apiErr.Message = strings.TrimSpace(errBody.Message)
+		}
+		return "", fmt.Errorf("exchange installation token: %w", apiErr)
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("decode installation token: %w", err)
+	}
+	if strings.TrimSpace(result.Token) == "" {
+		return "", errors.New("installation token is empty")
+	}
+
+	s.mu.Lock()
+	s.tokens[installationID] = installationToken{token: result.Token, expiresAt: result.ExpiresAt}
+	s.mu.Unlock()
+	return result.Token, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, pemBytes
+}
+
+func TestGitHubAppTokenSource_ExchangesAndCachesInstallationToken(t *testing.T) {
+	key, pemBytes := newTestAppKey(t)
+	now := time.Now()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(raw, claims, func(tok *jwt.Token) (any, error) {
+			if tok.Method != jwt.SigningMethodRS256 {
+				return nil, fmt.Errorf("unexpected alg %v", tok.Header["alg"])
+			}
+			return &key.PublicKey, nil
+		})
+		if err != nil {
+			t.Errorf("invalid app jwt: %v", err)
+		}
+		if claims.Issuer != "1234" {
+			t.Errorf("expected iss=1234, got %q", claims.Issuer)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token":"ghs_installation","expires_at":"%s"}`, now.Add(time.Hour).UTC().Format(time.RFC3339))
+	}))
+	defer srv.Close()
+
+	src, err := NewGitHubAppTokenSource("1234", pemBytes)
+	if err != nil {
+		t.Fatalf("new token source: %v", err)
+	}
+	src.BaseURL = srv.URL
+	src.HTTPClient = srv.Client()
+	src.Now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		token, err := src.InstallationToken(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("installation token: %v", err)
+		}
+		if token != "ghs_installation" {
+			t.Fatalf("unexpected token %q", token)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected cached token to be reused, got %d exchanges", calls)
+	}
+
+	// Within the refresh margin the token is exchanged again.
+	src.Now = func() time.Time { return now.Add(56 * time.Minute) }
+	if _, err := src.InstallationToken(context.Background(), 42); err != nil {
+		t.Fatalf("refresh installation token: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected token refresh near expiry, got %d exchanges", calls)
+	}
+}
+
+func TestGitHubExecutor_UsesInstallationTokenFromContext(t *testing.T) {
+	_, pemBytes := newTestAppKey(t)
+	var seenAuth []string
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/app/installations/") {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token":"ghs_%s","expires_at":"%s"}`, strings.Split(r.URL.Path, "/")[3], time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+			return
+		}
+		seenAuth = append(seenAuth, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	})
+	src, err := NewGitHubAppTokenSource("1234", pemBytes)
+	if err != nil {
+		t.Fatalf("new token source: %v", err)
+	}
+	src.BaseURL = exec.BaseURL
+	src.HTTPClient = exec.HTTPClient
+	exec.AppTokens = src
+	exec.InstallationLookup = func(context.Context) (int64, error) { return 7, nil }
+
+	if err := exec.AddLabel(WithGitHubInstallationID(context.Background(), 99), "owner/repo", 1, "x"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	if err := exec.AddLabel(context.Background(), "owner/repo", 1, "x"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	exec.InstallationLookup = func(context.Context) (int64, error) { return 0, nil }
+	if err := exec.AddLabel(context.Background(), "owner/repo", 1, "x"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+
+	want := []string{"Bearer ghs_99", "Bearer ghs_7", "Bearer token-" + t.Name()}
+	if strings.Join(seenAuth, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected authorization headers: %v", seenAuth)
+	}
+}
+
+func TestNewGitHubAppTokenSource_RejectsInvalidKey(t *testing.T) {
+	if _, err := NewGitHubAppTokenSource("1234", []byte("not a key")); err == nil {
+		t.Fatalf("expected invalid key error")
+	}
+	if _, err := NewGitHubAppTokenSource("", nil); err == nil {
+		t.Fatalf("expected missing app id error")
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Event source kinds that can be polled through the events API.
+const (
+	GitHubEventSourceRepo = "repo"
+	GitHubEventSourceOrg  = "org"
+)
+
+// githubEventsMaxPages matches the events API, which serves at most 300 events
+// at 100 per page.
+const githubEventsMaxPages = 3
+
+// GitHubEventSource is a repository (owner/repo) or organisation to poll.
+type GitHubEventSource struct {
+	Kind string
+	Name string
+}
+
+func (s GitHubEventSource) path() (string, error) {
+	name := strings.Trim(strings.TrimSpace(s.Name), "/")
+	switch s.Kind {
+	case GitHubEventSourceRepo:
+		if strings.Count(name, "/") != 1 {
+			return "", fmt.Errorf("repo source needs owner/repo")
+		}
+		return "/repos/" + name + "/events", nil
+	case GitHubEventSourceOrg:
+		if name == "" || strings.Contains(name, "/") {
+			return "", fmt.Errorf("org source needs an org login")
+		}
+		return "/orgs/" + url.PathEscape(name) + "/events", nil
+	}
+	return "", fmt.Errorf("unsupported event source kind %q", s.Kind)
+}
+
+// ValidateGitHubEventSource reports whether src can be polled.
+func ValidateGitHubEventSource(src GitHubEventSource) error {
+	_, err := src.path()
+	return err
+}
+
+// GitHubEventPoll is the result of polling one source. Events are newest first
+// and only include events after the cursor. LastEventID is the newest event seen,
+// or the cursor when nothing new arrived.
+type GitHubEventPoll struct {
+	Events      []GitHubUserEvent
+	ETag        string
+	LastEventID int64
+	NotModified bool
+}
+
+// PollSourceEvents fetches events of src newer than sinceID. The first page is
+// requested with If-None-Match so an unchanged feed costs no rate limit; later
+// pages are followed through the Link header until the cursor is reached.
+func (e *GitHubActionExecutor) PollSourceEvents(ctx context.Context, src GitHubEventSource, etag string, sinceID int64) (GitHubEventPoll, error) {
+	path, err := src.path()
+	if err != nil {
+		return GitHubEventPoll{}, err
+	}
+	token, key, err := e.credentials(ctx)
+	if err != nil {
+		return GitHubEventPoll{}, err
+	}
+
+	poll := GitHubEventPoll{ETag: etag, LastEventID: sinceID}
+	endpoint := e.baseURL() + path + "?per_page=100"
+	for page := 0; page < githubEventsMaxPages && endpoint != ""; page++ {
+		conditional := ""
+		if page == 0 {
+			conditional = etag
+		}
+		body, headers, err := e.send(ctx, http.MethodGet, endpoint, nil, token, key, conditional)
+		if errors.Is(err, errGitHubNotModified) {
+			poll.NotModified = true
+			return poll, nil
+		}
+		if err != nil {
+			return GitHubEventPoll{}, err
+		}
+		if page == 0 {
+			poll.ETag = headers.Get("ETag")
+		}
+		events, err := decodeGitHubEvents(body)
+		if err != nil {
+			return GitHubEventPoll{}, err
+		}
+		for _, evt := range events {
+			id := GitHubEventID(evt)
+			if id <= sinceID {
+				return poll, nil
+			}
+			if id > poll.LastEventID {
+				poll.LastEventID = id
+			}
+			poll.Events = append(poll.Events, evt)
+		}
+		endpoint = ParseNextLink(headers.Get("Link"))
+	}
+	return poll, nil
+}
+
+// GitHubEventID returns the numeric id behind a "gh-<id>" delivery id, or 0.
+func GitHubEventID(evt GitHubUserEvent) int64 {
+	id, err := strconv.ParseInt(strings.TrimPrefix(evt.DeliveryID, "gh-"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGitHubExecutor_PollSourceEventsFollowsLinksToCursor(t *testing.T) {
+	var srvURL string
+	requests := 0
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/repos/owner/repo/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`[{"id":"103","type":"IssuesEvent"},{"id":"100","type":"IssuesEvent"},{"id":"99","type":"IssuesEvent"}]`))
+			return
+		}
+		if r.Header.Get("If-None-Match") == `W/"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `W/"v2"`)
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/events?per_page=100&page=2>; rel="next"`, srvURL))
+		_, _ = w.Write([]byte(`[{"id":"105","type":"IssuesEvent","payload":{"action":"opened"}},{"id":"104","type":"WatchEvent"}]`))
+	})
+	srvURL = exec.BaseURL
+	src := GitHubEventSource{Kind: GitHubEventSourceRepo, Name: "owner/repo"}
+
+	poll, err := exec.PollSourceEvents(context.Background(), src, `W/"v1"`, 100)
+	if err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if len(poll.Events) != 3 || poll.Events[2].DeliveryID != "gh-103" || poll.LastEventID != 105 || poll.ETag != `W/"v2"` {
+		t.Fatalf("unexpected poll: %+v", poll)
+	}
+	if poll.Events[0].Action != "opened" {
+		t.Fatalf("expected events to be normalised, got %+v", poll.Events[0])
+	}
+
+	requests = 0
+	poll, err = exec.PollSourceEvents(context.Background(), src, `W/"v2"`, 105)
+	if err != nil || !poll.NotModified || len(poll.Events) != 0 || poll.LastEventID != 105 || poll.ETag != `W/"v2"` {
+		t.Fatalf("expected not modified poll, got %+v err=%v", poll, err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected a single conditional request, got %d", requests)
+	}
+}
+
+func TestValidateGitHubEventSource(t *testing.T) {
+	cases := []struct {
+		src GitHubEventSource
+		ok  bool
+	}{
+		{GitHubEventSource{Kind: GitHubEventSourceRepo, Name: "owner/repo"}, true},
+		{GitHubEventSource{Kind: GitHubEventSourceRepo, Name: "owner"}, false},
+		{GitHubEventSource{Kind: GitHubEventSourceOrg, Name: "acme"}, true},
+		{GitHubEventSource{Kind: GitHubEventSourceOrg, Name: "acme/repo"}, false},
+		{GitHubEventSource{Kind: "user", Name: "alice"}, false},
+	}
+	for _, tc := range cases {
+		if err := ValidateGitHubEventSource(tc.src); (err == nil) != tc.ok {
+			t.Fatalf("ValidateGitHubEventSource(%+v) = %v, want ok=%t", tc.src, err, tc.ok)
+		}
+	}
+}
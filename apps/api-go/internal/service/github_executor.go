@@ -4,17 +4,37 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"maintainer-firewall/api-go/internal/metrics"
+	"maintainer-firewall/api-go/internal/tenantctx"
+	"maintainer-firewall/api-go/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type GitHubActionExecutor struct {
-	Token      string
-	HTTPClient *http.Client
+	Token         string
+	HTTPClient    *http.Client
+	BaseURL       string
+	WriteInterval time.Duration
+
+	// AppTokens, when set, authenticates as a GitHub App installation. The
+	// installation comes from the request context, then InstallationLookup
+	// (typically the tenant's mapped installation). Otherwise TokenLookup may
+	// supply a tenant's own token; Token is the fallback.
+	AppTokens          *GitHubAppTokenSource
+	InstallationLookup func(ctx context.Context) (int64, error)
+	TokenLookup        func(ctx context.Context) (string, error)
 }
 
 type GitHubUserEvent struct {
@@ -26,10 +46,40 @@ type GitHubUserEvent struct {
 	PayloadJSON        json.RawMessage
 }
 
+type GitHubItemQuery struct {
+	RepositoryFullName string
+	TargetKind         string
+	Label              string
+	CreatedBefore      time.Time
+	UpdatedBefore      time.Time
+	Page               int
+	PerPage            int
+}
+
+type GitHubIssueItem struct {
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	State         string    `json:"state"`
+	AuthorLogin   string    `json:"author_login"`
+	IsPullRequest bool      `json:"is_pull_request"`
+	HTMLURL       string    `json:"html_url"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type GitHubIssueComment struct {
+	AuthorLogin string    `json:"author_login"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// githubCommentPages caps how many pages of comments are read per item.
+const githubCommentPages = 10
+
 func NewGitHubActionExecutor(token string) *GitHubActionExecutor {
 	return &GitHubActionExecutor{
-		Token:      strings.TrimSpace(token),
-		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		Token:         strings.TrimSpace(token),
+		HTTPClient:    &http.Client{Timeout: 5 * time.Second},
+		BaseURL:       "https://api.github.com",
+		WriteInterval: defaultGitHubWriteInterval,
 	}
 }
 
@@ -44,7 +94,7 @@ func (e *GitHubActionExecutor) AddLabel(ctx context.Context, repositoryFullName
 		return fmt.Errorf("empty label")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/labels", repositoryFullName, number)
+	url := fmt.Sprintf(e.baseURL()+"/repos/%s/issues/%d/labels", repositoryFullName, number)
 	body, _ := json.Marshal(map[string]any{"labels": []string{label}})
 	return e.doJSONRequest(ctx, http.MethodPost, url, body)
 }
@@ -60,11 +110,130 @@ func (e *GitHubActionExecutor) AddComment(ctx context.Context, repositoryFullNam
 		return fmt.Errorf("empty comment")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/comments", repositoryFullName, number)
+	url := fmt.Sprintf(e.baseURL()+"/repos/%s/issues/%d/comments", repositoryFullName, number)
 	body, _ := json.Marshal(map[string]any{"body": comment})
 	return e.doJSONRequest(ctx, http.MethodPost, url, body)
 }
 
+func (e *GitHubActionExecutor) CloseIssue(ctx context.Context, repositoryFullName string, number int) error {
+	if strings.TrimSpace(repositoryFullName) == "" || repositoryFullName == "unknown" {
+		return fmt.Errorf("invalid repository full name")
+	}
+	if number <= 0 {
+		return fmt.Errorf("invalid issue/pull_request number")
+	}
+
+	url := fmt.Sprintf(e.baseURL()+"/repos/%s/issues/%d", repositoryFullName, number)
+	body, _ := json.Marshal(map[string]any{"state": "closed"})
+	return e.doJSONRequest(ctx, http.MethodPatch, url, body)
+}
+
+// SearchOpenItems lists one page of open issues or pull requests through the
+// search API, least recently updated first.
+func (e *GitHubActionExecutor) SearchOpenItems(ctx context.Context, q GitHubItemQuery) ([]GitHubIssueItem, error) {
+	if strings.TrimSpace(q.RepositoryFullName) == "" || q.RepositoryFullName == "unknown" {
+		return nil, fmt.Errorf("invalid repository full name")
+	}
+	perPage := q.PerPage
+	if perPage <= 0 || perPage > 100 {
+		perPage = 100
+	}
+	page := q.Page
+	if page < 1 {
+		page = 1
+	}
+
+	terms := []string{"repo:" + strings.TrimSpace(q.RepositoryFullName), "is:open"}
+	if q.TargetKind == "pull_request" {
+		terms = append(terms, "is:pr")
+	} else {
+		terms = append(terms, "is:issue")
+	}
+	if label := strings.TrimSpace(q.Label); label != "" {
+		terms = append(terms, fmt.Sprintf("label:\"%s\"", label))
+	}
+	if !q.CreatedBefore.IsZero() {
+		terms = append(terms, "created:<"+q.CreatedBefore.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	if !q.UpdatedBefore.IsZero() {
+		terms = append(terms, "updated:<"+q.UpdatedBefore.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+
+	endpoint := fmt.Sprintf(e.baseURL()+"/search/issues?q=%s&sort=updated&order=asc&per_page=%d&page=%d", url.QueryEscape(strings.Join(terms, " ")), perPage, page)
+	body, err := e.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Items []struct {
+			Number      int       `json:"number"`
+			Title       string    `json:"title"`
+			State       string    `json:"state"`
+			HTMLURL     string    `json:"html_url"`
+			UpdatedAt   time.Time `json:"updated_at"`
+			PullRequest *struct{} `json:"pull_request"`
+			User        struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("decode github search: %w", err)
+	}
+
+	out := make([]GitHubIssueItem, 0, len(result.Items))
+	for _, item := range result.Items {
+		out = append(out, GitHubIssueItem{
+			Number:        item.Number,
+			Title:         item.Title,
+			State:         item.State,
+			AuthorLogin:   item.User.Login,
+			IsPullRequest: item.PullRequest != nil,
+			HTMLURL:       item.HTMLURL,
+			UpdatedAt:     item.UpdatedAt,
+		})
+	}
+	return out, nil
+}
+
+// ListIssueCommentsSince lists comments on an issue or pull request that were
+// created or edited at or after since, oldest first.
+func (e *GitHubActionExecutor) ListIssueCommentsSince(ctx context.Context, repositoryFullName string, number int, since time.Time) ([]GitHubIssueComment, error) {
+	if strings.TrimSpace(repositoryFullName) == "" || repositoryFullName == "unknown" {
+		return nil, fmt.Errorf("invalid repository full name")
+	}
+	if number <= 0 {
+		return nil, fmt.Errorf("invalid issue/pull_request number")
+	}
+
+	out := make([]GitHubIssueComment, 0)
+	for page := 1; page <= githubCommentPages; page++ {
+		endpoint := fmt.Sprintf(e.baseURL()+"/repos/%s/issues/%d/comments?since=%s&per_page=100&page=%d",
+			repositoryFullName, number, url.QueryEscape(since.UTC().Format(time.RFC3339)), page)
+		body, err := e.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result []struct {
+			CreatedAt time.Time `json:"created_at"`
+			User      struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("decode github comments: %w", err)
+		}
+		for _, item := range result {
+			out = append(out, GitHubIssueComment{AuthorLogin: item.User.Login, CreatedAt: item.CreatedAt})
+		}
+		if len(result) < 100 {
+			break
+		}
+	}
+	return out, nil
+}
+
 func (e *GitHubActionExecutor) ListRecentEventTypes(ctx context.Context) ([]string, error) {
 	events, err := e.ListRecentEvents(ctx)
 	if err != nil {
@@ -91,11 +260,15 @@ func (e *GitHubActionExecutor) ListRecentEvents(ctx context.Context) ([]GitHubUs
 	if err != nil {
 		return nil, err
 	}
-	body, err := e.doRequest(ctx, http.MethodGet, fmt.Sprintf("https://api.github.com/users/%s/events?per_page=100", login), nil)
+	body, err := e.doRequest(ctx, http.MethodGet, fmt.Sprintf(e.baseURL()+"/users/%s/events?per_page=100", login), nil)
 	if err != nil {
 		return nil, err
 	}
+	return decodeGitHubEvents(body)
+}
 
+// decodeGitHubEvents maps an events API response to stored-event fields.
+func decodeGitHubEvents(body []byte) ([]GitHubUserEvent, error) {
 	var raw []map[string]any
 	if err := json.Unmarshal(body, &raw); err != nil {
 		return nil, fmt.Errorf("decode github events: %w", err)
@@ -170,7 +343,7 @@ func normalizeGitHubEventAction(eventType string, payload map[string]any) string
 }
 
 func (e *GitHubActionExecutor) getAuthenticatedLogin(ctx context.Context) (string, error) {
-	body, err := e.doRequest(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	body, err := e.doRequest(ctx, http.MethodGet, e.baseURL()+"/user", nil)
 	if err != nil {
 		return "", err
 	}
@@ -192,33 +365,217 @@ func (e *GitHubActionExecutor) doJSONRequest(ctx context.Context, method string,
 	return err
 }
 
-func (e *GitHubActionExecutor) doRequest(ctx context.Context, method string, url string, body []byte) ([]byte, error) {
+func (e *GitHubActionExecutor) baseURL() string {
+	base := strings.TrimRight(strings.TrimSpace(e.BaseURL), "/")
+	if base == "" {
+		return "https://api.github.com"
+	}
+	return base
+}
+
+func (e *GitHubActionExecutor) installationID(ctx context.Context) int64 {
+	if e.AppTokens == nil {
+		return 0
+	}
+	if id, ok := GitHubInstallationIDFromContext(ctx); ok {
+		return id
+	}
+	if e.InstallationLookup == nil {
+		return 0
+	}
+	id, err := e.InstallationLookup(ctx)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// credentials returns the bearer token for ctx and the key its rate limits are
+// tracked under. Installation tokens rotate hourly, so they are keyed by installation.
+func (e *GitHubActionExecutor) credentials(ctx context.Context) (string, string, error) {
+	if id := e.installationID(ctx); id > 0 {
+		token, err := e.AppTokens.InstallationToken(ctx, id)
+		if err != nil {
+			return "", "", err
+		}
+		return token, fmt.Sprintf("installation:%d", id), nil
+	}
+	// A failed lookup must not silently fall back to the shared token.
+	token, err := e.tenantToken(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve tenant github token: %w", err)
+	}
+	if token != "" {
+		return token, tokenThrottleKey(token), nil
+	}
 	if strings.TrimSpace(e.Token) == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN is not configured")
+		return "", "", fmt.Errorf("GITHUB_TOKEN is not configured")
+	}
+	return e.Token, tokenThrottleKey(e.Token), nil
+}
+
+func (e *GitHubActionExecutor) tenantToken(ctx context.Context) (string, error) {
+	if e.TokenLookup == nil {
+		return "", nil
+	}
+	token, err := e.TokenLookup(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func (e *GitHubActionExecutor) throttleKey(ctx context.Context) string {
+	if id := e.installationID(ctx); id > 0 {
+		return fmt.Sprintf("installation:%d", id)
+	}
+	if token, _ := e.tenantToken(ctx); token != "" {
+		return tokenThrottleKey(token)
+	}
+	return tokenThrottleKey(e.Token)
+}
+
+func (e *GitHubActionExecutor) doRequest(ctx context.Context, method string, url string, body []byte) ([]byte, error) {
+	token, key, err := e.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	respBody, _, err := e.send(ctx, method, url, body, token, key, "")
+	return respBody, err
+}
+
+// errGitHubNotModified is returned by send when a conditional request hit.
+var errGitHubNotModified = errors.New("github api: not modified")
+
+// send performs an authenticated request with the given token; key selects the
+// rate-limit throttle. It also returns the response headers for pagination.
+// A non-empty ifNoneMatch makes the request conditional.
+func (e *GitHubActionExecutor) send(ctx context.Context, method string, url string, body []byte, token string, key string, ifNoneMatch string) ([]byte, http.Header, error) {
+	ctx, span := tracing.Start(ctx, "github.api", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("http.target", githubSpanTarget(url)),
+		attribute.String("mf.tenant_id", tenantctx.MustFromContext(ctx, tenantctx.DefaultTenantID)),
+	))
+	defer span.End()
+	respBody, header, err := e.sendRequest(ctx, method, url, body, token, key, ifNoneMatch)
+	var apiErr *GitHubAPIError
+	switch {
+	case errors.As(err, &apiErr):
+		span.SetAttributes(attribute.Int("http.status_code", apiErr.StatusCode))
+		tracing.RecordError(span, err)
+	case errors.Is(err, errGitHubNotModified):
+		span.SetAttributes(attribute.Int("http.status_code", http.StatusNotModified))
+	case err != nil:
+		tracing.RecordError(span, err)
+	default:
+		span.SetAttributes(attribute.Int("http.status_code", http.StatusOK))
+	}
+	return respBody, header, err
+}
+
+// githubSpanTarget keeps the path of a GitHub API URL; query strings may carry
+// search terms and are left out of spans.
+func githubSpanTarget(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
 	}
+	return u.Path
+}
+
+func (e *GitHubActionExecutor) sendRequest(ctx context.Context, method string, url string, body []byte, token string, key string, ifNoneMatch string) ([]byte, http.Header, error) {
 	client := e.HTTPClient
 	if client == nil {
 		client = &http.Client{Timeout: 5 * time.Second}
 	}
 
+	throttle := throttleForToken(key)
+	if err := throttle.wait(ctx, method != http.MethodGet, e.WriteInterval); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, nil, fmt.Errorf("create request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+e.Token)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 	req.Header.Set("Content-Type", "application/json")
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
 
+	started := time.Now()
 	resp, err := client.Do(req)
+	metrics.GitHubAPIRequestSeconds.WithLabelValues(method).Observe(time.Since(started).Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("request github api: %w", err)
+		metrics.GitHubAPIRequests.WithLabelValues(method, "error").Inc()
+		return nil, nil, fmt.Errorf("request github api: %w", err)
 	}
 	defer resp.Body.Close()
+	metrics.GitHubAPIRequests.WithLabelValues(method, strconv.Itoa(resp.StatusCode)).Inc()
 	respBody, _ := io.ReadAll(resp.Body)
 
+	now := time.Now()
+	rateLimit := parseGitHubRateLimit(resp.Header, now)
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	throttle.observe(rateLimit, retryAfter, now)
+
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return respBody, nil
+		return respBody, resp.Header, nil
+	}
+	if resp.StatusCode == http.StatusNotModified && ifNoneMatch != "" {
+		return nil, resp.Header, errGitHubNotModified
+	}
+
+	apiErr := &GitHubAPIError{StatusCode: resp.StatusCode, RateLimit: rateLimit, RetryAfter: retryAfter}
+	var errBody struct {
+		Message string `json:"message"`
 	}
-	return nil, fmt.Errorf("github api status: %d", resp.StatusCode)
+	if json.Unmarshal(respBody, &errBody) == nil {
+		apiErr.Message = strings.TrimSpace(errBody.Message)
+	}
+	if apiErr.IsRateLimited() && retryAfter == 0 {
+		// Secondary limits without Retry-After: back off for at least a minute.
+		if delay, _ := GitHubRetryDelay(apiErr, now); delay > 0 {
+			throttle.observe(nil, delay, now)
+		}
+	}
+	return nil, resp.Header, apiErr
+}
+
+// RateLimitStatus returns the most recently observed limits for the credentials
+// ctx resolves to and the time until which requests are paused, if any.
+func (e *GitHubActionExecutor) RateLimitStatus(ctx context.Context) (map[string]GitHubRateLimit, time.Time) {
+	return throttleForToken(e.throttleKey(ctx)).snapshot()
+}
+
+// FetchRateLimit queries /rate_limit, which does not count against the limit.
+func (e *GitHubActionExecutor) FetchRateLimit(ctx context.Context) (map[string]GitHubRateLimit, error) {
+	body, err := e.doRequest(ctx, http.MethodGet, e.baseURL()+"/rate_limit", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Used      int   `json:"used"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("decode github rate limit: %w", err)
+	}
+
+	now := time.Now().UTC()
+	throttle := throttleForToken(e.throttleKey(ctx))
+	out := make(map[string]GitHubRateLimit, len(result.Resources))
+	for name, r := range result.Resources {
+		info := GitHubRateLimit{Resource: name, Limit: r.Limit, Remaining: r.Remaining, Used: r.Used, Reset: time.Unix(r.Reset, 0).UTC(), ObservedAt: now}
+		out[name] = info
+		throttle.observe(&info, 0, now)
+	}
+	return out, nil
 }
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestExecutor(t *testing.T, handler http.HandlerFunc) *GitHubActionExecutor {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	exec := NewGitHubActionExecutor("token-" + t.Name())
+	exec.BaseURL = srv.URL
+	exec.HTTPClient = srv.Client()
+	return exec
+}
+
+func TestGitHubExecutor_SecondaryRateLimitError(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", reset))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`))
+	})
+
+	err := exec.AddLabel(context.Background(), "owner/repo", 1, "spam")
+	var apiErr *GitHubAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected GitHubAPIError, got %T %v", err, err)
+	}
+	if !strings.HasPrefix(err.Error(), "github api status: 403") {
+		t.Fatalf("expected status prefix to be preserved, got %q", err.Error())
+	}
+	if !apiErr.IsRateLimited() || apiErr.RetryAfter != 30*time.Second {
+		t.Fatalf("expected secondary rate limit with 30s retry-after, got %+v", apiErr)
+	}
+	if apiErr.RateLimit == nil || apiErr.RateLimit.Remaining != 4000 || apiErr.RateLimit.Reset.Unix() != reset {
+		t.Fatalf("unexpected rate limit info: %+v", apiErr.RateLimit)
+	}
+	if delay, ok := GitHubRetryDelay(err, time.Now()); !ok || delay != 30*time.Second {
+		t.Fatalf("expected retry delay 30s, got %v %t", delay, ok)
+	}
+	if IsPermanentActionError(err) {
+		t.Fatalf("rate limit errors must not be permanent")
+	}
+}
+
+func TestGitHubExecutor_NotFoundIsNotRateLimited(t *testing.T) {
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+
+	err := exec.AddComment(context.Background(), "owner/repo", 1, "hello")
+	if _, ok := GitHubRetryDelay(err, time.Now()); ok {
+		t.Fatalf("expected 404 not to be treated as rate limited")
+	}
+	if !IsPermanentActionError(err) {
+		t.Fatalf("expected 404 to be permanent, got %v", err)
+	}
+}
+
+func TestGitHubExecutor_PausesWhenCoreLimitExhausted(t *testing.T) {
+	calls := 0
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	if err := exec.AddLabel(context.Background(), "owner/repo", 1, "a"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := exec.AddLabel(ctx, "owner/repo", 1, "b")
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Fatalf("expected throttled error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected throttled request not to reach GitHub, got %d calls", calls)
+	}
+
+	limits, pausedUntil := exec.RateLimitStatus(context.Background())
+	if limits["core"].Remaining != 0 || !pausedUntil.After(time.Now()) {
+		t.Fatalf("expected paused core limit, got %+v paused_until=%v", limits, pausedUntil)
+	}
+}
+
+func TestGitHubExecutor_SpacesWrites(t *testing.T) {
+	var mu sync.Mutex
+	var seen []time.Time
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+	exec.WriteInterval = 50 * time.Millisecond
+
+	for i := 0; i < 3; i++ {
+		if err := exec.AddLabel(context.Background(), "owner/repo", 1, "x"); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(seen))
+	}
+	if gap := seen[2].Sub(seen[0]); gap < 100*time.Millisecond {
+		t.Fatalf("expected writes spaced by the write interval, total gap=%v", gap)
+	}
+}
+
+func TestGitHubExecutor_FetchRateLimit(t *testing.T) {
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"resources":{"core":{"limit":5000,"remaining":4321,"used":679,"reset":1767225600},"search":{"limit":30,"remaining":30,"used":0,"reset":1767225600}}}`))
+	})
+
+	limits, err := exec.FetchRateLimit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if limits["core"].Remaining != 4321 || limits["search"].Limit != 30 {
+		t.Fatalf("unexpected limits: %+v", limits)
+	}
+	observed, _ := exec.RateLimitStatus(context.Background())
+	if observed["core"].Used != 679 {
+		t.Fatalf("expected fetched limits to be cached, got %+v", observed)
+	}
+}
+
+func TestGitHubExecutor_RateLimitStatusUsesTenantCredentials(t *testing.T) {
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		remaining := "4000"
+		if r.Header.Get("Authorization") == "Bearer tenant-"+t.Name() {
+			remaining = "12"
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+		w.WriteHeader(http.StatusOK)
+	})
+	type tenantKey struct{}
+	exec.TokenLookup = func(ctx context.Context) (string, error) {
+		if ctx.Value(tenantKey{}) != nil {
+			return "tenant-" + t.Name(), nil
+		}
+		return "", nil
+	}
+	tenantCtx := context.WithValue(context.Background(), tenantKey{}, true)
+
+	if err := exec.AddLabel(tenantCtx, "owner/repo", 1, "a"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := exec.AddLabel(context.Background(), "owner/repo", 1, "b"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	tenantLimits, _ := exec.RateLimitStatus(tenantCtx)
+	sharedLimits, _ := exec.RateLimitStatus(context.Background())
+	if tenantLimits["core"].Remaining != 12 || sharedLimits["core"].Remaining != 4000 {
+		t.Fatalf("expected limits per credential, tenant=%+v shared=%+v", tenantLimits, sharedLimits)
+	}
+}
+
+func TestGitHubThrottles_HashTokensAndEvictIdleEntries(t *testing.T) {
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	if err := exec.AddLabel(context.Background(), "owner/repo", 1, "a"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	githubThrottles.mu.Lock()
+	_, rawKept := githubThrottles.items[exec.Token]
+	_, hashed := githubThrottles.items[tokenThrottleKey(exec.Token)]
+	githubThrottles.mu.Unlock()
+	if rawKept || !hashed {
+		t.Fatalf("expected throttle keyed by token hash, raw=%v hashed=%v", rawKept, hashed)
+	}
+
+	now := time.Now()
+	limited := &githubThrottle{lastUsedAt: now.Add(-time.Hour), limits: map[string]GitHubRateLimit{
+		"core": {Resource: "core", Reset: now.Add(time.Minute)},
+	}}
+	if limited.idle(now) {
+		t.Fatal("expected a throttle inside its reset window to be kept")
+	}
+	if !limited.idle(now.Add(2 * time.Minute)) {
+		t.Fatal("expected a throttle past its reset window to be evicted")
+	}
+	recent := &githubThrottle{lastUsedAt: now, limits: map[string]GitHubRateLimit{}}
+	if recent.idle(now) {
+		t.Fatal("expected a recently used throttle to be kept")
+	}
+}
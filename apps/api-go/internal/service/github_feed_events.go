@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// githubFeedEventTypes maps Events API types to the webhook event names they
+// correspond to. Types not listed here fall back to githubFeedTypeToWebhook.
+var githubFeedEventTypes = map[string]string{
+	"CommitCommentEvent":            "commit_comment",
+	"CreateEvent":                   "create",
+	"DeleteEvent":                   "delete",
+	"DiscussionEvent":               "discussion",
+	"ForkEvent":                     "fork",
+	"GollumEvent":                   "gollum",
+	"IssueCommentEvent":             "issue_comment",
+	"IssuesEvent":                   "issues",
+	"MemberEvent":                   "member",
+	"PublicEvent":                   "public",
+	"PullRequestEvent":              "pull_request",
+	"PullRequestReviewEvent":        "pull_request_review",
+	"PullRequestReviewCommentEvent": "pull_request_review_comment",
+	"PullRequestReviewThreadEvent":  "pull_request_review_thread",
+	"PushEvent":                     "push",
+	"ReleaseEvent":                  "release",
+	"SponsorshipEvent":              "sponsorship",
+	"WatchEvent":                    "watch",
+}
+
+// GitHubFeedEventType returns the webhook event name for an Events API type,
+// e.g. "issues" for "IssuesEvent".
+func GitHubFeedEventType(feedType string) string {
+	feedType = strings.TrimSpace(feedType)
+	if name, ok := githubFeedEventTypes[feedType]; ok {
+		return name
+	}
+	return githubFeedTypeToWebhook(feedType)
+}
+
+func githubFeedTypeToWebhook(feedType string) string {
+	base := strings.TrimSuffix(feedType, "Event")
+	var b strings.Builder
+	for i, r := range base {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NormalizeGitHubFeedEvent turns an Events API envelope into the event a webhook
+// delivery would have produced: the nested payload becomes the body, with the
+// envelope's repo and actor added as repository and sender. Raw holds that
+// webhook-shaped body, so stored feed events look like webhook events.
+func NormalizeGitHubFeedEvent(evt GitHubUserEvent) (NormalizedEvent, error) {
+	var envelope struct {
+		Type    string         `json:"type"`
+		Payload map[string]any `json:"payload"`
+	}
+	if len(evt.PayloadJSON) > 0 {
+		if err := json.Unmarshal(evt.PayloadJSON, &envelope); err != nil {
+			return NormalizedEvent{}, fmt.Errorf("decode github feed event %s: %w", evt.DeliveryID, err)
+		}
+	}
+	feedType := strings.TrimSpace(evt.EventType)
+	if feedType == "" {
+		feedType = strings.TrimSpace(envelope.Type)
+	}
+
+	payload := make(map[string]any, len(envelope.Payload)+2)
+	for k, v := range envelope.Payload {
+		payload[k] = v
+	}
+	if _, ok := payload["repository"].(map[string]any); !ok && evt.RepositoryFullName != "" && evt.RepositoryFullName != "unknown" {
+		repo := map[string]any{"full_name": evt.RepositoryFullName}
+		if _, name, found := strings.Cut(evt.RepositoryFullName, "/"); found {
+			repo["name"] = name
+		}
+		payload["repository"] = repo
+	}
+	if _, ok := payload["sender"].(map[string]any); !ok && evt.SenderLogin != "" && evt.SenderLogin != "unknown" {
+		payload["sender"] = map[string]any{"login": evt.SenderLogin}
+	}
+
+	normalized := NormalizePayload(store.EventSourceGitHub, GitHubFeedEventType(feedType), payload)
+	// Push, watch and similar events have no action in their webhook payload; keep
+	// the one derived from the feed so they stay filterable.
+	if normalized.Action == "" && evt.Action != "unknown" {
+		normalized.Action = evt.Action
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return NormalizedEvent{}, fmt.Errorf("encode github feed event %s: %w", evt.DeliveryID, err)
+	}
+	normalized.Raw = raw
+	return normalized, nil
+}
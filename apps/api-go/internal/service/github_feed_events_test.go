@@ -0,0 +1,75 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGitHubFeedEventType(t *testing.T) {
+	cases := map[string]string{
+		"IssuesEvent":                   "issues",
+		"IssueCommentEvent":             "issue_comment",
+		"PullRequestReviewCommentEvent": "pull_request_review_comment",
+		"PushEvent":                     "push",
+		"MergeQueueEntryEvent":          "merge_queue_entry",
+	}
+	for in, want := range cases {
+		if got := GitHubFeedEventType(in); got != want {
+			t.Fatalf("GitHubFeedEventType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeGitHubFeedEvent_IssueCommentMatchesWebhookShape(t *testing.T) {
+	evts, err := decodeGitHubEvents([]byte(`[{
+		"id": "501",
+		"type": "IssueCommentEvent",
+		"actor": {"login": "alice"},
+		"repo": {"name": "owner/repo"},
+		"payload": {
+			"action": "created",
+			"issue": {"number": 7, "title": "Crash", "user": {"login": "bob"}, "labels": [{"name": "bug"}], "pull_request": {}},
+			"comment": {"body": "please fix"}
+		}
+	}]`))
+	if err != nil || len(evts) != 1 {
+		t.Fatalf("decode: %v", err)
+	}
+
+	got, err := NormalizeGitHubFeedEvent(evts[0])
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got.EventType != "issue_comment" || got.Action != "created" || got.Repository != "owner/repo" || got.Sender != "alice" {
+		t.Fatalf("unexpected envelope fields: %+v", got)
+	}
+	if got.Number != 7 || got.TargetKind != TargetKindPullRequest || got.Body != "please fix" || got.Author != "bob" || len(got.Labels) != 1 {
+		t.Fatalf("unexpected target fields: %+v", got)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(got.Raw, &raw); err != nil {
+		t.Fatalf("raw is not JSON: %v", err)
+	}
+	if _, ok := raw["payload"]; ok {
+		t.Fatalf("raw must be the webhook body, not the envelope: %s", got.Raw)
+	}
+	again := NormalizePayload("github", got.EventType, raw)
+	if again.Repository != got.Repository || again.Number != got.Number || again.Sender != got.Sender {
+		t.Fatalf("raw does not re-normalise to the same event: %+v", again)
+	}
+}
+
+func TestNormalizeGitHubFeedEvent_KeepsFeedActionWithoutPayloadAction(t *testing.T) {
+	evts, err := decodeGitHubEvents([]byte(`[{"id":"9","type":"PushEvent","actor":{"login":"alice"},"repo":{"name":"owner/repo"},"payload":{"ref":"refs/heads/main"}}]`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got, err := NormalizeGitHubFeedEvent(evts[0])
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got.EventType != "push" || got.Action != "pushed" {
+		t.Fatalf("unexpected push event: %+v", got)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Hook scopes understood by the hook deliveries API.
+const (
+	GitHubHookScopeRepo = "repo"
+	GitHubHookScopeOrg  = "org"
+	GitHubHookScopeApp  = "app"
+)
+
+// GitHubHookRef identifies a webhook whose deliveries GitHub records. Target is
+// the repository full name for repo hooks, the org login for org hooks and empty
+// for the GitHub App's own hook.
+type GitHubHookRef struct {
+	Scope  string
+	Target string
+	HookID int64
+}
+
+func (r GitHubHookRef) path() (string, error) {
+	target := strings.Trim(strings.TrimSpace(r.Target), "/")
+	switch r.Scope {
+	case GitHubHookScopeRepo:
+		if strings.Count(target, "/") != 1 || r.HookID <= 0 {
+			return "", fmt.Errorf("repo hook needs owner/repo and a hook id")
+		}
+		return fmt.Sprintf("/repos/%s/hooks/%d", target, r.HookID), nil
+	case GitHubHookScopeOrg:
+		if target == "" || strings.Contains(target, "/") || r.HookID <= 0 {
+			return "", fmt.Errorf("org hook needs an org login and a hook id")
+		}
+		return fmt.Sprintf("/orgs/%s/hooks/%d", url.PathEscape(target), r.HookID), nil
+	case GitHubHookScopeApp:
+		return "/app/hook", nil
+	}
+	return "", fmt.Errorf("unsupported hook scope %q", r.Scope)
+}
+
+// ValidateGitHubHookRef reports whether ref can address the deliveries API.
+func ValidateGitHubHookRef(ref GitHubHookRef) error {
+	_, err := ref.path()
+	return err
+}
+
+// GitHubHookDelivery is one entry of a hook's delivery log. GUID is the
+// X-GitHub-Delivery header of the original request; redeliveries share it.
+type GitHubHookDelivery struct {
+	ID             int64     `json:"id"`
+	GUID           string    `json:"guid"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+	Redelivery     bool      `json:"redelivery"`
+	StatusCode     int       `json:"status_code"`
+	Event          string    `json:"event"`
+	Action         string    `json:"action"`
+	InstallationID int64     `json:"installation_id"`
+}
+
+// GitHubHookDeliveryPayload is the recorded request of a delivery.
+type GitHubHookDeliveryPayload struct {
+	GUID    string
+	Event   string
+	Payload json.RawMessage
+}
+
+// ListHookDeliveries returns one page of deliveries, newest first, and the URL
+// of the next page ("" on the last page). Pass an empty next for the first page.
+func (e *GitHubActionExecutor) ListHookDeliveries(ctx context.Context, hook GitHubHookRef, next string) ([]GitHubHookDelivery, string, error) {
+	endpoint := next
+	if endpoint == "" {
+		path, err := hook.path()
+		if err != nil {
+			return nil, "", err
+		}
+		endpoint = e.baseURL() + path + "/deliveries?per_page=100"
+	}
+	body, headers, err := e.hookRequest(ctx, hook, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	var items []GitHubHookDelivery
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, "", fmt.Errorf("decode hook deliveries: %w", err)
+	}
+	return items, ParseNextLink(headers.Get("Link")), nil
+}
+
+func (e *GitHubActionExecutor) GetHookDelivery(ctx context.Context, hook GitHubHookRef, deliveryID int64) (GitHubHookDeliveryPayload, error) {
+	path, err := hook.path()
+	if err != nil {
+		return GitHubHookDeliveryPayload{}, err
+	}
+	body, _, err := e.hookRequest(ctx, hook, http.MethodGet, fmt.Sprintf("%s%s/deliveries/%d", e.baseURL(), path, deliveryID))
+	if err != nil {
+		return GitHubHookDeliveryPayload{}, err
+	}
+	var detail struct {
+		GUID    string `json:"guid"`
+		Event   string `json:"event"`
+		Request struct {
+			Payload json.RawMessage `json:"payload"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return GitHubHookDeliveryPayload{}, fmt.Errorf("decode hook delivery: %w", err)
+	}
+	if len(detail.Request.Payload) == 0 || string(detail.Request.Payload) == "null" {
+		return GitHubHookDeliveryPayload{}, fmt.Errorf("hook delivery %d has no recorded payload", deliveryID)
+	}
+	return GitHubHookDeliveryPayload{GUID: detail.GUID, Event: detail.Event, Payload: detail.Request.Payload}, nil
+}
+
+// RedeliverHookDelivery asks GitHub to send the delivery again; it arrives at the
+// webhook endpoint with its original GUID.
+func (e *GitHubActionExecutor) RedeliverHookDelivery(ctx context.Context, hook GitHubHookRef, deliveryID int64) error {
+	path, err := hook.path()
+	if err != nil {
+		return err
+	}
+	_, _, err = e.hookRequest(ctx, hook, http.MethodPost, fmt.Sprintf("%s%s/deliveries/%d/attempts", e.baseURL(), path, deliveryID))
+	return err
+}
+
+// hookRequest authenticates app hook calls with the app JWT and everything else
+// like other API calls for the tenant in ctx.
+func (e *GitHubActionExecutor) hookRequest(ctx context.Context, hook GitHubHookRef, method string, endpoint string) ([]byte, http.Header, error) {
+	if hook.Scope != GitHubHookScopeApp {
+		token, key, err := e.credentials(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return e.send(ctx, method, endpoint, nil, token, key, "")
+	}
+	if e.AppTokens == nil {
+		return nil, nil, fmt.Errorf("github app is not configured")
+	}
+	jwt, err := e.AppTokens.AppJWT()
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.send(ctx, method, endpoint, nil, jwt, "app", "")
+}
+
+// ParseNextLink returns the rel="next" URL of an RFC 8288 Link header.
+func ParseNextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+			}
+		}
+	}
+	return ""
+}
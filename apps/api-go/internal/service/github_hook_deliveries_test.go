@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseNextLink(t *testing.T) {
+	header := `<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?cursor=abc>; rel="next"`
+	if got := ParseNextLink(header); got != "https://api.github.com/x?cursor=abc" {
+		t.Fatalf("unexpected next link %q", got)
+	}
+	if got := ParseNextLink(`<https://api.github.com/x?page=1>; rel="prev"`); got != "" {
+		t.Fatalf("expected no next link, got %q", got)
+	}
+}
+
+func TestGitHubExecutor_HookDeliveries(t *testing.T) {
+	var srvURL string
+	exec := newTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/hooks/7/deliveries" && r.URL.Query().Get("cursor") == "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/hooks/7/deliveries?cursor=n2>; rel="next"`, srvURL))
+			_, _ = w.Write([]byte(`[{"id":12,"guid":"g-12","event":"issues","redelivery":false}]`))
+		case r.URL.Path == "/repos/owner/repo/hooks/7/deliveries":
+			_, _ = w.Write([]byte(`[{"id":11,"guid":"g-11","event":"push"}]`))
+		case r.URL.Path == "/repos/owner/repo/hooks/7/deliveries/12" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":12,"guid":"g-12","event":"issues","request":{"headers":{},"payload":{"action":"opened"}}}`))
+		case r.URL.Path == "/repos/owner/repo/hooks/7/deliveries/12/attempts" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srvURL = exec.BaseURL
+	hook := GitHubHookRef{Scope: GitHubHookScopeRepo, Target: "owner/repo", HookID: 7}
+
+	items, next, err := exec.ListHookDeliveries(context.Background(), hook, "")
+	if err != nil || len(items) != 1 || items[0].GUID != "g-12" || next == "" {
+		t.Fatalf("unexpected first page: %+v next=%q err=%v", items, next, err)
+	}
+	items, next, err = exec.ListHookDeliveries(context.Background(), hook, next)
+	if err != nil || len(items) != 1 || items[0].ID != 11 || next != "" {
+		t.Fatalf("unexpected last page: %+v next=%q err=%v", items, next, err)
+	}
+
+	delivery, err := exec.GetHookDelivery(context.Background(), hook, 12)
+	if err != nil || delivery.Event != "issues" || string(delivery.Payload) != `{"action":"opened"}` {
+		t.Fatalf("unexpected delivery: %+v err=%v", delivery, err)
+	}
+	if err := exec.RedeliverHookDelivery(context.Background(), hook, 12); err != nil {
+		t.Fatalf("redeliver failed: %v", err)
+	}
+}
+
+func TestValidateGitHubHookRef(t *testing.T) {
+	cases := []struct {
+		ref GitHubHookRef
+		ok  bool
+	}{
+		{GitHubHookRef{Scope: GitHubHookScopeRepo, Target: "owner/repo", HookID: 1}, true},
+		{GitHubHookRef{Scope: GitHubHookScopeRepo, Target: "owner", HookID: 1}, false},
+		{GitHubHookRef{Scope: GitHubHookScopeOrg, Target: "acme", HookID: 1}, true},
+		{GitHubHookRef{Scope: GitHubHookScopeOrg, Target: "acme"}, false},
+		{GitHubHookRef{Scope: GitHubHookScopeApp}, true},
+		{GitHubHookRef{Scope: "user", Target: "x", HookID: 1}, false},
+	}
+	for _, tc := range cases {
+		if err := ValidateGitHubHookRef(tc.ref); (err == nil) != tc.ok {
+			t.Fatalf("ValidateGitHubHookRef(%+v) = %v, want ok=%t", tc.ref, err, tc.ok)
+		}
+	}
+}
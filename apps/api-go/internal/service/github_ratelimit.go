@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGitHubWriteInterval     = 250 * time.Millisecond
+	defaultSecondaryRateLimitDelay = time.Minute
+	// githubThrottleIdleTTL is how long an unused throttle is kept once its
+	// pause and rate limit windows have passed.
+	githubThrottleIdleTTL = 10 * time.Minute
+)
+
+type GitHubRateLimit struct {
+	Resource   string    `json:"resource"`
+	Limit      int       `json:"limit"`
+	Remaining  int       `json:"remaining"`
+	Used       int       `json:"used"`
+	Reset      time.Time `json:"reset"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// GitHubAPIError is returned for any non-2xx GitHub response. Its message keeps the
+// "github api status: <code>" prefix that callers match on.
+type GitHubAPIError struct {
+	StatusCode int
+	Message    string
+	RateLimit  *GitHubRateLimit
+	RetryAfter time.Duration
+}
+
+func (e *GitHubAPIError) Error() string {
+	if strings.TrimSpace(e.Message) == "" {
+		return fmt.Sprintf("github api status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("github api status: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsRateLimited covers both the primary limit (remaining=0) and secondary limits,
+// which GitHub reports as 403 or 429 with Retry-After or a "rate limit" message.
+func (e *GitHubAPIError) IsRateLimited() bool {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if e.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if e.RetryAfter > 0 {
+		return true
+	}
+	if e.RateLimit != nil && e.RateLimit.Limit > 0 && e.RateLimit.Remaining == 0 {
+		return true
+	}
+	return strings.Contains(strings.ToLower(e.Message), "rate limit")
+}
+
+// GitHubRetryDelay returns how long to wait before retrying err, and false if err
+// is not a rate-limit error.
+func GitHubRetryDelay(err error, now time.Time) (time.Duration, bool) {
+	var apiErr *GitHubAPIError
+	if !errors.As(err, &apiErr) || !apiErr.IsRateLimited() {
+		return 0, false
+	}
+	if apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	if apiErr.RateLimit != nil && apiErr.RateLimit.Remaining == 0 && apiErr.RateLimit.Reset.After(now) {
+		return apiErr.RateLimit.Reset.Sub(now), true
+	}
+	return defaultSecondaryRateLimitDelay, true
+}
+
+func parseGitHubRateLimit(h http.Header, now time.Time) *GitHubRateLimit {
+	limitRaw := strings.TrimSpace(h.Get("X-RateLimit-Limit"))
+	remainingRaw := strings.TrimSpace(h.Get("X-RateLimit-Remaining"))
+	if limitRaw == "" || remainingRaw == "" {
+		return nil
+	}
+	limit, err := strconv.Atoi(limitRaw)
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.Atoi(remainingRaw)
+	if err != nil {
+		return nil
+	}
+	info := &GitHubRateLimit{
+		Resource:   strings.TrimSpace(h.Get("X-RateLimit-Resource")),
+		Limit:      limit,
+		Remaining:  remaining,
+		ObservedAt: now.UTC(),
+	}
+	if info.Resource == "" {
+		info.Resource = "core"
+	}
+	if used, err := strconv.Atoi(strings.TrimSpace(h.Get("X-RateLimit-Used"))); err == nil {
+		info.Used = used
+	}
+	if reset, err := strconv.ParseInt(strings.TrimSpace(h.Get("X-RateLimit-Reset")), 10, 64); err == nil {
+		info.Reset = time.Unix(reset, 0).UTC()
+	}
+	return info
+}
+
+func parseRetryAfter(raw string, now time.Time) time.Duration {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(raw); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(raw); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// githubThrottle smooths outgoing requests for one token: writes are spaced by a
+// minimum interval and every request waits while the token is known to be limited.
+type githubThrottle struct {
+	mu          sync.Mutex
+	nextWriteAt time.Time
+	pausedUntil time.Time
+	lastUsedAt  time.Time
+	limits      map[string]GitHubRateLimit
+}
+
+// githubThrottles is keyed by installation or token hash, never by the raw token,
+// and swept of idle entries at most once per githubThrottleIdleTTL.
+var githubThrottles = struct {
+	mu      sync.Mutex
+	items   map[string]*githubThrottle
+	sweptAt time.Time
+}{items: map[string]*githubThrottle{}}
+
+// tokenThrottleKey identifies a personal or tenant token without keeping it.
+func tokenThrottleKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:])
+}
+
+func throttleForToken(key string) *githubThrottle {
+	now := time.Now()
+	githubThrottles.mu.Lock()
+	defer githubThrottles.mu.Unlock()
+	if now.Sub(githubThrottles.sweptAt) >= githubThrottleIdleTTL {
+		for k, t := range githubThrottles.items {
+			if t.idle(now) {
+				delete(githubThrottles.items, k)
+			}
+		}
+		githubThrottles.sweptAt = now
+	}
+	t, ok := githubThrottles.items[key]
+	if !ok {
+		t = &githubThrottle{limits: map[string]GitHubRateLimit{}}
+		githubThrottles.items[key] = t
+	}
+	t.mu.Lock()
+	t.lastUsedAt = now
+	t.mu.Unlock()
+	return t
+}
+
+// idle reports whether t has not been used for githubThrottleIdleTTL and holds
+// no pause or rate limit window that is still running.
+func (t *githubThrottle) idle(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.Sub(t.lastUsedAt) < githubThrottleIdleTTL || t.pausedUntil.After(now) || t.nextWriteAt.After(now) {
+		return false
+	}
+	for _, limit := range t.limits {
+		if limit.Reset.After(now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *githubThrottle) wait(ctx context.Context, write bool, writeInterval time.Duration) error {
+	now := time.Now()
+	t.mu.Lock()
+	readyAt := now
+	if t.pausedUntil.After(readyAt) {
+		readyAt = t.pausedUntil
+	}
+	if write && t.nextWriteAt.After(readyAt) {
+		readyAt = t.nextWriteAt
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(readyAt) {
+		t.mu.Unlock()
+		return fmt.Errorf("github api throttled for %s", readyAt.Sub(now).Round(time.Second))
+	}
+	if write {
+		t.nextWriteAt = readyAt.Add(writeInterval)
+	}
+	t.mu.Unlock()
+
+	delay := readyAt.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *githubThrottle) observe(info *GitHubRateLimit, retryAfter time.Duration, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if info != nil {
+		t.limits[info.Resource] = *info
+		// Only the core limit gates every request; an exhausted search limit must not pause label/comment writes.
+		if info.Resource == "core" && info.Remaining == 0 && info.Reset.After(t.pausedUntil) {
+			t.pausedUntil = info.Reset
+		}
+	}
+	if retryAfter > 0 && now.Add(retryAfter).After(t.pausedUntil) {
+		t.pausedUntil = now.Add(retryAfter)
+	}
+}
+
+func (t *githubThrottle) snapshot() (map[string]GitHubRateLimit, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]GitHubRateLimit, len(t.limits))
+	for k, v := range t.limits {
+		out[k] = v
+	}
+	return out, t.pausedUntil
+}
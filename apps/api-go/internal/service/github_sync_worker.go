@@ -2,33 +2,19 @@ package service
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 )
 
-func StartGitHubEventsSyncWorker(ctx context.Context, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
-	if interval <= 0 || runOnce == nil {
-		return
-	}
+// GitHubEventsSyncWorkerName is the supervisor name of the sync scheduler.
+const GitHubEventsSyncWorkerName = "github events sync"
 
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				runCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				saved, total, err := runOnce(runCtx)
-				cancel()
-				if err != nil {
-					log.Printf("github events sync failed: %v", err)
-					continue
-				}
-				log.Printf("github events sync done: saved=%d total=%d", saved, total)
-			}
+// StartGitHubEventsSyncWorker checks for due tenant sync schedules every interval;
+// timeout bounds one whole check, including every sync it runs.
+func StartGitHubEventsSyncWorker(sup *Supervisor, interval time.Duration, timeout time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, GitHubEventsSyncWorkerName, interval, timeout, runOnce, func(ctx context.Context, ran int, due int) {
+		if due > 0 {
+			slog.InfoContext(ctx, "github events sync done", "ran", ran, "due", due)
 		}
-	}()
+	})
 }
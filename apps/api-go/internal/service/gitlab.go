@@ -0,0 +1,189 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// GitLabEvent is a GitLab webhook mapped onto GitHub webhook names and payload
+// shapes, so rules, alerts and retries treat it like a GitHub delivery.
+type GitLabEvent struct {
+	EventType string
+	Action    string
+	Payload   map[string]any
+}
+
+func GitLabProvider(executor ActionExecutor) Provider {
+	return Provider{Name: store.EventSourceGitLab, Parse: ParseGitLabEvent, Executor: executor}
+}
+
+// ParseGitLabEvent normalises a delivery by its X-Gitlab-Event header. The
+// reshaped payload, not the original body, becomes Raw.
+func ParseGitLabEvent(hook string, body []byte) (NormalizedEvent, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return NormalizedEvent{}, fmt.Errorf("invalid JSON payload")
+	}
+	event, err := NormalizeGitLabEvent(hook, raw)
+	if err != nil {
+		return NormalizedEvent{}, err
+	}
+	encoded, err := json.Marshal(event.Payload)
+	if err != nil {
+		return NormalizedEvent{}, fmt.Errorf("failed to encode event: %v", err)
+	}
+	evt := NormalizePayload(store.EventSourceGitLab, event.EventType, event.Payload)
+	evt.Raw = encoded
+	return evt, nil
+}
+
+var gitLabActions = map[string]string{
+	"open":   "opened",
+	"close":  "closed",
+	"reopen": "reopened",
+	"update": "edited",
+	"merge":  "closed",
+}
+
+// NormalizeGitLabEvent converts Issue Hook, Merge Request Hook and Note Hook
+// payloads. Other hooks keep their object_kind as event type. The original
+// payload is kept under the "gitlab" key.
+func NormalizeGitLabEvent(hook string, raw map[string]any) (GitLabEvent, error) {
+	if raw == nil {
+		return GitLabEvent{}, fmt.Errorf("empty gitlab payload")
+	}
+	kind, _ := raw["object_kind"].(string)
+	if kind == "" {
+		kind = gitLabKindFromHook(hook)
+	}
+	if kind == "" {
+		return GitLabEvent{}, fmt.Errorf("unsupported gitlab event %q", hook)
+	}
+
+	attrs, _ := raw["object_attributes"].(map[string]any)
+	payload := map[string]any{
+		"repository": map[string]any{"full_name": gitLabProjectPath(raw)},
+		"sender":     map[string]any{"login": gitLabUsername(raw["user"])},
+		"gitlab":     raw,
+	}
+
+	switch kind {
+	case "issue":
+		action := gitLabAction(attrs)
+		payload["action"] = action
+		payload["issue"] = gitLabItem(attrs, raw)
+		return GitLabEvent{EventType: "issues", Action: action, Payload: payload}, nil
+	case "merge_request":
+		action := gitLabAction(attrs)
+		item := gitLabItem(attrs, raw)
+		item["merged"] = stringField(attrs, "action") == "merge" || stringField(attrs, "state") == "merged"
+		payload["action"] = action
+		payload["pull_request"] = item
+		return GitLabEvent{EventType: "pull_request", Action: action, Payload: payload}, nil
+	case "note":
+		payload["action"] = "created"
+		payload["comment"] = map[string]any{
+			"body":     stringField(attrs, "note"),
+			"html_url": stringField(attrs, "url"),
+			"user":     map[string]any{"login": gitLabUsername(raw["user"])},
+		}
+		if mr, ok := raw["merge_request"].(map[string]any); ok {
+			item := gitLabItem(mr, nil)
+			item["pull_request"] = map[string]any{}
+			payload["issue"] = item
+		} else if issue, ok := raw["issue"].(map[string]any); ok {
+			payload["issue"] = gitLabItem(issue, nil)
+		}
+		return GitLabEvent{EventType: "issue_comment", Action: "created", Payload: payload}, nil
+	default:
+		action := stringField(attrs, "action")
+		payload["action"] = action
+		return GitLabEvent{EventType: kind, Action: action, Payload: payload}, nil
+	}
+}
+
+func gitLabKindFromHook(hook string) string {
+	switch strings.TrimPrefix(strings.TrimSpace(hook), "Confidential ") {
+	case "Issue Hook":
+		return "issue"
+	case "Merge Request Hook":
+		return "merge_request"
+	case "Note Hook":
+		return "note"
+	}
+	return ""
+}
+
+func gitLabAction(attrs map[string]any) string {
+	action := stringField(attrs, "action")
+	if mapped, ok := gitLabActions[action]; ok {
+		return mapped
+	}
+	return action
+}
+
+// gitLabItem maps issue or merge request attributes; GitLab's project-scoped iid
+// becomes the number because that is what its API addresses.
+func gitLabItem(attrs map[string]any, raw map[string]any) map[string]any {
+	labels := []any{}
+	source := attrs["labels"]
+	if raw != nil {
+		if top, ok := raw["labels"]; ok {
+			source = top
+		}
+	}
+	if list, ok := source.([]any); ok {
+		for _, l := range list {
+			if m, ok := l.(map[string]any); ok {
+				if title := stringField(m, "title"); title != "" {
+					labels = append(labels, map[string]any{"name": title})
+				}
+			}
+		}
+	}
+	number, _ := attrs["iid"].(float64)
+	author := ""
+	if raw != nil {
+		author = gitLabUsername(raw["user"])
+	}
+	return map[string]any{
+		"number":   number,
+		"title":    stringField(attrs, "title"),
+		"body":     stringField(attrs, "description"),
+		"state":    stringField(attrs, "state"),
+		"html_url": stringField(attrs, "url"),
+		"labels":   labels,
+		"user":     map[string]any{"login": author},
+	}
+}
+
+func gitLabProjectPath(raw map[string]any) string {
+	if project, ok := raw["project"].(map[string]any); ok {
+		if path := stringField(project, "path_with_namespace"); path != "" {
+			return path
+		}
+	}
+	return "unknown"
+}
+
+func gitLabUsername(v any) string {
+	user, ok := v.(map[string]any)
+	if !ok {
+		return "unknown"
+	}
+	if name := stringField(user, "username"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+func stringField(m map[string]any, key string) string {
+	if m == nil {
+		return ""
+	}
+	v, _ := m[key].(string)
+	return strings.TrimSpace(v)
+}
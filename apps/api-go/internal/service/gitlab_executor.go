@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLabActionExecutor applies label and comment actions through the GitLab REST
+// API. Merge requests are addressed when the context carries TargetKindPullRequest.
+type GitLabActionExecutor struct {
+	Token      string
+	HTTPClient *http.Client
+	BaseURL    string
+}
+
+func NewGitLabActionExecutor(baseURL string, token string) *GitLabActionExecutor {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	return &GitLabActionExecutor{
+		Token:      strings.TrimSpace(token),
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		BaseURL:    baseURL,
+	}
+}
+
+func (e *GitLabActionExecutor) AddLabel(ctx context.Context, projectPath string, number int, label string) error {
+	if strings.TrimSpace(label) == "" {
+		return fmt.Errorf("empty label")
+	}
+	endpoint, err := e.itemURL(ctx, projectPath, number, "")
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]any{"add_labels": label})
+	return e.do(ctx, http.MethodPut, endpoint, body)
+}
+
+func (e *GitLabActionExecutor) AddComment(ctx context.Context, projectPath string, number int, comment string) error {
+	if strings.TrimSpace(comment) == "" {
+		return fmt.Errorf("empty comment")
+	}
+	endpoint, err := e.itemURL(ctx, projectPath, number, "/notes")
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]any{"body": comment})
+	return e.do(ctx, http.MethodPost, endpoint, body)
+}
+
+func (e *GitLabActionExecutor) itemURL(ctx context.Context, projectPath string, number int, suffix string) (string, error) {
+	if strings.TrimSpace(projectPath) == "" || projectPath == "unknown" {
+		return "", fmt.Errorf("invalid repository full name")
+	}
+	if number <= 0 {
+		return "", fmt.Errorf("invalid issue/merge_request number")
+	}
+	collection := "issues"
+	if TargetKindFromContext(ctx) == TargetKindPullRequest {
+		collection = "merge_requests"
+	}
+	return fmt.Sprintf("%s/api/v4/projects/%s/%s/%d%s", strings.TrimRight(e.BaseURL, "/"), url.PathEscape(projectPath), collection, number, suffix), nil
+}
+
+func (e *GitLabActionExecutor) do(ctx context.Context, method string, endpoint string, body []byte) error {
+	if e.Token == "" {
+		return fmt.Errorf("gitlab token is not configured")
+	}
+	client := e.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", e.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request gitlab api: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var errBody struct {
+		Message any `json:"message"`
+	}
+	if json.Unmarshal(respBody, &errBody) == nil && errBody.Message != nil {
+		return fmt.Errorf("gitlab api status: %d: %v", resp.StatusCode, errBody.Message)
+	}
+	return fmt.Errorf("gitlab api status: %d", resp.StatusCode)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+func decodeGitLabPayload(t *testing.T, raw string) map[string]any {
+	t.Helper()
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	return payload
+}
+
+func TestNormalizeGitLabEvent(t *testing.T) {
+	issue := decodeGitLabPayload(t, `{
+		"object_kind":"issue",
+		"user":{"username":"alice"},
+		"project":{"path_with_namespace":"group/app"},
+		"object_attributes":{"iid":7,"title":"Urgent crash","description":"help wanted","action":"open","state":"opened"},
+		"labels":[{"title":"bug"}]
+	}`)
+	evt, err := NormalizeGitLabEvent("Issue Hook", issue)
+	if err != nil {
+		t.Fatalf("normalize issue: %v", err)
+	}
+	if evt.EventType != "issues" || evt.Action != "opened" {
+		t.Fatalf("unexpected issue event %s/%s", evt.EventType, evt.Action)
+	}
+	item := evt.Payload["issue"].(map[string]any)
+	if item["number"].(float64) != 7 || item["title"] != "Urgent crash" || item["body"] != "help wanted" {
+		t.Fatalf("unexpected issue item %+v", item)
+	}
+	if got := NormalizePayload(store.EventSourceGitLab, evt.EventType, evt.Payload).Text(); !strings.Contains(got, "Urgent crash") {
+		t.Fatalf("rule engine text not extracted: %q", got)
+	}
+	if repo := evt.Payload["repository"].(map[string]any)["full_name"]; repo != "group/app" {
+		t.Fatalf("unexpected repository %v", repo)
+	}
+
+	mr := decodeGitLabPayload(t, `{
+		"object_kind":"merge_request",
+		"user":{"username":"bob"},
+		"project":{"path_with_namespace":"group/app"},
+		"object_attributes":{"iid":3,"title":"Fix","description":"","action":"merge","state":"merged"}
+	}`)
+	evt, err = NormalizeGitLabEvent("Merge Request Hook", mr)
+	if err != nil {
+		t.Fatalf("normalize merge request: %v", err)
+	}
+	pr := evt.Payload["pull_request"].(map[string]any)
+	if evt.EventType != "pull_request" || evt.Action != "closed" || pr["merged"] != true || pr["number"].(float64) != 3 {
+		t.Fatalf("unexpected merge request event %s/%s %+v", evt.EventType, evt.Action, pr)
+	}
+
+	note := decodeGitLabPayload(t, `{
+		"object_kind":"note",
+		"user":{"username":"carol"},
+		"project":{"path_with_namespace":"group/app"},
+		"object_attributes":{"note":"duplicate of #1","noteable_type":"MergeRequest"},
+		"merge_request":{"iid":3,"title":"Fix"}
+	}`)
+	evt, err = NormalizeGitLabEvent("Note Hook", note)
+	if err != nil {
+		t.Fatalf("normalize note: %v", err)
+	}
+	commented := evt.Payload["issue"].(map[string]any)
+	if evt.EventType != "issue_comment" || commented["number"].(float64) != 3 || commented["pull_request"] == nil {
+		t.Fatalf("unexpected note event %s %+v", evt.EventType, commented)
+	}
+
+	if _, err := NormalizeGitLabEvent("Unknown Hook", map[string]any{}); err == nil {
+		t.Fatalf("expected unsupported event error")
+	}
+}
+
+func TestGitLabExecutor_LabelsIssuesAndCommentsOnMergeRequests(t *testing.T) {
+	type call struct{ method, path, token, body string }
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, call{r.Method, r.URL.EscapedPath(), r.Header.Get("PRIVATE-TOKEN"), string(body)})
+		if strings.Contains(r.URL.Path, "/99") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	exec := NewGitLabActionExecutor(srv.URL, "glpat-test")
+	exec.HTTPClient = srv.Client()
+
+	if err := exec.AddLabel(context.Background(), "group/app", 7, "needs-triage"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	if err := exec.AddComment(WithTargetKind(context.Background(), TargetKindPullRequest), "group/app", 3, "thanks"); err != nil {
+		t.Fatalf("add comment: %v", err)
+	}
+	err := exec.AddLabel(context.Background(), "group/app", 99, "x")
+	if err == nil || !IsPermanentActionError(err) {
+		t.Fatalf("expected permanent error for missing issue, got %v", err)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(calls))
+	}
+	if calls[0].method != http.MethodPut || calls[0].path != "/api/v4/projects/group%2Fapp/issues/7" || !strings.Contains(calls[0].body, `"add_labels":"needs-triage"`) {
+		t.Fatalf("unexpected label call %+v", calls[0])
+	}
+	if calls[1].method != http.MethodPost || calls[1].path != "/api/v4/projects/group%2Fapp/merge_requests/3/notes" {
+		t.Fatalf("unexpected comment call %+v", calls[1])
+	}
+	if calls[0].token != "glpat-test" {
+		t.Fatalf("expected PRIVATE-TOKEN header, got %q", calls[0].token)
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func StartHookRecoveryWorker(sup *Supervisor, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, "hook delivery recovery", interval, 5*time.Minute, runOnce, func(ctx context.Context, recovered int, hooks int) {
+		if recovered > 0 {
+			slog.InfoContext(ctx, "hook delivery recovery done", "recovered", recovered, "hooks", hooks)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func StartMetricsRollupWorker(sup *Supervisor, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, "metrics rollup compaction", interval, 4*time.Minute, runOnce, func(ctx context.Context, written int, _ int) {
+		if written > 0 {
+			slog.DebugContext(ctx, "metrics rollups compacted", "minutes", written)
+		}
+	})
+}
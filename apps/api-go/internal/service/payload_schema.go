@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+type payloadField struct {
+	path     string
+	kind     string
+	required bool
+}
+
+// commonPayloadFields are checked for every event: only their types, since not
+// every event carries them.
+var commonPayloadFields = []payloadField{
+	{path: "action", kind: "string"},
+	{path: "repository", kind: "object"},
+	{path: "repository.full_name", kind: "string"},
+	{path: "sender", kind: "object"},
+	{path: "sender.login", kind: "string"},
+	{path: "installation", kind: "object"},
+	{path: "installation.id", kind: "number"},
+}
+
+// payloadSchemas are the minimal per-event shapes ingestion relies on. Payloads
+// are GitHub-shaped, so provider parsers must produce them as well.
+var payloadSchemas = map[string][]payloadField{
+	"issues": {
+		{path: "action", kind: "string", required: true},
+		{path: "issue", kind: "object"},
+		{path: "issue.number", kind: "number"},
+		{path: "issue.title", kind: "string"},
+		{path: "issue.body", kind: "string"},
+		{path: "issue.labels", kind: "array"},
+	},
+	"pull_request": {
+		{path: "action", kind: "string", required: true},
+		{path: "pull_request", kind: "object"},
+		{path: "pull_request.number", kind: "number"},
+		{path: "pull_request.title", kind: "string"},
+		{path: "pull_request.body", kind: "string"},
+		{path: "pull_request.labels", kind: "array"},
+	},
+	"issue_comment": {
+		{path: "action", kind: "string", required: true},
+		{path: "issue", kind: "object"},
+		{path: "issue.number", kind: "number"},
+		{path: "comment", kind: "object"},
+		{path: "comment.body", kind: "string"},
+	},
+	"installation": {
+		{path: "action", kind: "string", required: true},
+		{path: "installation", kind: "object", required: true},
+		{path: "repositories", kind: "array"},
+	},
+	"installation_repositories": {
+		{path: "action", kind: "string", required: true},
+		{path: "installation", kind: "object", required: true},
+		{path: "repositories_added", kind: "array"},
+		{path: "repositories_removed", kind: "array"},
+	},
+	"repository": {
+		{path: "action", kind: "string", required: true},
+		{path: "repository", kind: "object", required: true},
+		{path: "repository.id", kind: "number", required: true},
+		{path: "repository.full_name", kind: "string", required: true},
+		{path: "changes", kind: "object"},
+	},
+	"ping": {
+		{path: "hook_id", kind: "number"},
+		{path: "zen", kind: "string"},
+	},
+}
+
+// ValidatePayload checks a normalised payload against the minimal schema for its
+// event type. JSON null counts as absent. Unknown event types only get the common
+// type checks.
+func ValidatePayload(eventType string, payload map[string]any) error {
+	if payload == nil {
+		return fmt.Errorf("payload must be a JSON object")
+	}
+	fields := append(append([]payloadField{}, commonPayloadFields...), payloadSchemas[eventType]...)
+	problems := []string{}
+	seen := map[string]bool{}
+	for _, field := range fields {
+		value, parentPresent := lookupPayloadPath(payload, field.path)
+		if value == nil {
+			if field.required && parentPresent && !seen[field.path] {
+				problems = append(problems, field.path+" is required")
+				seen[field.path] = true
+			}
+			continue
+		}
+		if !payloadKindMatches(value, field.kind) && !seen[field.path] {
+			problems = append(problems, fmt.Sprintf("%s must be %s", field.path, articleFor(field.kind)))
+			seen[field.path] = true
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s payload: %s", eventType, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// lookupPayloadPath walks a dotted path. parentPresent is false when an enclosing
+// object is missing or not an object, in which case the parent's own check reports it.
+func lookupPayloadPath(payload map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	current := payload
+	for i, part := range parts {
+		value := current[part]
+		if i == len(parts)-1 {
+			return value, true
+		}
+		next, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return nil, false
+}
+
+func payloadKindMatches(value any, kind string) bool {
+	switch kind {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	}
+	return true
+}
+
+func articleFor(kind string) string {
+	if kind == "object" || kind == "array" {
+		return "an " + kind
+	}
+	return "a " + kind
+}
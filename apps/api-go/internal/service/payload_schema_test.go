@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePayload(t *testing.T) {
+	cases := []struct {
+		name    string
+		event   string
+		payload map[string]any
+		wantErr string
+	}{
+		{name: "minimal issue", event: "issues", payload: map[string]any{"action": "opened"}},
+		{name: "missing action", event: "issues", payload: map[string]any{"issue": map[string]any{}}, wantErr: "action is required"},
+		{name: "null counts as absent", event: "pull_request", payload: map[string]any{"action": nil}, wantErr: "action is required"},
+		{name: "wrong nested type", event: "issues", payload: map[string]any{"action": "opened", "issue": map[string]any{"number": "1"}}, wantErr: "issue.number must be a number"},
+		{name: "common field type", event: "custom", payload: map[string]any{"sender": "alice"}, wantErr: "sender must be an object"},
+		{name: "repository needs id", event: "repository", payload: map[string]any{"action": "renamed", "repository": map[string]any{"full_name": "a/b"}}, wantErr: "repository.id is required"},
+		{name: "ping", event: "ping", payload: map[string]any{"zen": "hi", "hook_id": float64(1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePayload(tc.event, tc.payload)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"maintainer-firewall/api-go/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startPeriodicWorker runs runOnce every interval under sup. A run in progress at
+// shutdown is allowed to finish; its context derives from the supervisor's work
+// context, not from the stop signal. Each run is the root span of its own trace,
+// so the queries and GitHub calls it makes are grouped under it.
+func startPeriodicWorker(sup *Supervisor, name string, interval time.Duration, timeout time.Duration, runOnce func(context.Context) (int, int, error), report func(context.Context, int, int)) {
+	if interval <= 0 || runOnce == nil {
+		return
+	}
+
+	sup.Go(name, func(ctx context.Context, stop <-chan struct{}) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return nil
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, timeout)
+				runCtx, span := tracing.Start(runCtx, "worker.run", trace.WithAttributes(attribute.String("mf.worker", name)))
+				a, b, err := runOnce(runCtx)
+				span.SetAttributes(attribute.Int("mf.processed", a), attribute.Int("mf.total", b))
+				tracing.RecordError(span, err)
+				if err != nil {
+					slog.ErrorContext(runCtx, "worker run failed", "worker", name, "error", err)
+				} else if report != nil {
+					report(runCtx, a, b)
+				}
+				span.End()
+				cancel()
+			}
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// NormalizedEvent is the provider-independent view of a delivery that rules,
+// alerts and actions work from. EventType and Payload use GitHub webhook names
+// and shapes, which every provider parser maps onto.
+type NormalizedEvent struct {
+	Provider   string
+	EventType  string
+	Action     string
+	Repository string
+	TargetKind string
+	Number     int
+	Author     string
+	Sender     string
+	Title      string
+	Body       string
+	Labels     []string
+	Payload    map[string]any
+	// Raw is the payload as persisted; it must re-normalise through NormalizePayload.
+	Raw []byte
+}
+
+// Text is the searchable text rules match keywords against.
+func (e NormalizedEvent) Text() string {
+	parts := []string{}
+	if e.Title != "" {
+		parts = append(parts, e.Title)
+	}
+	if e.Body != "" {
+		parts = append(parts, e.Body)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ActionExecutor applies suggested actions to an issue or pull request. The
+// target kind is carried by the context (see WithTargetKind).
+type ActionExecutor interface {
+	AddLabel(ctx context.Context, repositoryFullName string, number int, label string) error
+	AddComment(ctx context.Context, repositoryFullName string, number int, body string) error
+}
+
+// EventParser turns a raw delivery and the provider's event name into a NormalizedEvent.
+type EventParser func(eventName string, body []byte) (NormalizedEvent, error)
+
+// Provider plugs a forge into ingestion and action execution. Executor may be nil
+// when no credentials are configured; events are still ingested.
+type Provider struct {
+	Name     string
+	Parse    EventParser
+	Executor ActionExecutor
+}
+
+// ProviderRegistry maps provider names (the event source stored with each event)
+// to their parser and executor.
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func NewProviderRegistry(providers ...Provider) *ProviderRegistry {
+	r := &ProviderRegistry{providers: map[string]Provider{}}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds or replaces a provider.
+func (r *ProviderRegistry) Register(p Provider) {
+	name := strings.ToLower(strings.TrimSpace(p.Name))
+	if name == "" || p.Parse == nil {
+		return
+	}
+	p.Name = name
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = p
+}
+
+func (r *ProviderRegistry) Lookup(name string) (Provider, bool) {
+	if r == nil {
+		return Provider{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+// Executor returns nil for unknown providers and providers without credentials.
+func (r *ProviderRegistry) Executor(name string) ActionExecutor {
+	p, ok := r.Lookup(name)
+	if !ok {
+		return nil
+	}
+	return p.Executor
+}
+
+func (r *ProviderRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultProviderRegistry knows how to parse every built-in forge but has no
+// executors; callers attach those once credentials are available.
+func DefaultProviderRegistry() *ProviderRegistry {
+	return NewProviderRegistry(GitHubProvider(nil), GitLabProvider(nil), GiteaProvider(nil))
+}
+
+func GitHubProvider(executor ActionExecutor) Provider {
+	return Provider{Name: store.EventSourceGitHub, Parse: ParseGitHubEvent, Executor: executor}
+}
+
+func ParseGitHubEvent(eventName string, body []byte) (NormalizedEvent, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return NormalizedEvent{}, fmt.Errorf("invalid JSON payload")
+	}
+	eventType := strings.TrimSpace(eventName)
+	if eventType == "" {
+		eventType = "unknown"
+	}
+	evt := NormalizePayload(store.EventSourceGitHub, eventType, payload)
+	evt.Raw = body
+	return evt, nil
+}
+
+// NormalizePayload extracts the normalised fields from a GitHub-shaped payload,
+// such as a stored event or the output of a provider parser.
+func NormalizePayload(provider string, eventType string, payload map[string]any) NormalizedEvent {
+	evt := NormalizedEvent{
+		Provider:  provider,
+		EventType: eventType,
+		Payload:   payload,
+	}
+	if provider == "" {
+		evt.Provider = store.EventSourceGitHub
+	}
+	evt.Action, _ = payload["action"].(string)
+	if repo, ok := payload["repository"].(map[string]any); ok {
+		evt.Repository = strings.TrimSpace(stringField(repo, "full_name"))
+	}
+	if sender, ok := payload["sender"].(map[string]any); ok {
+		evt.Sender = strings.TrimSpace(stringField(sender, "login"))
+	}
+
+	var target map[string]any
+	switch eventType {
+	case "issues", "issue_comment":
+		target, _ = payload["issue"].(map[string]any)
+		evt.TargetKind = TargetKindIssue
+		if _, isPR := target["pull_request"]; isPR {
+			evt.TargetKind = TargetKindPullRequest
+		}
+	case "pull_request", "pull_request_review", "pull_request_review_comment":
+		target, _ = payload["pull_request"].(map[string]any)
+		evt.TargetKind = TargetKindPullRequest
+	}
+	if target != nil {
+		if n, ok := target["number"].(float64); ok {
+			evt.Number = int(n)
+		}
+		evt.Title = stringField(target, "title")
+		evt.Body = stringField(target, "body")
+		if user, ok := target["user"].(map[string]any); ok {
+			evt.Author = stringField(user, "login")
+		}
+		evt.Labels = labelNames(target["labels"])
+	}
+	if eventType == "issue_comment" {
+		if comment, ok := payload["comment"].(map[string]any); ok {
+			evt.Body = stringField(comment, "body")
+		}
+	}
+	if evt.Author == "" {
+		evt.Author = evt.Sender
+	}
+	return evt
+}
+
+func labelNames(raw any) []string {
+	items, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			names = append(names, v)
+		case map[string]any:
+			if name := stringField(v, "name"); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+type nopExecutor struct{}
+
+func (nopExecutor) AddLabel(context.Context, string, int, string) error   { return nil }
+func (nopExecutor) AddComment(context.Context, string, int, string) error { return nil }
+
+func TestProviderRegistry_LookupAndExecutors(t *testing.T) {
+	reg := DefaultProviderRegistry()
+	if got := reg.Names(); len(got) != 3 || got[0] != "gitea" || got[1] != "github" || got[2] != "gitlab" {
+		t.Fatalf("unexpected providers %v", got)
+	}
+	if reg.Executor(store.EventSourceGitHub) != nil {
+		t.Fatalf("default registry should have no executors")
+	}
+
+	reg.Register(GitLabProvider(nopExecutor{}))
+	if reg.Executor("GitLab") == nil {
+		t.Fatalf("expected gitlab executor after register")
+	}
+	if _, ok := reg.Lookup("bitbucket"); ok {
+		t.Fatalf("unexpected provider bitbucket")
+	}
+
+	var empty *ProviderRegistry
+	if _, ok := empty.Lookup("github"); ok || empty.Executor("github") != nil {
+		t.Fatalf("nil registry should resolve nothing")
+	}
+}
+
+func TestParseGitHubEvent_Normalizes(t *testing.T) {
+	body := []byte(`{"action":"opened","repository":{"full_name":"owner/repo"},"sender":{"login":"bob"},
+		"pull_request":{"number":12,"title":"Urgent fix","body":"details","user":{"login":"alice"},"labels":[{"name":"bug"}]}}`)
+	evt, err := ParseGitHubEvent("pull_request", body)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if evt.Provider != store.EventSourceGitHub || evt.EventType != "pull_request" || evt.Action != "opened" {
+		t.Fatalf("unexpected event %+v", evt)
+	}
+	if evt.Repository != "owner/repo" || evt.TargetKind != TargetKindPullRequest || evt.Number != 12 {
+		t.Fatalf("unexpected target %+v", evt)
+	}
+	if evt.Author != "alice" || evt.Sender != "bob" || len(evt.Labels) != 1 || evt.Labels[0] != "bug" {
+		t.Fatalf("unexpected people/labels %+v", evt)
+	}
+	if evt.Text() != "Urgent fix\ndetails" || string(evt.Raw) != string(body) {
+		t.Fatalf("unexpected text or raw %q", evt.Text())
+	}
+
+	if _, err := ParseGitHubEvent("issues", []byte("{")); err == nil {
+		t.Fatalf("expected error for invalid JSON")
+	}
+}
+
+func TestNormalizePayload_CommentOnPullRequest(t *testing.T) {
+	evt := NormalizePayload("", "issue_comment", map[string]any{
+		"issue":   map[string]any{"number": float64(4), "title": "t", "pull_request": map[string]any{}},
+		"comment": map[string]any{"body": "looks good"},
+	})
+	if evt.Provider != store.EventSourceGitHub || evt.TargetKind != TargetKindPullRequest || evt.Number != 4 || evt.Body != "looks good" {
+		t.Fatalf("unexpected comment event %+v", evt)
+	}
+}
+
+func TestRuleEngine_EvaluateEventMatchesAnyProvider(t *testing.T) {
+	evt, err := ParseGitLabEvent("Issue Hook", []byte(`{"object_kind":"issue","project":{"path_with_namespace":"group/app"},
+		"object_attributes":{"iid":3,"title":"urgent outage","action":"open"}}`))
+	if err != nil {
+		t.Fatalf("parse gitlab: %v", err)
+	}
+	if evt.Provider != store.EventSourceGitLab || evt.Number != 3 || evt.Repository != "group/app" {
+		t.Fatalf("unexpected gitlab event %+v", evt)
+	}
+	got := NewRuleEngine().EvaluateEvent(evt, DefaultRules())
+	if len(got) == 0 || got[0].Matched != "urgent" {
+		t.Fatalf("expected urgent suggestions, got %+v", got)
+	}
+}
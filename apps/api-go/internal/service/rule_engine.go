@@ -25,15 +25,19 @@ func NewRuleEngine() *RuleEngine {
 }
 
 func (e *RuleEngine) Evaluate(eventType string, payload map[string]any) []SuggestedAction {
-	return e.EvaluateWithRules(eventType, payload, defaultRules())
+	return e.EvaluateWithRules(eventType, payload, DefaultRules())
 }
 
 func (e *RuleEngine) EvaluateWithRules(eventType string, payload map[string]any, rules []RuleDefinition) []SuggestedAction {
-	if eventType != "issues" && eventType != "pull_request" {
+	return e.EvaluateEvent(NormalizePayload("", eventType, payload), rules)
+}
+
+// EvaluateEvent matches rules against a normalised event from any provider.
+func (e *RuleEngine) EvaluateEvent(evt NormalizedEvent, rules []RuleDefinition) []SuggestedAction {
+	if evt.EventType != "issues" && evt.EventType != "pull_request" {
 		return nil
 	}
-
-	text := strings.ToLower(extractText(payload))
+	text := strings.ToLower(evt.Text())
 	if strings.TrimSpace(text) == "" {
 		return nil
 	}
@@ -43,7 +47,7 @@ func (e *RuleEngine) EvaluateWithRules(eventType string, payload map[string]any,
 		if strings.TrimSpace(rule.Keyword) == "" {
 			continue
 		}
-		if rule.EventType != "" && rule.EventType != eventType {
+		if rule.EventType != "" && rule.EventType != evt.EventType {
 			continue
 		}
 		keyword := strings.ToLower(rule.Keyword)
@@ -60,7 +64,7 @@ func (e *RuleEngine) EvaluateWithRules(eventType string, payload map[string]any,
 	return dedupeActions(result)
 }
 
-func defaultRules() []RuleDefinition {
+func DefaultRules() []RuleDefinition {
 	return []RuleDefinition{
 		{EventType: "issues", Keyword: "duplicate", SuggestionType: "label", SuggestionValue: "needs-triage", Reason: "contains duplicate keyword"},
 		{EventType: "issues", Keyword: "duplicate", SuggestionType: "comment", SuggestionValue: "Thanks! This may be a duplicate. Please reference related issue links.", Reason: "contains duplicate keyword"},
@@ -77,30 +81,6 @@ func defaultRules() []RuleDefinition {
 	}
 }
 
-func extractText(payload map[string]any) string {
-	parts := []string{}
-
-	if issue, ok := payload["issue"].(map[string]any); ok {
-		if t, _ := issue["title"].(string); t != "" {
-			parts = append(parts, t)
-		}
-		if b, _ := issue["body"].(string); b != "" {
-			parts = append(parts, b)
-		}
-	}
-
-	if pr, ok := payload["pull_request"].(map[string]any); ok {
-		if t, _ := pr["title"].(string); t != "" {
-			parts = append(parts, t)
-		}
-		if b, _ := pr["body"].(string); b != "" {
-			parts = append(parts, b)
-		}
-	}
-
-	return strings.Join(parts, "\n")
-}
-
 func dedupeActions(in []SuggestedAction) []SuggestedAction {
 	if len(in) == 0 {
 		return in
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func StartScheduledJobsWorker(sup *Supervisor, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, "scheduled jobs", interval, 2*time.Minute, runOnce, func(ctx context.Context, ran int, due int) {
+		if due > 0 {
+			slog.InfoContext(ctx, "scheduled jobs done", "ran", ran, "due", due)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+// BurnRatePolicy alerts when the error budget burns faster than the rate that
+// would spend BudgetFraction of it within LongWindow, measured over both
+// windows: the long one shows the burn is significant, the short one that it is
+// still going on.
+type BurnRatePolicy struct {
+	Name           string
+	Severity       string
+	LongWindow     time.Duration
+	ShortWindow    time.Duration
+	BudgetFraction float64
+}
+
+// DefaultBurnRatePolicies are the multi-window alerts from the SRE workbook.
+// For a 30-day objective they fire at burn rates of 14.4, 6 and 1.
+var DefaultBurnRatePolicies = []BurnRatePolicy{
+	{Name: "fast", Severity: "page", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BudgetFraction: 0.02},
+	{Name: "medium", Severity: "page", LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, BudgetFraction: 0.05},
+	{Name: "slow", Severity: "ticket", LongWindow: 72 * time.Hour, ShortWindow: 6 * time.Hour, BudgetFraction: 0.10},
+}
+
+const (
+	SLOStatusOK        = "ok"
+	SLOStatusBurning   = "burning"
+	SLOStatusExhausted = "exhausted"
+	SLOStatusNoData    = "no_data"
+)
+
+type ErrorBudget struct {
+	// Allowed is how many bad deliveries the objective permits over the window
+	// at the current volume.
+	Allowed        float64 `json:"allowed"`
+	Spent          int64   `json:"spent"`
+	Remaining      float64 `json:"remaining"`
+	RemainingRatio float64 `json:"remaining_ratio"`
+}
+
+type BurnRate struct {
+	Window    string  `json:"window"`
+	Total     int64   `json:"total"`
+	Good      int64   `json:"good"`
+	ErrorRate float64 `json:"error_rate"`
+	BurnRate  float64 `json:"burn_rate"`
+}
+
+type BurnRateAlert struct {
+	Policy        string  `json:"policy"`
+	Severity      string  `json:"severity"`
+	LongWindow    string  `json:"long_window"`
+	ShortWindow   string  `json:"short_window"`
+	Threshold     float64 `json:"threshold"`
+	LongBurnRate  float64 `json:"long_burn_rate"`
+	ShortBurnRate float64 `json:"short_burn_rate"`
+	Firing        bool    `json:"firing"`
+}
+
+type SLOStatus struct {
+	SLO         store.SLORecord `json:"slo"`
+	Status      string          `json:"status"`
+	Total       int64           `json:"total"`
+	Good        int64           `json:"good"`
+	SLI         float64         `json:"sli"`
+	ErrorBudget ErrorBudget     `json:"error_budget"`
+	BurnRates   []BurnRate      `json:"burn_rates"`
+	Alerts      []BurnRateAlert `json:"alerts"`
+}
+
+// SLOWindow is the compliance window of slo.
+func SLOWindow(slo store.SLORecord) time.Duration {
+	return time.Duration(slo.WindowDays) * 24 * time.Hour
+}
+
+// SLOEvaluationWindows lists, in ascending order, every window EvaluateSLO needs
+// counts for. Policy windows longer than the SLO window are clamped to it.
+func SLOEvaluationWindows(slo store.SLORecord, policies []BurnRatePolicy) []time.Duration {
+	window := SLOWindow(slo)
+	seen := map[time.Duration]bool{window: true}
+	for _, p := range policies {
+		seen[min(p.LongWindow, window)] = true
+		seen[min(p.ShortWindow, window)] = true
+	}
+	out := make([]time.Duration, 0, len(seen))
+	for d := range seen {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// EvaluateSLO computes the SLI, error budget, burn rates and alert states from
+// delivery counts keyed by window.
+func EvaluateSLO(slo store.SLORecord, counts map[time.Duration]store.SLOWindowCount, policies []BurnRatePolicy) SLOStatus {
+	window := SLOWindow(slo)
+	allowedErrorRate := 1 - slo.Objective/100
+
+	good := func(c store.SLOWindowCount) int64 {
+		if slo.SLI == store.SLILatency {
+			return c.WithinThreshold
+		}
+		return c.Success
+	}
+	burn := func(d time.Duration) BurnRate {
+		c := counts[d]
+		out := BurnRate{Window: FormatSLOWindow(d), Total: c.Total, Good: good(c)}
+		if c.Total > 0 {
+			out.ErrorRate = float64(c.Total-out.Good) / float64(c.Total)
+			if allowedErrorRate > 0 {
+				out.BurnRate = out.ErrorRate / allowedErrorRate
+			}
+		}
+		return out
+	}
+
+	full := burn(window)
+	status := SLOStatus{
+		SLO:       slo,
+		Status:    SLOStatusOK,
+		Total:     full.Total,
+		Good:      full.Good,
+		SLI:       100,
+		BurnRates: []BurnRate{},
+		Alerts:    []BurnRateAlert{},
+	}
+	status.ErrorBudget.RemainingRatio = 1
+	if full.Total == 0 {
+		status.Status = SLOStatusNoData
+	} else {
+		status.SLI = float64(full.Good) / float64(full.Total) * 100
+		spent := full.Total - full.Good
+		allowed := float64(full.Total) * allowedErrorRate
+		status.ErrorBudget = ErrorBudget{Allowed: allowed, Spent: spent, Remaining: allowed - float64(spent)}
+		switch {
+		case allowed > 0:
+			status.ErrorBudget.RemainingRatio = status.ErrorBudget.Remaining / allowed
+		case spent > 0:
+			status.ErrorBudget.RemainingRatio = -1
+		}
+		if status.ErrorBudget.Remaining <= 0 && spent > 0 {
+			status.Status = SLOStatusExhausted
+		}
+	}
+
+	for _, d := range SLOEvaluationWindows(slo, policies) {
+		status.BurnRates = append(status.BurnRates, burn(d))
+	}
+	for _, p := range policies {
+		long, short := min(p.LongWindow, window), min(p.ShortWindow, window)
+		alert := BurnRateAlert{
+			Policy:        p.Name,
+			Severity:      p.Severity,
+			LongWindow:    FormatSLOWindow(long),
+			ShortWindow:   FormatSLOWindow(short),
+			Threshold:     p.BudgetFraction * float64(window) / float64(long),
+			LongBurnRate:  burn(long).BurnRate,
+			ShortBurnRate: burn(short).BurnRate,
+		}
+		alert.Firing = alert.LongBurnRate > alert.Threshold && alert.ShortBurnRate > alert.Threshold
+		if alert.Firing && status.Status == SLOStatusOK {
+			status.Status = SLOStatusBurning
+		}
+		status.Alerts = append(status.Alerts, alert)
+	}
+	return status
+}
+
+// FormatSLOWindow renders whole days as "30d", whole hours as "6h" and the rest
+// in minutes.
+func FormatSLOWindow(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+// ValidateSLO checks an SLO definition before it is stored.
+func ValidateSLO(slo store.SLORecord) error {
+	switch {
+	case slo.Name == "":
+		return fmt.Errorf("name is required")
+	case len(slo.Name) > 120:
+		return fmt.Errorf("name must be at most 120 characters")
+	case slo.SLI != store.SLIDeliverySuccess && slo.SLI != store.SLILatency:
+		return fmt.Errorf("sli must be %s or %s", store.SLIDeliverySuccess, store.SLILatency)
+	case slo.Objective <= 0 || slo.Objective >= 100:
+		return fmt.Errorf("objective must be a percentage between 0 and 100 (exclusive)")
+	case slo.WindowDays < 1 || slo.WindowDays > 90:
+		return fmt.Errorf("window_days must be between 1 and 90")
+	case slo.SLI == store.SLILatency && slo.LatencyThresholdMS <= 0:
+		return fmt.Errorf("latency_threshold_ms is required for latency objectives")
+	}
+	return nil
+}
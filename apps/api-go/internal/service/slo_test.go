@@ -0,0 +1,116 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/store"
+)
+
+func sloCounts(total int64, good int64) store.SLOWindowCount {
+	return store.SLOWindowCount{Total: total, Success: good, WithinThreshold: good}
+}
+
+func TestEvaluateSLOErrorBudget(t *testing.T) {
+	slo := store.SLORecord{Name: "deliveries", SLI: store.SLIDeliverySuccess, Objective: 99.5, WindowDays: 30}
+	counts := map[time.Duration]store.SLOWindowCount{}
+	for _, d := range SLOEvaluationWindows(slo, DefaultBurnRatePolicies) {
+		counts[d] = sloCounts(0, 0)
+	}
+	counts[SLOWindow(slo)] = sloCounts(10000, 9980)
+
+	status := EvaluateSLO(slo, counts, DefaultBurnRatePolicies)
+	if status.Status != SLOStatusOK {
+		t.Fatalf("expected ok, got %s", status.Status)
+	}
+	if math.Abs(status.SLI-99.8) > 1e-9 {
+		t.Fatalf("expected SLI 99.8, got %v", status.SLI)
+	}
+	budget := status.ErrorBudget
+	if math.Abs(budget.Allowed-50) > 1e-9 || budget.Spent != 20 || math.Abs(budget.RemainingRatio-0.6) > 1e-9 {
+		t.Fatalf("unexpected budget %+v", budget)
+	}
+
+	counts[SLOWindow(slo)] = sloCounts(10000, 9900)
+	if got := EvaluateSLO(slo, counts, DefaultBurnRatePolicies).Status; got != SLOStatusExhausted {
+		t.Fatalf("expected exhausted budget, got %s", got)
+	}
+}
+
+func TestEvaluateSLOMultiWindowBurnRate(t *testing.T) {
+	slo := store.SLORecord{Name: "deliveries", SLI: store.SLIDeliverySuccess, Objective: 99, WindowDays: 30}
+	counts := map[time.Duration]store.SLOWindowCount{}
+	for _, d := range SLOEvaluationWindows(slo, DefaultBurnRatePolicies) {
+		counts[d] = sloCounts(100000, 100000)
+	}
+	// 20% errors in the last hour is a burn rate of 20, above the fast threshold
+	// of 14.4, but the last five minutes have recovered.
+	counts[time.Hour] = sloCounts(1000, 800)
+	counts[5*time.Minute] = sloCounts(100, 100)
+
+	status := EvaluateSLO(slo, counts, DefaultBurnRatePolicies)
+	fast := status.Alerts[0]
+	if fast.Policy != "fast" || math.Abs(fast.Threshold-14.4) > 1e-9 || math.Abs(fast.LongBurnRate-20) > 1e-9 {
+		t.Fatalf("unexpected fast policy %+v", fast)
+	}
+	if fast.Firing || status.Status != SLOStatusOK {
+		t.Fatalf("expected no alert once the short window recovered, got %+v", status.Alerts)
+	}
+
+	counts[5*time.Minute] = sloCounts(100, 70)
+	status = EvaluateSLO(slo, counts, DefaultBurnRatePolicies)
+	if !status.Alerts[0].Firing || status.Status != SLOStatusBurning {
+		t.Fatalf("expected fast burn to fire, got %s %+v", status.Status, status.Alerts)
+	}
+	if status.Alerts[1].Firing || status.Alerts[2].Firing {
+		t.Fatalf("expected slower policies to stay quiet, got %+v", status.Alerts)
+	}
+}
+
+func TestEvaluateSLOLatencyAndShortWindows(t *testing.T) {
+	slo := store.SLORecord{Name: "p95", SLI: store.SLILatency, Objective: 95, LatencyThresholdMS: 500, WindowDays: 1}
+	windows := SLOEvaluationWindows(slo, DefaultBurnRatePolicies)
+	if windows[len(windows)-1] != 24*time.Hour {
+		t.Fatalf("expected policy windows clamped to the SLO window, got %v", windows)
+	}
+	counts := map[time.Duration]store.SLOWindowCount{}
+	for _, d := range windows {
+		counts[d] = store.SLOWindowCount{Total: 100, Success: 100, WithinThreshold: 90}
+	}
+	status := EvaluateSLO(slo, counts, DefaultBurnRatePolicies)
+	if math.Abs(status.SLI-90) > 1e-9 || status.Good != 90 {
+		t.Fatalf("expected latency SLI from within-threshold count, got %+v", status)
+	}
+	slow := status.Alerts[2]
+	if slow.LongWindow != "1d" || math.Abs(slow.Threshold-0.1) > 1e-9 || !slow.Firing {
+		t.Fatalf("unexpected slow policy on a 1-day SLO: %+v", slow)
+	}
+}
+
+func TestEvaluateSLONoData(t *testing.T) {
+	slo := store.SLORecord{Name: "quiet", SLI: store.SLIDeliverySuccess, Objective: 99.5, WindowDays: 30}
+	status := EvaluateSLO(slo, map[time.Duration]store.SLOWindowCount{}, DefaultBurnRatePolicies)
+	if status.Status != SLOStatusNoData || status.ErrorBudget.RemainingRatio != 1 || len(status.Alerts) != 3 {
+		t.Fatalf("unexpected status without traffic: %+v", status)
+	}
+}
+
+func TestValidateSLO(t *testing.T) {
+	valid := store.SLORecord{Name: "x", SLI: store.SLIDeliverySuccess, Objective: 99.5, WindowDays: 30}
+	if err := ValidateSLO(valid); err != nil {
+		t.Fatalf("expected valid slo, got %v", err)
+	}
+	invalid := []store.SLORecord{
+		{Name: "", SLI: store.SLIDeliverySuccess, Objective: 99, WindowDays: 30},
+		{Name: "x", SLI: "errors", Objective: 99, WindowDays: 30},
+		{Name: "x", SLI: store.SLIDeliverySuccess, Objective: 100, WindowDays: 30},
+		{Name: "x", SLI: store.SLIDeliverySuccess, Objective: 99, WindowDays: 91},
+		{Name: "x", SLI: store.SLILatency, Objective: 95, WindowDays: 30},
+	}
+	for _, item := range invalid {
+		if err := ValidateSLO(item); err == nil {
+			t.Fatalf("expected %+v to be rejected", item)
+		}
+	}
+}
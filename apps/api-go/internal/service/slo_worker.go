@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func StartSLOWorker(sup *Supervisor, interval time.Duration, runOnce func(context.Context) (int, int, error)) {
+	startPeriodicWorker(sup, "slo burn rate evaluation", interval, 2*time.Minute, runOnce, func(ctx context.Context, raised int, slos int) {
+		if raised > 0 {
+			slog.WarnContext(ctx, "slo burn rate alerts raised", "alerts", raised, "slos", slos)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WorkerStateRunning = "running"
+	WorkerStateBackoff = "backoff"
+	WorkerStateStopped = "stopped"
+)
+
+// Worker is a long-lived background task. It should return once stop is closed,
+// finishing the work in hand; ctx is only cancelled when the shutdown deadline
+// passes, so in-flight work is not cut off early.
+type Worker func(ctx context.Context, stop <-chan struct{}) error
+
+type WorkerStatus struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Restarts  int        `json:"restarts"`
+	LastError string     `json:"last_error,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	CrashedAt *time.Time `json:"crashed_at,omitempty"`
+}
+
+// Supervisor runs background workers. A worker that fails or panics before
+// shutdown is restarted with exponential backoff; Shutdown stops them all and
+// waits for in-flight work up to a deadline.
+type Supervisor struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	work     context.Context
+	cancel   context.CancelFunc
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	workers map[string]*WorkerStatus
+}
+
+func NewSupervisor() *Supervisor {
+	work, cancel := context.WithCancel(context.Background())
+	return &Supervisor{
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		work:       work,
+		cancel:     cancel,
+		stop:       make(chan struct{}),
+		workers:    map[string]*WorkerStatus{},
+	}
+}
+
+// Go starts run under supervision. Names identify workers in Statuses and must
+// be unique.
+func (s *Supervisor) Go(name string, run Worker) {
+	s.mu.Lock()
+	if _, exists := s.workers[name]; exists {
+		s.mu.Unlock()
+		panic(fmt.Sprintf("supervisor: worker %q already registered", name))
+	}
+	s.workers[name] = &WorkerStatus{Name: name, State: WorkerStateRunning}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.supervise(name, run)
+}
+
+func (s *Supervisor) supervise(name string, run Worker) {
+	defer s.wg.Done()
+	backoff := s.MinBackoff
+	for {
+		started := time.Now().UTC()
+		s.update(name, func(st *WorkerStatus) {
+			st.State = WorkerStateRunning
+			st.StartedAt = &started
+		})
+		err := runWorker(s.work, s.stop, run)
+		if s.stopping() {
+			s.update(name, func(st *WorkerStatus) {
+				st.State = WorkerStateStopped
+				if err != nil {
+					st.LastError = err.Error()
+				}
+			})
+			return
+		}
+		if err == nil {
+			err = errors.New("worker returned before shutdown")
+		}
+		// A worker that stayed up for a while starts its backoff over.
+		if time.Since(started) > s.MaxBackoff {
+			backoff = s.MinBackoff
+		}
+		crashed := time.Now().UTC()
+		s.update(name, func(st *WorkerStatus) {
+			st.State = WorkerStateBackoff
+			st.Restarts++
+			st.LastError = err.Error()
+			st.CrashedAt = &crashed
+		})
+		slog.Error("worker crashed; restarting", "worker", name, "backoff", backoff.String(), "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			s.update(name, func(st *WorkerStatus) { st.State = WorkerStateStopped })
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, s.MaxBackoff)
+	}
+}
+
+func runWorker(ctx context.Context, stop <-chan struct{}, run Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return run(ctx, stop)
+}
+
+func (s *Supervisor) update(name string, fn func(st *WorkerStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.workers[name]; ok {
+		fn(st)
+	}
+}
+
+func (s *Supervisor) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Statuses returns a snapshot of every worker, sorted by name.
+func (s *Supervisor) Statuses() []WorkerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]WorkerStatus, 0, len(s.workers))
+	for _, st := range s.workers {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Healthy reports whether every worker is running; a worker waiting to restart
+// is unhealthy.
+func (s *Supervisor) Healthy() bool {
+	for _, st := range s.Statuses() {
+		if st.State != WorkerStateRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// Shutdown tells workers to stop and waits for them until ctx is done. Work still
+// running at the deadline has its context cancelled and the names of those
+// workers are returned in the error.
+func (s *Supervisor) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+	s.cancel()
+
+	var pending []string
+	for _, st := range s.Statuses() {
+		if st.State != WorkerStateStopped {
+			pending = append(pending, st.Name)
+		}
+	}
+	return fmt.Errorf("workers still running at shutdown deadline: %s", strings.Join(pending, ", "))
+}
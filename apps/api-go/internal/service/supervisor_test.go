@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSupervisor() *Supervisor {
+	sup := NewSupervisor()
+	sup.MinBackoff = time.Millisecond
+	sup.MaxBackoff = 5 * time.Millisecond
+	return sup
+}
+
+func TestSupervisorRestartsCrashedWorker(t *testing.T) {
+	sup := newTestSupervisor()
+	var starts atomic.Int32
+	sup.Go("flaky", func(ctx context.Context, stop <-chan struct{}) error {
+		if starts.Add(1) < 3 {
+			panic("boom")
+		}
+		<-stop
+		return nil
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for starts.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	statuses := sup.Statuses()
+	if len(statuses) != 1 || statuses[0].Restarts != 2 || !strings.Contains(statuses[0].LastError, "boom") {
+		t.Fatalf("expected two restarts after panics, got %+v", statuses)
+	}
+
+	if err := sup.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if got := sup.Statuses()[0].State; got != WorkerStateStopped {
+		t.Fatalf("expected stopped worker, got %s", got)
+	}
+}
+
+func TestSupervisorShutdownDrainsInFlightWork(t *testing.T) {
+	sup := newTestSupervisor()
+	started := make(chan struct{})
+	var finished atomic.Bool
+	sup.Go("busy", func(ctx context.Context, stop <-chan struct{}) error {
+		close(started)
+		<-stop
+		select {
+		case <-time.After(20 * time.Millisecond):
+			finished.Store(true)
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	<-started
+
+	if err := sup.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if !finished.Load() {
+		t.Fatalf("expected in-flight work to finish before shutdown returned")
+	}
+}
+
+func TestSupervisorShutdownDeadlineCancelsWork(t *testing.T) {
+	sup := newTestSupervisor()
+	cancelled := make(chan struct{})
+	sup.Go("stuck", func(ctx context.Context, stop <-chan struct{}) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := sup.Shutdown(ctx)
+	if err == nil || !strings.Contains(err.Error(), "stuck") {
+		t.Fatalf("expected deadline error naming the worker, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("expected worker context to be cancelled at the deadline")
+	}
+}
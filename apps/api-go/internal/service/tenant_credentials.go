@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"maintainer-firewall/api-go/internal/secretbox"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+)
+
+const (
+	tenantCredentialCacheTTL = time.Minute
+	// tenantCredentialVersionInterval bounds how long a change made through
+	// another replica goes unnoticed by this one's cache.
+	tenantCredentialVersionInterval = 5 * time.Second
+)
+
+type TenantCredentialReader interface {
+	GetTenantCredential(ctx context.Context, kind string) (store.TenantCredentialRecord, error)
+	TenantCredentialsVersion(ctx context.Context) (string, error)
+}
+
+type cachedCredential struct {
+	value     string
+	found     bool
+	validTill time.Time
+	expiresAt time.Time
+}
+
+// WebhookSecretSet is the set of secrets a delivery may be signed with. Secondary is
+// the outgoing secret of a rotation and is only accepted before SecondaryExpiresAt.
+type WebhookSecretSet struct {
+	Primary            string
+	Secondary          string
+	SecondaryExpiresAt time.Time
+}
+
+type WebhookSecretCandidate struct {
+	Slot   string
+	Secret string
+}
+
+// Candidates returns the secrets to try at now, primary first.
+func (s WebhookSecretSet) Candidates(now time.Time) []WebhookSecretCandidate {
+	out := make([]WebhookSecretCandidate, 0, 2)
+	if strings.TrimSpace(s.Primary) != "" {
+		out = append(out, WebhookSecretCandidate{Slot: "primary", Secret: s.Primary})
+	}
+	if strings.TrimSpace(s.Secondary) != "" && (s.SecondaryExpiresAt.IsZero() || now.Before(s.SecondaryExpiresAt)) {
+		out = append(out, WebhookSecretCandidate{Slot: "secondary", Secret: s.Secondary})
+	}
+	return out
+}
+
+// TenantCredentials decrypts per-tenant secrets and caches them briefly so the
+// webhook and executor hot paths do not hit the database on every call. Local
+// changes call Invalidate; changes made through other replicas are picked up by
+// polling TenantCredentialsVersion.
+type TenantCredentials struct {
+	Store TenantCredentialReader
+	Box   *secretbox.Box
+
+	mu               sync.Mutex
+	cache            map[string]cachedCredential
+	version          string
+	versionCheckedAt time.Time
+}
+
+func NewTenantCredentials(reader TenantCredentialReader, box *secretbox.Box) *TenantCredentials {
+	return &TenantCredentials{Store: reader, Box: box, cache: map[string]cachedCredential{}}
+}
+
+// Lookup returns the current tenant's credential of kind. found is false when the
+// tenant has none or it expired, in which case callers fall back to the global configuration.
+func (t *TenantCredentials) Lookup(ctx context.Context, kind string) (string, bool, error) {
+	entry, err := t.lookup(ctx, kind)
+	if err != nil || !entry.found {
+		return "", false, err
+	}
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (t *TenantCredentials) lookup(ctx context.Context, kind string) (cachedCredential, error) {
+	if t == nil || t.Store == nil || t.Box == nil {
+		return cachedCredential{}, nil
+	}
+	tenantID := tenantctx.MustFromContext(ctx, "")
+	key := tenantID + "/" + kind
+	now := time.Now()
+	t.checkVersion(ctx, now)
+
+	t.mu.Lock()
+	if t.cache == nil {
+		t.cache = map[string]cachedCredential{}
+	}
+	entry, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok && now.Before(entry.validTill) {
+		return entry, nil
+	}
+
+	entry = cachedCredential{validTill: now.Add(tenantCredentialCacheTTL)}
+	record, err := t.Store.GetTenantCredential(tenantctx.WithTenantID(ctx, tenantID), kind)
+	if err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return cachedCredential{}, err
+		}
+	} else {
+		value, err := t.Box.Open(record.SealedValue)
+		if err != nil {
+			return cachedCredential{}, err
+		}
+		entry.value, entry.found, entry.expiresAt = value, true, record.ExpiresAt
+	}
+
+	t.mu.Lock()
+	t.cache[key] = entry
+	t.mu.Unlock()
+	return entry, nil
+}
+
+// checkVersion drops the whole cache when the stored credentials changed since
+// the last check. On error the cache is kept; entries still expire after the TTL.
+func (t *TenantCredentials) checkVersion(ctx context.Context, now time.Time) {
+	t.mu.Lock()
+	if now.Sub(t.versionCheckedAt) < tenantCredentialVersionInterval {
+		t.mu.Unlock()
+		return
+	}
+	t.versionCheckedAt = now
+	t.mu.Unlock()
+
+	version, err := t.Store.TenantCredentialsVersion(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "check tenant credentials version failed", "error", err)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if version != t.version {
+		t.version = version
+		t.cache = map[string]cachedCredential{}
+	}
+}
+
+// Invalidate drops cached credentials of a tenant after they change.
+func (t *TenantCredentials) Invalidate(tenantID string) {
+	if t == nil {
+		return
+	}
+	prefix := strings.TrimSpace(tenantID) + "/"
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(t.cache, key)
+		}
+	}
+}
+
+// WebhookSecrets returns the tenant's webhook secret and, during a rotation, the
+// previous one. found is false when the tenant has no secret of its own.
+func (t *TenantCredentials) WebhookSecrets(ctx context.Context) (WebhookSecretSet, bool, error) {
+	primary, found, err := t.Lookup(ctx, store.CredentialKindWebhookSecret)
+	if err != nil || !found {
+		return WebhookSecretSet{}, false, err
+	}
+	set := WebhookSecretSet{Primary: primary}
+	previous, err := t.lookup(ctx, store.CredentialKindWebhookSecretPrevious)
+	if err != nil {
+		return WebhookSecretSet{}, false, err
+	}
+	if previous.found {
+		set.Secondary, set.SecondaryExpiresAt = previous.value, previous.expiresAt
+	}
+	return set, true, nil
+}
+
+// GitHubToken matches GitHubActionExecutor.TokenLookup; it returns "" when the
+// tenant has no token of its own.
+func (t *TenantCredentials) GitHubToken(ctx context.Context) (string, error) {
+	value, _, err := t.Lookup(ctx, store.CredentialKindGitHubToken)
+	return value, err
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"maintainer-firewall/api-go/internal/secretbox"
+	"maintainer-firewall/api-go/internal/store"
+	"maintainer-firewall/api-go/internal/tenantctx"
+)
+
+type mockCredentialReader struct {
+	items   map[string]store.TenantCredentialRecord
+	version string
+	gets    int
+}
+
+func (m *mockCredentialReader) GetTenantCredential(ctx context.Context, kind string) (store.TenantCredentialRecord, error) {
+	m.gets++
+	item, ok := m.items[tenantctx.MustFromContext(ctx, "")+"/"+kind]
+	if !ok {
+		return store.TenantCredentialRecord{}, fmt.Errorf("credential not found")
+	}
+	return item, nil
+}
+
+func (m *mockCredentialReader) TenantCredentialsVersion(context.Context) (string, error) {
+	return m.version, nil
+}
+
+func TestTenantCredentials_PicksUpChangesFromOtherReplicas(t *testing.T) {
+	box, _ := secretbox.New("test-key")
+	seal := func(value string) store.TenantCredentialRecord {
+		sealed, err := box.Seal(value)
+		if err != nil {
+			t.Fatalf("seal: %v", err)
+		}
+		return store.TenantCredentialRecord{SealedValue: sealed}
+	}
+	reader := &mockCredentialReader{version: "1", items: map[string]store.TenantCredentialRecord{
+		"team-a/" + store.CredentialKindWebhookSecret: seal("old-secret"),
+	}}
+	creds := NewTenantCredentials(reader, box)
+	ctx := tenantctx.WithTenantID(context.Background(), "team-a")
+
+	secrets, _, err := creds.WebhookSecrets(ctx)
+	if err != nil || secrets.Primary != "old-secret" {
+		t.Fatalf("expected old secret, got %+v err=%v", secrets, err)
+	}
+	gets := reader.gets
+
+	// Another replica rotates the secret.
+	reader.items["team-a/"+store.CredentialKindWebhookSecret] = seal("new-secret")
+	reader.items["team-a/"+store.CredentialKindWebhookSecretPrevious] = seal("old-secret")
+	reader.version = "2"
+
+	if secrets, _, _ = creds.WebhookSecrets(ctx); secrets.Primary != "old-secret" || reader.gets != gets {
+		t.Fatalf("expected cached secret within the version interval, got %+v", secrets)
+	}
+
+	creds.mu.Lock()
+	creds.versionCheckedAt = time.Now().Add(-tenantCredentialVersionInterval)
+	creds.mu.Unlock()
+	secrets, _, err = creds.WebhookSecrets(ctx)
+	if err != nil || secrets.Primary != "new-secret" || secrets.Secondary != "old-secret" {
+		t.Fatalf("expected rotated secrets after version change, got %+v err=%v", secrets, err)
+	}
+}
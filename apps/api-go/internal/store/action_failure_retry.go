@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActionRetrySchedule is the backoff applied when listing retryable failures:
+// a failure is due once Base*2^retry_count, capped at Max, has passed since
+// its last attempt.
+type ActionRetrySchedule struct {
+	Now  time.Time
+	Base time.Duration
+	Max  time.Duration
+}
+
+// actionRetryMaxDoublings keeps 2^retry_count from overflowing in SQL.
+const actionRetryMaxDoublings = 30
+
+// ListRetryableActionFailures spans all tenants that have auto retry enabled and
+// returns unresolved, non-dead failures whose backoff has elapsed, least
+// recently attempted first.
+func (s *WebhookEventStore) ListRetryableActionFailures(ctx context.Context, schedule ActionRetrySchedule, limit int) ([]ActionExecutionFailureRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT f.id, f.tenant_id, f.delivery_id, f.event_type, f.action, f.repository_full_name, f.source, f.suggestion_type, f.suggestion_value, f.error_message, f.attempt_count, f.retry_count, f.last_retry_status, f.last_retry_message, COALESCE(f.last_retry_at, 'epoch'::timestamptz), f.is_resolved, f.occurred_at
+		FROM webhook_action_failures f
+		JOIN tenants t ON t.id = f.tenant_id
+		WHERE t.is_active = TRUE
+		  AND t.auto_retry_enabled = TRUE
+		  AND f.is_resolved = FALSE
+		  AND f.last_retry_status <> 'dead'
+		  AND COALESCE(f.last_retry_at, f.occurred_at)
+		      + LEAST($2::double precision * POWER(2, LEAST(f.retry_count, $4)), $3::double precision) * INTERVAL '1 second' <= $1
+		ORDER BY COALESCE(f.last_retry_at, f.occurred_at) ASC
+		LIMIT $5
+	`, schedule.Now.UTC(), schedule.Base.Seconds(), schedule.Max.Seconds(), actionRetryMaxDoublings, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query retryable action failures: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ActionExecutionFailureRecord, 0, limit)
+	for rows.Next() {
+		var rec ActionExecutionFailureRecord
+		if err := rows.Scan(&rec.ID, &rec.TenantID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan retryable action failure: %w", err)
+		}
+		if rec.LastRetryAt.Equal(time.Unix(0, 0).UTC()) {
+			rec.LastRetryAt = time.Time{}
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate retryable action failures: %w", err)
+	}
+	return items, nil
+}
+
+// ClaimActionFailureRetry stamps last_retry_at only if it still holds the
+// value the caller listed, so a failure is retried by one caller at a time.
+// Dead failures can still be claimed for a manual retry; marking a failure
+// dead moves last_retry_at, so a stale background claim cannot revive it.
+func (s *WebhookEventStore) ClaimActionFailureRetry(ctx context.Context, id int64, lastRetryAt time.Time) (bool, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var expected any
+	if !lastRetryAt.IsZero() {
+		expected = lastRetryAt
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE webhook_action_failures
+		SET last_retry_at = NOW()
+		WHERE id = $1
+		  AND tenant_id = $2
+		  AND is_resolved = FALSE
+		  AND last_retry_at IS NOT DISTINCT FROM $3::timestamptz
+	`, id, tenantID, expected)
+	if err != nil {
+		return false, fmt.Errorf("claim action failure retry: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (s *WebhookEventStore) ListActionExecutionFailuresByFilter(ctx context.Context, filter ActionFailureFilter, limit int) ([]ActionExecutionFailureRecord, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, COALESCE(last_retry_at, 'epoch'::timestamptz), is_resolved, occurred_at
+		FROM webhook_action_failures
+		WHERE tenant_id = $1
+		  AND is_resolved = FALSE
+		  AND ($2 = '' OR event_type = $2)
+		  AND ($3 = '' OR suggestion_type = $3)
+		  AND ($4 = '' OR repository_full_name = $4)
+		  AND ($5 = '' OR last_retry_status = $5)
+		ORDER BY occurred_at ASC
+		LIMIT $6
+	`, tenantID, strings.TrimSpace(filter.EventType), strings.TrimSpace(filter.SuggestionType), strings.TrimSpace(filter.RepositoryFullName), strings.TrimSpace(filter.LastRetryStatus), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query action failures by filter: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ActionExecutionFailureRecord, 0, limit)
+	for rows.Next() {
+		var rec ActionExecutionFailureRecord
+		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan action failure: %w", err)
+		}
+		if rec.LastRetryAt.Equal(time.Unix(0, 0).UTC()) {
+			rec.LastRetryAt = time.Time{}
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate action failures: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) MarkActionFailureDead(ctx context.Context, id int64, message string) error {
+	tenantID := tenantIDFromCtx(ctx)
+	result, err := s.pool.Exec(ctx, `
+		UPDATE webhook_action_failures
+		SET last_retry_status = 'dead',
+		    last_retry_message = $2,
+		    last_retry_at = NOW()
+		WHERE id = $1
+		  AND tenant_id = $3
+	`, id, strings.TrimSpace(message), tenantID)
+	if err != nil {
+		return fmt.Errorf("mark action failure dead: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("action failure not found")
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) GetTenantAutoRetryEnabled(ctx context.Context) (bool, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var enabled bool
+	err := s.pool.QueryRow(ctx, `SELECT auto_retry_enabled FROM tenants WHERE id = $1`, tenantID).Scan(&enabled)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return false, fmt.Errorf("tenant not found")
+		}
+		return false, fmt.Errorf("get tenant auto retry: %w", err)
+	}
+	return enabled, nil
+}
+
+func (s *WebhookEventStore) UpdateTenantAutoRetryEnabled(ctx context.Context, enabled bool) error {
+	tenantID := tenantIDFromCtx(ctx)
+	result, err := s.pool.Exec(ctx, `
+		UPDATE tenants
+		SET auto_retry_enabled = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, tenantID, enabled)
+	if err != nil {
+		return fmt.Errorf("update tenant auto retry: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (s *MySQLWebhookEventStore) ListRetryableActionFailures(ctx context.Context, schedule ActionRetrySchedule, limit int) ([]ActionExecutionFailureRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.id, f.tenant_id, f.delivery_id, f.event_type, f.action, f.repository_full_name, f.source, f.suggestion_type, f.suggestion_value, f.error_message, f.attempt_count, f.retry_count, f.last_retry_status, f.last_retry_message, f.last_retry_at, f.is_resolved, f.occurred_at
+		FROM webhook_action_failures f
+		JOIN tenants t ON t.id = f.tenant_id
+		WHERE t.is_active = TRUE
+		  AND t.auto_retry_enabled = TRUE
+		  AND f.is_resolved = FALSE
+		  AND f.last_retry_status <> 'dead'
+		  AND DATE_ADD(COALESCE(f.last_retry_at, f.occurred_at),
+		      INTERVAL CAST(LEAST(? * POW(2, LEAST(f.retry_count, ?)), ?) AS SIGNED) SECOND) <= ?
+		ORDER BY COALESCE(f.last_retry_at, f.occurred_at) ASC
+		LIMIT ?
+	`, int64(schedule.Base.Seconds()), actionRetryMaxDoublings, int64(schedule.Max.Seconds()), schedule.Now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query retryable action failures: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ActionExecutionFailureRecord, 0, limit)
+	for rows.Next() {
+		var rec ActionExecutionFailureRecord
+		var lastRetryAt sql.NullTime
+		if err := rows.Scan(&rec.ID, &rec.TenantID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan retryable action failure: %w", err)
+		}
+		normalizeLastRetryAt(&rec, lastRetryAt)
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate retryable action failures: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ClaimActionFailureRetry(ctx context.Context, id int64, lastRetryAt time.Time) (bool, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var expected any
+	if !lastRetryAt.IsZero() {
+		expected = lastRetryAt.UTC()
+	}
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_action_failures
+		SET last_retry_at = CURRENT_TIMESTAMP(6)
+		WHERE id = ?
+		  AND tenant_id = ?
+		  AND is_resolved = FALSE
+		  AND last_retry_at <=> ?
+	`, id, tenantID, expected)
+	if err != nil {
+		return false, fmt.Errorf("claim action failure retry: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim action failure retry rows affected: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (s *MySQLWebhookEventStore) ListActionExecutionFailuresByFilter(ctx context.Context, filter ActionFailureFilter, limit int) ([]ActionExecutionFailureRecord, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	eventType := strings.TrimSpace(filter.EventType)
+	suggestionType := strings.TrimSpace(filter.SuggestionType)
+	repository := strings.TrimSpace(filter.RepositoryFullName)
+	status := strings.TrimSpace(filter.LastRetryStatus)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved, occurred_at
+		FROM webhook_action_failures
+		WHERE tenant_id = ?
+		  AND is_resolved = FALSE
+		  AND (? = '' OR event_type = ?)
+		  AND (? = '' OR suggestion_type = ?)
+		  AND (? = '' OR repository_full_name = ?)
+		  AND (? = '' OR last_retry_status = ?)
+		ORDER BY occurred_at ASC
+		LIMIT ?
+	`, tenantID, eventType, eventType, suggestionType, suggestionType, repository, repository, status, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query action failures by filter: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ActionExecutionFailureRecord, 0, limit)
+	for rows.Next() {
+		var rec ActionExecutionFailureRecord
+		var lastRetryAt sql.NullTime
+		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan action failure: %w", err)
+		}
+		normalizeLastRetryAt(&rec, lastRetryAt)
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate action failures: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) MarkActionFailureDead(ctx context.Context, id int64, message string) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_action_failures
+		SET last_retry_status = 'dead',
+		    last_retry_message = ?,
+		    last_retry_at = CURRENT_TIMESTAMP(6)
+		WHERE id = ?
+		  AND tenant_id = ?
+	`, strings.TrimSpace(message), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("mark action failure dead: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("mark action failure dead rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("action failure not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) GetTenantAutoRetryEnabled(ctx context.Context) (bool, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var enabled bool
+	err := s.db.QueryRowContext(ctx, `SELECT auto_retry_enabled FROM tenants WHERE id = ?`, tenantID).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("tenant not found")
+		}
+		return false, fmt.Errorf("get tenant auto retry: %w", err)
+	}
+	return enabled, nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateTenantAutoRetryEnabled(ctx context.Context, enabled bool) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE tenants
+		SET auto_retry_enabled = ?
+		WHERE id = ?
+	`, enabled, tenantID)
+	if err != nil {
+		return fmt.Errorf("update tenant auto retry: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update tenant auto retry rows affected: %w", err)
+	}
+	if affected == 0 {
+		if _, err := s.GetTenantAutoRetryEnabled(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventReprocessFilter selects stored webhook events for bulk reprocessing.
+// Empty fields and zero times are not filtered on.
+type EventReprocessFilter struct {
+	EventType          string    `json:"event_type"`
+	Action             string    `json:"action"`
+	RepositoryFullName string    `json:"repository_full_name"`
+	Source             string    `json:"source"`
+	Since              time.Time `json:"since"`
+	Until              time.Time `json:"until"`
+}
+
+func (f EventReprocessFilter) normalized() EventReprocessFilter {
+	f.EventType = strings.TrimSpace(f.EventType)
+	f.Action = strings.TrimSpace(f.Action)
+	f.RepositoryFullName = strings.TrimSpace(f.RepositoryFullName)
+	f.Source = strings.ToLower(strings.TrimSpace(f.Source))
+	if f.Since.IsZero() {
+		f.Since = time.Unix(0, 0)
+	}
+	if f.Until.IsZero() {
+		f.Until = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	f.Since = f.Since.UTC()
+	f.Until = f.Until.UTC()
+	return f
+}
+
+func (s *WebhookEventStore) GetWebhookEventByDeliveryID(ctx context.Context, deliveryID string) (WebhookEventRecord, error) {
+	var item WebhookEventRecord
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
+		FROM webhook_events
+		WHERE tenant_id = $1 AND delivery_id = $2
+	`, tenantIDFromCtx(ctx), strings.TrimSpace(deliveryID)).Scan(
+		&item.ID, &item.DeliveryID, &item.EventType, &item.Action, &item.RepositoryFullName, &item.SenderLogin, &item.Source, &item.PayloadJSON, &item.ReceivedAt,
+	)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return WebhookEventRecord{}, fmt.Errorf("webhook event not found")
+		}
+		return WebhookEventRecord{}, fmt.Errorf("get webhook event by delivery id: %w", err)
+	}
+	return item, nil
+}
+
+// ListEventsForReprocess returns matching events oldest first, so they replay in
+// the order they were received.
+func (s *WebhookEventStore) ListEventsForReprocess(ctx context.Context, filter EventReprocessFilter, limit int) ([]WebhookEventRecord, error) {
+	f := filter.normalized()
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
+		FROM webhook_events
+		WHERE tenant_id = $1
+		  AND ($2 = '' OR event_type = $2)
+		  AND ($3 = '' OR action = $3)
+		  AND ($4 = '' OR repository_full_name = $4)
+		  AND ($5 = '' OR source = $5)
+		  AND received_at >= $6 AND received_at < $7
+		ORDER BY received_at ASC, id ASC
+		LIMIT $8
+	`, tenantIDFromCtx(ctx), f.EventType, f.Action, f.RepositoryFullName, f.Source, f.Since, f.Until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook events for reprocess: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WebhookEventRecord, 0, limit)
+	for rows.Next() {
+		var item WebhookEventRecord
+		if err := rows.Scan(&item.ID, &item.DeliveryID, &item.EventType, &item.Action, &item.RepositoryFullName, &item.SenderLogin, &item.Source, &item.PayloadJSON, &item.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook event: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook events: %w", err)
+	}
+	return items, nil
+}
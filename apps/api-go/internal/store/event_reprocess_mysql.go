@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (s *MySQLWebhookEventStore) GetWebhookEventByDeliveryID(ctx context.Context, deliveryID string) (WebhookEventRecord, error) {
+	var item WebhookEventRecord
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
+		FROM webhook_events
+		WHERE tenant_id = ? AND delivery_id = ?
+	`, tenantIDFromCtxMySQL(ctx), strings.TrimSpace(deliveryID)).Scan(
+		&item.ID, &item.DeliveryID, &item.EventType, &item.Action, &item.RepositoryFullName, &item.SenderLogin, &item.Source, &item.PayloadJSON, &item.ReceivedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookEventRecord{}, fmt.Errorf("webhook event not found")
+		}
+		return WebhookEventRecord{}, fmt.Errorf("get webhook event by delivery id: %w", err)
+	}
+	return item, nil
+}
+
+func (s *MySQLWebhookEventStore) ListEventsForReprocess(ctx context.Context, filter EventReprocessFilter, limit int) ([]WebhookEventRecord, error) {
+	f := filter.normalized()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
+		FROM webhook_events
+		WHERE tenant_id = ?
+		  AND (? = '' OR event_type = ?)
+		  AND (? = '' OR action = ?)
+		  AND (? = '' OR repository_full_name = ?)
+		  AND (? = '' OR source = ?)
+		  AND received_at >= ? AND received_at < ?
+		ORDER BY received_at ASC, id ASC
+		LIMIT ?
+	`, tenantIDFromCtxMySQL(ctx), f.EventType, f.EventType, f.Action, f.Action, f.RepositoryFullName, f.RepositoryFullName, f.Source, f.Source, f.Since, f.Until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook events for reprocess: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WebhookEventRecord, 0, limit)
+	for rows.Next() {
+		var item WebhookEventRecord
+		if err := rows.Scan(&item.ID, &item.DeliveryID, &item.EventType, &item.Action, &item.RepositoryFullName, &item.SenderLogin, &item.Source, &item.PayloadJSON, &item.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook event row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook events: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GitHubEventSourceRecord is a repository or organisation whose events feed a
+// tenant polls. ETag and LastEventID are the cursor: an unchanged feed is
+// skipped by ETag, and only events newer than LastEventID are ingested.
+// EvaluateRules runs the source's new events through rules and alerts; it is
+// meant for repositories that do not also deliver webhooks.
+type GitHubEventSourceRecord struct {
+	ID            int64     `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	Kind          string    `json:"kind"`
+	Name          string    `json:"name"`
+	EvaluateRules bool      `json:"evaluate_rules"`
+	ETag          string    `json:"etag"`
+	LastEventID   int64     `json:"last_event_id"`
+	LastPolledAt  time.Time `json:"last_polled_at"`
+	LastStatus    string    `json:"last_status"`
+	LastMessage   string    `json:"last_message"`
+	IsActive      bool      `json:"is_active"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (s *WebhookEventStore) ensureGitHubEventSourcesSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS github_event_sources (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			name TEXT NOT NULL,
+			evaluate_rules BOOLEAN NOT NULL DEFAULT FALSE,
+			etag TEXT NOT NULL DEFAULT '',
+			last_event_id BIGINT NOT NULL DEFAULT 0,
+			last_polled_at TIMESTAMPTZ NULL,
+			last_status TEXT NOT NULL DEFAULT 'never',
+			last_message TEXT NOT NULL DEFAULT '',
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (tenant_id, kind, name)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create github_event_sources table: %w", err)
+	}
+	return nil
+}
+
+const githubEventSourceColumns = `id, tenant_id, kind, name, evaluate_rules, etag, last_event_id, COALESCE(last_polled_at, 'epoch'::timestamptz), last_status, last_message, is_active, created_by, created_at`
+
+type githubEventSourceScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGitHubEventSource(row githubEventSourceScanner) (GitHubEventSourceRecord, error) {
+	var item GitHubEventSourceRecord
+	err := row.Scan(&item.ID, &item.TenantID, &item.Kind, &item.Name, &item.EvaluateRules, &item.ETag, &item.LastEventID, &item.LastPolledAt,
+		&item.LastStatus, &item.LastMessage, &item.IsActive, &item.CreatedBy, &item.CreatedAt)
+	if item.LastPolledAt.Unix() == 0 {
+		item.LastPolledAt = time.Time{}
+	}
+	return item, err
+}
+
+func (s *WebhookEventStore) CreateGitHubEventSource(ctx context.Context, item GitHubEventSourceRecord) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO github_event_sources (tenant_id, kind, name, evaluate_rules, is_active, created_by)
+		VALUES ($1, $2, $3, $4, TRUE, $5)
+		RETURNING id
+	`, tenantIDFromCtx(ctx), item.Kind, strings.TrimSpace(item.Name), item.EvaluateRules, strings.TrimSpace(item.CreatedBy)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("create github event source: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) ListGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+githubEventSourceColumns+` FROM github_event_sources WHERE tenant_id = $1 ORDER BY id ASC
+	`, tenantIDFromCtx(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query github event sources: %w", err)
+	}
+	defer rows.Close()
+
+	items := []GitHubEventSourceRecord{}
+	for rows.Next() {
+		item, err := scanGitHubEventSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan github event source: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github event sources: %w", err)
+	}
+	return items, nil
+}
+
+// ListActiveGitHubEventSources spans all active tenants; callers scope each
+// source by its TenantID.
+func (s *WebhookEventStore) ListActiveGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+githubEventSourceColumns+`
+		FROM github_event_sources
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active github event sources: %w", err)
+	}
+	defer rows.Close()
+
+	items := []GitHubEventSourceRecord{}
+	for rows.Next() {
+		item, err := scanGitHubEventSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan github event source: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github event sources: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) DeleteGitHubEventSource(ctx context.Context, id int64) error {
+	result, err := s.pool.Exec(ctx, `DELETE FROM github_event_sources WHERE id = $1 AND tenant_id = $2`, id, tenantIDFromCtx(ctx))
+	if err != nil {
+		return fmt.Errorf("delete github event source: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("github event source not found")
+	}
+	return nil
+}
+
+// RecordGitHubEventSourcePoll stores a poll's outcome and cursor. The event id
+// never moves backwards; an empty etag forces the next poll to fetch in full.
+func (s *WebhookEventStore) RecordGitHubEventSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE github_event_sources
+		SET etag = $3, last_event_id = GREATEST(last_event_id, $4), last_polled_at = NOW(), last_status = $5, last_message = $6
+		WHERE id = $1 AND tenant_id = $2
+	`, id, tenantIDFromCtx(ctx), etag, lastEventID, status, message)
+	if err != nil {
+		return fmt.Errorf("record github event source poll: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+var mysqlGitHubEventSourcesSchema = []string{
+	`CREATE TABLE IF NOT EXISTS github_event_sources (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		kind VARCHAR(16) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		evaluate_rules BOOLEAN NOT NULL DEFAULT FALSE,
+		etag VARCHAR(255) NOT NULL DEFAULT '',
+		last_event_id BIGINT NOT NULL DEFAULT 0,
+		last_polled_at DATETIME(6) NULL,
+		last_status VARCHAR(32) NOT NULL DEFAULT 'never',
+		last_message TEXT NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by VARCHAR(191) NOT NULL DEFAULT '',
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		UNIQUE KEY uq_github_event_sources (tenant_id, kind, name)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+const mysqlGitHubEventSourceColumns = `id, tenant_id, kind, name, evaluate_rules, etag, last_event_id, last_polled_at, last_status, last_message, is_active, created_by, created_at`
+
+func scanMySQLGitHubEventSource(row githubEventSourceScanner) (GitHubEventSourceRecord, error) {
+	var item GitHubEventSourceRecord
+	var lastPolledAt sql.NullTime
+	err := row.Scan(&item.ID, &item.TenantID, &item.Kind, &item.Name, &item.EvaluateRules, &item.ETag, &item.LastEventID, &lastPolledAt,
+		&item.LastStatus, &item.LastMessage, &item.IsActive, &item.CreatedBy, &item.CreatedAt)
+	if lastPolledAt.Valid {
+		item.LastPolledAt = lastPolledAt.Time
+	}
+	return item, err
+}
+
+func (s *MySQLWebhookEventStore) CreateGitHubEventSource(ctx context.Context, item GitHubEventSourceRecord) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO github_event_sources (tenant_id, kind, name, evaluate_rules, last_message, is_active, created_by)
+		VALUES (?, ?, ?, ?, '', TRUE, ?)
+	`, tenantIDFromCtxMySQL(ctx), item.Kind, strings.TrimSpace(item.Name), item.EvaluateRules, strings.TrimSpace(item.CreatedBy))
+	if err != nil {
+		return 0, fmt.Errorf("create github event source: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get github event source id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) ListGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+mysqlGitHubEventSourceColumns+` FROM github_event_sources WHERE tenant_id = ? ORDER BY id ASC
+	`, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query github event sources: %w", err)
+	}
+	defer rows.Close()
+
+	items := []GitHubEventSourceRecord{}
+	for rows.Next() {
+		item, err := scanMySQLGitHubEventSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan github event source: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github event sources: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ListActiveGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+mysqlGitHubEventSourceColumns+`
+		FROM github_event_sources
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active github event sources: %w", err)
+	}
+	defer rows.Close()
+
+	items := []GitHubEventSourceRecord{}
+	for rows.Next() {
+		item, err := scanMySQLGitHubEventSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan github event source: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github event sources: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) DeleteGitHubEventSource(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM github_event_sources WHERE id = ? AND tenant_id = ?`, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("delete github event source: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for github event source delete: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("github event source not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) RecordGitHubEventSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE github_event_sources
+		SET etag = ?, last_event_id = GREATEST(last_event_id, ?), last_polled_at = CURRENT_TIMESTAMP(6), last_status = ?, last_message = ?
+		WHERE id = ? AND tenant_id = ?
+	`, etag, lastEventID, status, message, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("record github event source poll: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetTenantGitHubInstallationID returns the GitHub App installation mapped to the
+// current tenant, or 0 when the tenant uses the static token.
+func (s *WebhookEventStore) GetTenantGitHubInstallationID(ctx context.Context) (int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var installationID int64
+	err := s.pool.QueryRow(ctx, `SELECT COALESCE(github_installation_id, 0) FROM tenants WHERE id = $1`, tenantID).Scan(&installationID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return 0, fmt.Errorf("tenant not found")
+		}
+		return 0, fmt.Errorf("get tenant github installation: %w", err)
+	}
+	return installationID, nil
+}
+
+func (s *WebhookEventStore) UpdateTenantGitHubInstallationID(ctx context.Context, tenantID string, installationID int64) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE tenants
+		SET github_installation_id = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, strings.TrimSpace(tenantID), installationID)
+	if err != nil {
+		return fmt.Errorf("update tenant github installation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+	return nil
+}
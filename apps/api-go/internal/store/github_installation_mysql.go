@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (s *MySQLWebhookEventStore) GetTenantGitHubInstallationID(ctx context.Context) (int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var installationID int64
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(github_installation_id, 0) FROM tenants WHERE id = ?`, tenantID).Scan(&installationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("tenant not found")
+		}
+		return 0, fmt.Errorf("get tenant github installation: %w", err)
+	}
+	return installationID, nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateTenantGitHubInstallationID(ctx context.Context, tenantID string, installationID int64) error {
+	tenantID = strings.TrimSpace(tenantID)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE tenants
+		SET github_installation_id = ?, updated_at = CURRENT_TIMESTAMP(6)
+		WHERE id = ?
+	`, installationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("update tenant github installation: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for tenant github installation update: %w", err)
+	}
+	if affected == 0 {
+		var exists int
+		if err := s.db.QueryRowContext(ctx, `SELECT 1 FROM tenants WHERE id = ?`, tenantID).Scan(&exists); err != nil {
+			return fmt.Errorf("tenant not found")
+		}
+	}
+	return nil
+}
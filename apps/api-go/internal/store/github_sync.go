@@ -0,0 +1,341 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GitHubSyncScheduleRecord is a tenant's GitHub event sync schedule. There is at
+// most one per tenant.
+type GitHubSyncScheduleRecord struct {
+	TenantID        string     `json:"tenant_id"`
+	IntervalMinutes int        `json:"interval_minutes"`
+	IsPaused        bool       `json:"is_paused"`
+	NextRunAt       time.Time  `json:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	UpdatedBy       string     `json:"updated_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// GitHubSyncRunRecord is one sync run. FinishedAt is nil while it is running.
+type GitHubSyncRunRecord struct {
+	ID           int64      `json:"id"`
+	TenantID     string     `json:"tenant_id"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	Saved        int        `json:"saved"`
+	Total        int        `json:"total"`
+	ErrorMessage string     `json:"error_message"`
+	Actor        string     `json:"actor"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// GitHubSyncHealthRecord is an active, unpaused schedule with its latest
+// finished run. LastStatus is empty when the schedule has not run yet.
+type GitHubSyncHealthRecord struct {
+	TenantID      string
+	NextRunAt     time.Time
+	LastStatus    string
+	LastError     string
+	LastStartedAt *time.Time
+}
+
+const githubSyncScheduleColumns = `tenant_id, interval_minutes, is_paused, next_run_at, last_run_at, updated_by, created_at, updated_at`
+
+const githubSyncRunColumns = `id, tenant_id, trigger_type, status, saved, total, error_message, actor, started_at, finished_at`
+
+type githubSyncScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGitHubSyncSchedule(row githubSyncScanner) (GitHubSyncScheduleRecord, error) {
+	var rec GitHubSyncScheduleRecord
+	var lastRunAt *time.Time
+	if err := row.Scan(&rec.TenantID, &rec.IntervalMinutes, &rec.IsPaused, &rec.NextRunAt, &lastRunAt, &rec.UpdatedBy, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return rec, err
+	}
+	if lastRunAt != nil {
+		ts := lastRunAt.UTC()
+		rec.LastRunAt = &ts
+	}
+	return rec, nil
+}
+
+func scanGitHubSyncRun(row githubSyncScanner) (GitHubSyncRunRecord, error) {
+	var rec GitHubSyncRunRecord
+	var finishedAt *time.Time
+	if err := row.Scan(&rec.ID, &rec.TenantID, &rec.Trigger, &rec.Status, &rec.Saved, &rec.Total, &rec.ErrorMessage, &rec.Actor, &rec.StartedAt, &finishedAt); err != nil {
+		return rec, err
+	}
+	if finishedAt != nil {
+		ts := finishedAt.UTC()
+		rec.FinishedAt = &ts
+	}
+	return rec, nil
+}
+
+func (s *WebhookEventStore) ensureGitHubSyncSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS github_sync_schedules (
+			tenant_id TEXT PRIMARY KEY,
+			interval_minutes INT NOT NULL,
+			is_paused BOOLEAN NOT NULL DEFAULT FALSE,
+			next_run_at TIMESTAMPTZ NOT NULL,
+			last_run_at TIMESTAMPTZ NULL,
+			updated_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create github_sync_schedules table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS github_sync_runs (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			trigger_type TEXT NOT NULL,
+			status TEXT NOT NULL,
+			saved INT NOT NULL DEFAULT 0,
+			total INT NOT NULL DEFAULT 0,
+			error_message TEXT NOT NULL DEFAULT '',
+			actor TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMPTZ NOT NULL,
+			finished_at TIMESTAMPTZ NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create github_sync_runs table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_github_sync_runs_tenant
+		ON github_sync_runs (tenant_id, started_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_github_sync_runs_tenant: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) GetGitHubSyncSchedule(ctx context.Context) (GitHubSyncScheduleRecord, error) {
+	rec, err := scanGitHubSyncSchedule(s.pool.QueryRow(ctx, `
+		SELECT `+githubSyncScheduleColumns+` FROM github_sync_schedules WHERE tenant_id = $1
+	`, tenantIDFromCtx(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rec, fmt.Errorf("github sync schedule not found")
+		}
+		return rec, fmt.Errorf("get github sync schedule: %w", err)
+	}
+	return rec, nil
+}
+
+// UpsertGitHubSyncSchedule creates or replaces the tenant's schedule.
+func (s *WebhookEventStore) UpsertGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO github_sync_schedules (tenant_id, interval_minutes, is_paused, next_run_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET interval_minutes = EXCLUDED.interval_minutes,
+		    is_paused = EXCLUDED.is_paused,
+		    next_run_at = EXCLUDED.next_run_at,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+	`, tenantIDFromCtx(ctx), item.IntervalMinutes, item.IsPaused, item.NextRunAt.UTC(), strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("upsert github sync schedule: %w", err)
+	}
+	return nil
+}
+
+// EnsureGitHubSyncSchedule creates the tenant's schedule unless one exists.
+func (s *WebhookEventStore) EnsureGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO github_sync_schedules (tenant_id, interval_minutes, is_paused, next_run_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO NOTHING
+	`, tenantIDFromCtx(ctx), item.IntervalMinutes, item.IsPaused, item.NextRunAt.UTC(), strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("ensure github sync schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) UpdateGitHubSyncSchedulePaused(ctx context.Context, isPaused bool, nextRunAt time.Time, updatedBy string) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE github_sync_schedules
+		SET is_paused = $2,
+		    next_run_at = $3,
+		    updated_by = $4,
+		    updated_at = NOW()
+		WHERE tenant_id = $1
+	`, tenantIDFromCtx(ctx), isPaused, nextRunAt.UTC(), strings.TrimSpace(updatedBy))
+	if err != nil {
+		return fmt.Errorf("update github sync schedule paused: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("github sync schedule not found")
+	}
+	return nil
+}
+
+// ListDueGitHubSyncSchedules spans all active tenants; callers scope each
+// schedule by its TenantID.
+func (s *WebhookEventStore) ListDueGitHubSyncSchedules(ctx context.Context, now time.Time, limit int) ([]GitHubSyncScheduleRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+githubSyncScheduleColumns+`
+		FROM github_sync_schedules
+		WHERE is_paused = FALSE
+		  AND next_run_at <= $1
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY next_run_at ASC
+		LIMIT $2
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due github sync schedules: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncScheduleRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanGitHubSyncSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan due github sync schedule: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due github sync schedules: %w", err)
+	}
+	return items, nil
+}
+
+// ClaimGitHubSyncSchedule advances next_run_at only if it still equals
+// expectedNextRunAt, so each activation runs at most once.
+func (s *WebhookEventStore) ClaimGitHubSyncSchedule(ctx context.Context, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error) {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE github_sync_schedules
+		SET last_run_at = NOW(),
+		    next_run_at = $3
+		WHERE tenant_id = $1
+		  AND next_run_at = $2
+	`, tenantIDFromCtx(ctx), expectedNextRunAt.UTC(), nextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("claim github sync schedule: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (s *WebhookEventStore) CreateGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO github_sync_runs (tenant_id, trigger_type, status, actor, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, tenantIDFromCtx(ctx), strings.TrimSpace(run.Trigger), strings.TrimSpace(run.Status), strings.TrimSpace(run.Actor), run.StartedAt.UTC()).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert github sync run: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) FinishGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) error {
+	finishedAt := time.Now().UTC()
+	if run.FinishedAt != nil {
+		finishedAt = run.FinishedAt.UTC()
+	}
+	_, err := s.pool.Exec(ctx, `
+		UPDATE github_sync_runs
+		SET status = $3, saved = $4, total = $5, error_message = $6, finished_at = $7
+		WHERE id = $1
+		  AND tenant_id = $2
+	`, run.ID, tenantIDFromCtx(ctx), strings.TrimSpace(run.Status), run.Saved, run.Total, strings.TrimSpace(run.ErrorMessage), finishedAt)
+	if err != nil {
+		return fmt.Errorf("finish github sync run: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) ListGitHubSyncRuns(ctx context.Context, limit int, offset int) ([]GitHubSyncRunRecord, int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM github_sync_runs WHERE tenant_id = $1`, tenantID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count github sync runs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+githubSyncRunColumns+`
+		FROM github_sync_runs
+		WHERE tenant_id = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query github sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncRunRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanGitHubSyncRun(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan github sync run: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate github sync runs: %w", err)
+	}
+	return items, total, nil
+}
+
+// ListGitHubSyncHealth spans all active tenants with an unpaused schedule.
+func (s *WebhookEventStore) ListGitHubSyncHealth(ctx context.Context) ([]GitHubSyncHealthRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT s.tenant_id, s.next_run_at, COALESCE(r.status, ''), COALESCE(r.error_message, ''), r.started_at
+		FROM github_sync_schedules s
+		JOIN tenants t ON t.id = s.tenant_id
+		LEFT JOIN github_sync_runs r ON r.id = (
+			SELECT id
+			FROM github_sync_runs
+			WHERE tenant_id = s.tenant_id
+			  AND finished_at IS NOT NULL
+			ORDER BY started_at DESC, id DESC
+			LIMIT 1
+		)
+		WHERE t.is_active = TRUE
+		  AND s.is_paused = FALSE
+		ORDER BY s.tenant_id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query github sync health: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncHealthRecord, 0)
+	for rows.Next() {
+		var rec GitHubSyncHealthRecord
+		var startedAt *time.Time
+		if err := rows.Scan(&rec.TenantID, &rec.NextRunAt, &rec.LastStatus, &rec.LastError, &startedAt); err != nil {
+			return nil, fmt.Errorf("scan github sync health: %w", err)
+		}
+		if startedAt != nil {
+			ts := startedAt.UTC()
+			rec.LastStartedAt = &ts
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github sync health: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,277 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var mysqlGitHubSyncSchema = []string{
+	`CREATE TABLE IF NOT EXISTS github_sync_schedules (
+		tenant_id VARCHAR(64) NOT NULL PRIMARY KEY,
+		interval_minutes INT NOT NULL,
+		is_paused BOOLEAN NOT NULL DEFAULT FALSE,
+		next_run_at DATETIME(6) NOT NULL,
+		last_run_at DATETIME(6) NULL,
+		updated_by VARCHAR(191) NOT NULL DEFAULT '',
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+	`CREATE TABLE IF NOT EXISTS github_sync_runs (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		trigger_type VARCHAR(32) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		saved INT NOT NULL DEFAULT 0,
+		total INT NOT NULL DEFAULT 0,
+		error_message TEXT NOT NULL,
+		actor VARCHAR(191) NOT NULL DEFAULT '',
+		started_at DATETIME(6) NOT NULL,
+		finished_at DATETIME(6) NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_github_sync_runs_tenant ON github_sync_runs (tenant_id, started_at)`,
+}
+
+func scanMySQLGitHubSyncSchedule(row githubSyncScanner) (GitHubSyncScheduleRecord, error) {
+	var rec GitHubSyncScheduleRecord
+	var lastRunAt sql.NullTime
+	if err := row.Scan(&rec.TenantID, &rec.IntervalMinutes, &rec.IsPaused, &rec.NextRunAt, &lastRunAt, &rec.UpdatedBy, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return rec, err
+	}
+	if lastRunAt.Valid {
+		ts := lastRunAt.Time.UTC()
+		rec.LastRunAt = &ts
+	}
+	return rec, nil
+}
+
+func scanMySQLGitHubSyncRun(row githubSyncScanner) (GitHubSyncRunRecord, error) {
+	var rec GitHubSyncRunRecord
+	var finishedAt sql.NullTime
+	if err := row.Scan(&rec.ID, &rec.TenantID, &rec.Trigger, &rec.Status, &rec.Saved, &rec.Total, &rec.ErrorMessage, &rec.Actor, &rec.StartedAt, &finishedAt); err != nil {
+		return rec, err
+	}
+	if finishedAt.Valid {
+		ts := finishedAt.Time.UTC()
+		rec.FinishedAt = &ts
+	}
+	return rec, nil
+}
+
+func (s *MySQLWebhookEventStore) GetGitHubSyncSchedule(ctx context.Context) (GitHubSyncScheduleRecord, error) {
+	rec, err := scanMySQLGitHubSyncSchedule(s.db.QueryRowContext(ctx, `
+		SELECT `+githubSyncScheduleColumns+` FROM github_sync_schedules WHERE tenant_id = ?
+	`, tenantIDFromCtxMySQL(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rec, fmt.Errorf("github sync schedule not found")
+		}
+		return rec, fmt.Errorf("get github sync schedule: %w", err)
+	}
+	return rec, nil
+}
+
+func (s *MySQLWebhookEventStore) UpsertGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO github_sync_schedules (tenant_id, interval_minutes, is_paused, next_run_at, updated_by)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			interval_minutes = VALUES(interval_minutes),
+			is_paused = VALUES(is_paused),
+			next_run_at = VALUES(next_run_at),
+			updated_by = VALUES(updated_by)
+	`, tenantIDFromCtxMySQL(ctx), item.IntervalMinutes, item.IsPaused, item.NextRunAt.UTC(), strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("upsert github sync schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) EnsureGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT IGNORE INTO github_sync_schedules (tenant_id, interval_minutes, is_paused, next_run_at, updated_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, tenantIDFromCtxMySQL(ctx), item.IntervalMinutes, item.IsPaused, item.NextRunAt.UTC(), strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("ensure github sync schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateGitHubSyncSchedulePaused(ctx context.Context, isPaused bool, nextRunAt time.Time, updatedBy string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE github_sync_schedules
+		SET is_paused = ?,
+		    next_run_at = ?,
+		    updated_by = ?
+		WHERE tenant_id = ?
+	`, isPaused, nextRunAt.UTC(), strings.TrimSpace(updatedBy), tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("update github sync schedule paused: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update github sync schedule paused rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("github sync schedule not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ListDueGitHubSyncSchedules(ctx context.Context, now time.Time, limit int) ([]GitHubSyncScheduleRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+githubSyncScheduleColumns+`
+		FROM github_sync_schedules
+		WHERE is_paused = FALSE
+		  AND next_run_at <= ?
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY next_run_at ASC
+		LIMIT ?
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due github sync schedules: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncScheduleRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanMySQLGitHubSyncSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan due github sync schedule: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due github sync schedules: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ClaimGitHubSyncSchedule(ctx context.Context, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE github_sync_schedules
+		SET last_run_at = CURRENT_TIMESTAMP(6),
+		    next_run_at = ?
+		WHERE tenant_id = ?
+		  AND next_run_at = ?
+	`, nextRunAt.UTC(), tenantIDFromCtxMySQL(ctx), expectedNextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("claim github sync schedule: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim github sync schedule rows affected: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (s *MySQLWebhookEventStore) CreateGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO github_sync_runs (tenant_id, trigger_type, status, error_message, actor, started_at)
+		VALUES (?, ?, ?, '', ?, ?)
+	`, tenantIDFromCtxMySQL(ctx), strings.TrimSpace(run.Trigger), strings.TrimSpace(run.Status), strings.TrimSpace(run.Actor), run.StartedAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("insert github sync run: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get github sync run id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) FinishGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) error {
+	finishedAt := time.Now().UTC()
+	if run.FinishedAt != nil {
+		finishedAt = run.FinishedAt.UTC()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE github_sync_runs
+		SET status = ?, saved = ?, total = ?, error_message = ?, finished_at = ?
+		WHERE id = ?
+		  AND tenant_id = ?
+	`, strings.TrimSpace(run.Status), run.Saved, run.Total, strings.TrimSpace(run.ErrorMessage), finishedAt, run.ID, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("finish github sync run: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ListGitHubSyncRuns(ctx context.Context, limit int, offset int) ([]GitHubSyncRunRecord, int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM github_sync_runs WHERE tenant_id = ?`, tenantID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count github sync runs: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+githubSyncRunColumns+`
+		FROM github_sync_runs
+		WHERE tenant_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query github sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncRunRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanMySQLGitHubSyncRun(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan github sync run: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate github sync runs: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *MySQLWebhookEventStore) ListGitHubSyncHealth(ctx context.Context) ([]GitHubSyncHealthRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.tenant_id, s.next_run_at, COALESCE(r.status, ''), COALESCE(r.error_message, ''), r.started_at
+		FROM github_sync_schedules s
+		JOIN tenants t ON t.id = s.tenant_id
+		LEFT JOIN github_sync_runs r ON r.id = (
+			SELECT id
+			FROM github_sync_runs
+			WHERE tenant_id = s.tenant_id
+			  AND finished_at IS NOT NULL
+			ORDER BY started_at DESC, id DESC
+			LIMIT 1
+		)
+		WHERE t.is_active = TRUE
+		  AND s.is_paused = FALSE
+		ORDER BY s.tenant_id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query github sync health: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]GitHubSyncHealthRecord, 0)
+	for rows.Next() {
+		var rec GitHubSyncHealthRecord
+		var startedAt sql.NullTime
+		if err := rows.Scan(&rec.TenantID, &rec.NextRunAt, &rec.LastStatus, &rec.LastError, &startedAt); err != nil {
+			return nil, fmt.Errorf("scan github sync health: %w", err)
+		}
+		if startedAt.Valid {
+			ts := startedAt.Time.UTC()
+			rec.LastStartedAt = &ts
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate github sync health: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// requiredSchemaColumns lists, per table, the columns schema setup adds to
+// existing tables and one column of each table added since. Setup ignores
+// ALTER TABLE errors, so these are what an upgraded database can be missing.
+var requiredSchemaColumns = map[string][]string{
+	"admin_users":              {"tenant_id", "role", "permissions", "last_login_at", "updated_at"},
+	"audit_logs":               {"tenant_id"},
+	"github_event_sources":     {"evaluate_rules"},
+	"github_sync_runs":         {"status", "finished_at"},
+	"repositories":             {"full_name"},
+	"scheduled_job_items":      {"item_number"},
+	"scheduled_jobs":           {"inactive_by"},
+	"tenant_credentials":       {"expires_at"},
+	"tenants":                  {"auto_retry_enabled", "github_installation_id", "webhook_signature_schemes"},
+	"webhook_action_failures":  {"tenant_id", "source", "retry_count", "last_retry_status", "last_retry_message", "last_retry_at", "is_resolved"},
+	"webhook_alerts":           {"tenant_id"},
+	"webhook_delivery_metrics": {"tenant_id", "outcome", "matched_secret"},
+	"webhook_events":           {"tenant_id", "source"},
+	"webhook_recovery_hooks":   {"scan_cursor", "scan_top_delivery_id"},
+	"webhook_routes":           {"match_value"},
+	"webhook_rule_versions":    {"tenant_id"},
+	"webhook_rules":            {"tenant_id"},
+	"worker_leases":            {"holder"},
+}
+
+func requiredSchemaTables() []string {
+	tables := make([]string, 0, len(requiredSchemaColumns))
+	for table := range requiredSchemaColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// missingSchemaColumns compares the columns found in the database against
+// requiredSchemaColumns and returns an error naming any that are missing.
+func missingSchemaColumns(found map[string]bool) error {
+	var missing []string
+	for _, table := range requiredSchemaTables() {
+		for _, column := range requiredSchemaColumns[table] {
+			if !found[table+"."+column] {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schema is missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// DBStatsCollector exports the pgx connection pool statistics.
+func (s *WebhookEventStore) DBStatsCollector() prometheus.Collector {
+	return newPgxPoolCollector(s.pool.Stat)
+}
+
+func (s *WebhookEventStore) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+// CheckSchema confirms the tables and columns this build depends on exist.
+func (s *WebhookEventStore) CheckSchema(ctx context.Context) error {
+	rows, err := s.pool.Query(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+		  AND table_name = ANY($1)
+	`, requiredSchemaTables())
+	if err != nil {
+		return fmt.Errorf("query schema columns: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("scan schema column: %w", err)
+		}
+		found[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate schema columns: %w", err)
+	}
+	return missingSchemaColumns(found)
+}
+
+// CountActionRetryBacklog counts failed actions still waiting for an automatic
+// retry, across active tenants.
+func (s *WebhookEventStore) CountActionRetryBacklog(ctx context.Context) (int64, error) {
+	var total int64
+	err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM webhook_action_failures f
+		JOIN tenants t ON t.id = f.tenant_id
+		WHERE t.is_active = TRUE
+		  AND t.auto_retry_enabled = TRUE
+		  AND f.is_resolved = FALSE
+		  AND f.last_retry_status <> 'dead'
+	`).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("count action retry backlog: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+func (s *MySQLWebhookEventStore) DBStatsCollector() prometheus.Collector {
+	return collectors.NewDBStatsCollector(s.db, "mysql")
+}
+
+func (s *MySQLWebhookEventStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *MySQLWebhookEventStore) CheckSchema(ctx context.Context) error {
+	tables := requiredSchemaTables()
+	args := make([]any, 0, len(tables))
+	for _, table := range tables {
+		args = append(args, table)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE()
+		  AND table_name IN (?`+strings.Repeat(",?", len(tables)-1)+`)
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("query schema columns: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("scan schema column: %w", err)
+		}
+		found[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate schema columns: %w", err)
+	}
+	return missingSchemaColumns(found)
+}
+
+func (s *MySQLWebhookEventStore) CountActionRetryBacklog(ctx context.Context) (int64, error) {
+	var total int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM webhook_action_failures f
+		JOIN tenants t ON t.id = f.tenant_id
+		WHERE t.is_active = TRUE
+		  AND t.auto_retry_enabled = TRUE
+		  AND f.is_resolved = FALSE
+		  AND f.last_retry_status <> 'dead'
+	`).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("count action retry backlog: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,758 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Metrics rollups pre-aggregate webhook_events, webhook_alerts and
+// webhook_delivery_metrics per tenant and minute, and those minutes again per
+// hour, so the metrics endpoints read at most a few hundred rows per window
+// instead of every event. Compaction rebuilds recent minutes from the raw tables
+// and moves a watermark; reads use rollups up to the watermark and the raw
+// tables after it, so results stay exact apart from the latency percentile,
+// which is estimated from a histogram.
+//
+// Action failures are not rolled up: they are resolved after the fact, which
+// would invalidate already compacted buckets. They are few enough to aggregate
+// from webhook_action_failures directly.
+
+// LatencyBucketsMS are the upper bounds of the rollup latency histogram. Slower
+// deliveries fall into one more overflow bucket bounded by the observed maximum.
+var LatencyBucketsMS = []int64{
+	1, 2, 3, 4, 5, 6, 8, 10, 12, 15, 20, 25, 30, 40, 50, 60, 80, 100, 120, 150, 200, 250, 300, 400, 500,
+	600, 800, 1000, 1200, 1500, 2000, 2500, 3000, 4000, 5000, 6000, 8000, 10000, 12000, 15000, 20000,
+	25000, 30000, 40000, 50000, 60000,
+}
+
+const (
+	metricsRollupMinuteTable = "metrics_rollups_minute"
+	metricsRollupHourTable   = "metrics_rollups_hour"
+
+	// metricsRollupSettle keeps compaction away from minutes that may still
+	// receive rows, and metricsRollupLookback rebuilds a few already compacted
+	// minutes on every run to pick up rows that committed late.
+	metricsRollupSettle   = 2 * time.Minute
+	metricsRollupLookback = 10 * time.Minute
+	// A database without rollups is backfilled over the longest metrics window.
+	// Each span scans at most a day of raw rows in its own transaction.
+	metricsRollupBackfill    = 31 * 24 * time.Hour
+	metricsRollupSpan        = 24 * time.Hour
+	metricsRollupSpansPerRun = 8
+	metricsRollupInsertBatch = 200
+)
+
+type metricsRollup struct {
+	TenantID        string
+	BucketStart     time.Time
+	Events          int64
+	Alerts          int64
+	Deliveries      int64
+	DeliverySuccess int64
+	Misrouted       int64
+	ProcessingMSSum int64
+	ProcessingMSMax int64
+	Latency         []int64
+}
+
+func (r *metricsRollup) add(o metricsRollup) {
+	r.Events += o.Events
+	r.Alerts += o.Alerts
+	r.Deliveries += o.Deliveries
+	r.DeliverySuccess += o.DeliverySuccess
+	r.Misrouted += o.Misrouted
+	r.ProcessingMSSum += o.ProcessingMSSum
+	if o.ProcessingMSMax > r.ProcessingMSMax {
+		r.ProcessingMSMax = o.ProcessingMSMax
+	}
+	r.Latency = addLatencyHistograms(r.Latency, o.Latency)
+}
+
+// metricsRollupState is how far rollups reach: they are complete for
+// [CoveredFrom, CompactedUntil) in whole minutes, and in whole hours up to the
+// hour CompactedUntil falls in.
+type metricsRollupState struct {
+	CoveredFrom    time.Time
+	CompactedUntil time.Time
+}
+
+type metricsCount struct {
+	BucketStart time.Time
+	Count       int64
+}
+
+// metricsRollupBackend is what each database provides to compaction and reads.
+// An empty tenantID means all tenants; a zero upper bound means no upper bound.
+type metricsRollupBackend interface {
+	loadMetricsRollupState(ctx context.Context) (metricsRollupState, bool, error)
+	rawMetricsRollups(ctx context.Context, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error)
+	loadMetricsRollups(ctx context.Context, table string, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error)
+	replaceMetricsRollups(ctx context.Context, minutes metricsRollupRange, hours metricsRollupRange, state metricsRollupState) error
+	countUnresolvedFailuresByMinute(ctx context.Context, tenantID string, since time.Time) ([]metricsCount, error)
+}
+
+// metricsRollupRange replaces every row of a table in [From, To) with Rows.
+type metricsRollupRange struct {
+	From time.Time
+	To   time.Time
+	Rows []metricsRollup
+}
+
+// compactMetricsRollups advances the rollups towards now and returns how many
+// minute rows it wrote.
+func compactMetricsRollups(ctx context.Context, b metricsRollupBackend, now time.Time) (int, error) {
+	target := now.UTC().Add(-metricsRollupSettle).Truncate(time.Minute)
+	state, ok, err := b.loadMetricsRollupState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		start := target.Add(-metricsRollupBackfill).Truncate(time.Hour)
+		state = metricsRollupState{CoveredFrom: start, CompactedUntil: start}
+	}
+
+	written := 0
+	for i := 0; i < metricsRollupSpansPerRun; i++ {
+		from := state.CompactedUntil.Add(-metricsRollupLookback)
+		if from.Before(state.CoveredFrom) {
+			from = state.CoveredFrom
+		}
+		to := target
+		if to.Sub(from) > metricsRollupSpan {
+			to = from.Add(metricsRollupSpan)
+		}
+		if !to.After(state.CompactedUntil) {
+			break
+		}
+
+		minutes, err := b.rawMetricsRollups(ctx, "", from, to)
+		if err != nil {
+			return written, err
+		}
+		// Hours touched by this span are rebuilt from all of their minutes.
+		hourFrom, hourTo := from.Truncate(time.Hour), to.Truncate(time.Hour)
+		hours := metricsRollupRange{From: hourFrom, To: hourFrom}
+		if hourTo.After(hourFrom) {
+			earlier, err := b.loadMetricsRollups(ctx, metricsRollupMinuteTable, "", hourFrom, from)
+			if err != nil {
+				return written, err
+			}
+			hours.To = hourTo
+			hours.Rows = rollupMetricsByHour(append(earlier, minutes...), hourTo)
+		}
+
+		state.CompactedUntil = to
+		if err := b.replaceMetricsRollups(ctx, metricsRollupRange{From: from, To: to, Rows: minutes}, hours, state); err != nil {
+			return written, err
+		}
+		written += len(minutes)
+	}
+	return written, nil
+}
+
+func rollupMetricsByHour(minutes []metricsRollup, before time.Time) []metricsRollup {
+	type key struct {
+		tenantID string
+		hour     time.Time
+	}
+	byHour := map[key]*metricsRollup{}
+	for _, m := range minutes {
+		hour := m.BucketStart.UTC().Truncate(time.Hour)
+		if !hour.Before(before) {
+			continue
+		}
+		k := key{tenantID: m.TenantID, hour: hour}
+		r, ok := byHour[k]
+		if !ok {
+			r = &metricsRollup{TenantID: m.TenantID, BucketStart: hour}
+			byHour[k] = r
+		}
+		r.add(m)
+	}
+	out := make([]metricsRollup, 0, len(byHour))
+	for _, r := range byHour {
+		out = append(out, *r)
+	}
+	sortMetricsRollups(out)
+	return out
+}
+
+// mergeMetricsRollups folds rows that share a tenant and bucket, as the raw
+// delivery query returns one row per latency bucket.
+func mergeMetricsRollups(rows []metricsRollup) []metricsRollup {
+	type key struct {
+		tenantID string
+		bucket   time.Time
+	}
+	index := map[key]int{}
+	out := make([]metricsRollup, 0, len(rows))
+	for _, r := range rows {
+		r.BucketStart = r.BucketStart.UTC()
+		k := key{tenantID: r.TenantID, bucket: r.BucketStart}
+		if i, ok := index[k]; ok {
+			out[i].add(r)
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, r)
+	}
+	sortMetricsRollups(out)
+	return out
+}
+
+func sortMetricsRollups(rows []metricsRollup) {
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].BucketStart.Equal(rows[j].BucketStart) {
+			return rows[i].BucketStart.Before(rows[j].BucketStart)
+		}
+		return rows[i].TenantID < rows[j].TenantID
+	})
+}
+
+const (
+	metricsSourceRaw    = "raw"
+	metricsSourceMinute = metricsRollupMinuteTable
+	metricsSourceHour   = metricsRollupHourTable
+)
+
+type metricsRollupSegment struct {
+	Source string
+	From   time.Time
+	To     time.Time
+}
+
+// planMetricsRollupRead splits [since, now] into contiguous segments read from
+// the raw tables, minute rollups or, when useHours is set, hour rollups. The
+// last segment is always raw and open-ended.
+func planMetricsRollupRead(since time.Time, state metricsRollupState, ok bool, useHours bool) []metricsRollupSegment {
+	since = since.UTC()
+	segments := []metricsRollupSegment{}
+	push := func(source string, from time.Time, to time.Time) {
+		if to.After(from) {
+			segments = append(segments, metricsRollupSegment{Source: source, From: from, To: to})
+		}
+	}
+	end := state.CompactedUntil.UTC()
+	if !ok || !since.Before(end) {
+		return append(segments, metricsRollupSegment{Source: metricsSourceRaw, From: since})
+	}
+
+	cursor := since
+	if cursor.Before(state.CoveredFrom) {
+		push(metricsSourceRaw, cursor, state.CoveredFrom.UTC())
+		cursor = state.CoveredFrom.UTC()
+	}
+	if minute := ceilTime(cursor, time.Minute); minute.After(cursor) {
+		push(metricsSourceRaw, cursor, minTime(minute, end))
+		cursor = minTime(minute, end)
+	}
+	if hourEnd := end.Truncate(time.Hour); useHours && ceilTime(cursor, time.Hour).Before(hourEnd) {
+		hour := ceilTime(cursor, time.Hour)
+		push(metricsSourceMinute, cursor, hour)
+		push(metricsSourceHour, hour, hourEnd)
+		cursor = hourEnd
+	}
+	push(metricsSourceMinute, cursor, end)
+	return append(segments, metricsRollupSegment{Source: metricsSourceRaw, From: maxTime(cursor, end)})
+}
+
+// readMetricsRollups returns the tenant's rollup rows covering [since, now].
+func readMetricsRollups(ctx context.Context, b metricsRollupBackend, tenantID string, since time.Time, useHours bool) ([]metricsRollup, error) {
+	state, ok, err := b.loadMetricsRollupState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := []metricsRollup{}
+	for _, seg := range planMetricsRollupRead(since, state, ok, useHours) {
+		var part []metricsRollup
+		if seg.Source == metricsSourceRaw {
+			part, err = b.rawMetricsRollups(ctx, tenantID, seg.From, seg.To)
+		} else {
+			part, err = b.loadMetricsRollups(ctx, seg.Source, tenantID, seg.From, seg.To)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, part...)
+	}
+	return rows, nil
+}
+
+// applyMetricsRollups fills the delivery and volume figures of out. Failure
+// counts are left to the caller.
+func applyMetricsRollups(out *MetricsOverview, rows []metricsRollup) {
+	var total metricsRollup
+	for _, r := range rows {
+		total.add(r)
+	}
+	out.Events24h = total.Events
+	out.Alerts24h = total.Alerts
+	out.DeliveryAttempts = total.Deliveries
+	out.DeliverySuccess = total.DeliverySuccess
+	out.MisroutedDeliveries24h = total.Misrouted
+	if total.Deliveries > 0 {
+		out.SuccessRate24h = (float64(total.DeliverySuccess) / float64(total.Deliveries)) * 100
+		out.FailureRate24h = 100 - out.SuccessRate24h
+		out.EstimatedManualMinutes24h = float64(total.Deliveries-total.DeliverySuccess) * 2
+		if out.Events24h > 0 {
+			out.AutomationCoverage24h = (float64(total.Deliveries) / float64(out.Events24h)) * 100
+			if out.AutomationCoverage24h > 100 {
+				out.AutomationCoverage24h = 100
+			}
+		}
+		out.AvgProcessingMS24h = float64(total.ProcessingMSSum) / float64(total.Deliveries)
+		out.P95LatencyMS24h = latencyPercentile(total.Latency, total.ProcessingMSMax, 0.95)
+	}
+}
+
+func metricsTimeSeriesFromRollups(since time.Time, now time.Time, step time.Duration, rows []metricsRollup, failures []metricsCount) []MetricsTimePoint {
+	start := since.UTC().Truncate(step)
+	now = now.UTC()
+	buckets := make(map[time.Time]*MetricsTimePoint)
+	for t := start; !t.After(now); t = t.Add(step) {
+		buckets[t] = &MetricsTimePoint{BucketStart: t}
+	}
+	for _, r := range rows {
+		if p, ok := buckets[r.BucketStart.UTC().Truncate(step)]; ok {
+			p.Events += r.Events
+			p.Alerts += r.Alerts
+		}
+	}
+	for _, f := range failures {
+		if p, ok := buckets[f.BucketStart.UTC().Truncate(step)]; ok {
+			p.Failures += f.Count
+		}
+	}
+	out := make([]MetricsTimePoint, 0, len(buckets))
+	for t := start; !t.After(now); t = t.Add(step) {
+		out = append(out, *buckets[t])
+	}
+	return out
+}
+
+// latencyBucketIndex is the Go twin of latencyBucketCase.
+func latencyBucketIndex(ms int64) int {
+	return sort.Search(len(LatencyBucketsMS), func(i int) bool { return ms <= LatencyBucketsMS[i] })
+}
+
+// latencyBucketCase is a SQL expression mapping column to its histogram bucket.
+func latencyBucketCase(column string) string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i, upper := range LatencyBucketsMS {
+		fmt.Fprintf(&b, " WHEN %s <= %d THEN %d", column, upper, i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(LatencyBucketsMS))
+	return b.String()
+}
+
+func addLatencyHistograms(a []int64, b []int64) []int64 {
+	if len(b) > len(a) {
+		a = append(a, make([]int64, len(b)-len(a))...)
+	}
+	for i, v := range b {
+		a[i] += v
+	}
+	return a
+}
+
+// encodeLatencyHistogram stores counts as comma-separated values without
+// trailing zeros, which keeps quiet buckets to a few bytes.
+func encodeLatencyHistogram(counts []int64) string {
+	n := len(counts)
+	for n > 0 && counts[n-1] == 0 {
+		n--
+	}
+	parts := make([]string, n)
+	for i := 0; i < n; i++ {
+		parts[i] = strconv.FormatInt(counts[i], 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func decodeLatencyHistogram(raw string) []int64 {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]int64, len(parts))
+	for i, p := range parts {
+		out[i], _ = strconv.ParseInt(strings.TrimSpace(p), 10, 64)
+	}
+	return out
+}
+
+// latencyPercentile estimates the p-quantile the way the raw query picks it
+// (the sorted value at index floor((n-1)*p)), interpolating linearly inside the
+// bucket that holds that rank.
+func latencyPercentile(counts []int64, maxMS int64, p float64) float64 {
+	var n int64
+	for _, c := range counts {
+		n += c
+	}
+	if n == 0 {
+		return 0
+	}
+	rank := int64(float64(n-1)*p) + 1
+	var cumulative int64
+	for i, c := range counts {
+		if c == 0 || cumulative+c < rank {
+			cumulative += c
+			continue
+		}
+		lower, upper := float64(0), float64(maxMS)
+		if i > 0 && i-1 < len(LatencyBucketsMS) {
+			lower = float64(LatencyBucketsMS[i-1])
+		}
+		if i < len(LatencyBucketsMS) {
+			upper = math.Min(float64(LatencyBucketsMS[i]), float64(maxMS))
+		}
+		if upper < lower {
+			upper = lower
+		}
+		return lower + (upper-lower)*float64(rank-cumulative)/float64(c)
+	}
+	return float64(maxMS)
+}
+
+func ceilTime(t time.Time, d time.Duration) time.Time {
+	floor := t.Truncate(d)
+	if floor.Equal(t) {
+		return t
+	}
+	return floor.Add(d)
+}
+
+func minTime(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// metricsSQLDialect holds what differs between the databases in the rollup
+// queries.
+type metricsSQLDialect struct {
+	placeholder func(n int) string
+	truncMinute func(column string) string
+	bigint      string
+}
+
+var postgresMetricsDialect = metricsSQLDialect{
+	placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	truncMinute: func(column string) string { return "date_trunc('minute', " + column + ")" },
+	bigint:      "BIGINT",
+}
+
+var mysqlMetricsDialect = metricsSQLDialect{
+	placeholder: func(int) string { return "?" },
+	truncMinute: func(column string) string {
+		return "CAST(DATE_FORMAT(" + column + ", '%Y-%m-%d %H:%i:00') AS DATETIME)"
+	},
+	bigint: "SIGNED",
+}
+
+const (
+	rawMetricsEvents     = "events"
+	rawMetricsAlerts     = "alerts"
+	rawMetricsDeliveries = "deliveries"
+)
+
+type metricsQuery struct {
+	Kind  string
+	SQL   string
+	Args  []any
+	Label string
+}
+
+// rangeCondition renders column >= from [AND column < to] [AND tenant_id = ?].
+func (d metricsSQLDialect) rangeCondition(column string, tenantID string, from time.Time, to time.Time) (string, []any) {
+	args := []any{from.UTC()}
+	clauses := []string{column + " >= " + d.placeholder(1)}
+	if !to.IsZero() {
+		args = append(args, to.UTC())
+		clauses = append(clauses, column+" < "+d.placeholder(len(args)))
+	}
+	if tenantID != "" {
+		args = append(args, tenantID)
+		clauses = append(clauses, "tenant_id = "+d.placeholder(len(args)))
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// rawQueries aggregates the raw tables per tenant and minute; deliveries are
+// further split by latency bucket.
+func (d metricsSQLDialect) rawQueries(tenantID string, from time.Time, to time.Time) []metricsQuery {
+	count := func(kind string, table string, column string) metricsQuery {
+		cond, args := d.rangeCondition(column, tenantID, from, to)
+		return metricsQuery{
+			Kind:  kind,
+			Label: table,
+			SQL:   `SELECT tenant_id, ` + d.truncMinute(column) + `, COUNT(*) FROM ` + table + ` WHERE ` + cond + ` GROUP BY 1, 2`,
+			Args:  args,
+		}
+	}
+	cond, args := d.rangeCondition("recorded_at", tenantID, from, to)
+	return []metricsQuery{
+		count(rawMetricsEvents, "webhook_events", "received_at"),
+		count(rawMetricsAlerts, "webhook_alerts", "created_at"),
+		{
+			Kind:  rawMetricsDeliveries,
+			Label: "webhook_delivery_metrics",
+			SQL: `SELECT tenant_id, ` + d.truncMinute("recorded_at") + `, ` + latencyBucketCase("processing_ms") + `,
+				COUNT(*),
+				COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END), 0),
+				COALESCE(SUM(CASE WHEN outcome = '` + DeliveryOutcomeMisrouted + `' THEN 1 ELSE 0 END), 0),
+				CAST(COALESCE(SUM(processing_ms), 0) AS ` + d.bigint + `),
+				COALESCE(MAX(processing_ms), 0)
+			FROM webhook_delivery_metrics
+			WHERE ` + cond + `
+			GROUP BY 1, 2, 3`,
+			Args: args,
+		},
+	}
+}
+
+func (d metricsSQLDialect) unresolvedFailuresQuery(tenantID string, since time.Time) (string, []any) {
+	cond, args := d.rangeCondition("occurred_at", tenantID, since, time.Time{})
+	return `SELECT ` + d.truncMinute("occurred_at") + `, COUNT(*) FROM webhook_action_failures WHERE ` + cond + ` AND is_resolved = FALSE GROUP BY 1`, args
+}
+
+func scanRawMetricsRow(kind string, scan func(dest ...any) error) (metricsRollup, error) {
+	var r metricsRollup
+	switch kind {
+	case rawMetricsEvents:
+		return r, scan(&r.TenantID, &r.BucketStart, &r.Events)
+	case rawMetricsAlerts:
+		return r, scan(&r.TenantID, &r.BucketStart, &r.Alerts)
+	}
+	var bucket int
+	if err := scan(&r.TenantID, &r.BucketStart, &bucket, &r.Deliveries, &r.DeliverySuccess, &r.Misrouted, &r.ProcessingMSSum, &r.ProcessingMSMax); err != nil {
+		return r, err
+	}
+	r.Latency = make([]int64, len(LatencyBucketsMS)+1)
+	if bucket >= 0 && bucket < len(r.Latency) {
+		r.Latency[bucket] = r.Deliveries
+	}
+	return r, nil
+}
+
+const metricsRollupColumns = `tenant_id, bucket_start, events, alerts, deliveries, delivery_success, misrouted, processing_ms_sum, processing_ms_max, latency_histogram`
+
+func (d metricsSQLDialect) loadQuery(table string, tenantID string, from time.Time, to time.Time) (string, []any) {
+	cond, args := d.rangeCondition("bucket_start", tenantID, from, to)
+	return `SELECT ` + metricsRollupColumns + ` FROM ` + table + ` WHERE ` + cond, args
+}
+
+func scanMetricsRollup(scan func(dest ...any) error) (metricsRollup, error) {
+	var r metricsRollup
+	var histogram string
+	err := scan(&r.TenantID, &r.BucketStart, &r.Events, &r.Alerts, &r.Deliveries, &r.DeliverySuccess, &r.Misrouted,
+		&r.ProcessingMSSum, &r.ProcessingMSMax, &histogram)
+	r.BucketStart = r.BucketStart.UTC()
+	r.Latency = decodeLatencyHistogram(histogram)
+	return r, err
+}
+
+// insertQueries batches rows into multi-row INSERT statements.
+func (d metricsSQLDialect) insertQueries(table string, rows []metricsRollup) []metricsQuery {
+	out := []metricsQuery{}
+	for start := 0; start < len(rows); start += metricsRollupInsertBatch {
+		end := min(start+metricsRollupInsertBatch, len(rows))
+		values := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*10)
+		for _, r := range rows[start:end] {
+			marks := make([]string, 10)
+			for i := range marks {
+				marks[i] = d.placeholder(len(args) + i + 1)
+			}
+			values = append(values, "("+strings.Join(marks, ", ")+")")
+			args = append(args, r.TenantID, r.BucketStart.UTC(), r.Events, r.Alerts, r.Deliveries, r.DeliverySuccess, r.Misrouted,
+				r.ProcessingMSSum, r.ProcessingMSMax, encodeLatencyHistogram(r.Latency))
+		}
+		out = append(out, metricsQuery{
+			Label: table,
+			SQL:   `INSERT INTO ` + table + ` (` + metricsRollupColumns + `) VALUES ` + strings.Join(values, ", "),
+			Args:  args,
+		})
+	}
+	return out
+}
+
+func (s *WebhookEventStore) ensureMetricsRollupSchema(ctx context.Context) error {
+	for _, table := range []string{metricsRollupMinuteTable, metricsRollupHourTable} {
+		_, err := s.pool.Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS `+table+` (
+				tenant_id TEXT NOT NULL,
+				bucket_start TIMESTAMPTZ NOT NULL,
+				events BIGINT NOT NULL DEFAULT 0,
+				alerts BIGINT NOT NULL DEFAULT 0,
+				deliveries BIGINT NOT NULL DEFAULT 0,
+				delivery_success BIGINT NOT NULL DEFAULT 0,
+				misrouted BIGINT NOT NULL DEFAULT 0,
+				processing_ms_sum BIGINT NOT NULL DEFAULT 0,
+				processing_ms_max BIGINT NOT NULL DEFAULT 0,
+				latency_histogram TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (tenant_id, bucket_start)
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("create %s table: %w", table, err)
+		}
+		_, err = s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_`+table+`_bucket_start ON `+table+` (bucket_start)`)
+		if err != nil {
+			return fmt.Errorf("create idx_%s_bucket_start: %w", table, err)
+		}
+	}
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS metrics_rollup_state (
+			id SMALLINT PRIMARY KEY,
+			covered_from TIMESTAMPTZ NOT NULL,
+			compacted_until TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create metrics_rollup_state table: %w", err)
+	}
+	return nil
+}
+
+// CompactMetricsRollups brings the metrics rollups up to date with the raw
+// tables. Runs must not overlap; callers hold a worker lease.
+func (s *WebhookEventStore) CompactMetricsRollups(ctx context.Context, now time.Time) (int, error) {
+	return compactMetricsRollups(ctx, s, now)
+}
+
+func (s *WebhookEventStore) loadMetricsRollupState(ctx context.Context) (metricsRollupState, bool, error) {
+	var state metricsRollupState
+	err := s.pool.QueryRow(ctx, `SELECT covered_from, compacted_until FROM metrics_rollup_state WHERE id = 1`).Scan(&state.CoveredFrom, &state.CompactedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return state, false, nil
+		}
+		return state, false, fmt.Errorf("load metrics rollup state: %w", err)
+	}
+	state.CoveredFrom, state.CompactedUntil = state.CoveredFrom.UTC(), state.CompactedUntil.UTC()
+	return state, true, nil
+}
+
+func (s *WebhookEventStore) rawMetricsRollups(ctx context.Context, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	out := []metricsRollup{}
+	for _, q := range postgresMetricsDialect.rawQueries(tenantID, from, to) {
+		rows, err := s.pool.Query(ctx, q.SQL, q.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("aggregate %s: %w", q.Label, err)
+		}
+		for rows.Next() {
+			r, err := scanRawMetricsRow(q.Kind, rows.Scan)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan %s aggregate: %w", q.Label, err)
+			}
+			out = append(out, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("iterate %s aggregate: %w", q.Label, err)
+		}
+	}
+	return mergeMetricsRollups(out), nil
+}
+
+func (s *WebhookEventStore) loadMetricsRollups(ctx context.Context, table string, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	query, args := postgresMetricsDialect.loadQuery(table, tenantID, from, to)
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	out := []metricsRollup{}
+	for rows.Next() {
+		r, err := scanMetricsRollup(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", table, err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s: %w", table, err)
+	}
+	return out, nil
+}
+
+func (s *WebhookEventStore) replaceMetricsRollups(ctx context.Context, minutes metricsRollupRange, hours metricsRollupRange, state metricsRollupState) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin metrics rollup tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, part := range []struct {
+		table string
+		rng   metricsRollupRange
+	}{{metricsRollupMinuteTable, minutes}, {metricsRollupHourTable, hours}} {
+		if !part.rng.To.After(part.rng.From) {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM `+part.table+` WHERE bucket_start >= $1 AND bucket_start < $2`, part.rng.From.UTC(), part.rng.To.UTC()); err != nil {
+			return fmt.Errorf("clear %s: %w", part.table, err)
+		}
+		for _, q := range postgresMetricsDialect.insertQueries(part.table, part.rng.Rows) {
+			if _, err := tx.Exec(ctx, q.SQL, q.Args...); err != nil {
+				return fmt.Errorf("insert %s: %w", part.table, err)
+			}
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO metrics_rollup_state (id, covered_from, compacted_until, updated_at)
+		VALUES (1, $1, $2, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET compacted_until = EXCLUDED.compacted_until, updated_at = NOW()
+	`, state.CoveredFrom.UTC(), state.CompactedUntil.UTC()); err != nil {
+		return fmt.Errorf("save metrics rollup state: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit metrics rollups: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) countUnresolvedFailuresByMinute(ctx context.Context, tenantID string, since time.Time) ([]metricsCount, error) {
+	query, args := postgresMetricsDialect.unresolvedFailuresQuery(tenantID, since)
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate webhook_action_failures: %w", err)
+	}
+	defer rows.Close()
+
+	out := []metricsCount{}
+	for rows.Next() {
+		var c metricsCount
+		if err := rows.Scan(&c.BucketStart, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan webhook_action_failures aggregate: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook_action_failures aggregate: %w", err)
+	}
+	return out, nil
+}
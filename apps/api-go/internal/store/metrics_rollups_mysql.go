@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var mysqlMetricsRollupSchema = []string{
+	`CREATE TABLE IF NOT EXISTS metrics_rollups_minute (
+		tenant_id VARCHAR(64) NOT NULL,
+		bucket_start DATETIME(6) NOT NULL,
+		events BIGINT NOT NULL DEFAULT 0,
+		alerts BIGINT NOT NULL DEFAULT 0,
+		deliveries BIGINT NOT NULL DEFAULT 0,
+		delivery_success BIGINT NOT NULL DEFAULT 0,
+		misrouted BIGINT NOT NULL DEFAULT 0,
+		processing_ms_sum BIGINT NOT NULL DEFAULT 0,
+		processing_ms_max BIGINT NOT NULL DEFAULT 0,
+		latency_histogram TEXT NOT NULL,
+		PRIMARY KEY (tenant_id, bucket_start)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_metrics_rollups_minute_bucket_start ON metrics_rollups_minute (bucket_start)`,
+	`CREATE TABLE IF NOT EXISTS metrics_rollups_hour (
+		tenant_id VARCHAR(64) NOT NULL,
+		bucket_start DATETIME(6) NOT NULL,
+		events BIGINT NOT NULL DEFAULT 0,
+		alerts BIGINT NOT NULL DEFAULT 0,
+		deliveries BIGINT NOT NULL DEFAULT 0,
+		delivery_success BIGINT NOT NULL DEFAULT 0,
+		misrouted BIGINT NOT NULL DEFAULT 0,
+		processing_ms_sum BIGINT NOT NULL DEFAULT 0,
+		processing_ms_max BIGINT NOT NULL DEFAULT 0,
+		latency_histogram TEXT NOT NULL,
+		PRIMARY KEY (tenant_id, bucket_start)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_metrics_rollups_hour_bucket_start ON metrics_rollups_hour (bucket_start)`,
+	`CREATE TABLE IF NOT EXISTS metrics_rollup_state (
+		id SMALLINT NOT NULL PRIMARY KEY,
+		covered_from DATETIME(6) NOT NULL,
+		compacted_until DATETIME(6) NOT NULL,
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+func (s *MySQLWebhookEventStore) CompactMetricsRollups(ctx context.Context, now time.Time) (int, error) {
+	return compactMetricsRollups(ctx, s, now)
+}
+
+func (s *MySQLWebhookEventStore) loadMetricsRollupState(ctx context.Context) (metricsRollupState, bool, error) {
+	var state metricsRollupState
+	err := s.db.QueryRowContext(ctx, `SELECT covered_from, compacted_until FROM metrics_rollup_state WHERE id = 1`).Scan(&state.CoveredFrom, &state.CompactedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return state, false, nil
+		}
+		return state, false, fmt.Errorf("load metrics rollup state: %w", err)
+	}
+	state.CoveredFrom, state.CompactedUntil = state.CoveredFrom.UTC(), state.CompactedUntil.UTC()
+	return state, true, nil
+}
+
+func (s *MySQLWebhookEventStore) rawMetricsRollups(ctx context.Context, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	out := []metricsRollup{}
+	for _, q := range mysqlMetricsDialect.rawQueries(tenantID, from, to) {
+		rows, err := s.db.QueryContext(ctx, q.SQL, q.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("aggregate %s: %w", q.Label, err)
+		}
+		for rows.Next() {
+			r, err := scanRawMetricsRow(q.Kind, rows.Scan)
+			if err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("scan %s aggregate: %w", q.Label, err)
+			}
+			out = append(out, r)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("iterate %s aggregate: %w", q.Label, err)
+		}
+	}
+	return mergeMetricsRollups(out), nil
+}
+
+func (s *MySQLWebhookEventStore) loadMetricsRollups(ctx context.Context, table string, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	query, args := mysqlMetricsDialect.loadQuery(table, tenantID, from, to)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	out := []metricsRollup{}
+	for rows.Next() {
+		r, err := scanMetricsRollup(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", table, err)
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s: %w", table, err)
+	}
+	return out, nil
+}
+
+func (s *MySQLWebhookEventStore) replaceMetricsRollups(ctx context.Context, minutes metricsRollupRange, hours metricsRollupRange, state metricsRollupState) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin metrics rollup tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, part := range []struct {
+		table string
+		rng   metricsRollupRange
+	}{{metricsRollupMinuteTable, minutes}, {metricsRollupHourTable, hours}} {
+		if !part.rng.To.After(part.rng.From) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+part.table+` WHERE bucket_start >= ? AND bucket_start < ?`, part.rng.From.UTC(), part.rng.To.UTC()); err != nil {
+			return fmt.Errorf("clear %s: %w", part.table, err)
+		}
+		for _, q := range mysqlMetricsDialect.insertQueries(part.table, part.rng.Rows) {
+			if _, err := tx.ExecContext(ctx, q.SQL, q.Args...); err != nil {
+				return fmt.Errorf("insert %s: %w", part.table, err)
+			}
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO metrics_rollup_state (id, covered_from, compacted_until)
+		VALUES (1, ?, ?)
+		ON DUPLICATE KEY UPDATE compacted_until = VALUES(compacted_until)
+	`, state.CoveredFrom.UTC(), state.CompactedUntil.UTC()); err != nil {
+		return fmt.Errorf("save metrics rollup state: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit metrics rollups: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) countUnresolvedFailuresByMinute(ctx context.Context, tenantID string, since time.Time) ([]metricsCount, error) {
+	query, args := mysqlMetricsDialect.unresolvedFailuresQuery(tenantID, since)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate webhook_action_failures: %w", err)
+	}
+	defer rows.Close()
+
+	out := []metricsCount{}
+	for rows.Next() {
+		var c metricsCount
+		if err := rows.Scan(&c.BucketStart, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan webhook_action_failures aggregate: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook_action_failures aggregate: %w", err)
+	}
+	return out, nil
+}
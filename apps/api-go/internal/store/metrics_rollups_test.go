@@ -0,0 +1,251 @@
+package store
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+type fakeMetricsRow struct {
+	tenantID string
+	at       time.Time
+	success  bool
+	outcome  string
+	ms       int64
+}
+
+// fakeMetricsBackend aggregates in Go what the SQL backends aggregate in the
+// database, so compaction and read planning can be checked against brute force.
+type fakeMetricsBackend struct {
+	events     []fakeMetricsRow
+	alerts     []fakeMetricsRow
+	deliveries []fakeMetricsRow
+	tables     map[string][]metricsRollup
+	state      *metricsRollupState
+}
+
+func inRange(r fakeMetricsRow, tenantID string, from time.Time, to time.Time) bool {
+	return (tenantID == "" || r.tenantID == tenantID) && !r.at.Before(from) && (to.IsZero() || r.at.Before(to))
+}
+
+func (f *fakeMetricsBackend) loadMetricsRollupState(_ context.Context) (metricsRollupState, bool, error) {
+	if f.state == nil {
+		return metricsRollupState{}, false, nil
+	}
+	return *f.state, true, nil
+}
+
+func (f *fakeMetricsBackend) rawMetricsRollups(_ context.Context, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	out := []metricsRollup{}
+	for _, r := range f.events {
+		if inRange(r, tenantID, from, to) {
+			out = append(out, metricsRollup{TenantID: r.tenantID, BucketStart: r.at.Truncate(time.Minute), Events: 1})
+		}
+	}
+	for _, r := range f.alerts {
+		if inRange(r, tenantID, from, to) {
+			out = append(out, metricsRollup{TenantID: r.tenantID, BucketStart: r.at.Truncate(time.Minute), Alerts: 1})
+		}
+	}
+	for _, r := range f.deliveries {
+		if !inRange(r, tenantID, from, to) {
+			continue
+		}
+		row := metricsRollup{TenantID: r.tenantID, BucketStart: r.at.Truncate(time.Minute), Deliveries: 1,
+			ProcessingMSSum: r.ms, ProcessingMSMax: r.ms, Latency: make([]int64, len(LatencyBucketsMS)+1)}
+		if r.success {
+			row.DeliverySuccess = 1
+		}
+		if r.outcome == DeliveryOutcomeMisrouted {
+			row.Misrouted = 1
+		}
+		row.Latency[latencyBucketIndex(r.ms)] = 1
+		out = append(out, row)
+	}
+	return mergeMetricsRollups(out), nil
+}
+
+func (f *fakeMetricsBackend) loadMetricsRollups(_ context.Context, table string, tenantID string, from time.Time, to time.Time) ([]metricsRollup, error) {
+	out := []metricsRollup{}
+	for _, r := range f.tables[table] {
+		if (tenantID == "" || r.TenantID == tenantID) && !r.BucketStart.Before(from) && (to.IsZero() || r.BucketStart.Before(to)) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeMetricsBackend) replaceMetricsRollups(_ context.Context, minutes metricsRollupRange, hours metricsRollupRange, state metricsRollupState) error {
+	for table, rng := range map[string]metricsRollupRange{metricsRollupMinuteTable: minutes, metricsRollupHourTable: hours} {
+		kept := []metricsRollup{}
+		for _, r := range f.tables[table] {
+			if r.BucketStart.Before(rng.From) || !r.BucketStart.Before(rng.To) {
+				kept = append(kept, r)
+			}
+		}
+		f.tables[table] = append(kept, rng.Rows...)
+	}
+	f.state = &state
+	return nil
+}
+
+func (f *fakeMetricsBackend) countUnresolvedFailuresByMinute(_ context.Context, _ string, _ time.Time) ([]metricsCount, error) {
+	return nil, nil
+}
+
+func TestMetricsRollupsMatchRawAggregation(t *testing.T) {
+	now := time.Date(2026, 5, 10, 14, 37, 25, 0, time.UTC)
+	rng := rand.New(rand.NewSource(7))
+	f := &fakeMetricsBackend{tables: map[string][]metricsRollup{}}
+	add := func(from time.Time, to time.Time, n int) {
+		for i := 0; i < n; i++ {
+			tenantID := []string{"default", "acme"}[rng.Intn(2)]
+			at := from.Add(time.Duration(rng.Int63n(int64(to.Sub(from)))))
+			f.events = append(f.events, fakeMetricsRow{tenantID: tenantID, at: at})
+			if rng.Intn(4) == 0 {
+				f.alerts = append(f.alerts, fakeMetricsRow{tenantID: tenantID, at: at})
+			}
+			outcome := DeliveryOutcomeProcessed
+			if rng.Intn(20) == 0 {
+				outcome = DeliveryOutcomeMisrouted
+			}
+			ms := int64(rng.ExpFloat64() * 180)
+			f.deliveries = append(f.deliveries, fakeMetricsRow{tenantID: tenantID, at: at, success: rng.Intn(10) > 0, outcome: outcome, ms: ms})
+		}
+	}
+	add(now.Add(-72*time.Hour), now.Add(-5*time.Minute), 6000)
+
+	ctx := context.Background()
+	for i := 0; i < 6; i++ {
+		if _, err := compactMetricsRollups(ctx, f, now.Add(-5*time.Minute)); err != nil {
+			t.Fatalf("compact: %v", err)
+		}
+	}
+	// Rows committed late into compacted minutes and rows after the watermark
+	// are both picked up: the former by the next run's lookback, the latter by
+	// reading the raw tail.
+	add(now.Add(-12*time.Minute), now.Add(-9*time.Minute), 50)
+	if _, err := compactMetricsRollups(ctx, f, now); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	add(now.Add(-90*time.Second), now, 40)
+	if len(f.tables[metricsRollupHourTable]) == 0 || f.state.CompactedUntil != now.Add(-metricsRollupSettle).Truncate(time.Minute) {
+		t.Fatalf("expected compaction to catch up, state=%+v hours=%d", f.state, len(f.tables[metricsRollupHourTable]))
+	}
+
+	since := now.Add(-24*time.Hour - 17*time.Second)
+	rows, err := readMetricsRollups(ctx, f, "acme", since, true)
+	if err != nil {
+		t.Fatalf("read rollups: %v", err)
+	}
+	var got MetricsOverview
+	applyMetricsRollups(&got, rows)
+
+	var want MetricsOverview
+	latencies := []int64{}
+	var sum int64
+	for _, r := range f.events {
+		if inRange(r, "acme", since, time.Time{}) {
+			want.Events24h++
+		}
+	}
+	for _, r := range f.alerts {
+		if inRange(r, "acme", since, time.Time{}) {
+			want.Alerts24h++
+		}
+	}
+	for _, r := range f.deliveries {
+		if !inRange(r, "acme", since, time.Time{}) {
+			continue
+		}
+		want.DeliveryAttempts++
+		if r.success {
+			want.DeliverySuccess++
+		}
+		if r.outcome == DeliveryOutcomeMisrouted {
+			want.MisroutedDeliveries24h++
+		}
+		latencies = append(latencies, r.ms)
+		sum += r.ms
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	wantP95 := float64(latencies[int(float64(len(latencies)-1)*0.95)])
+
+	if got.Events24h != want.Events24h || got.Alerts24h != want.Alerts24h || got.DeliveryAttempts != want.DeliveryAttempts ||
+		got.DeliverySuccess != want.DeliverySuccess || got.MisroutedDeliveries24h != want.MisroutedDeliveries24h {
+		t.Fatalf("counts differ from raw aggregation:\n got %+v\nwant %+v", got, want)
+	}
+	if math.Abs(got.AvgProcessingMS24h-float64(sum)/float64(len(latencies))) > 1e-9 {
+		t.Fatalf("average differs: got %v", got.AvgProcessingMS24h)
+	}
+	if math.Abs(got.P95LatencyMS24h-wantP95) > 0.1*wantP95 {
+		t.Fatalf("p95 %v not within 10%% of raw %v", got.P95LatencyMS24h, wantP95)
+	}
+
+	for _, intervalMinutes := range []int{15, 60, 180} {
+		step := time.Duration(intervalMinutes) * time.Minute
+		rows, err := readMetricsRollups(ctx, f, "acme", since, intervalMinutes%60 == 0)
+		if err != nil {
+			t.Fatalf("read rollups: %v", err)
+		}
+		points := metricsTimeSeriesFromRollups(since, now, step, rows, nil)
+		wantEvents := map[time.Time]int64{}
+		for _, r := range f.events {
+			if inRange(r, "acme", since, time.Time{}) {
+				wantEvents[r.at.Truncate(step)]++
+			}
+		}
+		for _, p := range points {
+			if p.Events != wantEvents[p.BucketStart] {
+				t.Fatalf("interval %d bucket %s: got %d events, want %d", intervalMinutes, p.BucketStart, p.Events, wantEvents[p.BucketStart])
+			}
+		}
+	}
+}
+
+func TestPlanMetricsRollupRead(t *testing.T) {
+	state := metricsRollupState{
+		CoveredFrom:    time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		CompactedUntil: time.Date(2026, 5, 10, 14, 35, 0, 0, time.UTC),
+	}
+	since := time.Date(2026, 5, 9, 14, 37, 25, 0, time.UTC)
+	segments := planMetricsRollupRead(since, state, true, true)
+	want := []metricsRollupSegment{
+		{Source: metricsSourceRaw, From: since, To: time.Date(2026, 5, 9, 14, 38, 0, 0, time.UTC)},
+		{Source: metricsSourceMinute, From: time.Date(2026, 5, 9, 14, 38, 0, 0, time.UTC), To: time.Date(2026, 5, 9, 15, 0, 0, 0, time.UTC)},
+		{Source: metricsSourceHour, From: time.Date(2026, 5, 9, 15, 0, 0, 0, time.UTC), To: time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)},
+		{Source: metricsSourceMinute, From: time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC), To: state.CompactedUntil},
+		{Source: metricsSourceRaw, From: state.CompactedUntil},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("got %+v", segments)
+	}
+	for i := range want {
+		if segments[i] != want[i] {
+			t.Fatalf("segment %d: got %+v want %+v", i, segments[i], want[i])
+		}
+	}
+
+	if got := planMetricsRollupRead(since, state, false, true); len(got) != 1 || got[0].Source != metricsSourceRaw {
+		t.Fatalf("expected raw read without rollups, got %+v", got)
+	}
+}
+
+func TestLatencyHistogramRoundTrip(t *testing.T) {
+	counts := make([]int64, len(LatencyBucketsMS)+1)
+	counts[3], counts[10] = 4, 2
+	encoded := encodeLatencyHistogram(counts)
+	if encoded != "0,0,0,4,0,0,0,0,0,0,2" {
+		t.Fatalf("unexpected encoding %q", encoded)
+	}
+	decoded := decodeLatencyHistogram(encoded)
+	if len(decoded) != 11 || decoded[3] != 4 || decoded[10] != 2 {
+		t.Fatalf("unexpected decoding %v", decoded)
+	}
+	if latencyBucketIndex(0) != 0 || latencyBucketIndex(1) != 0 || latencyBucketIndex(2) != 1 || latencyBucketIndex(1e6) != len(LatencyBucketsMS) {
+		t.Fatalf("unexpected bucket indexes")
+	}
+}
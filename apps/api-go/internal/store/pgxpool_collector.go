@@ -0,0 +1,64 @@
+package store
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pgxPoolCollector exports pgxpool.Stat at scrape time, using the metric names
+// of github.com/IBM/pgxpoolprometheus.
+type pgxPoolCollector struct {
+	stat    func() *pgxpool.Stat
+	metrics []pgxPoolMetric
+}
+
+type pgxPoolMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(*pgxpool.Stat) float64
+}
+
+func newPgxPoolCollector(stat func() *pgxpool.Stat) *pgxPoolCollector {
+	metric := func(name, help string, valueType prometheus.ValueType, value func(*pgxpool.Stat) float64) pgxPoolMetric {
+		return pgxPoolMetric{desc: prometheus.NewDesc("pgxpool_"+name, help, nil, nil), valueType: valueType, value: value}
+	}
+	return &pgxPoolCollector{stat: stat, metrics: []pgxPoolMetric{
+		metric("acquire_count", "Cumulative count of successful acquires from the pool.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.AcquireCount()) }),
+		metric("acquire_duration_ns", "Total duration of all successful acquires from the pool in nanoseconds.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.AcquireDuration().Nanoseconds()) }),
+		metric("acquired_conns", "Number of currently acquired connections in the pool.", prometheus.GaugeValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.AcquiredConns()) }),
+		metric("canceled_acquire_count", "Cumulative count of acquires from the pool that were canceled by a context.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.CanceledAcquireCount()) }),
+		metric("constructing_conns", "Number of conns with construction in progress in the pool.", prometheus.GaugeValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.ConstructingConns()) }),
+		metric("empty_acquire_count", "Cumulative count of successful acquires that waited for a resource because the pool was empty.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.EmptyAcquireCount()) }),
+		metric("idle_conns", "Number of currently idle conns in the pool.", prometheus.GaugeValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.IdleConns()) }),
+		metric("max_conns", "Maximum size of the pool.", prometheus.GaugeValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.MaxConns()) }),
+		metric("total_conns", "Total number of resources currently in the pool.", prometheus.GaugeValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.TotalConns()) }),
+		metric("new_conns_count", "Cumulative count of new connections opened.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.NewConnsCount()) }),
+		metric("max_lifetime_destroy_count", "Cumulative count of connections destroyed because they exceeded MaxConnLifetime.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.MaxLifetimeDestroyCount()) }),
+		metric("max_idle_destroy_count", "Cumulative count of connections destroyed because they exceeded MaxConnIdleTime.", prometheus.CounterValue,
+			func(st *pgxpool.Stat) float64 { return float64(st.MaxIdleDestroyCount()) }),
+	}}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c.metrics {
+		ch <- m.desc
+	}
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.stat()
+	for _, m := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, m.value(st))
+	}
+}
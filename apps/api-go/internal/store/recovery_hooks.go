@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Recovery modes: fetch ingests the payload recorded by GitHub, redeliver asks
+// GitHub to send the delivery to the webhook endpoint again.
+const (
+	RecoveryModeFetch     = "fetch"
+	RecoveryModeRedeliver = "redeliver"
+)
+
+// RecoveryHookRecord is a GitHub webhook whose delivery log is scanned for
+// deliveries that never reached us. LastDeliveryID is the watermark: deliveries
+// up to it have been handled. A scan that has not reached the watermark yet
+// resumes from ScanCursor, and ScanTopDeliveryID becomes the watermark once it
+// does.
+type RecoveryHookRecord struct {
+	ID                int64     `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	Scope             string    `json:"scope"`
+	Target            string    `json:"target"`
+	HookID            int64     `json:"hook_id"`
+	Mode              string    `json:"mode"`
+	LastDeliveryID    int64     `json:"last_delivery_id"`
+	ScanCursor        string    `json:"scan_cursor,omitempty"`
+	ScanTopDeliveryID int64     `json:"scan_top_delivery_id,omitempty"`
+	LastRunAt         time.Time `json:"last_run_at"`
+	LastStatus        string    `json:"last_status"`
+	LastMessage       string    `json:"last_message"`
+	IsActive          bool      `json:"is_active"`
+	CreatedBy         string    `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// RecoveryRunRecord is the outcome of one recovery run.
+type RecoveryRunRecord struct {
+	LastDeliveryID    int64
+	ScanCursor        string
+	ScanTopDeliveryID int64
+	Status            string
+	Message           string
+}
+
+func IsValidRecoveryMode(mode string) bool {
+	return mode == RecoveryModeFetch || mode == RecoveryModeRedeliver
+}
+
+func (s *WebhookEventStore) ensureRecoveryHooksSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS webhook_recovery_hooks (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			scope TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			hook_id BIGINT NOT NULL DEFAULT 0,
+			mode TEXT NOT NULL DEFAULT 'fetch',
+			last_delivery_id BIGINT NOT NULL DEFAULT 0,
+			scan_cursor TEXT NOT NULL DEFAULT '',
+			scan_top_delivery_id BIGINT NOT NULL DEFAULT 0,
+			last_run_at TIMESTAMPTZ NULL,
+			last_status TEXT NOT NULL DEFAULT 'never',
+			last_message TEXT NOT NULL DEFAULT '',
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (tenant_id, scope, target, hook_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create webhook_recovery_hooks table: %w", err)
+	}
+	return nil
+}
+
+const recoveryHookColumns = `id, tenant_id, scope, target, hook_id, mode, last_delivery_id, scan_cursor, scan_top_delivery_id, COALESCE(last_run_at, 'epoch'::timestamptz), last_status, last_message, is_active, created_by, created_at`
+
+type recoveryHookScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecoveryHook(row recoveryHookScanner) (RecoveryHookRecord, error) {
+	var item RecoveryHookRecord
+	err := row.Scan(&item.ID, &item.TenantID, &item.Scope, &item.Target, &item.HookID, &item.Mode, &item.LastDeliveryID, &item.ScanCursor, &item.ScanTopDeliveryID, &item.LastRunAt,
+		&item.LastStatus, &item.LastMessage, &item.IsActive, &item.CreatedBy, &item.CreatedAt)
+	if item.LastRunAt.Unix() == 0 {
+		item.LastRunAt = time.Time{}
+	}
+	return item, err
+}
+
+func (s *WebhookEventStore) CreateRecoveryHook(ctx context.Context, item RecoveryHookRecord) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO webhook_recovery_hooks (tenant_id, scope, target, hook_id, mode, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, TRUE, $6)
+		RETURNING id
+	`, tenantIDFromCtx(ctx), item.Scope, strings.TrimSpace(item.Target), item.HookID, item.Mode, strings.TrimSpace(item.CreatedBy)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("create recovery hook: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) ListRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+recoveryHookColumns+` FROM webhook_recovery_hooks WHERE tenant_id = $1 ORDER BY id ASC
+	`, tenantIDFromCtx(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query recovery hooks: %w", err)
+	}
+	defer rows.Close()
+
+	items := []RecoveryHookRecord{}
+	for rows.Next() {
+		item, err := scanRecoveryHook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan recovery hook: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recovery hooks: %w", err)
+	}
+	return items, nil
+}
+
+// ListActiveRecoveryHooks spans all active tenants; callers scope each hook by its TenantID.
+func (s *WebhookEventStore) ListActiveRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+recoveryHookColumns+`
+		FROM webhook_recovery_hooks
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active recovery hooks: %w", err)
+	}
+	defer rows.Close()
+
+	items := []RecoveryHookRecord{}
+	for rows.Next() {
+		item, err := scanRecoveryHook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan recovery hook: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recovery hooks: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) GetRecoveryHook(ctx context.Context, id int64) (RecoveryHookRecord, error) {
+	item, err := scanRecoveryHook(s.pool.QueryRow(ctx, `
+		SELECT `+recoveryHookColumns+` FROM webhook_recovery_hooks WHERE id = $1 AND tenant_id = $2
+	`, id, tenantIDFromCtx(ctx)))
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return RecoveryHookRecord{}, fmt.Errorf("recovery hook not found")
+		}
+		return RecoveryHookRecord{}, fmt.Errorf("get recovery hook: %w", err)
+	}
+	return item, nil
+}
+
+func (s *WebhookEventStore) DeleteRecoveryHook(ctx context.Context, id int64) error {
+	result, err := s.pool.Exec(ctx, `DELETE FROM webhook_recovery_hooks WHERE id = $1 AND tenant_id = $2`, id, tenantIDFromCtx(ctx))
+	if err != nil {
+		return fmt.Errorf("delete recovery hook: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("recovery hook not found")
+	}
+	return nil
+}
+
+// RecordRecoveryRun stores a run's outcome and scan cursor. The watermark never
+// moves backwards.
+func (s *WebhookEventStore) RecordRecoveryRun(ctx context.Context, id int64, run RecoveryRunRecord) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE webhook_recovery_hooks
+		SET last_delivery_id = GREATEST(last_delivery_id, $3), scan_cursor = $4, scan_top_delivery_id = $5,
+		    last_run_at = NOW(), last_status = $6, last_message = $7
+		WHERE id = $1 AND tenant_id = $2
+	`, id, tenantIDFromCtx(ctx), run.LastDeliveryID, run.ScanCursor, run.ScanTopDeliveryID, run.Status, run.Message)
+	if err != nil {
+		return fmt.Errorf("record recovery run: %w", err)
+	}
+	return nil
+}
+
+// ListKnownDeliveryIDs returns which of the given delivery ids are already stored
+// as webhook events for the tenant.
+func (s *WebhookEventStore) ListKnownDeliveryIDs(ctx context.Context, deliveryIDs []string) (map[string]bool, error) {
+	known := make(map[string]bool, len(deliveryIDs))
+	if len(deliveryIDs) == 0 {
+		return known, nil
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT delivery_id FROM webhook_events WHERE tenant_id = $1 AND delivery_id = ANY($2)
+	`, tenantIDFromCtx(ctx), deliveryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query known delivery ids: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan delivery id: %w", err)
+		}
+		known[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery ids: %w", err)
+	}
+	return known, nil
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var mysqlRecoveryHooksSchema = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_recovery_hooks (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		scope VARCHAR(16) NOT NULL,
+		target VARCHAR(255) NOT NULL DEFAULT '',
+		hook_id BIGINT NOT NULL DEFAULT 0,
+		mode VARCHAR(16) NOT NULL DEFAULT 'fetch',
+		last_delivery_id BIGINT NOT NULL DEFAULT 0,
+		scan_cursor TEXT NOT NULL,
+		scan_top_delivery_id BIGINT NOT NULL DEFAULT 0,
+		last_run_at DATETIME(6) NULL,
+		last_status VARCHAR(32) NOT NULL DEFAULT 'never',
+		last_message TEXT NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by VARCHAR(191) NOT NULL DEFAULT '',
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		UNIQUE KEY uq_webhook_recovery_hooks (tenant_id, scope, target, hook_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+const mysqlRecoveryHookColumns = `id, tenant_id, scope, target, hook_id, mode, last_delivery_id, scan_cursor, scan_top_delivery_id, last_run_at, last_status, last_message, is_active, created_by, created_at`
+
+func scanMySQLRecoveryHook(row recoveryHookScanner) (RecoveryHookRecord, error) {
+	var item RecoveryHookRecord
+	var lastRunAt sql.NullTime
+	err := row.Scan(&item.ID, &item.TenantID, &item.Scope, &item.Target, &item.HookID, &item.Mode, &item.LastDeliveryID, &item.ScanCursor, &item.ScanTopDeliveryID, &lastRunAt,
+		&item.LastStatus, &item.LastMessage, &item.IsActive, &item.CreatedBy, &item.CreatedAt)
+	if lastRunAt.Valid {
+		item.LastRunAt = lastRunAt.Time
+	}
+	return item, err
+}
+
+func (s *MySQLWebhookEventStore) CreateRecoveryHook(ctx context.Context, item RecoveryHookRecord) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_recovery_hooks (tenant_id, scope, target, hook_id, mode, scan_cursor, last_message, is_active, created_by)
+		VALUES (?, ?, ?, ?, ?, '', '', TRUE, ?)
+	`, tenantIDFromCtxMySQL(ctx), item.Scope, strings.TrimSpace(item.Target), item.HookID, item.Mode, strings.TrimSpace(item.CreatedBy))
+	if err != nil {
+		return 0, fmt.Errorf("create recovery hook: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get recovery hook id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) ListRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+mysqlRecoveryHookColumns+` FROM webhook_recovery_hooks WHERE tenant_id = ? ORDER BY id ASC
+	`, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query recovery hooks: %w", err)
+	}
+	defer rows.Close()
+
+	items := []RecoveryHookRecord{}
+	for rows.Next() {
+		item, err := scanMySQLRecoveryHook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan recovery hook: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recovery hooks: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ListActiveRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+mysqlRecoveryHookColumns+`
+		FROM webhook_recovery_hooks
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active recovery hooks: %w", err)
+	}
+	defer rows.Close()
+
+	items := []RecoveryHookRecord{}
+	for rows.Next() {
+		item, err := scanMySQLRecoveryHook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan recovery hook: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recovery hooks: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) GetRecoveryHook(ctx context.Context, id int64) (RecoveryHookRecord, error) {
+	item, err := scanMySQLRecoveryHook(s.db.QueryRowContext(ctx, `
+		SELECT `+mysqlRecoveryHookColumns+` FROM webhook_recovery_hooks WHERE id = ? AND tenant_id = ?
+	`, id, tenantIDFromCtxMySQL(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return RecoveryHookRecord{}, fmt.Errorf("recovery hook not found")
+		}
+		return RecoveryHookRecord{}, fmt.Errorf("get recovery hook: %w", err)
+	}
+	return item, nil
+}
+
+func (s *MySQLWebhookEventStore) DeleteRecoveryHook(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhook_recovery_hooks WHERE id = ? AND tenant_id = ?`, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("delete recovery hook: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for recovery hook delete: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("recovery hook not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) RecordRecoveryRun(ctx context.Context, id int64, run RecoveryRunRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_recovery_hooks
+		SET last_delivery_id = GREATEST(last_delivery_id, ?), scan_cursor = ?, scan_top_delivery_id = ?,
+		    last_run_at = CURRENT_TIMESTAMP(6), last_status = ?, last_message = ?
+		WHERE id = ? AND tenant_id = ?
+	`, run.LastDeliveryID, run.ScanCursor, run.ScanTopDeliveryID, run.Status, run.Message, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("record recovery run: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ListKnownDeliveryIDs(ctx context.Context, deliveryIDs []string) (map[string]bool, error) {
+	known := make(map[string]bool, len(deliveryIDs))
+	if len(deliveryIDs) == 0 {
+		return known, nil
+	}
+	args := make([]any, 0, len(deliveryIDs)+1)
+	args = append(args, tenantIDFromCtxMySQL(ctx))
+	for _, id := range deliveryIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(deliveryIDs)), ",")
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT delivery_id FROM webhook_events WHERE tenant_id = ? AND delivery_id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query known delivery ids: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan delivery id: %w", err)
+		}
+		known[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery ids: %w", err)
+	}
+	return known, nil
+}
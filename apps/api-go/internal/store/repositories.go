@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RepositoryRecord is a repository a tenant's installation or webhook has access
+// to. ExternalID is the forge's numeric repository ID, which survives renames.
+type RepositoryRecord struct {
+	ID             int64     `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	Source         string    `json:"source"`
+	ExternalID     int64     `json:"external_id"`
+	FullName       string    `json:"full_name"`
+	InstallationID int64     `json:"installation_id"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// repositoryRenameTables hold repository_full_name references rewritten on a rename.
+var repositoryRenameTables = []string{"webhook_events", "webhook_alerts", "webhook_action_failures", "scheduled_jobs"}
+
+// repositoryRenameNamedTables name a repository in column where kindColumn is
+// "repo"; their cursors carry over to the new name. A row is left alone when the
+// tenant already has one under the new name.
+var repositoryRenameNamedTables = []struct{ table, column, kindColumn string }{
+	{"github_event_sources", "name", "kind"},
+	{"webhook_recovery_hooks", "target", "scope"},
+}
+
+func (s *WebhookEventStore) ensureRepositoriesSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS repositories (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT 'github',
+			external_id BIGINT NOT NULL,
+			full_name TEXT NOT NULL,
+			installation_id BIGINT NOT NULL DEFAULT 0,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create repositories table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_repositories_tenant_source_external
+		ON repositories (tenant_id, source, external_id)
+	`)
+	if err != nil {
+		return fmt.Errorf("create uq_repositories_tenant_source_external: %w", err)
+	}
+	return nil
+}
+
+// UpsertRepositories registers repositories for the tenant in ctx, reactivating
+// and renaming existing rows.
+func (s *WebhookEventStore) UpsertRepositories(ctx context.Context, items []RepositoryRecord) error {
+	tenantID := tenantIDFromCtx(ctx)
+	for _, item := range items {
+		if item.ExternalID <= 0 || strings.TrimSpace(item.FullName) == "" {
+			continue
+		}
+		_, err := s.pool.Exec(ctx, `
+			INSERT INTO repositories (tenant_id, source, external_id, full_name, installation_id)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (tenant_id, source, external_id) DO UPDATE
+			SET full_name = EXCLUDED.full_name,
+			    installation_id = CASE WHEN EXCLUDED.installation_id > 0 THEN EXCLUDED.installation_id ELSE repositories.installation_id END,
+			    is_active = TRUE,
+			    updated_at = NOW()
+		`, tenantID, normalizeEventSource(item.Source), item.ExternalID, strings.TrimSpace(item.FullName), item.InstallationID)
+		if err != nil {
+			return fmt.Errorf("upsert repository: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeactivateRepositories marks the given repositories inactive; they stay listed
+// so history keeps resolving.
+func (s *WebhookEventStore) DeactivateRepositories(ctx context.Context, source string, externalIDs []int64) error {
+	tenantID := tenantIDFromCtx(ctx)
+	for _, id := range externalIDs {
+		if _, err := s.pool.Exec(ctx, `
+			UPDATE repositories SET is_active = FALSE, updated_at = NOW()
+			WHERE tenant_id = $1 AND source = $2 AND external_id = $3
+		`, tenantID, normalizeEventSource(source), id); err != nil {
+			return fmt.Errorf("deactivate repository: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) DeactivateInstallationRepositories(ctx context.Context, installationID int64) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE repositories SET is_active = FALSE, updated_at = NOW()
+		WHERE tenant_id = $1 AND source = $2 AND installation_id = $3
+	`, tenantIDFromCtx(ctx), EventSourceGitHub, installationID)
+	if err != nil {
+		return fmt.Errorf("deactivate installation repositories: %w", err)
+	}
+	return nil
+}
+
+// RenameRepository records a rename or transfer and rewrites stored references to
+// the old full name. It returns the number of rows rewritten outside the registry.
+func (s *WebhookEventStore) RenameRepository(ctx context.Context, item RepositoryRecord, oldFullName string) (int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	oldFullName = strings.TrimSpace(oldFullName)
+	newFullName := strings.TrimSpace(item.FullName)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin rename repository tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if item.ExternalID > 0 {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO repositories (tenant_id, source, external_id, full_name, installation_id)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (tenant_id, source, external_id) DO UPDATE
+			SET full_name = EXCLUDED.full_name, is_active = TRUE, updated_at = NOW()
+		`, tenantID, normalizeEventSource(item.Source), item.ExternalID, newFullName, item.InstallationID); err != nil {
+			return 0, fmt.Errorf("rename registered repository: %w", err)
+		}
+	}
+
+	var rewritten int64
+	if oldFullName != "" && oldFullName != newFullName {
+		for _, table := range repositoryRenameTables {
+			result, err := tx.Exec(ctx, `UPDATE `+table+` SET repository_full_name = $3 WHERE tenant_id = $1 AND repository_full_name = $2`, tenantID, oldFullName, newFullName)
+			if err != nil {
+				return 0, fmt.Errorf("rename repository in %s: %w", table, err)
+			}
+			rewritten += result.RowsAffected()
+		}
+		for _, t := range repositoryRenameNamedTables {
+			result, err := tx.Exec(ctx, `
+				UPDATE `+t.table+` SET `+t.column+` = $3
+				WHERE tenant_id = $1 AND `+t.kindColumn+` = 'repo' AND LOWER(`+t.column+`) = LOWER($2)
+				  AND NOT EXISTS (
+					SELECT 1 FROM `+t.table+` other
+					WHERE other.tenant_id = $1 AND other.`+t.kindColumn+` = 'repo' AND LOWER(other.`+t.column+`) = LOWER($3)
+				  )
+			`, tenantID, oldFullName, newFullName)
+			if err != nil {
+				return 0, fmt.Errorf("rename repository in %s: %w", t.table, err)
+			}
+			rewritten += result.RowsAffected()
+		}
+		result, err := tx.Exec(ctx, `
+			UPDATE webhook_routes SET match_value = $4
+			WHERE tenant_id = $1 AND kind = $2 AND match_value = $3
+		`, tenantID, WebhookRouteKindRepository, strings.ToLower(oldFullName), strings.ToLower(newFullName))
+		if err != nil {
+			return 0, fmt.Errorf("rename repository route: %w", err)
+		}
+		rewritten += result.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit rename repository tx: %w", err)
+	}
+	return rewritten, nil
+}
+
+func (s *WebhookEventStore) ListRepositories(ctx context.Context, limit int, offset int, source string, query string, activeOnly bool) ([]RepositoryRecord, int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	src := strings.TrimSpace(source)
+	q := strings.TrimSpace(query)
+
+	var total int64
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM repositories
+		WHERE tenant_id = $1
+		  AND ($2 = '' OR source = $2)
+		  AND ($3 = '' OR full_name ILIKE '%' || $3 || '%')
+		  AND (NOT $4 OR is_active = true)
+	`, tenantID, src, q, activeOnly).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count repositories: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, tenant_id, source, external_id, full_name, installation_id, is_active, created_at, updated_at
+		FROM repositories
+		WHERE tenant_id = $1
+		  AND ($2 = '' OR source = $2)
+		  AND ($3 = '' OR full_name ILIKE '%' || $3 || '%')
+		  AND (NOT $4 OR is_active = true)
+		ORDER BY full_name ASC
+		LIMIT $5 OFFSET $6
+	`, tenantID, src, q, activeOnly, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query repositories: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]RepositoryRecord, 0, limit)
+	for rows.Next() {
+		var item RepositoryRecord
+		if err := rows.Scan(&item.ID, &item.TenantID, &item.Source, &item.ExternalID, &item.FullName, &item.InstallationID, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan repository: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate repositories: %w", err)
+	}
+	return items, total, nil
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+var mysqlRepositoriesSchema = []string{
+	`CREATE TABLE IF NOT EXISTS repositories (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		source VARCHAR(32) NOT NULL DEFAULT 'github',
+		external_id BIGINT NOT NULL,
+		full_name VARCHAR(191) NOT NULL,
+		installation_id BIGINT NOT NULL DEFAULT 0,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE UNIQUE INDEX uq_repositories_tenant_source_external ON repositories (tenant_id, source, external_id)`,
+}
+
+func (s *MySQLWebhookEventStore) UpsertRepositories(ctx context.Context, items []RepositoryRecord) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	for _, item := range items {
+		if item.ExternalID <= 0 || strings.TrimSpace(item.FullName) == "" {
+			continue
+		}
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO repositories (tenant_id, source, external_id, full_name, installation_id)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				full_name = VALUES(full_name),
+				installation_id = IF(VALUES(installation_id) > 0, VALUES(installation_id), installation_id),
+				is_active = TRUE,
+				updated_at = CURRENT_TIMESTAMP(6)
+		`, tenantID, normalizeEventSource(item.Source), item.ExternalID, strings.TrimSpace(item.FullName), item.InstallationID)
+		if err != nil {
+			return fmt.Errorf("upsert repository: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) DeactivateRepositories(ctx context.Context, source string, externalIDs []int64) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	for _, id := range externalIDs {
+		if _, err := s.db.ExecContext(ctx, `
+			UPDATE repositories SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP(6)
+			WHERE tenant_id = ? AND source = ? AND external_id = ?
+		`, tenantID, normalizeEventSource(source), id); err != nil {
+			return fmt.Errorf("deactivate repository: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) DeactivateInstallationRepositories(ctx context.Context, installationID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE repositories SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP(6)
+		WHERE tenant_id = ? AND source = ? AND installation_id = ?
+	`, tenantIDFromCtxMySQL(ctx), EventSourceGitHub, installationID)
+	if err != nil {
+		return fmt.Errorf("deactivate installation repositories: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) RenameRepository(ctx context.Context, item RepositoryRecord, oldFullName string) (int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	oldFullName = strings.TrimSpace(oldFullName)
+	newFullName := strings.TrimSpace(item.FullName)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin rename repository tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if item.ExternalID > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO repositories (tenant_id, source, external_id, full_name, installation_id)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE full_name = VALUES(full_name), is_active = TRUE, updated_at = CURRENT_TIMESTAMP(6)
+		`, tenantID, normalizeEventSource(item.Source), item.ExternalID, newFullName, item.InstallationID); err != nil {
+			return 0, fmt.Errorf("rename registered repository: %w", err)
+		}
+	}
+
+	var rewritten int64
+	if oldFullName != "" && oldFullName != newFullName {
+		for _, table := range repositoryRenameTables {
+			result, err := tx.ExecContext(ctx, `UPDATE `+table+` SET repository_full_name = ? WHERE tenant_id = ? AND repository_full_name = ?`, newFullName, tenantID, oldFullName)
+			if err != nil {
+				return 0, fmt.Errorf("rename repository in %s: %w", table, err)
+			}
+			affected, _ := result.RowsAffected()
+			rewritten += affected
+		}
+		for _, t := range repositoryRenameNamedTables {
+			// IGNORE skips rows whose new name the tenant already has.
+			result, err := tx.ExecContext(ctx, `
+				UPDATE IGNORE `+t.table+` SET `+t.column+` = ?
+				WHERE tenant_id = ? AND `+t.kindColumn+` = 'repo' AND `+t.column+` = ?
+			`, newFullName, tenantID, oldFullName)
+			if err != nil {
+				return 0, fmt.Errorf("rename repository in %s: %w", t.table, err)
+			}
+			affected, _ := result.RowsAffected()
+			rewritten += affected
+		}
+		result, err := tx.ExecContext(ctx, `
+			UPDATE webhook_routes SET match_value = ?
+			WHERE tenant_id = ? AND kind = ? AND match_value = ?
+		`, strings.ToLower(newFullName), tenantID, WebhookRouteKindRepository, strings.ToLower(oldFullName))
+		if err != nil {
+			return 0, fmt.Errorf("rename repository route: %w", err)
+		}
+		affected, _ := result.RowsAffected()
+		rewritten += affected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit rename repository tx: %w", err)
+	}
+	return rewritten, nil
+}
+
+func (s *MySQLWebhookEventStore) ListRepositories(ctx context.Context, limit int, offset int, source string, query string, activeOnly bool) ([]RepositoryRecord, int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	src := strings.TrimSpace(source)
+	q := strings.TrimSpace(query)
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM repositories
+		WHERE tenant_id = ?
+		  AND (? = '' OR source = ?)
+		  AND (? = '' OR full_name LIKE CONCAT('%', ?, '%'))
+		  AND (NOT ? OR is_active = true)
+	`, tenantID, src, src, q, q, activeOnly).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count repositories: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, source, external_id, full_name, installation_id, is_active, created_at, updated_at
+		FROM repositories
+		WHERE tenant_id = ?
+		  AND (? = '' OR source = ?)
+		  AND (? = '' OR full_name LIKE CONCAT('%', ?, '%'))
+		  AND (NOT ? OR is_active = true)
+		ORDER BY full_name ASC
+		LIMIT ? OFFSET ?
+	`, tenantID, src, src, q, q, activeOnly, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query repositories: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]RepositoryRecord, 0, limit)
+	for rows.Next() {
+		var item RepositoryRecord
+		if err := rows.Scan(&item.ID, &item.TenantID, &item.Source, &item.ExternalID, &item.FullName, &item.InstallationID, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan repository: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate repositories: %w", err)
+	}
+	return items, total, nil
+}
@@ -0,0 +1,383 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// A scheduled job's inactive_days either counts from the item's last activity
+// of any kind, or from the item's creation and its author's last comment.
+const (
+	ScheduledJobInactiveByActivity = "activity"
+	ScheduledJobInactiveByAuthor   = "author"
+)
+
+type ScheduledJobRecord struct {
+	ID                 int64      `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	Name               string     `json:"name"`
+	Schedule           string     `json:"schedule"`
+	RepositoryFullName string     `json:"repository_full_name"`
+	TargetKind         string     `json:"target_kind"`
+	Label              string     `json:"label"`
+	InactiveDays       int        `json:"inactive_days"`
+	InactiveBy         string     `json:"inactive_by"`
+	CommentBody        string     `json:"comment_body"`
+	AddLabel           string     `json:"add_label"`
+	CloseItem          bool       `json:"close_item"`
+	MaxItems           int        `json:"max_items"`
+	IsActive           bool       `json:"is_active"`
+	CreatedBy          string     `json:"created_by"`
+	LastRunAt          *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt          time.Time  `json:"next_run_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+type ScheduledJobRunRecord struct {
+	ID           int64     `json:"id"`
+	JobID        int64     `json:"job_id"`
+	Trigger      string    `json:"trigger"`
+	Status       string    `json:"status"`
+	Matched      int       `json:"matched"`
+	Processed    int       `json:"processed"`
+	Failed       int       `json:"failed"`
+	ErrorMessage string    `json:"error_message"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+const scheduledJobColumns = `id, tenant_id, name, schedule, repository_full_name, target_kind, label, inactive_days, inactive_by,
+	comment_body, add_label, close_item, max_items, is_active, created_by, last_run_at, next_run_at, created_at, updated_at`
+
+type scheduledJobScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanScheduledJob(row scheduledJobScanner) (ScheduledJobRecord, error) {
+	var rec ScheduledJobRecord
+	var lastRunAt *time.Time
+	if err := row.Scan(&rec.ID, &rec.TenantID, &rec.Name, &rec.Schedule, &rec.RepositoryFullName, &rec.TargetKind, &rec.Label, &rec.InactiveDays, &rec.InactiveBy,
+		&rec.CommentBody, &rec.AddLabel, &rec.CloseItem, &rec.MaxItems, &rec.IsActive, &rec.CreatedBy, &lastRunAt, &rec.NextRunAt, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return rec, err
+	}
+	if lastRunAt != nil {
+		ts := lastRunAt.UTC()
+		rec.LastRunAt = &ts
+	}
+	return rec, nil
+}
+
+func (s *WebhookEventStore) ListScheduledJobs(ctx context.Context, limit int, offset int) ([]ScheduledJobRecord, int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM scheduled_jobs WHERE tenant_id = $1`, tenantID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count scheduled jobs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanScheduledJob(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan scheduled job: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate scheduled jobs: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *WebhookEventStore) GetScheduledJobByID(ctx context.Context, id int64) (ScheduledJobRecord, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	rec, err := scanScheduledJob(s.pool.QueryRow(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE id = $1
+		  AND tenant_id = $2
+	`, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rec, fmt.Errorf("scheduled job not found")
+		}
+		return rec, fmt.Errorf("get scheduled job by id: %w", err)
+	}
+	return rec, nil
+}
+
+func (s *WebhookEventStore) CreateScheduledJob(ctx context.Context, job ScheduledJobRecord) (int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO scheduled_jobs (
+			tenant_id, name, schedule, repository_full_name, target_kind, label, inactive_days, inactive_by,
+			comment_body, add_label, close_item, max_items, is_active, created_by, next_run_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+		RETURNING id
+	`, tenantID, strings.TrimSpace(job.Name), strings.TrimSpace(job.Schedule), strings.TrimSpace(job.RepositoryFullName), strings.TrimSpace(job.TargetKind),
+		strings.TrimSpace(job.Label), job.InactiveDays, strings.TrimSpace(job.InactiveBy), strings.TrimSpace(job.CommentBody), strings.TrimSpace(job.AddLabel), job.CloseItem, job.MaxItems,
+		job.IsActive, strings.TrimSpace(job.CreatedBy), job.NextRunAt.UTC()).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert scheduled job: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) UpdateScheduledJobActive(ctx context.Context, id int64, isActive bool, nextRunAt time.Time) error {
+	tenantID := tenantIDFromCtx(ctx)
+	result, err := s.pool.Exec(ctx, `
+		UPDATE scheduled_jobs
+		SET is_active = $2,
+		    next_run_at = $3,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND tenant_id = $4
+	`, id, isActive, nextRunAt.UTC(), tenantID)
+	if err != nil {
+		return fmt.Errorf("update scheduled job active: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("scheduled job not found")
+	}
+	return nil
+}
+
+// ListDueScheduledJobs spans all active tenants; callers scope each job by its TenantID.
+func (s *WebhookEventStore) ListDueScheduledJobs(ctx context.Context, now time.Time, limit int) ([]ScheduledJobRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE is_active = TRUE
+		  AND next_run_at <= $1
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY next_run_at ASC
+		LIMIT $2
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanScheduledJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan due scheduled job: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due scheduled jobs: %w", err)
+	}
+	return items, nil
+}
+
+// ClaimScheduledJob advances next_run_at only if it still equals expectedNextRunAt,
+// so that concurrent schedulers run each activation at most once.
+func (s *WebhookEventStore) ClaimScheduledJob(ctx context.Context, id int64, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	result, err := s.pool.Exec(ctx, `
+		UPDATE scheduled_jobs
+		SET last_run_at = NOW(),
+		    next_run_at = $3
+		WHERE id = $1
+		  AND next_run_at = $2
+		  AND tenant_id = $4
+	`, id, expectedNextRunAt.UTC(), nextRunAt.UTC(), tenantID)
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled job: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (s *WebhookEventStore) SaveScheduledJobRun(ctx context.Context, run ScheduledJobRunRecord) (int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO scheduled_job_runs (tenant_id, job_id, trigger_type, status, matched, processed, failed, error_message, started_at, finished_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		RETURNING id
+	`, tenantID, run.JobID, strings.TrimSpace(run.Trigger), strings.TrimSpace(run.Status), run.Matched, run.Processed, run.Failed,
+		strings.TrimSpace(run.ErrorMessage), run.StartedAt.UTC(), run.FinishedAt.UTC()).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert scheduled job run: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) ListScheduledJobRuns(ctx context.Context, jobID int64, limit int, offset int) ([]ScheduledJobRunRecord, int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM scheduled_job_runs WHERE tenant_id = $1 AND job_id = $2`, tenantID, jobID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count scheduled job runs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, job_id, trigger_type, status, matched, processed, failed, error_message, started_at, finished_at
+		FROM scheduled_job_runs
+		WHERE tenant_id = $1
+		  AND job_id = $2
+		ORDER BY started_at DESC
+		LIMIT $3 OFFSET $4
+	`, tenantID, jobID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query scheduled job runs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRunRecord, 0, limit)
+	for rows.Next() {
+		var rec ScheduledJobRunRecord
+		if err := rows.Scan(&rec.ID, &rec.JobID, &rec.Trigger, &rec.Status, &rec.Matched, &rec.Processed, &rec.Failed, &rec.ErrorMessage, &rec.StartedAt, &rec.FinishedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan scheduled job run: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate scheduled job runs: %w", err)
+	}
+	return items, total, nil
+}
+
+// ListScheduledJobItems returns the item numbers the job has already acted on.
+func (s *WebhookEventStore) ListScheduledJobItems(ctx context.Context, jobID int64) (map[int]bool, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	rows, err := s.pool.Query(ctx, `
+		SELECT item_number
+		FROM scheduled_job_items
+		WHERE tenant_id = $1
+		  AND job_id = $2
+	`, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled job items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[int]bool)
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return nil, fmt.Errorf("scan scheduled job item: %w", err)
+		}
+		items[number] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scheduled job items: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) SaveScheduledJobItem(ctx context.Context, jobID int64, number int) error {
+	tenantID := tenantIDFromCtx(ctx)
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO scheduled_job_items (tenant_id, job_id, item_number)
+		VALUES ($1,$2,$3)
+		ON CONFLICT (tenant_id, job_id, item_number) DO NOTHING
+	`, tenantID, jobID, number)
+	if err != nil {
+		return fmt.Errorf("insert scheduled job item: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) ensureScheduledJobsSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS scheduled_jobs (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			name TEXT NOT NULL,
+			schedule TEXT NOT NULL,
+			repository_full_name TEXT NOT NULL,
+			target_kind TEXT NOT NULL,
+			label TEXT NOT NULL DEFAULT '',
+			inactive_days INT NOT NULL DEFAULT 0,
+			inactive_by TEXT NOT NULL DEFAULT 'activity',
+			comment_body TEXT NOT NULL DEFAULT '',
+			add_label TEXT NOT NULL DEFAULT '',
+			close_item BOOLEAN NOT NULL DEFAULT FALSE,
+			max_items INT NOT NULL DEFAULT 30,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL,
+			last_run_at TIMESTAMPTZ NULL,
+			next_run_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create scheduled_jobs table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			job_id BIGINT NOT NULL,
+			trigger_type TEXT NOT NULL,
+			status TEXT NOT NULL,
+			matched INT NOT NULL DEFAULT 0,
+			processed INT NOT NULL DEFAULT 0,
+			failed INT NOT NULL DEFAULT 0,
+			error_message TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMPTZ NOT NULL,
+			finished_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create scheduled_job_runs table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS scheduled_job_items (
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			job_id BIGINT NOT NULL,
+			item_number INT NOT NULL,
+			acted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, job_id, item_number)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create scheduled_job_items table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_due
+		ON scheduled_jobs (is_active, next_run_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_scheduled_jobs_due: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_tenant_id
+		ON scheduled_jobs (tenant_id)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_scheduled_jobs_tenant_id: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job
+		ON scheduled_job_runs (tenant_id, job_id, started_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_scheduled_job_runs_job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var mysqlScheduledJobsSchema = []string{
+	`CREATE TABLE IF NOT EXISTS scheduled_jobs (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+		name VARCHAR(191) NOT NULL,
+		schedule VARCHAR(128) NOT NULL,
+		repository_full_name VARCHAR(255) NOT NULL,
+		target_kind VARCHAR(32) NOT NULL,
+		label VARCHAR(191) NOT NULL DEFAULT '',
+		inactive_days INT NOT NULL DEFAULT 0,
+		inactive_by VARCHAR(32) NOT NULL DEFAULT 'activity',
+		comment_body TEXT NOT NULL,
+		add_label VARCHAR(191) NOT NULL DEFAULT '',
+		close_item BOOLEAN NOT NULL DEFAULT FALSE,
+		max_items INT NOT NULL DEFAULT 30,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by VARCHAR(191) NOT NULL,
+		last_run_at DATETIME(6) NULL,
+		next_run_at DATETIME(6) NOT NULL,
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_scheduled_jobs_due ON scheduled_jobs (is_active, next_run_at)`,
+	`CREATE INDEX idx_scheduled_jobs_tenant_id ON scheduled_jobs (tenant_id)`,
+
+	`CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+		job_id BIGINT NOT NULL,
+		trigger_type VARCHAR(32) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		matched INT NOT NULL DEFAULT 0,
+		processed INT NOT NULL DEFAULT 0,
+		failed INT NOT NULL DEFAULT 0,
+		error_message TEXT NOT NULL,
+		started_at DATETIME(6) NOT NULL,
+		finished_at DATETIME(6) NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_scheduled_job_runs_job ON scheduled_job_runs (tenant_id, job_id, started_at)`,
+
+	`CREATE TABLE IF NOT EXISTS scheduled_job_items (
+		tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+		job_id BIGINT NOT NULL,
+		item_number INT NOT NULL,
+		acted_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (tenant_id, job_id, item_number)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+func scanScheduledJobMySQL(row scheduledJobScanner) (ScheduledJobRecord, error) {
+	var rec ScheduledJobRecord
+	var lastRunAt sql.NullTime
+	if err := row.Scan(&rec.ID, &rec.TenantID, &rec.Name, &rec.Schedule, &rec.RepositoryFullName, &rec.TargetKind, &rec.Label, &rec.InactiveDays, &rec.InactiveBy,
+		&rec.CommentBody, &rec.AddLabel, &rec.CloseItem, &rec.MaxItems, &rec.IsActive, &rec.CreatedBy, &lastRunAt, &rec.NextRunAt, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return rec, err
+	}
+	if lastRunAt.Valid {
+		ts := lastRunAt.Time.UTC()
+		rec.LastRunAt = &ts
+	}
+	return rec, nil
+}
+
+func (s *MySQLWebhookEventStore) ListScheduledJobs(ctx context.Context, limit int, offset int) ([]ScheduledJobRecord, int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scheduled_jobs WHERE tenant_id = ?`, tenantID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count scheduled jobs: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE tenant_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, tenantID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanScheduledJobMySQL(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan scheduled job: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate scheduled jobs: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *MySQLWebhookEventStore) GetScheduledJobByID(ctx context.Context, id int64) (ScheduledJobRecord, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	rec, err := scanScheduledJobMySQL(s.db.QueryRowContext(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE id = ?
+		  AND tenant_id = ?
+	`, id, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rec, fmt.Errorf("scheduled job not found")
+		}
+		return rec, fmt.Errorf("get scheduled job by id: %w", err)
+	}
+	return rec, nil
+}
+
+func (s *MySQLWebhookEventStore) CreateScheduledJob(ctx context.Context, job ScheduledJobRecord) (int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduled_jobs (
+			tenant_id, name, schedule, repository_full_name, target_kind, label, inactive_days, inactive_by,
+			comment_body, add_label, close_item, max_items, is_active, created_by, next_run_at
+		) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+	`, tenantID, strings.TrimSpace(job.Name), strings.TrimSpace(job.Schedule), strings.TrimSpace(job.RepositoryFullName), strings.TrimSpace(job.TargetKind),
+		strings.TrimSpace(job.Label), job.InactiveDays, strings.TrimSpace(job.InactiveBy), strings.TrimSpace(job.CommentBody), strings.TrimSpace(job.AddLabel), job.CloseItem, job.MaxItems,
+		job.IsActive, strings.TrimSpace(job.CreatedBy), job.NextRunAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("insert scheduled job: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get scheduled job id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateScheduledJobActive(ctx context.Context, id int64, isActive bool, nextRunAt time.Time) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET is_active = ?,
+		    next_run_at = ?
+		WHERE id = ?
+		  AND tenant_id = ?
+	`, isActive, nextRunAt.UTC(), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("update scheduled job active: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update scheduled job active rows affected: %w", err)
+	}
+	if affected == 0 {
+		if _, err := s.GetScheduledJobByID(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ListDueScheduledJobs(ctx context.Context, now time.Time, limit int) ([]ScheduledJobRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+scheduledJobColumns+`
+		FROM scheduled_jobs
+		WHERE is_active = TRUE
+		  AND next_run_at <= ?
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY next_run_at ASC
+		LIMIT ?
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due scheduled jobs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRecord, 0, limit)
+	for rows.Next() {
+		rec, err := scanScheduledJobMySQL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan due scheduled job: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due scheduled jobs: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ClaimScheduledJob(ctx context.Context, id int64, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET last_run_at = CURRENT_TIMESTAMP(6),
+		    next_run_at = ?
+		WHERE id = ?
+		  AND next_run_at = ?
+		  AND tenant_id = ?
+	`, nextRunAt.UTC(), id, expectedNextRunAt.UTC(), tenantID)
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled job: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled job rows affected: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (s *MySQLWebhookEventStore) SaveScheduledJobRun(ctx context.Context, run ScheduledJobRunRecord) (int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO scheduled_job_runs (tenant_id, job_id, trigger_type, status, matched, processed, failed, error_message, started_at, finished_at)
+		VALUES (?,?,?,?,?,?,?,?,?,?)
+	`, tenantID, run.JobID, strings.TrimSpace(run.Trigger), strings.TrimSpace(run.Status), run.Matched, run.Processed, run.Failed,
+		strings.TrimSpace(run.ErrorMessage), run.StartedAt.UTC(), run.FinishedAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("insert scheduled job run: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get scheduled job run id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) ListScheduledJobRuns(ctx context.Context, jobID int64, limit int, offset int) ([]ScheduledJobRunRecord, int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scheduled_job_runs WHERE tenant_id = ? AND job_id = ?`, tenantID, jobID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count scheduled job runs: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, job_id, trigger_type, status, matched, processed, failed, error_message, started_at, finished_at
+		FROM scheduled_job_runs
+		WHERE tenant_id = ?
+		  AND job_id = ?
+		ORDER BY started_at DESC
+		LIMIT ? OFFSET ?
+	`, tenantID, jobID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query scheduled job runs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ScheduledJobRunRecord, 0, limit)
+	for rows.Next() {
+		var rec ScheduledJobRunRecord
+		if err := rows.Scan(&rec.ID, &rec.JobID, &rec.Trigger, &rec.Status, &rec.Matched, &rec.Processed, &rec.Failed, &rec.ErrorMessage, &rec.StartedAt, &rec.FinishedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan scheduled job run: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate scheduled job runs: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *MySQLWebhookEventStore) ListScheduledJobItems(ctx context.Context, jobID int64) (map[int]bool, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT item_number
+		FROM scheduled_job_items
+		WHERE tenant_id = ?
+		  AND job_id = ?
+	`, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled job items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[int]bool)
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return nil, fmt.Errorf("scan scheduled job item: %w", err)
+		}
+		items[number] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scheduled job items: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) SaveScheduledJobItem(ctx context.Context, jobID int64, number int) error {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT IGNORE INTO scheduled_job_items (tenant_id, job_id, item_number)
+		VALUES (?,?,?)
+	`, tenantID, jobID, number)
+	if err != nil {
+		return fmt.Errorf("insert scheduled job item: %w", err)
+	}
+	return nil
+}
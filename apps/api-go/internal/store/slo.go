@@ -0,0 +1,236 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SLO indicators. A delivery is good for SLIDeliverySuccess when it succeeded,
+// and for SLILatency when it was processed within LatencyThresholdMS.
+const (
+	SLIDeliverySuccess = "delivery_success"
+	SLILatency         = "latency"
+)
+
+// SLORecord is a tenant's service level objective over webhook deliveries.
+// Objective is the percentage of good deliveries required over WindowDays.
+type SLORecord struct {
+	ID                 int64     `json:"id"`
+	TenantID           string    `json:"tenant_id"`
+	Name               string    `json:"name"`
+	SLI                string    `json:"sli"`
+	Objective          float64   `json:"objective"`
+	LatencyThresholdMS int64     `json:"latency_threshold_ms"`
+	WindowDays         int       `json:"window_days"`
+	IsActive           bool      `json:"is_active"`
+	CreatedBy          string    `json:"created_by"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SLOWindowCount counts the deliveries recorded since Since that SLOs measure.
+type SLOWindowCount struct {
+	Since           time.Time `json:"since"`
+	Total           int64     `json:"total"`
+	Success         int64     `json:"success"`
+	WithinThreshold int64     `json:"within_threshold"`
+}
+
+// sloExcludedOutcomes are deliveries rejected because of the sender (bad
+// signature, unknown tenant, malformed or oversized payload). They do not spend
+// error budget.
+const sloExcludedOutcomes = `'` + DeliveryOutcomeUnauthorized + `', '` + DeliveryOutcomeMisrouted + `', '` + DeliveryOutcomeQuarantined + `', '` + DeliveryOutcomeTooLarge + `'`
+
+const sloColumns = `id, tenant_id, name, sli, objective, latency_threshold_ms, window_days, is_active, created_by, created_at, updated_at`
+
+type sloScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSLO(row sloScanner) (SLORecord, error) {
+	var rec SLORecord
+	err := row.Scan(&rec.ID, &rec.TenantID, &rec.Name, &rec.SLI, &rec.Objective, &rec.LatencyThresholdMS, &rec.WindowDays,
+		&rec.IsActive, &rec.CreatedBy, &rec.CreatedAt, &rec.UpdatedAt)
+	return rec, err
+}
+
+func (s *WebhookEventStore) ensureSLOSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS slo_definitions (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			sli TEXT NOT NULL,
+			objective DOUBLE PRECISION NOT NULL,
+			latency_threshold_ms BIGINT NOT NULL DEFAULT 0,
+			window_days INT NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (tenant_id, name)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create slo_definitions table: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_webhook_delivery_metrics_tenant_recorded
+		ON webhook_delivery_metrics (tenant_id, recorded_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_webhook_delivery_metrics_tenant_recorded: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) ListSLOs(ctx context.Context) ([]SLORecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+sloColumns+` FROM slo_definitions WHERE tenant_id = $1 ORDER BY id ASC
+	`, tenantIDFromCtx(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query slos: %w", err)
+	}
+	defer rows.Close()
+
+	items := []SLORecord{}
+	for rows.Next() {
+		rec, err := scanSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan slo: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate slos: %w", err)
+	}
+	return items, nil
+}
+
+// ListActiveSLOs spans all active tenants; callers scope each SLO by its TenantID.
+func (s *WebhookEventStore) ListActiveSLOs(ctx context.Context) ([]SLORecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+sloColumns+`
+		FROM slo_definitions
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active slos: %w", err)
+	}
+	defer rows.Close()
+
+	items := []SLORecord{}
+	for rows.Next() {
+		rec, err := scanSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan slo: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate active slos: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) GetSLO(ctx context.Context, id int64) (SLORecord, error) {
+	rec, err := scanSLO(s.pool.QueryRow(ctx, `
+		SELECT `+sloColumns+` FROM slo_definitions WHERE id = $1 AND tenant_id = $2
+	`, id, tenantIDFromCtx(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rec, fmt.Errorf("slo not found")
+		}
+		return rec, fmt.Errorf("get slo: %w", err)
+	}
+	return rec, nil
+}
+
+func (s *WebhookEventStore) CreateSLO(ctx context.Context, item SLORecord) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO slo_definitions (tenant_id, name, sli, objective, latency_threshold_ms, window_days, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, tenantIDFromCtx(ctx), strings.TrimSpace(item.Name), item.SLI, item.Objective, item.LatencyThresholdMS, item.WindowDays,
+		item.IsActive, strings.TrimSpace(item.CreatedBy)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert slo: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) UpdateSLO(ctx context.Context, item SLORecord) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE slo_definitions
+		SET name = $3, sli = $4, objective = $5, latency_threshold_ms = $6, window_days = $7, is_active = $8, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+	`, item.ID, tenantIDFromCtx(ctx), strings.TrimSpace(item.Name), item.SLI, item.Objective, item.LatencyThresholdMS, item.WindowDays, item.IsActive)
+	if err != nil {
+		return fmt.Errorf("update slo: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("slo not found")
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) DeleteSLO(ctx context.Context, id int64) error {
+	result, err := s.pool.Exec(ctx, `DELETE FROM slo_definitions WHERE id = $1 AND tenant_id = $2`, id, tenantIDFromCtx(ctx))
+	if err != nil {
+		return fmt.Errorf("delete slo: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("slo not found")
+	}
+	return nil
+}
+
+// CountSLODeliveries counts the tenant's deliveries for each start time in since
+// with a single scan; thresholdMS decides WithinThreshold.
+func (s *WebhookEventStore) CountSLODeliveries(ctx context.Context, thresholdMS int64, since []time.Time) ([]SLOWindowCount, error) {
+	if len(since) == 0 {
+		return nil, nil
+	}
+	earliest := since[0]
+	args := []any{tenantIDFromCtx(ctx), thresholdMS}
+	columns := make([]string, 0, len(since)*3)
+	for _, start := range since {
+		if start.Before(earliest) {
+			earliest = start
+		}
+		args = append(args, start.UTC())
+		n := len(args)
+		columns = append(columns,
+			fmt.Sprintf("COUNT(*) FILTER (WHERE recorded_at >= $%d)", n),
+			fmt.Sprintf("COUNT(*) FILTER (WHERE recorded_at >= $%d AND success)", n),
+			fmt.Sprintf("COUNT(*) FILTER (WHERE recorded_at >= $%d AND processing_ms <= $2)", n),
+		)
+	}
+	args = append(args, earliest.UTC())
+
+	out := make([]SLOWindowCount, len(since))
+	dest := make([]any, 0, len(columns))
+	for i := range out {
+		out[i].Since = since[i].UTC()
+		dest = append(dest, &out[i].Total, &out[i].Success, &out[i].WithinThreshold)
+	}
+	err := s.pool.QueryRow(ctx, `
+		SELECT `+strings.Join(columns, ", ")+`
+		FROM webhook_delivery_metrics
+		WHERE tenant_id = $1
+		  AND recorded_at >= $`+fmt.Sprint(len(args))+`
+		  AND outcome NOT IN (`+sloExcludedOutcomes+`)
+	`, args...).Scan(dest...)
+	if err != nil {
+		return nil, fmt.Errorf("count slo deliveries: %w", err)
+	}
+	return out, nil
+}
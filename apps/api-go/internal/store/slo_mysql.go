@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var mysqlSLOSchema = []string{
+	`CREATE TABLE IF NOT EXISTS slo_definitions (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		name VARCHAR(191) NOT NULL,
+		sli VARCHAR(32) NOT NULL,
+		objective DOUBLE NOT NULL,
+		latency_threshold_ms BIGINT NOT NULL DEFAULT 0,
+		window_days INT NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by VARCHAR(191) NOT NULL DEFAULT '',
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+		UNIQUE KEY uq_slo_definitions (tenant_id, name)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_webhook_delivery_metrics_tenant_recorded ON webhook_delivery_metrics (tenant_id, recorded_at)`,
+}
+
+func (s *MySQLWebhookEventStore) querySLOs(ctx context.Context, query string, args ...any) ([]SLORecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query slos: %w", err)
+	}
+	defer rows.Close()
+
+	items := []SLORecord{}
+	for rows.Next() {
+		rec, err := scanSLO(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan slo: %w", err)
+		}
+		items = append(items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate slos: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) ListSLOs(ctx context.Context) ([]SLORecord, error) {
+	return s.querySLOs(ctx, `SELECT `+sloColumns+` FROM slo_definitions WHERE tenant_id = ? ORDER BY id ASC`, tenantIDFromCtxMySQL(ctx))
+}
+
+func (s *MySQLWebhookEventStore) ListActiveSLOs(ctx context.Context) ([]SLORecord, error) {
+	return s.querySLOs(ctx, `
+		SELECT `+sloColumns+`
+		FROM slo_definitions
+		WHERE is_active = TRUE
+		  AND tenant_id IN (SELECT id FROM tenants WHERE is_active = TRUE)
+		ORDER BY id ASC
+	`)
+}
+
+func (s *MySQLWebhookEventStore) GetSLO(ctx context.Context, id int64) (SLORecord, error) {
+	rec, err := scanSLO(s.db.QueryRowContext(ctx, `
+		SELECT `+sloColumns+` FROM slo_definitions WHERE id = ? AND tenant_id = ?
+	`, id, tenantIDFromCtxMySQL(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rec, fmt.Errorf("slo not found")
+		}
+		return rec, fmt.Errorf("get slo: %w", err)
+	}
+	return rec, nil
+}
+
+func (s *MySQLWebhookEventStore) CreateSLO(ctx context.Context, item SLORecord) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO slo_definitions (tenant_id, name, sli, objective, latency_threshold_ms, window_days, is_active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, tenantIDFromCtxMySQL(ctx), strings.TrimSpace(item.Name), item.SLI, item.Objective, item.LatencyThresholdMS, item.WindowDays,
+		item.IsActive, strings.TrimSpace(item.CreatedBy))
+	if err != nil {
+		return 0, fmt.Errorf("insert slo: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get slo id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateSLO(ctx context.Context, item SLORecord) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE slo_definitions
+		SET name = ?, sli = ?, objective = ?, latency_threshold_ms = ?, window_days = ?, is_active = ?
+		WHERE id = ? AND tenant_id = ?
+	`, strings.TrimSpace(item.Name), item.SLI, item.Objective, item.LatencyThresholdMS, item.WindowDays, item.IsActive, item.ID, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("update slo: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update slo rows affected: %w", err)
+	}
+	if affected == 0 {
+		// MySQL reports unchanged rows as unaffected.
+		if _, err := s.GetSLO(ctx, item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) DeleteSLO(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM slo_definitions WHERE id = ? AND tenant_id = ?`, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("delete slo: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for slo delete: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("slo not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) CountSLODeliveries(ctx context.Context, thresholdMS int64, since []time.Time) ([]SLOWindowCount, error) {
+	if len(since) == 0 {
+		return nil, nil
+	}
+	earliest := since[0]
+	args := make([]any, 0, len(since)*4+2)
+	columns := make([]string, 0, len(since)*3)
+	for _, start := range since {
+		if start.Before(earliest) {
+			earliest = start
+		}
+		columns = append(columns,
+			"COALESCE(SUM(CASE WHEN recorded_at >= ? THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN recorded_at >= ? AND success THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN recorded_at >= ? AND processing_ms <= ? THEN 1 ELSE 0 END), 0)",
+		)
+		args = append(args, start.UTC(), start.UTC(), start.UTC(), thresholdMS)
+	}
+	args = append(args, tenantIDFromCtxMySQL(ctx), earliest.UTC())
+
+	out := make([]SLOWindowCount, len(since))
+	dest := make([]any, 0, len(columns))
+	for i := range out {
+		out[i].Since = since[i].UTC()
+		dest = append(dest, &out[i].Total, &out[i].Success, &out[i].WithinThreshold)
+	}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT `+strings.Join(columns, ", ")+`
+		FROM webhook_delivery_metrics
+		WHERE tenant_id = ?
+		  AND recorded_at >= ?
+		  AND outcome NOT IN (`+sloExcludedOutcomes+`)
+	`, args...).Scan(dest...)
+	if err != nil {
+		return nil, fmt.Errorf("count slo deliveries: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	CredentialKindWebhookSecret = "webhook_secret"
+	CredentialKindGitHubToken   = "github_token"
+	// CredentialKindWebhookSecretPrevious is the outgoing secret during a rotation;
+	// it is accepted until ExpiresAt.
+	CredentialKindWebhookSecretPrevious = "webhook_secret_previous"
+)
+
+// TenantCredentialRecord holds a per-tenant secret. SealedValue is encrypted by the
+// caller and never serialized. A zero ExpiresAt means the credential does not expire.
+type TenantCredentialRecord struct {
+	TenantID    string    `json:"tenant_id"`
+	Kind        string    `json:"kind"`
+	SealedValue string    `json:"-"`
+	UpdatedBy   string    `json:"updated_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func normalizeCredentialExpiresAt(t time.Time) time.Time {
+	if t.IsZero() || t.Unix() == 0 {
+		return time.Time{}
+	}
+	return t
+}
+
+func IsValidCredentialKind(kind string) bool {
+	return kind == CredentialKindWebhookSecret || kind == CredentialKindGitHubToken
+}
+
+func (s *WebhookEventStore) ensureTenantCredentialsSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS tenant_credentials (
+			tenant_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			sealed_value TEXT NOT NULL,
+			updated_by TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMPTZ NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, kind)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create tenant_credentials table: %w", err)
+	}
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE tenant_credentials ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ NULL`)
+	return nil
+}
+
+// GetTenantCredential returns the sealed credential of kind for the current tenant.
+func (s *WebhookEventStore) GetTenantCredential(ctx context.Context, kind string) (TenantCredentialRecord, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	var item TenantCredentialRecord
+	err := s.pool.QueryRow(ctx, `
+		SELECT tenant_id, kind, sealed_value, updated_by, COALESCE(expires_at, 'epoch'::timestamptz), created_at, updated_at
+		FROM tenant_credentials
+		WHERE tenant_id = $1 AND kind = $2
+	`, tenantID, strings.TrimSpace(kind)).Scan(&item.TenantID, &item.Kind, &item.SealedValue, &item.UpdatedBy, &item.ExpiresAt, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return item, fmt.Errorf("credential not found")
+		}
+		return item, fmt.Errorf("get tenant credential: %w", err)
+	}
+	item.ExpiresAt = normalizeCredentialExpiresAt(item.ExpiresAt)
+	return item, nil
+}
+
+// TenantCredentialsVersion changes whenever any tenant credential is added,
+// changed or removed, so caches in other processes can tell they are stale.
+func (s *WebhookEventStore) TenantCredentialsVersion(ctx context.Context) (string, error) {
+	var count int64
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(MAX(updated_at), 'epoch'::timestamptz)
+		FROM tenant_credentials
+	`).Scan(&count, &updatedAt)
+	if err != nil {
+		return "", fmt.Errorf("get tenant credentials version: %w", err)
+	}
+	return fmt.Sprintf("%d/%d", count, updatedAt.UnixNano()), nil
+}
+
+func (s *WebhookEventStore) ListTenantCredentials(ctx context.Context, tenantID string) ([]TenantCredentialRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT tenant_id, kind, updated_by, COALESCE(expires_at, 'epoch'::timestamptz), created_at, updated_at
+		FROM tenant_credentials
+		WHERE tenant_id = $1
+		ORDER BY kind ASC
+	`, strings.TrimSpace(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("query tenant credentials: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]TenantCredentialRecord, 0, 2)
+	for rows.Next() {
+		var item TenantCredentialRecord
+		if err := rows.Scan(&item.TenantID, &item.Kind, &item.UpdatedBy, &item.ExpiresAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan tenant credential: %w", err)
+		}
+		item.ExpiresAt = normalizeCredentialExpiresAt(item.ExpiresAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tenant credentials: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) UpsertTenantCredential(ctx context.Context, item TenantCredentialRecord) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO tenant_credentials (tenant_id, kind, sealed_value, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, kind) DO UPDATE
+		SET sealed_value = EXCLUDED.sealed_value,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+	`, strings.TrimSpace(item.TenantID), strings.TrimSpace(item.Kind), item.SealedValue, strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("upsert tenant credential: %w", err)
+	}
+	return nil
+}
+
+func (s *WebhookEventStore) DeleteTenantCredential(ctx context.Context, tenantID string, kind string) error {
+	result, err := s.pool.Exec(ctx, `
+		DELETE FROM tenant_credentials
+		WHERE tenant_id = $1 AND kind = $2
+	`, strings.TrimSpace(tenantID), strings.TrimSpace(kind))
+	if err != nil {
+		return fmt.Errorf("delete tenant credential: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("credential not found")
+	}
+	return nil
+}
+
+// RotateTenantWebhookSecret makes sealedValue the tenant's webhook secret and keeps
+// the current one as the previous secret until previousExpiresAt.
+func (s *WebhookEventStore) RotateTenantWebhookSecret(ctx context.Context, tenantID string, sealedValue string, previousExpiresAt time.Time, actor string) error {
+	tenantID = strings.TrimSpace(tenantID)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin rotate webhook secret tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM tenant_credentials WHERE tenant_id = $1 AND kind = $2`, tenantID, CredentialKindWebhookSecretPrevious); err != nil {
+		return fmt.Errorf("clear previous webhook secret: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE tenant_credentials
+		SET kind = $3, expires_at = $4, updated_by = $5, updated_at = NOW()
+		WHERE tenant_id = $1 AND kind = $2
+	`, tenantID, CredentialKindWebhookSecret, CredentialKindWebhookSecretPrevious, previousExpiresAt, strings.TrimSpace(actor)); err != nil {
+		return fmt.Errorf("demote current webhook secret: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO tenant_credentials (tenant_id, kind, sealed_value, updated_by)
+		VALUES ($1, $2, $3, $4)
+	`, tenantID, CredentialKindWebhookSecret, sealedValue, strings.TrimSpace(actor)); err != nil {
+		return fmt.Errorf("insert new webhook secret: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit rotate webhook secret tx: %w", err)
+	}
+	return nil
+}
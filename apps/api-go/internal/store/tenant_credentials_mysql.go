@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var mysqlTenantCredentialsSchema = []string{
+	`CREATE TABLE IF NOT EXISTS tenant_credentials (
+		tenant_id VARCHAR(64) NOT NULL,
+		kind VARCHAR(64) NOT NULL,
+		sealed_value TEXT NOT NULL,
+		updated_by VARCHAR(191) NOT NULL DEFAULT '',
+		expires_at DATETIME(6) NULL,
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (tenant_id, kind)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+func (s *MySQLWebhookEventStore) GetTenantCredential(ctx context.Context, kind string) (TenantCredentialRecord, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	var item TenantCredentialRecord
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT tenant_id, kind, sealed_value, updated_by, expires_at, created_at, updated_at
+		FROM tenant_credentials
+		WHERE tenant_id = ? AND kind = ?
+	`, tenantID, strings.TrimSpace(kind)).Scan(&item.TenantID, &item.Kind, &item.SealedValue, &item.UpdatedBy, &expiresAt, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return item, fmt.Errorf("credential not found")
+		}
+		return item, fmt.Errorf("get tenant credential: %w", err)
+	}
+	if expiresAt.Valid {
+		item.ExpiresAt = expiresAt.Time
+	}
+	return item, nil
+}
+
+func (s *MySQLWebhookEventStore) TenantCredentialsVersion(ctx context.Context) (string, error) {
+	var count int64
+	var updatedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), MAX(updated_at)
+		FROM tenant_credentials
+	`).Scan(&count, &updatedAt)
+	if err != nil {
+		return "", fmt.Errorf("get tenant credentials version: %w", err)
+	}
+	return fmt.Sprintf("%d/%d", count, updatedAt.Time.UnixNano()), nil
+}
+
+func (s *MySQLWebhookEventStore) ListTenantCredentials(ctx context.Context, tenantID string) ([]TenantCredentialRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT tenant_id, kind, updated_by, expires_at, created_at, updated_at
+		FROM tenant_credentials
+		WHERE tenant_id = ?
+		ORDER BY kind ASC
+	`, strings.TrimSpace(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("query tenant credentials: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]TenantCredentialRecord, 0, 2)
+	for rows.Next() {
+		var item TenantCredentialRecord
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&item.TenantID, &item.Kind, &item.UpdatedBy, &expiresAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan tenant credential: %w", err)
+		}
+		if expiresAt.Valid {
+			item.ExpiresAt = expiresAt.Time
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tenant credentials: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) UpsertTenantCredential(ctx context.Context, item TenantCredentialRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO tenant_credentials (tenant_id, kind, sealed_value, updated_by)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			sealed_value = VALUES(sealed_value),
+			updated_by = VALUES(updated_by),
+			updated_at = CURRENT_TIMESTAMP(6)
+	`, strings.TrimSpace(item.TenantID), strings.TrimSpace(item.Kind), item.SealedValue, strings.TrimSpace(item.UpdatedBy))
+	if err != nil {
+		return fmt.Errorf("upsert tenant credential: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) DeleteTenantCredential(ctx context.Context, tenantID string, kind string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM tenant_credentials
+		WHERE tenant_id = ? AND kind = ?
+	`, strings.TrimSpace(tenantID), strings.TrimSpace(kind))
+	if err != nil {
+		return fmt.Errorf("delete tenant credential: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for tenant credential delete: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("credential not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) RotateTenantWebhookSecret(ctx context.Context, tenantID string, sealedValue string, previousExpiresAt time.Time, actor string) error {
+	tenantID = strings.TrimSpace(tenantID)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin rotate webhook secret tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tenant_credentials WHERE tenant_id = ? AND kind = ?`, tenantID, CredentialKindWebhookSecretPrevious); err != nil {
+		return fmt.Errorf("clear previous webhook secret: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tenant_credentials
+		SET kind = ?, expires_at = ?, updated_by = ?
+		WHERE tenant_id = ? AND kind = ?
+	`, CredentialKindWebhookSecretPrevious, previousExpiresAt, strings.TrimSpace(actor), tenantID, CredentialKindWebhookSecret); err != nil {
+		return fmt.Errorf("demote current webhook secret: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO tenant_credentials (tenant_id, kind, sealed_value, updated_by)
+		VALUES (?, ?, ?, ?)
+	`, tenantID, CredentialKindWebhookSecret, sealedValue, strings.TrimSpace(actor)); err != nil {
+		return fmt.Errorf("insert new webhook secret: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rotate webhook secret tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"maintainer-firewall/api-go/internal/tracing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxTracedStatementLen = 256
+
+// queryAttributes describes a store query on its span. Statements are traced
+// with placeholders only; argument values never reach the exporter.
+func queryAttributes(ctx context.Context, system string, statement string) []attribute.KeyValue {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > maxTracedStatementLen {
+		statement = statement[:maxTracedStatementLen] + "..."
+	}
+	return []attribute.KeyValue{
+		attribute.String("db.system", system),
+		attribute.String("db.statement", statement),
+		attribute.String("mf.tenant_id", tenantIDFromCtx(ctx)),
+	}
+}
+
+type pgxSpanKey struct{}
+
+// pgxQueryTracer records a span for every query run through the pool.
+type pgxQueryTracer struct{}
+
+func (pgxQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx
+	}
+	spanCtx, span := tracing.Start(ctx, "db.query", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(queryAttributes(ctx, "postgresql", data.SQL)...))
+	return context.WithValue(spanCtx, pgxSpanKey{}, span)
+}
+
+func (pgxQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, _ := ctx.Value(pgxSpanKey{}).(trace.Span)
+	if span == nil {
+		return
+	}
+	tracing.RecordError(span, data.Err)
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	span.End()
+}
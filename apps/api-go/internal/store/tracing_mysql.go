@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// openTracedMySQL opens the MySQL pool through otelsql, so that statements run
+// through database/sql are recorded as "db.query" spans like the Postgres ones.
+func openTracedMySQL(connector driver.Connector) *sql.DB {
+	return otelsql.OpenDB(connector,
+		otelsql.WithAttributes(attribute.String("db.system", "mysql")),
+		otelsql.WithAttributesGetter(func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("mf.tenant_id", tenantIDFromCtxMySQL(ctx))}
+		}),
+		otelsql.WithSpanNameFormatter(func(_ context.Context, method otelsql.Method, _ string) string {
+			switch method {
+			case otelsql.MethodConnQuery, otelsql.MethodConnExec, otelsql.MethodStmtQuery, otelsql.MethodStmtExec:
+				return "db.query"
+			}
+			return string(method)
+		}),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			OmitConnectorConnect: true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanFromContext(ctx).IsRecording()
+			},
+		}),
+	)
+}
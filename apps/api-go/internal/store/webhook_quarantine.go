@@ -0,0 +1,210 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Quarantine statuses. Pending deliveries can be fixed up and re-ingested;
+// a delivery is reingesting while one request runs it through the pipeline.
+const (
+	QuarantineStatusPending     = "pending"
+	QuarantineStatusReingesting = "reingesting"
+	QuarantineStatusReingested  = "reingested"
+	QuarantineStatusDiscarded   = "discarded"
+)
+
+// QuarantinedDelivery is a webhook delivery rejected for its size or payload.
+// Payload holds the raw body, which need not be valid JSON; it is empty when the
+// body exceeded the size limit.
+type QuarantinedDelivery struct {
+	ID          int64     `json:"id"`
+	Source      string    `json:"source"`
+	DeliveryID  string    `json:"delivery_id"`
+	EventName   string    `json:"event_name"`
+	Reason      string    `json:"reason"`
+	HeadersJSON string    `json:"headers_json"`
+	Payload     []byte    `json:"-"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	UpdatedBy   string    `json:"updated_by"`
+	ReceivedAt  time.Time `json:"received_at"`
+	ResolvedAt  time.Time `json:"resolved_at"`
+}
+
+func IsValidQuarantineStatus(status string) bool {
+	return status == QuarantineStatusPending || status == QuarantineStatusReingesting || status == QuarantineStatusReingested || status == QuarantineStatusDiscarded
+}
+
+func (s *WebhookEventStore) ensureWebhookQuarantineSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS webhook_quarantine (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT 'github',
+			delivery_id TEXT NOT NULL DEFAULT '',
+			event_name TEXT NOT NULL DEFAULT '',
+			reason TEXT NOT NULL,
+			headers_json TEXT NOT NULL DEFAULT '{}',
+			payload BYTEA NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			note TEXT NOT NULL DEFAULT '',
+			updated_by TEXT NOT NULL DEFAULT '',
+			received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMPTZ NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create webhook_quarantine table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_webhook_quarantine_tenant_status
+		ON webhook_quarantine (tenant_id, status, received_at DESC)
+	`)
+	if err != nil {
+		return fmt.Errorf("create idx_webhook_quarantine_tenant_status: %w", err)
+	}
+	return nil
+}
+
+const webhookQuarantineColumns = `id, source, delivery_id, event_name, reason, headers_json, payload, status, note, updated_by, received_at, COALESCE(resolved_at, 'epoch'::timestamptz)`
+
+type quarantineScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuarantinedDelivery(row quarantineScanner) (QuarantinedDelivery, error) {
+	var item QuarantinedDelivery
+	err := row.Scan(&item.ID, &item.Source, &item.DeliveryID, &item.EventName, &item.Reason, &item.HeadersJSON, &item.Payload,
+		&item.Status, &item.Note, &item.UpdatedBy, &item.ReceivedAt, &item.ResolvedAt)
+	if item.ResolvedAt.Unix() == 0 {
+		item.ResolvedAt = time.Time{}
+	}
+	return item, err
+}
+
+func (s *WebhookEventStore) SaveQuarantinedDelivery(ctx context.Context, item QuarantinedDelivery) (int64, error) {
+	if item.Payload == nil {
+		item.Payload = []byte{}
+	}
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO webhook_quarantine (tenant_id, source, delivery_id, event_name, reason, headers_json, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, tenantIDFromCtx(ctx), normalizeEventSource(item.Source), strings.TrimSpace(item.DeliveryID), strings.TrimSpace(item.EventName),
+		item.Reason, item.HeadersJSON, item.Payload).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("save quarantined delivery: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) ListQuarantinedDeliveries(ctx context.Context, limit int, offset int, status string) ([]QuarantinedDelivery, int64, error) {
+	tenantID := tenantIDFromCtx(ctx)
+	st := strings.TrimSpace(status)
+
+	var total int64
+	if err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM webhook_quarantine WHERE tenant_id = $1 AND ($2 = '' OR status = $2)
+	`, tenantID, st).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count quarantined deliveries: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+webhookQuarantineColumns+`
+		FROM webhook_quarantine
+		WHERE tenant_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY received_at DESC
+		LIMIT $3 OFFSET $4
+	`, tenantID, st, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query quarantined deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuarantinedDelivery, 0, limit)
+	for rows.Next() {
+		item, err := scanQuarantinedDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan quarantined delivery: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate quarantined deliveries: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *WebhookEventStore) GetQuarantinedDelivery(ctx context.Context, id int64) (QuarantinedDelivery, error) {
+	item, err := scanQuarantinedDelivery(s.pool.QueryRow(ctx, `
+		SELECT `+webhookQuarantineColumns+` FROM webhook_quarantine WHERE id = $1 AND tenant_id = $2
+	`, id, tenantIDFromCtx(ctx)))
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return QuarantinedDelivery{}, fmt.Errorf("quarantined delivery not found")
+		}
+		return QuarantinedDelivery{}, fmt.Errorf("get quarantined delivery: %w", err)
+	}
+	return item, nil
+}
+
+// UpdateQuarantinedPayload replaces the stored body of a pending delivery.
+func (s *WebhookEventStore) UpdateQuarantinedPayload(ctx context.Context, id int64, payload []byte, actor string) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE webhook_quarantine SET payload = $3, updated_by = $4
+		WHERE id = $1 AND tenant_id = $2 AND status = 'pending'
+	`, id, tenantIDFromCtx(ctx), payload, strings.TrimSpace(actor))
+	if err != nil {
+		return fmt.Errorf("update quarantined payload: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("pending quarantined delivery not found")
+	}
+	return nil
+}
+
+// ClaimQuarantinedDelivery moves a pending delivery to reingesting. It reports
+// false when another request claimed or resolved it first.
+func (s *WebhookEventStore) ClaimQuarantinedDelivery(ctx context.Context, id int64, actor string) (bool, error) {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE webhook_quarantine SET status = 'reingesting', updated_by = $3
+		WHERE id = $1 AND tenant_id = $2 AND status = 'pending'
+	`, id, tenantIDFromCtx(ctx), strings.TrimSpace(actor))
+	if err != nil {
+		return false, fmt.Errorf("claim quarantined delivery: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ReleaseQuarantinedDelivery returns a claimed delivery to pending after a
+// failed re-ingest.
+func (s *WebhookEventStore) ReleaseQuarantinedDelivery(ctx context.Context, id int64) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE webhook_quarantine SET status = 'pending'
+		WHERE id = $1 AND tenant_id = $2 AND status = 'reingesting'
+	`, id, tenantIDFromCtx(ctx))
+	if err != nil {
+		return fmt.Errorf("release quarantined delivery: %w", err)
+	}
+	return nil
+}
+
+// ResolveQuarantinedDelivery closes a pending or reingesting delivery.
+func (s *WebhookEventStore) ResolveQuarantinedDelivery(ctx context.Context, id int64, status string, note string, actor string) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE webhook_quarantine SET status = $3, note = $4, updated_by = $5, resolved_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('pending', 'reingesting')
+	`, id, tenantIDFromCtx(ctx), status, note, strings.TrimSpace(actor))
+	if err != nil {
+		return fmt.Errorf("resolve quarantined delivery: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("pending quarantined delivery not found")
+	}
+	return nil
+}
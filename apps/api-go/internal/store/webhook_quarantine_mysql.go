@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var mysqlWebhookQuarantineSchema = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_quarantine (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		source VARCHAR(32) NOT NULL DEFAULT 'github',
+		delivery_id VARCHAR(191) NOT NULL DEFAULT '',
+		event_name VARCHAR(128) NOT NULL DEFAULT '',
+		reason TEXT NOT NULL,
+		headers_json TEXT NOT NULL,
+		payload LONGBLOB NOT NULL,
+		status VARCHAR(32) NOT NULL DEFAULT 'pending',
+		note TEXT NOT NULL,
+		updated_by VARCHAR(191) NOT NULL DEFAULT '',
+		received_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		resolved_at DATETIME(6) NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE INDEX idx_webhook_quarantine_tenant_status ON webhook_quarantine (tenant_id, status, received_at)`,
+}
+
+const mysqlWebhookQuarantineColumns = `id, source, delivery_id, event_name, reason, headers_json, payload, status, note, updated_by, received_at, resolved_at`
+
+func scanMySQLQuarantinedDelivery(row quarantineScanner) (QuarantinedDelivery, error) {
+	var item QuarantinedDelivery
+	var resolvedAt sql.NullTime
+	err := row.Scan(&item.ID, &item.Source, &item.DeliveryID, &item.EventName, &item.Reason, &item.HeadersJSON, &item.Payload,
+		&item.Status, &item.Note, &item.UpdatedBy, &item.ReceivedAt, &resolvedAt)
+	if resolvedAt.Valid {
+		item.ResolvedAt = resolvedAt.Time
+	}
+	return item, err
+}
+
+func (s *MySQLWebhookEventStore) SaveQuarantinedDelivery(ctx context.Context, item QuarantinedDelivery) (int64, error) {
+	if item.Payload == nil {
+		item.Payload = []byte{}
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_quarantine (tenant_id, source, delivery_id, event_name, reason, headers_json, payload, note)
+		VALUES (?, ?, ?, ?, ?, ?, ?, '')
+	`, tenantIDFromCtxMySQL(ctx), normalizeEventSource(item.Source), strings.TrimSpace(item.DeliveryID), strings.TrimSpace(item.EventName),
+		item.Reason, item.HeadersJSON, item.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("save quarantined delivery: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get quarantined delivery id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) ListQuarantinedDeliveries(ctx context.Context, limit int, offset int, status string) ([]QuarantinedDelivery, int64, error) {
+	tenantID := tenantIDFromCtxMySQL(ctx)
+	st := strings.TrimSpace(status)
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM webhook_quarantine WHERE tenant_id = ? AND (? = '' OR status = ?)
+	`, tenantID, st, st).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count quarantined deliveries: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+mysqlWebhookQuarantineColumns+`
+		FROM webhook_quarantine
+		WHERE tenant_id = ? AND (? = '' OR status = ?)
+		ORDER BY received_at DESC
+		LIMIT ? OFFSET ?
+	`, tenantID, st, st, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query quarantined deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]QuarantinedDelivery, 0, limit)
+	for rows.Next() {
+		item, err := scanMySQLQuarantinedDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan quarantined delivery: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate quarantined deliveries: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *MySQLWebhookEventStore) GetQuarantinedDelivery(ctx context.Context, id int64) (QuarantinedDelivery, error) {
+	item, err := scanMySQLQuarantinedDelivery(s.db.QueryRowContext(ctx, `
+		SELECT `+mysqlWebhookQuarantineColumns+` FROM webhook_quarantine WHERE id = ? AND tenant_id = ?
+	`, id, tenantIDFromCtxMySQL(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return QuarantinedDelivery{}, fmt.Errorf("quarantined delivery not found")
+		}
+		return QuarantinedDelivery{}, fmt.Errorf("get quarantined delivery: %w", err)
+	}
+	return item, nil
+}
+
+func (s *MySQLWebhookEventStore) UpdateQuarantinedPayload(ctx context.Context, id int64, payload []byte, actor string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_quarantine SET payload = ?, updated_by = ?
+		WHERE id = ? AND tenant_id = ? AND status = 'pending'
+	`, payload, strings.TrimSpace(actor), id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("update quarantined payload: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for quarantined payload update: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("pending quarantined delivery not found")
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ClaimQuarantinedDelivery(ctx context.Context, id int64, actor string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_quarantine SET status = 'reingesting', updated_by = ?
+		WHERE id = ? AND tenant_id = ? AND status = 'pending'
+	`, strings.TrimSpace(actor), id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return false, fmt.Errorf("claim quarantined delivery: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get affected rows for quarantined delivery claim: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (s *MySQLWebhookEventStore) ReleaseQuarantinedDelivery(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_quarantine SET status = 'pending'
+		WHERE id = ? AND tenant_id = ? AND status = 'reingesting'
+	`, id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("release quarantined delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *MySQLWebhookEventStore) ResolveQuarantinedDelivery(ctx context.Context, id int64, status string, note string, actor string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_quarantine SET status = ?, note = ?, updated_by = ?, resolved_at = CURRENT_TIMESTAMP(6)
+		WHERE id = ? AND tenant_id = ? AND status IN ('pending', 'reingesting')
+	`, status, note, strings.TrimSpace(actor), id, tenantIDFromCtxMySQL(ctx))
+	if err != nil {
+		return fmt.Errorf("resolve quarantined delivery: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for quarantined delivery resolve: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("pending quarantined delivery not found")
+	}
+	return nil
+}
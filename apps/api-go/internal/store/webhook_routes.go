@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Webhook route kinds. A token route matches the SHA-256 of an opaque URL token;
+// repository and installation routes map deliveries on the shared endpoint.
+const (
+	WebhookRouteKindToken        = "token"
+	WebhookRouteKindRepository   = "repository"
+	WebhookRouteKindInstallation = "installation"
+)
+
+type WebhookRouteRecord struct {
+	ID         int64     `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	Kind       string    `json:"kind"`
+	MatchValue string    `json:"match_value"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func IsValidWebhookRouteKind(kind string) bool {
+	return kind == WebhookRouteKindToken || kind == WebhookRouteKindRepository || kind == WebhookRouteKindInstallation
+}
+
+func (s *WebhookEventStore) ensureWebhookRoutesSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS webhook_routes (
+			id BIGSERIAL PRIMARY KEY,
+			tenant_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			match_value TEXT NOT NULL,
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create webhook_routes table: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_routes_kind_match
+		ON webhook_routes (kind, match_value)
+	`)
+	if err != nil {
+		return fmt.Errorf("create uq_webhook_routes_kind_match: %w", err)
+	}
+	return nil
+}
+
+// ResolveWebhookRoute is not tenant scoped: it is how a delivery finds its tenant.
+func (s *WebhookEventStore) ResolveWebhookRoute(ctx context.Context, kind string, matchValue string) (string, error) {
+	var tenantID string
+	err := s.pool.QueryRow(ctx, `
+		SELECT tenant_id FROM webhook_routes WHERE kind = $1 AND match_value = $2
+	`, strings.TrimSpace(kind), strings.TrimSpace(matchValue)).Scan(&tenantID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return "", fmt.Errorf("webhook route not found")
+		}
+		return "", fmt.Errorf("resolve webhook route: %w", err)
+	}
+	return tenantID, nil
+}
+
+func (s *WebhookEventStore) ListWebhookRoutes(ctx context.Context, tenantID string) ([]WebhookRouteRecord, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, tenant_id, kind, match_value, created_by, created_at
+		FROM webhook_routes
+		WHERE tenant_id = $1
+		ORDER BY id ASC
+	`, strings.TrimSpace(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("query webhook routes: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WebhookRouteRecord, 0, 8)
+	for rows.Next() {
+		var item WebhookRouteRecord
+		if err := rows.Scan(&item.ID, &item.TenantID, &item.Kind, &item.MatchValue, &item.CreatedBy, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook route: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook routes: %w", err)
+	}
+	return items, nil
+}
+
+func (s *WebhookEventStore) CreateWebhookRoute(ctx context.Context, item WebhookRouteRecord) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO webhook_routes (tenant_id, kind, match_value, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, strings.TrimSpace(item.TenantID), strings.TrimSpace(item.Kind), strings.TrimSpace(item.MatchValue), strings.TrimSpace(item.CreatedBy)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("create webhook route: %w", err)
+	}
+	return id, nil
+}
+
+func (s *WebhookEventStore) DeleteWebhookRoute(ctx context.Context, tenantID string, id int64) error {
+	result, err := s.pool.Exec(ctx, `
+		DELETE FROM webhook_routes WHERE id = $1 AND tenant_id = $2
+	`, id, strings.TrimSpace(tenantID))
+	if err != nil {
+		return fmt.Errorf("delete webhook route: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("webhook route not found")
+	}
+	return nil
+}
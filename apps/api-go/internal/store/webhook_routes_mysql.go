@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var mysqlWebhookRoutesSchema = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_routes (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		tenant_id VARCHAR(64) NOT NULL,
+		kind VARCHAR(32) NOT NULL,
+		match_value VARCHAR(191) NOT NULL,
+		created_by VARCHAR(191) NOT NULL DEFAULT '',
+		created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	`CREATE UNIQUE INDEX uq_webhook_routes_kind_match ON webhook_routes (kind, match_value)`,
+}
+
+func (s *MySQLWebhookEventStore) ResolveWebhookRoute(ctx context.Context, kind string, matchValue string) (string, error) {
+	var tenantID string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT tenant_id FROM webhook_routes WHERE kind = ? AND match_value = ?
+	`, strings.TrimSpace(kind), strings.TrimSpace(matchValue)).Scan(&tenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("webhook route not found")
+		}
+		return "", fmt.Errorf("resolve webhook route: %w", err)
+	}
+	return tenantID, nil
+}
+
+func (s *MySQLWebhookEventStore) ListWebhookRoutes(ctx context.Context, tenantID string) ([]WebhookRouteRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, kind, match_value, created_by, created_at
+		FROM webhook_routes
+		WHERE tenant_id = ?
+		ORDER BY id ASC
+	`, strings.TrimSpace(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("query webhook routes: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WebhookRouteRecord, 0, 8)
+	for rows.Next() {
+		var item WebhookRouteRecord
+		if err := rows.Scan(&item.ID, &item.TenantID, &item.Kind, &item.MatchValue, &item.CreatedBy, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook route: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook routes: %w", err)
+	}
+	return items, nil
+}
+
+func (s *MySQLWebhookEventStore) CreateWebhookRoute(ctx context.Context, item WebhookRouteRecord) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_routes (tenant_id, kind, match_value, created_by)
+		VALUES (?, ?, ?, ?)
+	`, strings.TrimSpace(item.TenantID), strings.TrimSpace(item.Kind), strings.TrimSpace(item.MatchValue), strings.TrimSpace(item.CreatedBy))
+	if err != nil {
+		return 0, fmt.Errorf("create webhook route: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get webhook route id: %w", err)
+	}
+	return id, nil
+}
+
+func (s *MySQLWebhookEventStore) DeleteWebhookRoute(ctx context.Context, tenantID string, id int64) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM webhook_routes WHERE id = ? AND tenant_id = ?
+	`, id, strings.TrimSpace(tenantID))
+	if err != nil {
+		return fmt.Errorf("delete webhook route: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for webhook route delete: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("webhook route not found")
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package store
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +14,15 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Event sources. Events from other forges are stored with GitHub-shaped payloads
+// so that rules, retries and listings work the same for every source.
+const (
+	EventSourceGitHub = "github"
+	EventSourceGitLab = "gitlab"
+	EventSourceGitea  = "gitea"
 )
 
 type WebhookEvent struct {
@@ -24,6 +32,15 @@ type WebhookEvent struct {
 	RepositoryFullName string
 	SenderLogin        string
 	PayloadJSON        json.RawMessage
+	Source             string
+}
+
+func normalizeEventSource(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		return EventSourceGitHub
+	}
+	return source
 }
 
 type WebhookEventStore struct {
@@ -36,6 +53,7 @@ type WebhookEventRecord struct {
 	Action             string          `json:"action"`
 	RepositoryFullName string          `json:"repository_full_name"`
 	SenderLogin        string          `json:"sender_login"`
+	Source             string          `json:"source"`
 	PayloadJSON        json.RawMessage `json:"payload_json,omitempty"`
 	ReceivedAt         time.Time       `json:"received_at"`
 }
@@ -89,6 +107,7 @@ type ActionExecutionFailure struct {
 	EventType          string    `json:"event_type"`
 	Action             string    `json:"action"`
 	RepositoryFullName string    `json:"repository_full_name"`
+	Source             string    `json:"source"`
 	SuggestionType     string    `json:"suggestion_type"`
 	SuggestionValue    string    `json:"suggestion_value"`
 	ErrorMessage       string    `json:"error_message"`
@@ -106,6 +125,7 @@ type EventFilterOptions struct {
 	Actions      []string `json:"actions"`
 	Repositories []string `json:"repositories"`
 	Senders      []string `json:"senders"`
+	Sources      []string `json:"sources"`
 }
 
 type AlertFilterOptions struct {
@@ -123,18 +143,29 @@ type RuleFilterOptions struct {
 }
 
 type TenantRecord struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                   string    `json:"id"`
+	Name                 string    `json:"name"`
+	IsActive             bool      `json:"is_active"`
+	AutoRetryEnabled     bool      `json:"auto_retry_enabled"`
+	GitHubInstallationID int64     `json:"github_installation_id"`
+	SignatureSchemes     string    `json:"webhook_signature_schemes"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
 }
 
 type ActionExecutionFailureRecord struct {
-	ID int64 `json:"id"`
+	ID       int64  `json:"id"`
+	TenantID string `json:"tenant_id,omitempty"`
 	ActionExecutionFailure
 }
 
+type ActionFailureFilter struct {
+	EventType          string `json:"event_type"`
+	SuggestionType     string `json:"suggestion_type"`
+	RepositoryFullName string `json:"repository_full_name"`
+	LastRetryStatus    string `json:"last_retry_status"`
+}
+
 type AuditLogRecord struct {
 	ID        int64     `json:"id"`
 	Actor     string    `json:"actor"`
@@ -145,10 +176,21 @@ type AuditLogRecord struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+const (
+	DeliveryOutcomeProcessed    = "processed"
+	DeliveryOutcomeFailed       = "failed"
+	DeliveryOutcomeUnauthorized = "unauthorized"
+	DeliveryOutcomeMisrouted    = "misrouted"
+	DeliveryOutcomeQuarantined  = "quarantined"
+	DeliveryOutcomeTooLarge     = "too_large"
+)
+
 type DeliveryMetric struct {
 	EventType     string    `json:"event_type"`
 	DeliveryID    string    `json:"delivery_id"`
 	Success       bool      `json:"success"`
+	Outcome       string    `json:"outcome"`
+	MatchedSecret string    `json:"matched_secret"`
 	ProcessingMS  int64     `json:"processing_ms"`
 	RecordedAtUTC time.Time `json:"recorded_at_utc"`
 }
@@ -166,6 +208,7 @@ type MetricsOverview struct {
 	FailureRate24h            float64 `json:"failure_rate_24h"`
 	EstimatedManualMinutes24h float64 `json:"estimated_manual_minutes_24h"`
 	AvgProcessingMS24h        float64 `json:"avg_processing_ms_24h"`
+	MisroutedDeliveries24h    int64   `json:"misrouted_deliveries_24h"`
 }
 
 type MetricsTimePoint struct {
@@ -178,7 +221,7 @@ type MetricsTimePoint struct {
 type WebhookStore interface {
 	Close()
 	SaveEvent(ctx context.Context, evt WebhookEvent) error
-	SaveAlert(ctx context.Context, alert AlertRecord) error
+	SaveAlert(ctx context.Context, alert AlertRecord) (int64, error)
 	ListEvents(ctx context.Context, limit int, offset int, eventType string, action string) ([]WebhookEventRecord, int64, error)
 	ListAlerts(ctx context.Context, limit int, offset int, eventType string, action string, suggestionType string) ([]AlertRecord, int64, error)
 	ListRules(ctx context.Context, limit int, offset int, eventType string, keyword string, activeOnly bool) ([]RuleRecord, int64, error)
@@ -192,6 +235,44 @@ type WebhookStore interface {
 	GetActionExecutionFailureByID(ctx context.Context, id int64) (ActionExecutionFailureRecord, error)
 	UpdateActionFailureRetryResult(ctx context.Context, id int64, success bool, message string) error
 	GetWebhookEventPayloadByDeliveryID(ctx context.Context, deliveryID string) (json.RawMessage, error)
+	GetWebhookEventByDeliveryID(ctx context.Context, deliveryID string) (WebhookEventRecord, error)
+	ListEventsForReprocess(ctx context.Context, filter EventReprocessFilter, limit int) ([]WebhookEventRecord, error)
+	CreateRecoveryHook(ctx context.Context, item RecoveryHookRecord) (int64, error)
+	ListRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error)
+	ListActiveRecoveryHooks(ctx context.Context) ([]RecoveryHookRecord, error)
+	GetRecoveryHook(ctx context.Context, id int64) (RecoveryHookRecord, error)
+	DeleteRecoveryHook(ctx context.Context, id int64) error
+	RecordRecoveryRun(ctx context.Context, id int64, run RecoveryRunRecord) error
+	ListKnownDeliveryIDs(ctx context.Context, deliveryIDs []string) (map[string]bool, error)
+	CreateGitHubEventSource(ctx context.Context, item GitHubEventSourceRecord) (int64, error)
+	ListGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error)
+	ListActiveGitHubEventSources(ctx context.Context) ([]GitHubEventSourceRecord, error)
+	DeleteGitHubEventSource(ctx context.Context, id int64) error
+	RecordGitHubEventSourcePoll(ctx context.Context, id int64, etag string, lastEventID int64, status string, message string) error
+	GetGitHubSyncSchedule(ctx context.Context) (GitHubSyncScheduleRecord, error)
+	UpsertGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error
+	EnsureGitHubSyncSchedule(ctx context.Context, item GitHubSyncScheduleRecord) error
+	UpdateGitHubSyncSchedulePaused(ctx context.Context, isPaused bool, nextRunAt time.Time, updatedBy string) error
+	ListDueGitHubSyncSchedules(ctx context.Context, now time.Time, limit int) ([]GitHubSyncScheduleRecord, error)
+	ClaimGitHubSyncSchedule(ctx context.Context, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error)
+	CreateGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) (int64, error)
+	FinishGitHubSyncRun(ctx context.Context, run GitHubSyncRunRecord) error
+	ListGitHubSyncRuns(ctx context.Context, limit int, offset int) ([]GitHubSyncRunRecord, int64, error)
+	ListGitHubSyncHealth(ctx context.Context) ([]GitHubSyncHealthRecord, error)
+	AcquireWorkerLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	ReleaseWorkerLease(ctx context.Context, name string, holder string) error
+	Ping(ctx context.Context) error
+	CheckSchema(ctx context.Context) error
+	DBStatsCollector() prometheus.Collector
+	CountActionRetryBacklog(ctx context.Context) (int64, error)
+	ListSLOs(ctx context.Context) ([]SLORecord, error)
+	ListActiveSLOs(ctx context.Context) ([]SLORecord, error)
+	GetSLO(ctx context.Context, id int64) (SLORecord, error)
+	CreateSLO(ctx context.Context, item SLORecord) (int64, error)
+	UpdateSLO(ctx context.Context, item SLORecord) error
+	DeleteSLO(ctx context.Context, id int64) error
+	CompactMetricsRollups(ctx context.Context, now time.Time) (int, error)
+	CountSLODeliveries(ctx context.Context, thresholdMS int64, since []time.Time) ([]SLOWindowCount, error)
 	SaveAuditLog(ctx context.Context, item AuditLogRecord) error
 	ListAuditLogs(ctx context.Context, limit int, offset int, actor string, action string, since *time.Time) ([]AuditLogRecord, int64, error)
 	GetAdminUserByUsername(ctx context.Context, username string) (AdminUser, error)
@@ -203,10 +284,52 @@ type WebhookStore interface {
 	ListTenants(ctx context.Context) ([]TenantRecord, error)
 	CreateTenant(ctx context.Context, id string, name string) error
 	UpdateTenantActive(ctx context.Context, id string, isActive bool) error
+	UpdateTenantWebhookSignatureSchemes(ctx context.Context, tenantID string, schemes string) error
 	CreateRuleVersionSnapshot(ctx context.Context, createdBy string, sourceVersion int64) (int64, int, error)
 	ListRuleVersions(ctx context.Context, limit int, offset int) ([]RuleVersionRecord, int64, error)
 	GetRulesByVersion(ctx context.Context, version int64) ([]RuleRecord, error)
 	RestoreRulesFromVersion(ctx context.Context, version int64) (int, error)
+	ListRetryableActionFailures(ctx context.Context, schedule ActionRetrySchedule, limit int) ([]ActionExecutionFailureRecord, error)
+	ClaimActionFailureRetry(ctx context.Context, id int64, lastRetryAt time.Time) (bool, error)
+	ListActionExecutionFailuresByFilter(ctx context.Context, filter ActionFailureFilter, limit int) ([]ActionExecutionFailureRecord, error)
+	MarkActionFailureDead(ctx context.Context, id int64, message string) error
+	GetTenantAutoRetryEnabled(ctx context.Context) (bool, error)
+	UpdateTenantAutoRetryEnabled(ctx context.Context, enabled bool) error
+	GetTenantGitHubInstallationID(ctx context.Context) (int64, error)
+	UpdateTenantGitHubInstallationID(ctx context.Context, tenantID string, installationID int64) error
+	GetTenantCredential(ctx context.Context, kind string) (TenantCredentialRecord, error)
+	TenantCredentialsVersion(ctx context.Context) (string, error)
+	ListTenantCredentials(ctx context.Context, tenantID string) ([]TenantCredentialRecord, error)
+	UpsertTenantCredential(ctx context.Context, item TenantCredentialRecord) error
+	DeleteTenantCredential(ctx context.Context, tenantID string, kind string) error
+	RotateTenantWebhookSecret(ctx context.Context, tenantID string, sealedValue string, previousExpiresAt time.Time, actor string) error
+	GetTenant(ctx context.Context, id string) (TenantRecord, error)
+	ResolveWebhookRoute(ctx context.Context, kind string, matchValue string) (string, error)
+	ListWebhookRoutes(ctx context.Context, tenantID string) ([]WebhookRouteRecord, error)
+	CreateWebhookRoute(ctx context.Context, item WebhookRouteRecord) (int64, error)
+	DeleteWebhookRoute(ctx context.Context, tenantID string, id int64) error
+	UpsertRepositories(ctx context.Context, items []RepositoryRecord) error
+	DeactivateRepositories(ctx context.Context, source string, externalIDs []int64) error
+	DeactivateInstallationRepositories(ctx context.Context, installationID int64) error
+	RenameRepository(ctx context.Context, item RepositoryRecord, oldFullName string) (int64, error)
+	ListRepositories(ctx context.Context, limit int, offset int, source string, query string, activeOnly bool) ([]RepositoryRecord, int64, error)
+	SaveQuarantinedDelivery(ctx context.Context, item QuarantinedDelivery) (int64, error)
+	ListQuarantinedDeliveries(ctx context.Context, limit int, offset int, status string) ([]QuarantinedDelivery, int64, error)
+	GetQuarantinedDelivery(ctx context.Context, id int64) (QuarantinedDelivery, error)
+	UpdateQuarantinedPayload(ctx context.Context, id int64, payload []byte, actor string) error
+	ClaimQuarantinedDelivery(ctx context.Context, id int64, actor string) (bool, error)
+	ReleaseQuarantinedDelivery(ctx context.Context, id int64) error
+	ResolveQuarantinedDelivery(ctx context.Context, id int64, status string, note string, actor string) error
+	ListScheduledJobs(ctx context.Context, limit int, offset int) ([]ScheduledJobRecord, int64, error)
+	GetScheduledJobByID(ctx context.Context, id int64) (ScheduledJobRecord, error)
+	CreateScheduledJob(ctx context.Context, job ScheduledJobRecord) (int64, error)
+	UpdateScheduledJobActive(ctx context.Context, id int64, isActive bool, nextRunAt time.Time) error
+	ListDueScheduledJobs(ctx context.Context, now time.Time, limit int) ([]ScheduledJobRecord, error)
+	ClaimScheduledJob(ctx context.Context, id int64, expectedNextRunAt time.Time, nextRunAt time.Time) (bool, error)
+	SaveScheduledJobRun(ctx context.Context, run ScheduledJobRunRecord) (int64, error)
+	ListScheduledJobRuns(ctx context.Context, jobID int64, limit int, offset int) ([]ScheduledJobRunRecord, int64, error)
+	ListScheduledJobItems(ctx context.Context, jobID int64) (map[int]bool, error)
+	SaveScheduledJobItem(ctx context.Context, jobID int64, number int) error
 	UserStore
 }
 
@@ -233,7 +356,12 @@ func NewWebhookEventStore(ctx context.Context, databaseURL string) (WebhookStore
 }
 
 func newPostgresWebhookEventStore(ctx context.Context, databaseURL string) (*WebhookEventStore, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse DATABASE_URL: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = pgxQueryTracer{}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create pgx pool: %w", err)
 	}
@@ -267,29 +395,36 @@ func (s *WebhookEventStore) SaveEvent(ctx context.Context, evt WebhookEvent) err
 	_, err := s.pool.Exec(ctx, `
 		INSERT INTO webhook_events (
 			tenant_id, delivery_id, event_type, action,
-			repository_full_name, sender_login, payload_json
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			repository_full_name, sender_login, payload_json, source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (tenant_id, delivery_id) DO NOTHING
-	`, tenantID, evt.DeliveryID, evt.EventType, evt.Action, evt.RepositoryFullName, evt.SenderLogin, evt.PayloadJSON)
+	`, tenantID, evt.DeliveryID, evt.EventType, evt.Action, evt.RepositoryFullName, evt.SenderLogin, evt.PayloadJSON, normalizeEventSource(evt.Source))
 	if err != nil {
 		return fmt.Errorf("insert webhook event: %w", err)
 	}
 	return nil
 }
 
-func (s *WebhookEventStore) SaveAlert(ctx context.Context, alert AlertRecord) error {
+// SaveAlert inserts an alert and returns its id, or 0 when the same alert was
+// already raised for the delivery.
+func (s *WebhookEventStore) SaveAlert(ctx context.Context, alert AlertRecord) (int64, error) {
 	tenantID := tenantIDFromCtx(ctx)
-	_, err := s.pool.Exec(ctx, `
+	var id int64
+	err := s.pool.QueryRow(ctx, `
 		INSERT INTO webhook_alerts (
 			tenant_id, delivery_id, event_type, action, repository_full_name,
 			sender_login, rule_matched, suggestion_type, suggestion_value, reason
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (tenant_id, delivery_id, suggestion_type, suggestion_value, rule_matched) DO NOTHING
-	`, tenantID, alert.DeliveryID, alert.EventType, alert.Action, alert.RepositoryFullName, alert.SenderLogin, alert.RuleMatched, alert.SuggestionType, alert.SuggestionValue, alert.Reason)
+		RETURNING id
+	`, tenantID, alert.DeliveryID, alert.EventType, alert.Action, alert.RepositoryFullName, alert.SenderLogin, alert.RuleMatched, alert.SuggestionType, alert.SuggestionValue, alert.Reason).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("insert webhook alert: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("insert webhook alert: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 func (s *WebhookEventStore) ListEvents(ctx context.Context, limit int, offset int, eventType string, action string) ([]WebhookEventRecord, int64, error) {
@@ -309,7 +444,7 @@ func (s *WebhookEventStore) ListEvents(ctx context.Context, limit int, offset in
 	}
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, payload_json, received_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
 		FROM webhook_events
 		WHERE tenant_id = $1
 		  AND ($2 = '' OR event_type = $2)
@@ -332,6 +467,7 @@ func (s *WebhookEventStore) ListEvents(ctx context.Context, limit int, offset in
 			&item.Action,
 			&item.RepositoryFullName,
 			&item.SenderLogin,
+			&item.Source,
 			&item.PayloadJSON,
 			&item.ReceivedAt,
 		); err != nil {
@@ -495,7 +631,11 @@ func (s *WebhookEventStore) ListEventFilterOptions(ctx context.Context) (EventFi
 	if err != nil {
 		return EventFilterOptions{}, fmt.Errorf("list distinct sender from webhook_events: %w", err)
 	}
-	return EventFilterOptions{EventTypes: et, Actions: ac, Repositories: repo, Senders: sender}, nil
+	sources, err := listDistinctNonEmpty(ctx, s.pool, `SELECT DISTINCT source FROM webhook_events WHERE tenant_id = $1 AND source <> '' ORDER BY source ASC`, tenantID)
+	if err != nil {
+		return EventFilterOptions{}, fmt.Errorf("list distinct source from webhook_events: %w", err)
+	}
+	return EventFilterOptions{EventTypes: et, Actions: ac, Repositories: repo, Senders: sender, Sources: sources}, nil
 }
 
 func (s *WebhookEventStore) ListAlertFilterOptions(ctx context.Context) (AlertFilterOptions, error) {
@@ -746,11 +886,11 @@ func (s *WebhookEventStore) SaveActionExecutionFailure(ctx context.Context, item
 	tenantID := tenantIDFromCtx(ctx)
 	_, err := s.pool.Exec(ctx, `
 		INSERT INTO webhook_action_failures (
-			tenant_id, delivery_id, event_type, action, repository_full_name,
+			tenant_id, delivery_id, event_type, action, repository_full_name, source,
 			suggestion_type, suggestion_value, error_message, attempt_count,
 			retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,0,'never','',NULL,FALSE)
-	`, tenantID, item.DeliveryID, item.EventType, item.Action, item.RepositoryFullName, item.SuggestionType, item.SuggestionValue, item.ErrorMessage, item.AttemptCount)
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,0,'never','',NULL,FALSE)
+	`, tenantID, item.DeliveryID, item.EventType, item.Action, item.RepositoryFullName, normalizeEventSource(item.Source), item.SuggestionType, item.SuggestionValue, item.ErrorMessage, item.AttemptCount)
 	if err != nil {
 		return fmt.Errorf("insert webhook action failure: %w", err)
 	}
@@ -765,7 +905,7 @@ func (s *WebhookEventStore) ListActionExecutionFailures(ctx context.Context, lim
 	}
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, COALESCE(last_retry_at, 'epoch'::timestamptz), is_resolved, occurred_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, COALESCE(last_retry_at, 'epoch'::timestamptz), is_resolved, occurred_at
 		FROM webhook_action_failures
 		WHERE tenant_id = $1
 		  AND ($2 OR is_resolved = FALSE)
@@ -780,7 +920,7 @@ func (s *WebhookEventStore) ListActionExecutionFailures(ctx context.Context, lim
 	items := make([]ActionExecutionFailureRecord, 0, limit)
 	for rows.Next() {
 		var rec ActionExecutionFailureRecord
-		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
 			return nil, 0, fmt.Errorf("scan action failure: %w", err)
 		}
 		if rec.LastRetryAt.Equal(time.Unix(0, 0).UTC()) {
@@ -798,11 +938,11 @@ func (s *WebhookEventStore) GetActionExecutionFailureByID(ctx context.Context, i
 	tenantID := tenantIDFromCtx(ctx)
 	var rec ActionExecutionFailureRecord
 	err := s.pool.QueryRow(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, COALESCE(last_retry_at, 'epoch'::timestamptz), is_resolved, occurred_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, COALESCE(last_retry_at, 'epoch'::timestamptz), is_resolved, occurred_at
 		FROM webhook_action_failures
 		WHERE id = $1
 		  AND tenant_id = $2
-	`, id, tenantID).Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt)
+	`, id, tenantID).Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &rec.LastRetryAt, &rec.IsResolved, &rec.OccurredAt)
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
 			return rec, fmt.Errorf("action failure not found")
@@ -1148,80 +1288,28 @@ func (s *WebhookEventStore) ListAuditLogs(ctx context.Context, limit int, offset
 func (s *WebhookEventStore) SaveDeliveryMetric(ctx context.Context, metric DeliveryMetric) error {
 	tenantID := tenantIDFromCtx(ctx)
 	_, err := s.pool.Exec(ctx, `
-		INSERT INTO webhook_delivery_metrics (tenant_id, event_type, delivery_id, success, processing_ms, recorded_at)
-		VALUES ($1,$2,$3,$4,$5,$6)
-	`, tenantID, strings.TrimSpace(metric.EventType), strings.TrimSpace(metric.DeliveryID), metric.Success, metric.ProcessingMS, metric.RecordedAtUTC)
+		INSERT INTO webhook_delivery_metrics (tenant_id, event_type, delivery_id, success, outcome, matched_secret, processing_ms, recorded_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	`, tenantID, strings.TrimSpace(metric.EventType), strings.TrimSpace(metric.DeliveryID), metric.Success, strings.TrimSpace(metric.Outcome), strings.TrimSpace(metric.MatchedSecret), metric.ProcessingMS, metric.RecordedAtUTC)
 	if err != nil {
 		return fmt.Errorf("insert delivery metric: %w", err)
 	}
 	return nil
 }
 
+// GetMetricsOverview reads event, alert and delivery figures from the metrics
+// rollups; action failures are counted from their table since they get resolved.
 func (s *WebhookEventStore) GetMetricsOverview(ctx context.Context, since time.Time) (MetricsOverview, error) {
 	tenantID := tenantIDFromCtx(ctx)
 	var out MetricsOverview
-	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_events WHERE tenant_id = $1 AND received_at >= $2`, tenantID, since).Scan(&out.Events24h); err != nil {
-		return out, fmt.Errorf("count events metrics: %w", err)
-	}
-	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_alerts WHERE tenant_id = $1 AND created_at >= $2`, tenantID, since).Scan(&out.Alerts24h); err != nil {
-		return out, fmt.Errorf("count alerts metrics: %w", err)
-	}
-	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_action_failures WHERE tenant_id = $1 AND occurred_at >= $2 AND is_resolved = FALSE`, tenantID, since).Scan(&out.Failures24h); err != nil {
-		return out, fmt.Errorf("count failures metrics: %w", err)
-	}
-
-	var total int64
-	var success int64
-	var avgProcessing sql.NullFloat64
-	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END),0), AVG(processing_ms)::float8 FROM webhook_delivery_metrics WHERE tenant_id = $1 AND recorded_at >= $2`, tenantID, since).Scan(&total, &success, &avgProcessing); err != nil {
-		return out, fmt.Errorf("count delivery metrics: %w", err)
-	}
-	out.DeliveryAttempts = total
-	out.DeliverySuccess = success
-	if total > 0 {
-		out.SuccessRate24h = (float64(success) / float64(total)) * 100
-		out.FailureRate24h = 100 - out.SuccessRate24h
-		out.EstimatedManualMinutes24h = float64(total-success) * 2
-		if out.Events24h > 0 {
-			out.AutomationCoverage24h = (float64(total) / float64(out.Events24h)) * 100
-			if out.AutomationCoverage24h > 100 {
-				out.AutomationCoverage24h = 100
-			}
-		}
-	}
-	if avgProcessing.Valid {
-		out.AvgProcessingMS24h = avgProcessing.Float64
-	}
-	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_action_failures WHERE tenant_id = $1 AND occurred_at >= $2 AND is_resolved`, tenantID, since).Scan(&out.ResolvedFailures24h); err != nil {
-		return out, fmt.Errorf("count resolved failures metrics: %w", err)
-	}
-
-	rows, err := s.pool.Query(ctx, `SELECT processing_ms FROM webhook_delivery_metrics WHERE tenant_id = $1 AND recorded_at >= $2 ORDER BY processing_ms ASC`, tenantID, since)
+	rollups, err := readMetricsRollups(ctx, s, tenantID, since, true)
 	if err != nil {
-		return out, fmt.Errorf("query latency metrics: %w", err)
-	}
-	defer rows.Close()
-	latencies := make([]int64, 0, 256)
-	for rows.Next() {
-		var v int64
-		if err := rows.Scan(&v); err != nil {
-			return out, fmt.Errorf("scan latency metric: %w", err)
-		}
-		latencies = append(latencies, v)
+		return out, fmt.Errorf("read metrics rollups: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return out, fmt.Errorf("iterate latency metrics: %w", err)
-	}
-	if len(latencies) > 0 {
-		idx := int(float64(len(latencies)-1) * 0.95)
-		out.P95LatencyMS24h = float64(latencies[idx])
-		if out.AvgProcessingMS24h == 0 {
-			var sum int64
-			for _, v := range latencies {
-				sum += v
-			}
-			out.AvgProcessingMS24h = float64(sum) / float64(len(latencies))
-		}
+	applyMetricsRollups(&out, rollups)
+
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FILTER (WHERE NOT is_resolved), COUNT(*) FILTER (WHERE is_resolved) FROM webhook_action_failures WHERE tenant_id = $1 AND occurred_at >= $2`, tenantID, since).Scan(&out.Failures24h, &out.ResolvedFailures24h); err != nil {
+		return out, fmt.Errorf("count failures metrics: %w", err)
 	}
 	return out, nil
 }
@@ -1232,56 +1320,21 @@ func (s *WebhookEventStore) GetMetricsTimeSeries(ctx context.Context, since time
 		intervalMinutes = 60
 	}
 	step := time.Duration(intervalMinutes) * time.Minute
-	start := since.UTC().Truncate(step)
-	now := time.Now().UTC()
 
-	buckets := make(map[time.Time]*MetricsTimePoint)
-	for t := start; !t.After(now); t = t.Add(step) {
-		tt := t
-		buckets[tt] = &MetricsTimePoint{BucketStart: tt}
-	}
-
-	fill := func(query string, assign func(*MetricsTimePoint, int64)) error {
-		rows, err := s.pool.Query(ctx, query, tenantID, since)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-		for rows.Next() {
-			var ts time.Time
-			if err := rows.Scan(&ts); err != nil {
-				return err
-			}
-			b := ts.UTC().Truncate(step)
-			if p, ok := buckets[b]; ok {
-				assign(p, 1)
-			}
-		}
-		return rows.Err()
-	}
-
-	if err := fill(`SELECT received_at FROM webhook_events WHERE tenant_id = $1 AND received_at >= $2`, func(p *MetricsTimePoint, _ int64) { p.Events++ }); err != nil {
-		return nil, fmt.Errorf("fill events metrics timeseries: %w", err)
-	}
-	if err := fill(`SELECT created_at FROM webhook_alerts WHERE tenant_id = $1 AND created_at >= $2`, func(p *MetricsTimePoint, _ int64) { p.Alerts++ }); err != nil {
-		return nil, fmt.Errorf("fill alerts metrics timeseries: %w", err)
+	rollups, err := readMetricsRollups(ctx, s, tenantID, since, intervalMinutes%60 == 0)
+	if err != nil {
+		return nil, fmt.Errorf("read metrics rollups: %w", err)
 	}
-	if err := fill(`SELECT occurred_at FROM webhook_action_failures WHERE tenant_id = $1 AND occurred_at >= $2 AND is_resolved = FALSE`, func(p *MetricsTimePoint, _ int64) { p.Failures++ }); err != nil {
+	failures, err := s.countUnresolvedFailuresByMinute(ctx, tenantID, since)
+	if err != nil {
 		return nil, fmt.Errorf("fill failures metrics timeseries: %w", err)
 	}
-
-	out := make([]MetricsTimePoint, 0, len(buckets))
-	for t := start; !t.After(now); t = t.Add(step) {
-		if p, ok := buckets[t]; ok {
-			out = append(out, *p)
-		}
-	}
-	return out, nil
+	return metricsTimeSeriesFromRollups(since, time.Now(), step, rollups, failures), nil
 }
 
 func (s *WebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecord, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, name, is_active, created_at, updated_at
+		SELECT id, name, is_active, auto_retry_enabled, COALESCE(github_installation_id, 0), COALESCE(webhook_signature_schemes, ''), created_at, updated_at
 		FROM tenants
 		ORDER BY id ASC
 	`)
@@ -1293,7 +1346,7 @@ func (s *WebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecord, er
 	items := make([]TenantRecord, 0, 16)
 	for rows.Next() {
 		var item TenantRecord
-		if err := rows.Scan(&item.ID, &item.Name, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.Name, &item.IsActive, &item.AutoRetryEnabled, &item.GitHubInstallationID, &item.SignatureSchemes, &item.CreatedAt, &item.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan tenant: %w", err)
 		}
 		items = append(items, item)
@@ -1304,6 +1357,22 @@ func (s *WebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecord, er
 	return items, nil
 }
 
+func (s *WebhookEventStore) GetTenant(ctx context.Context, id string) (TenantRecord, error) {
+	var item TenantRecord
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, name, is_active, auto_retry_enabled, COALESCE(github_installation_id, 0), COALESCE(webhook_signature_schemes, ''), created_at, updated_at
+		FROM tenants
+		WHERE id = $1
+	`, strings.TrimSpace(id)).Scan(&item.ID, &item.Name, &item.IsActive, &item.AutoRetryEnabled, &item.GitHubInstallationID, &item.SignatureSchemes, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no rows") {
+			return item, fmt.Errorf("tenant not found")
+		}
+		return item, fmt.Errorf("get tenant: %w", err)
+	}
+	return item, nil
+}
+
 func (s *WebhookEventStore) CreateTenant(ctx context.Context, id string, name string) error {
 	_, err := s.pool.Exec(ctx, `
 		INSERT INTO tenants (id, name, is_active)
@@ -1331,6 +1400,24 @@ func (s *WebhookEventStore) UpdateTenantActive(ctx context.Context, id string, i
 	return nil
 }
 
+// UpdateTenantWebhookSignatureSchemes stores the comma-separated signature schemes
+// accepted for the tenant's deliveries; empty restores the server default.
+func (s *WebhookEventStore) UpdateTenantWebhookSignatureSchemes(ctx context.Context, tenantID string, schemes string) error {
+	result, err := s.pool.Exec(ctx, `
+		UPDATE tenants
+		SET webhook_signature_schemes = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, strings.TrimSpace(tenantID), strings.TrimSpace(schemes))
+	if err != nil {
+		return fmt.Errorf("update tenant webhook signature schemes: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+	return nil
+}
+
 func (s *WebhookEventStore) ensureSchema(ctx context.Context) error {
 	_, err := s.pool.Exec(ctx, `
 		CREATE TABLE IF NOT EXISTS tenants (
@@ -1511,6 +1598,11 @@ func (s *WebhookEventStore) ensureSchema(ctx context.Context) error {
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN IF NOT EXISTS last_retry_message TEXT NOT NULL DEFAULT ''`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN IF NOT EXISTS last_retry_at TIMESTAMPTZ NULL`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN IF NOT EXISTS is_resolved BOOLEAN NOT NULL DEFAULT FALSE`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'github'`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'github'`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE tenants ADD COLUMN IF NOT EXISTS auto_retry_enabled BOOLEAN NOT NULL DEFAULT TRUE`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE tenants ADD COLUMN IF NOT EXISTS github_installation_id BIGINT NOT NULL DEFAULT 0`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE tenants ADD COLUMN IF NOT EXISTS webhook_signature_schemes TEXT NOT NULL DEFAULT ''`)
 
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_alerts ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
@@ -1519,6 +1611,8 @@ func (s *WebhookEventStore) ensureSchema(ctx context.Context) error {
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN IF NOT EXISTS outcome TEXT NOT NULL DEFAULT ''`)
+	_, _ = s.pool.Exec(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN IF NOT EXISTS matched_secret TEXT NOT NULL DEFAULT ''`)
 	_, _ = s.pool.Exec(ctx, `ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default'`)
 
 	_, _ = s.pool.Exec(ctx, `UPDATE webhook_events SET tenant_id = 'default' WHERE tenant_id IS NULL OR tenant_id = ''`)
@@ -1715,6 +1809,40 @@ func (s *WebhookEventStore) ensureSchema(ctx context.Context) error {
 		return fmt.Errorf("create idx_webhook_delivery_metrics_tenant_id: %w", err)
 	}
 
+	if err := s.ensureScheduledJobsSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureTenantCredentialsSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureWebhookRoutesSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureRepositoriesSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureWebhookQuarantineSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureRecoveryHooksSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureGitHubEventSourcesSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureGitHubSyncSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureWorkerLeasesSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureMetricsRollupSchema(ctx); err != nil {
+		return err
+	}
+	if err := s.ensureSLOSchema(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
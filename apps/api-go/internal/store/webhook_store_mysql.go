@@ -25,10 +25,15 @@ func newMySQLWebhookEventStore(ctx context.Context, databaseURL string) (*MySQLW
 		return nil, err
 	}
 
-	db, err := sql.Open("mysql", dsn)
+	mysqlConfig, err := mysqlDriver.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse mysql dsn: %w", err)
+	}
+	connector, err := mysqlDriver.NewConnector(mysqlConfig)
 	if err != nil {
 		return nil, fmt.Errorf("open mysql: %w", err)
 	}
+	db := openTracedMySQL(connector)
 	if err := db.PingContext(ctx); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("ping mysql: %w", err)
@@ -97,19 +102,19 @@ func (s *MySQLWebhookEventStore) SaveEvent(ctx context.Context, evt WebhookEvent
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO webhook_events (
 			tenant_id, delivery_id, event_type, action,
-			repository_full_name, sender_login, payload_json
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+			repository_full_name, sender_login, payload_json, source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE delivery_id = delivery_id
-	`, tenantID, evt.DeliveryID, evt.EventType, evt.Action, evt.RepositoryFullName, evt.SenderLogin, string(evt.PayloadJSON))
+	`, tenantID, evt.DeliveryID, evt.EventType, evt.Action, evt.RepositoryFullName, evt.SenderLogin, string(evt.PayloadJSON), normalizeEventSource(evt.Source))
 	if err != nil {
 		return fmt.Errorf("insert webhook event: %w", err)
 	}
 	return nil
 }
 
-func (s *MySQLWebhookEventStore) SaveAlert(ctx context.Context, alert AlertRecord) error {
+func (s *MySQLWebhookEventStore) SaveAlert(ctx context.Context, alert AlertRecord) (int64, error) {
 	tenantID := tenantIDFromCtxMySQL(ctx)
-	_, err := s.db.ExecContext(ctx, `
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO webhook_alerts (
 			tenant_id, delivery_id, event_type, action, repository_full_name,
 			sender_login, rule_matched, suggestion_type, suggestion_value, reason
@@ -117,9 +122,21 @@ func (s *MySQLWebhookEventStore) SaveAlert(ctx context.Context, alert AlertRecor
 		ON DUPLICATE KEY UPDATE delivery_id = delivery_id
 	`, tenantID, alert.DeliveryID, alert.EventType, alert.Action, alert.RepositoryFullName, alert.SenderLogin, alert.RuleMatched, alert.SuggestionType, alert.SuggestionValue, alert.Reason)
 	if err != nil {
-		return fmt.Errorf("insert webhook alert: %w", err)
+		return 0, fmt.Errorf("insert webhook alert: %w", err)
 	}
-	return nil
+	// A no-op duplicate update affects no rows.
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get affected rows for webhook alert: %w", err)
+	}
+	if affected == 0 {
+		return 0, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get webhook alert id: %w", err)
+	}
+	return id, nil
 }
 
 func (s *MySQLWebhookEventStore) ListEvents(ctx context.Context, limit int, offset int, eventType string, action string) ([]WebhookEventRecord, int64, error) {
@@ -139,7 +156,7 @@ func (s *MySQLWebhookEventStore) ListEvents(ctx context.Context, limit int, offs
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, payload_json, received_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, sender_login, source, payload_json, received_at
 		FROM webhook_events
 		WHERE tenant_id = ?
 		  AND (? = '' OR event_type = ?)
@@ -155,7 +172,7 @@ func (s *MySQLWebhookEventStore) ListEvents(ctx context.Context, limit int, offs
 	items := make([]WebhookEventRecord, 0, limit)
 	for rows.Next() {
 		var rec WebhookEventRecord
-		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SenderLogin, &rec.PayloadJSON, &rec.ReceivedAt); err != nil {
+		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SenderLogin, &rec.Source, &rec.PayloadJSON, &rec.ReceivedAt); err != nil {
 			return nil, 0, fmt.Errorf("scan webhook event row: %w", err)
 		}
 		items = append(items, rec)
@@ -304,7 +321,11 @@ func (s *MySQLWebhookEventStore) ListEventFilterOptions(ctx context.Context) (Ev
 	if err != nil {
 		return EventFilterOptions{}, fmt.Errorf("list distinct sender from webhook_events: %w", err)
 	}
-	return EventFilterOptions{EventTypes: et, Actions: ac, Repositories: repo, Senders: sender}, nil
+	sources, err := listDistinctNonEmptyMySQL(ctx, s.db, `SELECT DISTINCT source FROM webhook_events WHERE tenant_id = ? AND source <> '' ORDER BY source ASC`, tenantID)
+	if err != nil {
+		return EventFilterOptions{}, fmt.Errorf("list distinct source from webhook_events: %w", err)
+	}
+	return EventFilterOptions{EventTypes: et, Actions: ac, Repositories: repo, Senders: sender, Sources: sources}, nil
 }
 
 func (s *MySQLWebhookEventStore) ListAlertFilterOptions(ctx context.Context) (AlertFilterOptions, error) {
@@ -561,11 +582,11 @@ func (s *MySQLWebhookEventStore) SaveActionExecutionFailure(ctx context.Context,
 	tenantID := tenantIDFromCtxMySQL(ctx)
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO webhook_action_failures (
-			tenant_id, delivery_id, event_type, action, repository_full_name,
+			tenant_id, delivery_id, event_type, action, repository_full_name, source,
 			suggestion_type, suggestion_value, error_message, attempt_count,
 			retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 'never', '', NULL, FALSE)
-	`, tenantID, item.DeliveryID, item.EventType, item.Action, item.RepositoryFullName, item.SuggestionType, item.SuggestionValue, item.ErrorMessage, item.AttemptCount)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 'never', '', NULL, FALSE)
+	`, tenantID, item.DeliveryID, item.EventType, item.Action, item.RepositoryFullName, normalizeEventSource(item.Source), item.SuggestionType, item.SuggestionValue, item.ErrorMessage, item.AttemptCount)
 	if err != nil {
 		return fmt.Errorf("insert webhook action failure: %w", err)
 	}
@@ -580,7 +601,7 @@ func (s *MySQLWebhookEventStore) ListActionExecutionFailures(ctx context.Context
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved, occurred_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved, occurred_at
 		FROM webhook_action_failures
 		WHERE tenant_id = ?
 		  AND (? OR is_resolved = FALSE)
@@ -596,7 +617,7 @@ func (s *MySQLWebhookEventStore) ListActionExecutionFailures(ctx context.Context
 	for rows.Next() {
 		var rec ActionExecutionFailureRecord
 		var lastRetryAt sql.NullTime
-		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
+		if err := rows.Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt); err != nil {
 			return nil, 0, fmt.Errorf("scan action failure: %w", err)
 		}
 		normalizeLastRetryAt(&rec, lastRetryAt)
@@ -613,11 +634,11 @@ func (s *MySQLWebhookEventStore) GetActionExecutionFailureByID(ctx context.Conte
 	var rec ActionExecutionFailureRecord
 	var lastRetryAt sql.NullTime
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, delivery_id, event_type, action, repository_full_name, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved, occurred_at
+		SELECT id, delivery_id, event_type, action, repository_full_name, source, suggestion_type, suggestion_value, error_message, attempt_count, retry_count, last_retry_status, last_retry_message, last_retry_at, is_resolved, occurred_at
 		FROM webhook_action_failures
 		WHERE id = ?
 		  AND tenant_id = ?
-	`, id, tenantID).Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt)
+	`, id, tenantID).Scan(&rec.ID, &rec.DeliveryID, &rec.EventType, &rec.Action, &rec.RepositoryFullName, &rec.Source, &rec.SuggestionType, &rec.SuggestionValue, &rec.ErrorMessage, &rec.AttemptCount, &rec.RetryCount, &rec.LastRetryStatus, &rec.LastRetryMessage, &lastRetryAt, &rec.IsResolved, &rec.OccurredAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return rec, fmt.Errorf("action failure not found")
@@ -991,9 +1012,9 @@ func (s *MySQLWebhookEventStore) EnsureBootstrapAdminUser(ctx context.Context, u
 func (s *MySQLWebhookEventStore) SaveDeliveryMetric(ctx context.Context, metric DeliveryMetric) error {
 	tenantID := tenantIDFromCtxMySQL(ctx)
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO webhook_delivery_metrics (tenant_id, event_type, delivery_id, success, processing_ms, recorded_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, tenantID, strings.TrimSpace(metric.EventType), strings.TrimSpace(metric.DeliveryID), metric.Success, metric.ProcessingMS, metric.RecordedAtUTC)
+		INSERT INTO webhook_delivery_metrics (tenant_id, event_type, delivery_id, success, outcome, matched_secret, processing_ms, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, tenantID, strings.TrimSpace(metric.EventType), strings.TrimSpace(metric.DeliveryID), metric.Success, strings.TrimSpace(metric.Outcome), strings.TrimSpace(metric.MatchedSecret), metric.ProcessingMS, metric.RecordedAtUTC)
 	if err != nil {
 		return fmt.Errorf("insert delivery metric: %w", err)
 	}
@@ -1003,68 +1024,14 @@ func (s *MySQLWebhookEventStore) SaveDeliveryMetric(ctx context.Context, metric
 func (s *MySQLWebhookEventStore) GetMetricsOverview(ctx context.Context, since time.Time) (MetricsOverview, error) {
 	tenantID := tenantIDFromCtxMySQL(ctx)
 	var out MetricsOverview
-	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_events WHERE tenant_id = ? AND received_at >= ?`, tenantID, since).Scan(&out.Events24h); err != nil {
-		return out, fmt.Errorf("count events metrics: %w", err)
-	}
-	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_alerts WHERE tenant_id = ? AND created_at >= ?`, tenantID, since).Scan(&out.Alerts24h); err != nil {
-		return out, fmt.Errorf("count alerts metrics: %w", err)
-	}
-	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_action_failures WHERE tenant_id = ? AND occurred_at >= ? AND is_resolved = FALSE`, tenantID, since).Scan(&out.Failures24h); err != nil {
-		return out, fmt.Errorf("count failures metrics: %w", err)
-	}
-
-	var total int64
-	var success int64
-	var avgProcessing sql.NullFloat64
-	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END),0), AVG(processing_ms) FROM webhook_delivery_metrics WHERE tenant_id = ? AND recorded_at >= ?`, tenantID, since).Scan(&total, &success, &avgProcessing); err != nil {
-		return out, fmt.Errorf("count delivery metrics: %w", err)
-	}
-	out.DeliveryAttempts = total
-	out.DeliverySuccess = success
-	if total > 0 {
-		out.SuccessRate24h = (float64(success) / float64(total)) * 100
-		out.FailureRate24h = 100 - out.SuccessRate24h
-		out.EstimatedManualMinutes24h = float64(total-success) * 2
-		if out.Events24h > 0 {
-			out.AutomationCoverage24h = (float64(total) / float64(out.Events24h)) * 100
-			if out.AutomationCoverage24h > 100 {
-				out.AutomationCoverage24h = 100
-			}
-		}
-	}
-	if avgProcessing.Valid {
-		out.AvgProcessingMS24h = avgProcessing.Float64
-	}
-	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_action_failures WHERE tenant_id = ? AND occurred_at >= ? AND is_resolved = TRUE`, tenantID, since).Scan(&out.ResolvedFailures24h); err != nil {
-		return out, fmt.Errorf("count resolved failures metrics: %w", err)
-	}
-
-	rows, err := s.db.QueryContext(ctx, `SELECT processing_ms FROM webhook_delivery_metrics WHERE tenant_id = ? AND recorded_at >= ? ORDER BY processing_ms ASC`, tenantID, since)
+	rollups, err := readMetricsRollups(ctx, s, tenantID, since, true)
 	if err != nil {
-		return out, fmt.Errorf("query latency metrics: %w", err)
-	}
-	defer rows.Close()
-	latencies := make([]int64, 0, 256)
-	for rows.Next() {
-		var v int64
-		if err := rows.Scan(&v); err != nil {
-			return out, fmt.Errorf("scan latency metric: %w", err)
-		}
-		latencies = append(latencies, v)
+		return out, fmt.Errorf("read metrics rollups: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return out, fmt.Errorf("iterate latency metrics: %w", err)
-	}
-	if len(latencies) > 0 {
-		idx := int(float64(len(latencies)-1) * 0.95)
-		out.P95LatencyMS24h = float64(latencies[idx])
-		if out.AvgProcessingMS24h == 0 {
-			var sum int64
-			for _, v := range latencies {
-				sum += v
-			}
-			out.AvgProcessingMS24h = float64(sum) / float64(len(latencies))
-		}
+	applyMetricsRollups(&out, rollups)
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(CASE WHEN is_resolved = FALSE THEN 1 ELSE 0 END), 0), COALESCE(SUM(CASE WHEN is_resolved = TRUE THEN 1 ELSE 0 END), 0) FROM webhook_action_failures WHERE tenant_id = ? AND occurred_at >= ?`, tenantID, since).Scan(&out.Failures24h, &out.ResolvedFailures24h); err != nil {
+		return out, fmt.Errorf("count failures metrics: %w", err)
 	}
 	return out, nil
 }
@@ -1075,56 +1042,21 @@ func (s *MySQLWebhookEventStore) GetMetricsTimeSeries(ctx context.Context, since
 		intervalMinutes = 60
 	}
 	step := time.Duration(intervalMinutes) * time.Minute
-	start := since.UTC().Truncate(step)
-	now := time.Now().UTC()
-
-	buckets := make(map[time.Time]*MetricsTimePoint)
-	for t := start; !t.After(now); t = t.Add(step) {
-		tt := t
-		buckets[tt] = &MetricsTimePoint{BucketStart: tt}
-	}
-
-	fill := func(query string, assign func(*MetricsTimePoint)) error {
-		rows, err := s.db.QueryContext(ctx, query, tenantID, since)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-		for rows.Next() {
-			var ts time.Time
-			if err := rows.Scan(&ts); err != nil {
-				return err
-			}
-			b := ts.UTC().Truncate(step)
-			if p, ok := buckets[b]; ok {
-				assign(p)
-			}
-		}
-		return rows.Err()
-	}
 
-	if err := fill(`SELECT received_at FROM webhook_events WHERE tenant_id = ? AND received_at >= ?`, func(p *MetricsTimePoint) { p.Events++ }); err != nil {
-		return nil, fmt.Errorf("fill events metrics timeseries: %w", err)
-	}
-	if err := fill(`SELECT created_at FROM webhook_alerts WHERE tenant_id = ? AND created_at >= ?`, func(p *MetricsTimePoint) { p.Alerts++ }); err != nil {
-		return nil, fmt.Errorf("fill alerts metrics timeseries: %w", err)
+	rollups, err := readMetricsRollups(ctx, s, tenantID, since, intervalMinutes%60 == 0)
+	if err != nil {
+		return nil, fmt.Errorf("read metrics rollups: %w", err)
 	}
-	if err := fill(`SELECT occurred_at FROM webhook_action_failures WHERE tenant_id = ? AND occurred_at >= ? AND is_resolved = FALSE`, func(p *MetricsTimePoint) { p.Failures++ }); err != nil {
+	failures, err := s.countUnresolvedFailuresByMinute(ctx, tenantID, since)
+	if err != nil {
 		return nil, fmt.Errorf("fill failures metrics timeseries: %w", err)
 	}
-
-	out := make([]MetricsTimePoint, 0, len(buckets))
-	for t := start; !t.After(now); t = t.Add(step) {
-		if p, ok := buckets[t]; ok {
-			out = append(out, *p)
-		}
-	}
-	return out, nil
+	return metricsTimeSeriesFromRollups(since, time.Now(), step, rollups, failures), nil
 }
 
 func (s *MySQLWebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecord, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, name, is_active, created_at, updated_at
+		SELECT id, name, is_active, auto_retry_enabled, COALESCE(github_installation_id, 0), COALESCE(webhook_signature_schemes, ''), created_at, updated_at
 		FROM tenants
 		ORDER BY id ASC
 	`)
@@ -1136,7 +1068,7 @@ func (s *MySQLWebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecor
 	items := make([]TenantRecord, 0, 16)
 	for rows.Next() {
 		var item TenantRecord
-		if err := rows.Scan(&item.ID, &item.Name, &item.IsActive, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.Name, &item.IsActive, &item.AutoRetryEnabled, &item.GitHubInstallationID, &item.SignatureSchemes, &item.CreatedAt, &item.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan tenant: %w", err)
 		}
 		items = append(items, item)
@@ -1147,6 +1079,22 @@ func (s *MySQLWebhookEventStore) ListTenants(ctx context.Context) ([]TenantRecor
 	return items, nil
 }
 
+func (s *MySQLWebhookEventStore) GetTenant(ctx context.Context, id string) (TenantRecord, error) {
+	var item TenantRecord
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, name, is_active, auto_retry_enabled, COALESCE(github_installation_id, 0), COALESCE(webhook_signature_schemes, ''), created_at, updated_at
+		FROM tenants
+		WHERE id = ?
+	`, strings.TrimSpace(id)).Scan(&item.ID, &item.Name, &item.IsActive, &item.AutoRetryEnabled, &item.GitHubInstallationID, &item.SignatureSchemes, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return item, fmt.Errorf("tenant not found")
+		}
+		return item, fmt.Errorf("get tenant: %w", err)
+	}
+	return item, nil
+}
+
 func (s *MySQLWebhookEventStore) CreateTenant(ctx context.Context, id string, name string) error {
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO tenants (id, name, is_active)
@@ -1177,6 +1125,29 @@ func (s *MySQLWebhookEventStore) UpdateTenantActive(ctx context.Context, id stri
 	return nil
 }
 
+func (s *MySQLWebhookEventStore) UpdateTenantWebhookSignatureSchemes(ctx context.Context, tenantID string, schemes string) error {
+	tenantID = strings.TrimSpace(tenantID)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE tenants
+		SET webhook_signature_schemes = ?, updated_at = CURRENT_TIMESTAMP(6)
+		WHERE id = ?
+	`, strings.TrimSpace(schemes), tenantID)
+	if err != nil {
+		return fmt.Errorf("update tenant webhook signature schemes: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get affected rows for tenant webhook signature schemes update: %w", err)
+	}
+	if affected == 0 {
+		var exists int
+		if err := s.db.QueryRowContext(ctx, `SELECT 1 FROM tenants WHERE id = ?`, tenantID).Scan(&exists); err != nil {
+			return fmt.Errorf("tenant not found")
+		}
+	}
+	return nil
+}
+
 func (s *MySQLWebhookEventStore) ensureSchema(ctx context.Context) error {
 	stmts := []string{
 		`CREATE TABLE IF NOT EXISTS tenants (
@@ -1318,6 +1289,17 @@ func (s *MySQLWebhookEventStore) ensureSchema(ctx context.Context) error {
 		`CREATE INDEX idx_webhook_delivery_metrics_recorded_at ON webhook_delivery_metrics (recorded_at)`,
 		`CREATE INDEX idx_webhook_delivery_metrics_tenant_id ON webhook_delivery_metrics (tenant_id)`,
 	}
+	stmts = append(stmts, mysqlScheduledJobsSchema...)
+	stmts = append(stmts, mysqlTenantCredentialsSchema...)
+	stmts = append(stmts, mysqlWebhookRoutesSchema...)
+	stmts = append(stmts, mysqlRepositoriesSchema...)
+	stmts = append(stmts, mysqlWebhookQuarantineSchema...)
+	stmts = append(stmts, mysqlRecoveryHooksSchema...)
+	stmts = append(stmts, mysqlGitHubEventSourcesSchema...)
+	stmts = append(stmts, mysqlGitHubSyncSchema...)
+	stmts = append(stmts, mysqlWorkerLeasesSchema...)
+	stmts = append(stmts, mysqlMetricsRollupSchema...)
+	stmts = append(stmts, mysqlSLOSchema...)
 
 	for _, stmt := range stmts {
 		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
@@ -1333,6 +1315,11 @@ func (s *MySQLWebhookEventStore) ensureSchema(ctx context.Context) error {
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN last_retry_message TEXT NOT NULL`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN last_retry_at DATETIME(6) NULL`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN is_resolved BOOLEAN NOT NULL DEFAULT FALSE`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_action_failures ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'github'`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_events ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'github'`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE tenants ADD COLUMN auto_retry_enabled BOOLEAN NOT NULL DEFAULT TRUE`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE tenants ADD COLUMN github_installation_id BIGINT NOT NULL DEFAULT 0`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE tenants ADD COLUMN webhook_signature_schemes VARCHAR(128) NOT NULL DEFAULT ''`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_events ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_alerts ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_rules ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
@@ -1341,6 +1328,9 @@ func (s *MySQLWebhookEventStore) ensureSchema(ctx context.Context) error {
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE audit_logs ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE admin_users ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default'`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN outcome VARCHAR(32) NOT NULL DEFAULT ''`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE webhook_delivery_metrics ADD COLUMN matched_secret VARCHAR(32) NOT NULL DEFAULT ''`)
+	_, _ = s.db.ExecContext(ctx, `ALTER TABLE tenant_credentials ADD COLUMN expires_at DATETIME(6) NULL`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE admin_users ADD COLUMN role VARCHAR(50) NOT NULL DEFAULT 'viewer'`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE admin_users ADD COLUMN permissions JSON NOT NULL DEFAULT ('["read"]')`)
 	_, _ = s.db.ExecContext(ctx, `ALTER TABLE admin_users ADD COLUMN last_login_at DATETIME(6) NULL`)
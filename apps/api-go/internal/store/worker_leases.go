@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *WebhookEventStore) ensureWorkerLeasesSchema(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS worker_leases (
+			name TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create worker_leases table: %w", err)
+	}
+	return nil
+}
+
+// AcquireWorkerLease takes or renews the named lease for holder. It succeeds when
+// the lease is free, expired or already held by holder; expiry uses database time
+// so replicas with skewed clocks agree.
+func (s *WebhookEventStore) AcquireWorkerLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	result, err := s.pool.Exec(ctx, `
+		INSERT INTO worker_leases (name, holder, expires_at)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (name) DO UPDATE
+		SET holder = EXCLUDED.holder,
+		    expires_at = EXCLUDED.expires_at,
+		    acquired_at = CASE WHEN worker_leases.holder = EXCLUDED.holder THEN worker_leases.acquired_at ELSE NOW() END
+		WHERE worker_leases.holder = EXCLUDED.holder
+		   OR worker_leases.expires_at < NOW()
+	`, name, holder, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("acquire worker lease: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ReleaseWorkerLease gives up the lease if holder still has it.
+func (s *WebhookEventStore) ReleaseWorkerLease(ctx context.Context, name string, holder string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM worker_leases WHERE name = $1 AND holder = $2`, name, holder)
+	if err != nil {
+		return fmt.Errorf("release worker lease: %w", err)
+	}
+	return nil
+}
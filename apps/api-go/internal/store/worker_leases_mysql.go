@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var mysqlWorkerLeasesSchema = []string{
+	`CREATE TABLE IF NOT EXISTS worker_leases (
+		name VARCHAR(128) NOT NULL PRIMARY KEY,
+		holder VARCHAR(255) NOT NULL,
+		expires_at DATETIME(6) NOT NULL,
+		acquired_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+}
+
+// AcquireWorkerLease renews or takes over an existing lease first, then tries to
+// create it; ON DUPLICATE KEY UPDATE cannot express the conditional takeover.
+func (s *MySQLWebhookEventStore) AcquireWorkerLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	micros := ttl.Microseconds()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE worker_leases
+		SET acquired_at = IF(holder = ?, acquired_at, CURRENT_TIMESTAMP(6)),
+		    holder = ?,
+		    expires_at = CURRENT_TIMESTAMP(6) + INTERVAL ? MICROSECOND
+		WHERE name = ?
+		  AND (holder = ? OR expires_at < CURRENT_TIMESTAMP(6))
+	`, holder, holder, micros, name, holder)
+	if err != nil {
+		return false, fmt.Errorf("acquire worker lease: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("acquire worker lease rows affected: %w", err)
+	}
+	if affected == 1 {
+		return true, nil
+	}
+
+	result, err = s.db.ExecContext(ctx, `
+		INSERT IGNORE INTO worker_leases (name, holder, expires_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP(6) + INTERVAL ? MICROSECOND)
+	`, name, holder, micros)
+	if err != nil {
+		return false, fmt.Errorf("create worker lease: %w", err)
+	}
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("create worker lease rows affected: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (s *MySQLWebhookEventStore) ReleaseWorkerLease(ctx context.Context, name string, holder string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM worker_leases WHERE name = ? AND holder = ?`, name, holder)
+	if err != nil {
+		return fmt.Errorf("release worker lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "jaeger"}); err == nil {
+		t.Fatalf("expected unknown exporter to be rejected")
+	}
+	shutdown, err := Setup(context.Background(), Config{Exporter: "none"})
+	if err != nil {
+		t.Fatalf("expected none to be accepted, err=%v", err)
+	}
+	_ = shutdown(context.Background())
+}
+
+func TestTraceIDFromContextKeepsCallerTraceWithoutExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "none"}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	ctx, span := Start(ctx, "server")
+	defer span.End()
+	if got := TraceIDFromContext(ctx); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("unexpected trace id %q", got)
+	}
+	if span.IsRecording() {
+		t.Fatalf("expected spans not to be recorded without an exporter")
+	}
+	if TraceIDFromContext(context.Background()) != "" {
+		t.Fatalf("expected no trace id without a span")
+	}
+}
+
+func TestOTLPTracesURL(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"":                                "http://localhost:4318/v1/traces",
+		"http://collector:4318/":          "http://collector:4318/v1/traces",
+		"http://collector:4318/v1/traces": "http://collector:4318/v1/traces",
+	} {
+		if got := otlpTracesURL(endpoint); got != want {
+			t.Fatalf("otlpTracesURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}